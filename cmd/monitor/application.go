@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/collector"
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
@@ -18,11 +24,66 @@ type Application struct {
 	CommandRunner  system.CommandRunner
 	DiskCollector  *collector.DiskCollector
 	CtrlCollector  *collector.ControllerCollector
+	SysCollector   *collector.SystemInfoCollector
 	HistoryStorage *storage.DiskHistoryStorage
 	ExitOnWarning  bool
+	ExpectDisks    int // exit with status 6 when fewer disks are found (0 disables the check)
 	OnlyWarnings   bool
 	Quiet          bool
 	CompactMode    bool
+	SummaryFooter  bool
+	TopN           int
+	EventCount     int // number of recent disk events shown in the report (--events)
+
+	// FormatterOptions holds formatter options passed through --set
+	FormatterOptions map[string]interface{}
+
+	// ParseStdin parses a single smartctl -a dump from stdin instead of collecting
+	ParseStdin bool
+	DiskName   string
+	DiskType   string
+	DiskModel  string
+
+	// ResetBaseline backs up and clears the history file instead of collecting
+	ResetBaseline bool
+	AssumeYes     bool
+
+	// ValidateHistory checks the given history file instead of collecting
+	ValidateHistory string
+
+	// DashboardReports are JSON reports from several hosts combined into one
+	// HTML page instead of collecting (--dashboard)
+	DashboardReports []string
+
+	// TUI shows an interactive, periodically refreshed disk table
+	TUI         bool
+	TUIInterval time.Duration
+
+	// Recorder captures every command and its output; the transcript is
+	// written to RecordFile when Run returns (--record)
+	Recorder   *system.RecordingCommandRunner
+	RecordFile string
+
+	// Syslog receives the summary and every warning or error in addition
+	// to the normal output (--syslog); nil disables it
+	Syslog system.SyslogWriter
+
+	// Replaying is set for --replay runs, which neither read nor write the
+	// local history files
+	Replaying bool
+
+	// ReadOnly is set for --tui, whose collections must not save history,
+	// controller temperatures or events, since the view re-collects every
+	// interval
+	ReadOnly bool
+
+	// Clock stamps collections and reports; nil means the wall clock.
+	// Replayed runs use the time the transcript was recorded
+	Clock system.Clock
+
+	// lastResult is the most recent collection pass (see lastSystemInfo)
+	resultMu   sync.Mutex
+	lastResult *CollectionResult
 }
 
 // NewApplication creates and initializes a new application instance
@@ -40,17 +101,53 @@ func NewApplication(config *model.Config, options map[string]interface{}) (*Appl
 	// Initialize logger
 	logger := system.NewLogger(config.LogFile, logLevel, config.Verbose)
 
+	// The health verdict must be the only thing on stdout; the log file still works
+	if config.OutputFormat == model.OutputFormatHealth {
+		logger.SetOutput(io.Discard)
+	}
+
 	if config.Debug {
 		logger.Debug("Debug mode enabled")
 	}
 	logger.Info("Initializing application")
 
-	// Initialize command runner
-	cmdRunner := &system.DefaultCommandRunner{}
+	// Initialize command runner, replaying a recorded transcript if requested
+	var cmdRunner system.CommandRunner = &system.DefaultCommandRunner{}
+	var replay *system.ReplayCommandRunner
+	if replayFile := getStringOption(options, "replay", ""); replayFile != "" {
+		var err error
+		replay, err = system.LoadReplayCommandRunner(replayFile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Replaying commands from %s", replayFile)
+		cmdRunner = replay
+	}
+
+	// Record every command for bug reports
+	recordFile := getStringOption(options, "record", "")
+	var recorder *system.RecordingCommandRunner
+	if recordFile != "" {
+		recorder = system.NewRecordingCommandRunner(cmdRunner)
+		cmdRunner = recorder
+	}
+
+	// Send the summary and problems to the local syslog as well
+	var syslogWriter system.SyslogWriter
+	if getBoolOption(options, "syslog", false) {
+		var err error
+		syslogWriter, err = system.NewSyslogWriter(getStringOption(options, "syslog_tag", system.DefaultSyslogTag))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+	}
 
 	// Initialize history storage
 	historyStorage := storage.NewDiskHistoryStorage(config.DataFile, logger)
 
+	historyStorage.SetSizeUnits(config.SizeUnits)
+	historyStorage.SetSizePrecision(config.SizePrecision)
+
 	// Set storage path to ensure directory exists
 	if err := historyStorage.SetStoragePath(config.DataFile); err != nil {
 		return nil, fmt.Errorf("failed to set storage path: %w", err)
@@ -64,21 +161,81 @@ func NewApplication(config *model.Config, options map[string]interface{}) (*Appl
 		HistoryStorage: historyStorage,
 		// Set additional options from options map
 		ExitOnWarning: getBoolOption(options, "exit_on_warning", false),
+		ExpectDisks:   getIntOption(options, "expect_disks", 0),
 		OnlyWarnings:  getBoolOption(options, "only_warnings", false),
 		Quiet:         getBoolOption(options, "quiet", false),
 		CompactMode:   getBoolOption(options, "compact", false),
+		SummaryFooter: getBoolOption(options, "summary_footer", false),
+		TopN:          getIntOption(options, "top", 0),
+		EventCount:    getIntOption(options, "events", 0),
+
+		FormatterOptions: getMapOption(options, "formatter_options"),
+
+		ParseStdin: getBoolOption(options, "parse_stdin", false),
+		DiskName:   getStringOption(options, "disk_name", ""),
+		DiskType:   getStringOption(options, "disk_type", "HDD"),
+		DiskModel:  getStringOption(options, "disk_model", ""),
+
+		ResetBaseline: getBoolOption(options, "reset_baseline", false),
+		AssumeYes:     getBoolOption(options, "yes", false),
+
+		ValidateHistory: getStringOption(options, "validate_history", ""),
+
+		DashboardReports: splitList(getStringOption(options, "dashboard", "")),
+
+		TUI:         getBoolOption(options, "tui", false),
+		TUIInterval: time.Duration(getIntOption(options, "tui_interval", int(DefaultTUIInterval.Seconds()))) * time.Second,
+
+		Recorder:   recorder,
+		RecordFile: recordFile,
+
+		Syslog: syslogWriter,
 	}
 
 	// Initialize collectors
 	// Note: We initialize them here after creating the app because they depend on the config
 	// which may have additional options
 	app.DiskCollector = collector.NewDiskCollector(config, logger, cmdRunner)
+	app.DiskCollector.SetHistoryStorage(historyStorage)
 	app.CtrlCollector = collector.NewControllerCollector(cmdRunner, logger)
+	app.CtrlCollector.SetSkipTemperature(config.NoControllerTemp)
+	app.CtrlCollector.SetControllerFilter(config.ControllerID)
+	app.SysCollector = collector.NewSystemInfoCollector(logger, cmdRunner)
+
+	if replay != nil {
+		app.useReplay(replay)
+	}
 
 	logger.Info("Application initialization complete")
 	return app, nil
 }
 
+// useReplay makes a replayed run reproduce the recorded report: local
+// history is ignored and timestamps come from the transcript
+func (app *Application) useReplay(replay *system.ReplayCommandRunner) {
+	app.Replaying = true
+	app.Clock = system.FixedClock{Time: replay.RecordedAt()}
+	app.HistoryStorage.SetClock(app.Clock)
+	app.DiskCollector.SetSkipHistory(true)
+}
+
+// now returns the current time according to app.Clock
+func (app *Application) now() time.Time {
+	if app.Clock != nil {
+		return app.Clock.Now()
+	}
+	return time.Now()
+}
+
+// saveTranscript writes the recorded commands to RecordFile
+func (app *Application) saveTranscript() {
+	if err := app.Recorder.Save(app.RecordFile); err != nil {
+		app.Logger.Error("Failed to save command transcript: %v", err)
+		return
+	}
+	app.Logger.Info("Saved %d commands to %s", len(app.Recorder.Entries()), app.RecordFile)
+}
+
 // getBoolOption safely extracts a boolean option from the options map
 func getBoolOption(options map[string]interface{}, key string, defaultValue bool) bool {
 	if options == nil {
@@ -93,42 +250,124 @@ func getBoolOption(options map[string]interface{}, key string, defaultValue bool
 	return defaultValue
 }
 
+// getStringOption safely extracts a string option from the options map
+func getStringOption(options map[string]interface{}, key string, defaultValue string) string {
+	if options == nil {
+		return defaultValue
+	}
+
+	if val, ok := options[key]; ok {
+		if strVal, ok := val.(string); ok {
+			return strVal
+		}
+	}
+	return defaultValue
+}
+
+// getIntOption safely extracts an integer option from the options map
+func getIntOption(options map[string]interface{}, key string, defaultValue int) int {
+	if options == nil {
+		return defaultValue
+	}
+
+	if val, ok := options[key]; ok {
+		if intVal, ok := val.(int); ok {
+			return intVal
+		}
+	}
+	return defaultValue
+}
+
+// getMapOption safely extracts a nested options map from the options map
+func getMapOption(options map[string]interface{}, key string) map[string]interface{} {
+	if options == nil {
+		return nil
+	}
+
+	if val, ok := options[key]; ok {
+		if mapVal, ok := val.(map[string]interface{}); ok {
+			return mapVal
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma-separated option value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // Run executes the main application workflow
 func (app *Application) Run() int {
 	app.Logger.Info("Starting disk health monitor")
 
+	// Write the command transcript however the run ends
+	if app.Recorder != nil {
+		defer app.saveTranscript()
+	}
+
+	if app.Syslog != nil {
+		defer app.Syslog.Close()
+	}
+
+	// Check a history file, bypassing collection entirely
+	if app.ValidateHistory != "" {
+		return app.runValidateHistory(os.Stdout)
+	}
+
+	// Combine reports from several hosts, bypassing collection entirely
+	if len(app.DashboardReports) > 0 {
+		return app.runDashboard(os.Stdout)
+	}
+
+	// Clear the history baseline, bypassing collection entirely
+	if app.ResetBaseline {
+		return app.runResetBaseline(os.Stdin, os.Stdout)
+	}
+
+	// Parse a single smartctl dump from stdin, bypassing collection entirely
+	if app.ParseStdin {
+		return app.runParseStdin(os.Stdin)
+	}
+
 	// Check required tools
 	if err := checkRequiredTools(app.Logger, app.CommandRunner); err != nil {
 		app.Logger.Error("Required tools check failed: %v", err)
+		if app.Config.OutputFormat == model.OutputFormatHealth {
+			fmt.Fprintln(os.Stdout, model.HealthUnknown)
+			return model.HealthUnknown.ExitCode()
+		}
 		createDummyOutput(app.Config, fmt.Sprintf("Required tools not found: %v", err))
 		return 2 // Initialization error
 	}
 
+	// Show the interactive view until the user quits
+	if app.TUI {
+		return app.runTUI(os.Stdin, os.Stdout, app.TUIInterval)
+	}
+
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.CommandTimeout)
 	defer cancel()
 
-	// Collect controller data (if needed)
-	var ctrlData *model.ControllerData
-	var ctrlErr error
+	// Collect once; the result can be reused for every output
+	result := app.Collect(ctx)
+	diskData, ctrlData := result.DiskData, result.ControllerData
 
-	if !app.Config.NoController {
-		app.Logger.Info("Collecting controller information")
-		ctrlData, ctrlErr = app.CtrlCollector.Collect(ctx)
-		if ctrlErr != nil {
-			app.Logger.Error("Failed to collect controller information: %v", ctrlErr)
-			// Continue with other operations even if controller collection fails
-		} else {
-			app.Logger.Info("Found %d controllers (%d LSI, %d NVMe)",
-				ctrlData.GetTotalControllerCount(),
-				ctrlData.GetLSIControllerCount(),
-				ctrlData.GetNVMeControllerCount())
-		}
+	// Print only the overall verdict
+	if app.Config.OutputFormat == model.OutputFormatHealth {
+		return app.runHealth(result, os.Stdout)
 	}
 
-	// Skip disk collection if only controller info is requested
+	// Only controller info was requested
 	if app.Config.ControllerOnly {
-		if ctrlErr != nil {
+		if result.ControllerErr != nil {
 			app.Logger.Error("Controller data collection failed and --controller-only was specified")
 			return 3 // Data collection error
 		}
@@ -138,47 +377,25 @@ func (app *Application) Run() int {
 			app.Logger.Error("Failed to generate output: %v", err)
 			return 4 // Output generation error
 		}
+		app.sendSyslog(nil, ctrlData)
 
 		return 0 // Success
 	}
 
-	// Collect disk data
-	app.Logger.Info("Collecting disk information")
-	diskData, diskErr := app.DiskCollector.Collect(ctx)
-	if diskErr != nil {
-		app.Logger.Error("Failed to collect disk information: %v", diskErr)
-
-		// If both controller and disk collection failed, return error
-		if ctrlErr != nil {
-			app.Logger.Error("All data collection operations failed")
-			createDummyOutput(app.Config, "Failed to collect any disk or controller data")
-			return 3 // Data collection error
-		}
-	} else {
-		// Log summary of disk data
-		app.Logger.Info("Found %d disks (SSD: %d, HDD: %d, warnings: %d, errors: %d)",
-			diskData.GetDiskCount(),
-			diskData.GetSSDCount(),
-			diskData.GetHDDCount(),
-			diskData.GetWarningCount(),
-			diskData.GetErrorCount())
+	// If both controller and disk collection failed, return error
+	if result.DiskErr != nil && result.ControllerErr != nil {
+		app.Logger.Error("All data collection operations failed")
+		createDummyOutput(app.Config, "Failed to collect any disk or controller data")
+		return 3 // Data collection error
 	}
 
-	// Filter only warning/error disks if requested
+	// Filter only warning/error disks if requested; pools, events and
+	// history are kept so pool fill warnings still show and still alert
 	if app.OnlyWarnings && diskData != nil {
-		// Create a new filtered disk data object
-		filteredData := model.NewDiskData()
-
-		// Copy only disks with warnings or errors
-		for _, disk := range diskData.Disks {
+		diskData = diskData.FilterDisks(func(disk *model.Disk) bool {
 			status := disk.GetStatus()
-			if status == model.DiskStatusWarning || status == model.DiskStatusError {
-				filteredData.AddDisk(disk)
-			}
-		}
-
-		// Use filtered data for output
-		diskData = filteredData
+			return status == model.DiskStatusWarning || status == model.DiskStatusError
+		})
 		app.Logger.Info("Filtered to %d disks with warnings or errors", diskData.GetDiskCount())
 	}
 
@@ -189,9 +406,17 @@ func (app *Application) Run() int {
 		return 4 // Output generation error
 	}
 
+	// Report every disk, not just the --only-warnings subset
+	app.sendSyslog(result.DiskData, ctrlData)
+
+	// A missing disk outranks warnings on the disks that were found
+	if app.missingDisks(result.DiskData) {
+		return 6 // Fewer disks than expected
+	}
+
 	// Check for warnings if --exit-on-warning is enabled
 	if app.ExitOnWarning && diskData != nil {
-		if diskData.GetWarningCount() > 0 || diskData.GetErrorCount() > 0 {
+		if diskData.GetAlertCount() > 0 {
 			app.Logger.Info("Exiting with status 5 due to warnings or errors detected")
 			return 5 // Warning found
 		}
@@ -201,11 +426,321 @@ func (app *Application) Run() int {
 	return 0 // Success
 }
 
+// runHealth prints a single OK/WARNING/CRITICAL verdict for the worst disk,
+// pool or controller status and returns the matching Nagios-style exit code.
+// UNKNOWN is printed when no data could be collected
+func (app *Application) runHealth(result *CollectionResult, out io.Writer) int {
+	verdict := model.HealthUnknown
+	if app.Config.ControllerOnly {
+		if result.ControllerErr == nil {
+			verdict = model.VerdictForStatus(model.OverallStatus(nil, result.ControllerData))
+		}
+	} else if result.DiskErr == nil || result.ControllerErr == nil {
+		// A missing disk outranks whatever the remaining disks report
+		if app.missingDisks(result.DiskData) {
+			verdict = model.HealthCritical
+		} else {
+			verdict = model.VerdictForStatus(model.OverallStatus(result.DiskData, result.ControllerData))
+		}
+	}
+
+	app.Logger.Info("Overall health: %s", verdict)
+	fmt.Fprintln(out, verdict)
+	return verdict.ExitCode()
+}
+
+// missingDisks reports whether fewer disks were collected than --expect-disks
+// requires, such as after a backplane drops out or a card is unseated
+func (app *Application) missingDisks(diskData *model.DiskData) bool {
+	if app.ExpectDisks <= 0 {
+		return false
+	}
+
+	found := 0
+	if diskData != nil {
+		found = diskData.GetDiskCount()
+	}
+	if found >= app.ExpectDisks {
+		return false
+	}
+
+	app.Logger.Error("Expected %d disks but found %d", app.ExpectDisks, found)
+	return true
+}
+
+// applyControllerHistory compares controller temperatures against the last
+// run, flags overheating controllers, and records the current temperatures
+func (app *Application) applyControllerHistory(ctrlData *model.ControllerData) {
+	previous := make(map[string]string)
+	if !app.Replaying {
+		loaded, err := app.HistoryStorage.LoadControllerTemperatures()
+		if err != nil {
+			app.Logger.Error("Warning: failed to load controller temperature history: %v", err)
+		} else {
+			previous = loaded
+		}
+	}
+
+	ctrlData.ApplyTemperatureHistory(previous, app.Config.ControllerCritTemp)
+	for _, controller := range ctrlData.GetFlaggedControllers() {
+		app.Logger.Info("Controller %s flagged: %s", controller.ID, controller.StatusReason)
+	}
+
+	if app.Replaying || app.ReadOnly {
+		return
+	}
+	if err := app.HistoryStorage.SaveControllerTemperatures(ctrlData.GetTemperatures()); err != nil {
+		app.Logger.Error("Warning: failed to save controller temperature history: %v", err)
+	}
+}
+
+// recordDiskEvents appends the events detected since the last run to the
+// event log, and loads the most recent ones into the report for --events
+func (app *Application) recordDiskEvents(diskData *model.DiskData) {
+	// A replayed transcript has nothing to do with this machine's timeline,
+	// and read-only collections would record the same events on every refresh
+	if app.Replaying || app.ReadOnly {
+		return
+	}
+
+	for _, event := range diskData.DetectEvents(app.now()) {
+		// Disks left out by --include were not removed
+		if event.Type == model.EventDiskRemoved && len(app.Config.IncludeDisks) > 0 {
+			continue
+		}
+		app.Logger.Info("Disk event: %s %s", event.Disk, event.Description())
+		if err := app.HistoryStorage.AppendEvent(event); err != nil {
+			app.Logger.Error("Warning: failed to record disk event: %v", err)
+			break
+		}
+	}
+
+	if app.EventCount > 0 {
+		events, err := app.HistoryStorage.LoadEvents(app.EventCount)
+		if err != nil {
+			app.Logger.Error("Warning: failed to load disk events: %v", err)
+			return
+		}
+		diskData.Events = events
+	}
+}
+
+// sendSyslog writes a one-line summary at LOG_INFO and every disk or
+// controller that is not healthy at LOG_WARNING or LOG_ERR (--syslog)
+func (app *Application) sendSyslog(diskData *model.DiskData, ctrlData *model.ControllerData) {
+	if app.Syslog == nil {
+		return
+	}
+
+	var parts []string
+	if diskData != nil {
+		parts = append(parts, fmt.Sprintf("%d disks, %d warnings, %d errors",
+			diskData.GetDiskCount(), diskData.GetWarningCount(), diskData.GetErrorCount()))
+	}
+	if ctrlData != nil {
+		parts = append(parts, fmt.Sprintf("%d controllers", ctrlData.GetTotalControllerCount()))
+	}
+	if err := app.Syslog.Info("disk health: " + strings.Join(parts, ", ")); err != nil {
+		app.Logger.Error("Failed to write to syslog: %v", err)
+		return
+	}
+
+	var err error
+	if diskData != nil {
+		for _, disk := range diskData.Disks {
+			switch disk.GetStatus() {
+			case model.DiskStatusWarning:
+				err = app.Syslog.Warning(syslogDiskMessage(disk))
+			case model.DiskStatusError:
+				err = app.Syslog.Err(syslogDiskMessage(disk))
+			}
+			if err != nil {
+				app.Logger.Error("Failed to write to syslog: %v", err)
+				return
+			}
+		}
+	}
+	if ctrlData != nil {
+		var controllers []*model.Controller
+		for _, controller := range ctrlData.GetSortedLSIControllers() {
+			controllers = append(controllers, &controller.Controller)
+		}
+		for _, controller := range ctrlData.GetSortedNVMeControllers() {
+			controllers = append(controllers, &controller.Controller)
+		}
+		for _, controller := range controllers {
+			switch controller.Status {
+			case model.ControllerStatusWarning:
+				err = app.Syslog.Warning(syslogControllerMessage(controller))
+			case model.ControllerStatusError:
+				err = app.Syslog.Err(syslogControllerMessage(controller))
+			}
+			if err != nil {
+				app.Logger.Error("Failed to write to syslog: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// syslogDiskMessage describes a problem disk, e.g.
+// "disk sda (ST4000NM0023, serial Z1Z0ABCD): WARNING: 启发式: 未修正错误 3 个"
+func syslogDiskMessage(disk *model.Disk) string {
+	message := fmt.Sprintf("disk %s (%s", disk.Name, disk.Model)
+	if disk.Serial != "" {
+		message += ", serial " + disk.Serial
+	}
+	message += fmt.Sprintf("): %s", disk.GetStatus())
+	if disk.StatusReason != "" {
+		message += ": " + disk.StatusReason
+	}
+	return message
+}
+
+// syslogControllerMessage describes a problem controller
+func syslogControllerMessage(controller *model.Controller) string {
+	message := fmt.Sprintf("controller %s (%s): %s", controller.ID, controller.Model, controller.Status)
+	if controller.StatusReason != "" {
+		message += ": " + controller.StatusReason
+	}
+	return message
+}
+
+// applyAcknowledgements annotates warning disks covered by an active
+// acknowledgement so they no longer trigger --exit-on-warning
+func (app *Application) applyAcknowledgements(diskData *model.DiskData) {
+	if len(app.Config.Acknowledgements) == 0 {
+		return
+	}
+
+	count := diskData.ApplyAcknowledgements(app.Config.Acknowledgements, app.now())
+	app.Logger.Info("Acknowledged %d disks with known issues", count)
+}
+
+// runValidateHistory prints a validation report for the history file and
+// returns 1 if it contains errors
+func (app *Application) runValidateHistory(out io.Writer) int {
+	historyStorage := storage.NewDiskHistoryStorage(app.ValidateHistory, app.Logger)
+	historyStorage.SetSizeUnits(app.Config.SizeUnits)
+
+	report := historyStorage.Validate()
+	fmt.Fprint(out, report.String())
+	if !report.Valid() {
+		return 1
+	}
+	return 0
+}
+
+// runDashboard combines the JSON reports of several hosts into one HTML
+// page, written to the output file or to out
+func (app *Application) runDashboard(out io.Writer) int {
+	reports := make([]*output.HostReport, 0, len(app.DashboardReports))
+	for _, filename := range app.DashboardReports {
+		report, err := output.LoadHostReport(filename)
+		if err != nil {
+			app.Logger.Error("Failed to load report %s: %v", filename, err)
+			return 4 // Output generation error
+		}
+		reports = append(reports, report)
+	}
+
+	timestamp := app.now().Format("2006-01-02 15:04:05")
+	if app.Config.OutputFile == "" {
+		if err := output.WriteDashboard(out, reports, timestamp); err != nil {
+			app.Logger.Error("Failed to generate dashboard: %v", err)
+			return 4 // Output generation error
+		}
+		return 0
+	}
+
+	file, err := os.Create(app.Config.OutputFile)
+	if err != nil {
+		app.Logger.Error("Failed to create %s: %v", app.Config.OutputFile, err)
+		return 4 // Output generation error
+	}
+	if err := output.WriteDashboard(file, reports, timestamp); err != nil {
+		file.Close()
+		app.Logger.Error("Failed to generate dashboard: %v", err)
+		return 4 // Output generation error
+	}
+	if err := file.Close(); err != nil {
+		app.Logger.Error("Failed to save %s: %v", app.Config.OutputFile, err)
+		return 4 // Output generation error
+	}
+
+	if !app.Quiet {
+		fmt.Fprintf(out, "Output saved to %s\n", app.Config.OutputFile)
+	}
+	return 0
+}
+
+// runResetBaseline backs up and clears the history file after confirmation
+func (app *Application) runResetBaseline(in io.Reader, out io.Writer) int {
+	if !app.AssumeYes {
+		fmt.Fprintf(out, "将备份并清空历史数据文件 %s，下次运行将重新建立基线。确认继续? [y/N]: ", app.Config.DataFile)
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "已取消，历史数据未修改")
+			return 1
+		}
+	}
+
+	backupPath, err := app.HistoryStorage.ResetBaseline()
+	if err != nil {
+		app.Logger.Error("Failed to reset history baseline: %v", err)
+		return 3 // Data collection error
+	}
+
+	if backupPath != "" {
+		fmt.Fprintf(out, "历史数据已备份到: %s\n", backupPath)
+	}
+	fmt.Fprintf(out, "历史基线已重置: %s\n", app.Config.DataFile)
+	return 0
+}
+
+// runParseStdin reads a smartctl -a dump and formats the parsed single-disk result
+func (app *Application) runParseStdin(r io.Reader) int {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		app.Logger.Error("Failed to read smartctl output from stdin: %v", err)
+		return 3 // Data collection error
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		app.Logger.Error("No smartctl output received on stdin")
+		return 3 // Data collection error
+	}
+
+	smartCollector := collector.NewSMARTCollector(app.Config, app.Logger, app.CommandRunner)
+	disk := smartCollector.ParseSMARTOutput(app.DiskName, app.DiskType, app.DiskModel, string(data))
+
+	diskData := model.NewDiskData()
+	diskData.AddDisk(disk)
+	app.applyAcknowledgements(diskData)
+
+	if err := app.generateOutput(diskData, nil); err != nil {
+		app.Logger.Error("Failed to generate output: %v", err)
+		return 4 // Output generation error
+	}
+
+	if app.ExitOnWarning && diskData.GetAlertCount() > 0 {
+		return 5 // Warning found
+	}
+	return 0
+}
+
 // generateOutput creates formatted output based on collected data
 func (app *Application) generateOutput(diskData *model.DiskData, ctrlData *model.ControllerData) error {
 	// Determine output format
 	format := string(app.Config.OutputFormat)
 
+	// Mask identifiers before anything is rendered, so every format is covered
+	if app.Config.Redact {
+		redactor := model.NewRedactor(app.Config.RedactPools)
+		redactor.RedactDiskData(diskData)
+		redactor.RedactControllerData(ctrlData)
+	}
+
 	// Get formatter options
 	options := formatFormatterOptions(app)
 
@@ -214,6 +749,17 @@ func (app *Application) generateOutput(diskData *model.DiskData, ctrlData *model
 	if err != nil {
 		return fmt.Errorf("failed to create output formatter: %w", err)
 	}
+	if clocked, ok := formatter.(interface{ SetClock(system.Clock) }); ok && app.Clock != nil {
+		clocked.SetClock(app.Clock)
+	}
+
+	// Warn about --set options the formatter does not know
+	supported := formatter.GetSupportedOptions()
+	for name := range app.FormatterOptions {
+		if _, ok := supported[name]; !ok {
+			app.Logger.Error("Warning: unknown formatter option %q for format %s", name, format)
+		}
+	}
 
 	// Set data in formatter
 	if app.Config.ControllerOnly {
@@ -239,31 +785,10 @@ func (app *Application) generateOutput(diskData *model.DiskData, ctrlData *model
 
 	}
 
-	// Verify buffer has content
-	if textFormatter, ok := formatter.(fmt.Stringer); ok {
-		if len(textFormatter.String()) == 0 {
-			app.Logger.Error("Formatter produced empty output")
-			return fmt.Errorf("empty output generated")
-		}
-	}
-
-	if app.Config.Debug {
-		if ctrlData != nil {
-			app.Logger.Debug("Controller Data: LSI=%d, NVMe=%d",
-				len(ctrlData.LSIControllers),
-				len(ctrlData.NVMeControllers))
-		}
-		if textFormatter, ok := formatter.(fmt.Stringer); ok {
-			output := textFormatter.String()
-			app.Logger.Debug("Formatted Output Length: %d bytes", len(output))
-			if len(output) < 100 {
-				app.Logger.Debug("Formatted Output: %s", output)
-			} else if len(output) == 0 {
-				app.Logger.Debug("WARNING: Output is empty!")
-			}
-		} else {
-			app.Logger.Debug("Formatter doesn't implement Stringer interface")
-		}
+	if app.Config.Debug && ctrlData != nil {
+		app.Logger.Debug("Controller Data: LSI=%d, NVMe=%d",
+			len(ctrlData.LSIControllers),
+			len(ctrlData.NVMeControllers))
 	}
 
 	// Save to file if output file is specified
@@ -276,18 +801,32 @@ func (app *Application) generateOutput(diskData *model.DiskData, ctrlData *model
 			fmt.Printf("Output saved to %s\n", app.Config.OutputFile)
 		}
 		app.Logger.Info("Output saved to %s", app.Config.OutputFile)
-	} else {
+	} else if !app.Quiet {
 		// Print to console if no output file is specified
-		if !app.Quiet {
-			if textFormatter, ok := formatter.(fmt.Stringer); ok {
-				fmt.Println(textFormatter.String())
-			} else {
-				// Fallback to simple output
-				fmt.Println("Data collection complete.")
-				// Print summary information
-			}
+		counter := &countingWriter{w: os.Stdout}
+		if err := formatter.WriteToWriter(counter); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
 		}
+		if counter.n == 0 {
+			app.Logger.Error("Formatter produced empty output")
+			return fmt.Errorf("empty output generated")
+		}
+		fmt.Println()
+		app.Logger.Debug("Formatted Output Length: %d bytes", counter.n)
 	}
 
 	return nil
 }
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write forwards p to the underlying writer and records its length
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -221,3 +227,638 @@ func TestMain(m *testing.M) {
 	// 退出测试
 	os.Exit(exitCode)
 }
+
+// TestApplicationParseStdin 测试从标准输入解析单个磁盘的 smartctl 输出
+func TestApplicationParseStdin(t *testing.T) {
+	dump, err := os.ReadFile("testdata/smartctl_sas_hdd.txt")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	config := model.NewDefaultConfig()
+	config.ControllerOnly = false
+	config.NoController = true
+	config.OutputFormat = model.OutputFormatJSON
+	config.OutputFile = filepath.Join(t.TempDir(), "report.json")
+
+	cmdRunner := system.NewMockCommandRunner()
+	app := &Application{
+		Config:        config,
+		Logger:        system.NewMockLogger(),
+		CommandRunner: cmdRunner,
+		Quiet:         true,
+		ParseStdin:    true,
+		DiskName:      "sdd",
+		DiskType:      "HDD",
+		DiskModel:     "SEAGATE ST600MM0006",
+	}
+
+	if exitCode := app.runParseStdin(bytes.NewReader(dump)); exitCode != 0 {
+		t.Fatalf("runParseStdin() = %d, want 0", exitCode)
+	}
+
+	// 不应执行任何命令
+	if len(cmdRunner.CalledCommands) != 0 {
+		t.Errorf("Expected no commands to run, got %v", cmdRunner.CalledCommands)
+	}
+
+	data, err := os.ReadFile(config.OutputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	var report struct {
+		Disks []struct {
+			Name      string            `json:"name"`
+			Status    string            `json:"status"`
+			Serial    string            `json:"serial"`
+			SMARTData map[string]string `json:"smart_data"`
+		} `json:"disks"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if len(report.Disks) != 1 {
+		t.Fatalf("Expected 1 disk, got %d", len(report.Disks))
+	}
+	disk := report.Disks[0]
+	if disk.Name != "sdd" {
+		t.Errorf("Expected disk name sdd, got %s", disk.Name)
+	}
+	if disk.SMARTData["Temperature"] != "36" {
+		t.Errorf("Expected temperature 36, got %s", disk.SMARTData["Temperature"])
+	}
+	if disk.SMARTData["Smart_Status"] != "OK" {
+		t.Errorf("Expected Smart_Status OK, got %s", disk.SMARTData["Smart_Status"])
+	}
+	if disk.Status != string(model.DiskStatusOK) {
+		t.Errorf("Expected status %s, got %s", model.DiskStatusOK, disk.Status)
+	}
+	if disk.Serial != "S0M1ABCD0000K1234XYZ" {
+		t.Errorf("Expected serial S0M1ABCD0000K1234XYZ, got %s", disk.Serial)
+	}
+}
+
+// TestApplicationResetBaseline 测试 --reset-baseline 的确认流程
+func TestApplicationResetBaseline(t *testing.T) {
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(config.DataFile, []byte(`{"version":"1.0","timestamp":"2024-01-01T00:00:00Z","disks":{"sda":{"Data_Read":"1 TB"}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+
+	app := &Application{
+		Config:         config,
+		Logger:         system.NewMockLogger(),
+		HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, system.NewMockLogger()),
+		ResetBaseline:  true,
+	}
+
+	// 拒绝确认时不应修改历史数据
+	var out bytes.Buffer
+	if exitCode := app.runResetBaseline(strings.NewReader("n\n"), &out); exitCode != 1 {
+		t.Errorf("runResetBaseline() declined = %d, want 1", exitCode)
+	}
+	data, _, err := app.HistoryStorage.LoadDiskData()
+	if err != nil || len(data) != 1 {
+		t.Fatalf("Expected history to be kept after declining, got %v (err %v)", data, err)
+	}
+
+	// 确认后应清空历史数据
+	out.Reset()
+	if exitCode := app.runResetBaseline(strings.NewReader("y\n"), &out); exitCode != 0 {
+		t.Fatalf("runResetBaseline() confirmed = %d, want 0", exitCode)
+	}
+	data, _, err = app.HistoryStorage.LoadDiskData()
+	if err != nil || len(data) != 0 {
+		t.Errorf("Expected empty history after reset, got %v (err %v)", data, err)
+	}
+	if !strings.Contains(out.String(), ".bak") {
+		t.Errorf("Expected backup path in output, got %q", out.String())
+	}
+}
+
+// TestApplicationDashboard 测试 --dashboard 将两个主机的JSON报告合并为一个HTML页面
+func TestApplicationDashboard(t *testing.T) {
+	dir := t.TempDir()
+	reports := map[string]string{
+		"nas-a.json": `{"system":{"hostname":"nas-a"},"disks":[{"name":"sda","type":"SAS_HDD","model":"ST4000","size":"4 TB","pool":"tank","status":"PASSED","smart_data":{}}]}`,
+		"nas-b.json": `{"disks":[{"name":"nvme0n1","type":"NVME_SSD","model":"PM983","size":"1 TB","pool":"fast","status":"FAILED","smart_data":{}}]}`,
+	}
+	var files []string
+	for name, content := range reports {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write report: %v", err)
+		}
+		files = append(files, path)
+	}
+
+	config := model.NewDefaultConfig()
+	config.OutputFile = filepath.Join(dir, "fleet.html")
+	app := &Application{
+		Config:           config,
+		Logger:           system.NewMockLogger(),
+		Quiet:            true,
+		DashboardReports: files,
+	}
+
+	if exitCode := app.runDashboard(&bytes.Buffer{}); exitCode != 0 {
+		t.Fatalf("runDashboard() = %d, want 0", exitCode)
+	}
+	data, err := os.ReadFile(config.OutputFile)
+	if err != nil {
+		t.Fatalf("Failed to read dashboard: %v", err)
+	}
+	// 有主机名的报告使用主机名，没有的使用文件名
+	for _, expected := range []string{">nas-a</li>", ">nas-b</li>", "<td>sda</td>", "<td>nvme0n1</td>"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected dashboard to contain %q", expected)
+		}
+	}
+
+	// 无法读取的报告应返回错误
+	app.DashboardReports = []string{filepath.Join(dir, "missing.json")}
+	if exitCode := app.runDashboard(&bytes.Buffer{}); exitCode != 4 {
+		t.Errorf("runDashboard() with missing report = %d, want 4", exitCode)
+	}
+}
+
+// TestApplicationAcknowledgements 测试已确认的警告磁盘不触发 --exit-on-warning 但仍带确认备注
+func TestApplicationAcknowledgements(t *testing.T) {
+	dump, err := os.ReadFile("testdata/smartctl_sas_hdd.txt")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	dir := t.TempDir()
+	ackFile := filepath.Join(dir, "acks.json")
+	if err := os.WriteFile(ackFile, []byte(`{"S0M1ABCD0000K1234XYZ": {"reason": "known hot slot", "until": "2999-12-31"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write acknowledgements: %v", err)
+	}
+	acks, err := model.LoadAcknowledgements(ackFile)
+	if err != nil {
+		t.Fatalf("LoadAcknowledgements() failed: %v", err)
+	}
+
+	newApp := func(acks map[string]model.Acknowledgement) *Application {
+		config := model.NewDefaultConfig()
+		config.ControllerOnly = false
+		config.NoController = true
+		config.OutputFormat = model.OutputFormatJSON
+		config.OutputFile = filepath.Join(dir, "report.json")
+		// 通过规则让磁盘进入警告状态
+		config.StatusRules = []model.StatusRule{
+			{Attribute: "Temperature", Comparator: model.ComparatorGreater, Threshold: "30", Status: model.DiskStatusWarning},
+		}
+		config.Acknowledgements = acks
+
+		return &Application{
+			Config:        config,
+			Logger:        system.NewMockLogger(),
+			CommandRunner: system.NewMockCommandRunner(),
+			ExitOnWarning: true,
+			Quiet:         true,
+			ParseStdin:    true,
+			DiskName:      "sdd",
+			DiskType:      "HDD",
+		}
+	}
+
+	// 未确认时警告触发退出码5
+	if exitCode := newApp(nil).runParseStdin(bytes.NewReader(dump)); exitCode != 5 {
+		t.Fatalf("runParseStdin() without ack = %d, want 5", exitCode)
+	}
+
+	// 确认后不再触发，但磁盘仍显示警告并带有确认备注
+	app := newApp(acks)
+	if exitCode := app.runParseStdin(bytes.NewReader(dump)); exitCode != 0 {
+		t.Fatalf("runParseStdin() with ack = %d, want 0", exitCode)
+	}
+
+	data, err := os.ReadFile(app.Config.OutputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	var report struct {
+		Disks []struct {
+			Status       string `json:"status"`
+			StatusReason string `json:"status_reason"`
+			Acknowledged string `json:"acknowledged"`
+		} `json:"disks"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(report.Disks) != 1 {
+		t.Fatalf("Expected 1 disk, got %d", len(report.Disks))
+	}
+	disk := report.Disks[0]
+	if disk.Status != string(model.DiskStatusWarning) {
+		t.Errorf("Expected status %s, got %s", model.DiskStatusWarning, disk.Status)
+	}
+	if disk.Acknowledged != "known hot slot" {
+		t.Errorf("Expected acknowledged reason, got %q", disk.Acknowledged)
+	}
+	if !strings.Contains(disk.StatusReason, "已确认(至 2999-12-31): known hot slot") {
+		t.Errorf("Expected acknowledgement in status reason, got %q", disk.StatusReason)
+	}
+}
+
+// TestApplicationReplayReport 测试回放记录文件得到与记录时完全相同的报告
+func TestApplicationReplayReport(t *testing.T) {
+	dump, err := os.ReadFile("testdata/smartctl_sas_hdd.txt")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'", "sda disk ST4000NM 4T\n")
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+	mock.SetMockOutput("smartctl -a /dev/sda", string(dump))
+	mock.SetMockOutput("smartctl -i /dev/sda", "Serial number:        S0M1ABCD\nLogical block size:   512 bytes\n")
+
+	// 两次运行共用历史文件，回放不应读取记录时保存的历史
+	dir := t.TempDir()
+	run := func(runner system.CommandRunner, report string, setup func(app *Application)) string {
+		t.Helper()
+		config := model.NewDefaultConfig()
+		config.ControllerOnly = false
+		config.OutputFormat = model.OutputFormatJSON
+		config.OutputFile = filepath.Join(dir, report)
+		config.DataFile = filepath.Join(dir, "disk_data.json")
+		logger := system.NewMockLogger()
+
+		app := &Application{
+			Config:         config,
+			Logger:         logger,
+			CommandRunner:  runner,
+			DiskCollector:  collector.NewDiskCollector(config, logger, runner),
+			CtrlCollector:  collector.NewControllerCollector(runner, logger),
+			HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, logger),
+			Quiet:          true,
+		}
+		setup(app)
+
+		result := app.Collect(context.Background())
+		if result.DiskErr != nil {
+			t.Fatalf("Collect failed: %v", result.DiskErr)
+		}
+		if err := app.generateOutput(result.DiskData, result.ControllerData); err != nil {
+			t.Fatalf("generateOutput failed: %v", err)
+		}
+		data, err := os.ReadFile(config.OutputFile)
+		if err != nil {
+			t.Fatalf("Failed to read report: %v", err)
+		}
+		return string(data)
+	}
+
+	recorder := system.NewRecordingCommandRunner(mock)
+	recorded := run(recorder, "recorded.json", func(app *Application) {
+		app.Clock = system.FixedClock{Time: recorder.RecordedAt()}
+	})
+
+	path := filepath.Join(dir, "transcript.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	replay, err := system.LoadReplayCommandRunner(path)
+	if err != nil {
+		t.Fatalf("LoadReplayCommandRunner failed: %v", err)
+	}
+	replayed := run(replay, "replayed.json", func(app *Application) {
+		app.useReplay(replay)
+	})
+
+	if !strings.Contains(recorded, `"sda"`) {
+		t.Fatalf("Expected sda in the recorded report, got %s", recorded)
+	}
+	if replayed != recorded {
+		t.Errorf("Replayed report differs from recording\nrecorded: %s\nreplayed: %s", recorded, replayed)
+	}
+}
+
+// TestApplicationExpectDisks 测试找到的磁盘少于 --expect-disks 时返回退出码6
+func TestApplicationExpectDisks(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk ST4000NM 4T\nsdb disk ST4000NM 4T\nsdc disk ST4000NM 4T\n")
+	for _, name := range []string{"sda", "sdb", "sdc"} {
+		mock.SetMockOutput("smartctl -H /dev/"+name, "SMART Health Status: OK")
+	}
+
+	dir := t.TempDir()
+	run := func(expect int) int {
+		t.Helper()
+		config := model.NewDefaultConfig()
+		config.ControllerOnly = false
+		config.NoController = true
+		config.OutputFormat = model.OutputFormatJSON
+		config.OutputFile = filepath.Join(dir, "report.json")
+		config.DataFile = filepath.Join(dir, "disk_data.json")
+		config.CommandTimeout = 10 * time.Second
+		logger := system.NewMockLogger()
+
+		app := &Application{
+			Config:         config,
+			Logger:         logger,
+			CommandRunner:  mock,
+			DiskCollector:  collector.NewDiskCollector(config, logger, mock),
+			CtrlCollector:  collector.NewControllerCollector(mock, logger),
+			HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, logger),
+			Quiet:          true,
+			ExpectDisks:    expect,
+		}
+		return app.Run()
+	}
+
+	if exitCode := run(5); exitCode != 6 {
+		t.Errorf("Run() expecting 5 disks with 3 present = %d, want 6", exitCode)
+	}
+	if exitCode := run(3); exitCode != 0 {
+		t.Errorf("Run() expecting 3 disks with 3 present = %d, want 0", exitCode)
+	}
+	if exitCode := run(0); exitCode != 0 {
+		t.Errorf("Run() without --expect-disks = %d, want 0", exitCode)
+	}
+}
+
+// TestApplicationSyslog 测试 --syslog 按磁盘/控制器状态以对应的优先级写入摘要和问题
+func TestApplicationSyslog(t *testing.T) {
+	newDisk := func(name, serial string, status model.DiskStatus, reason string) *model.Disk {
+		disk := model.NewDisk(name, "HDD", "ST4000NM0023", "4T")
+		disk.Serial = serial
+		disk.Escalate(status, reason)
+		return disk
+	}
+	diskData := model.NewDiskData()
+	diskData.AddDisk(newDisk("sda", "Z1", model.DiskStatusOK, "磁盘自检: PASSED"))
+	diskData.AddDisk(newDisk("sdb", "Z2", model.DiskStatusWarning, "启发式: 未修正错误 3 个"))
+	diskData.AddDisk(newDisk("sdc", "", model.DiskStatusError, "磁盘自检: FAILED"))
+
+	ctrlData := model.NewControllerData()
+	hot := ctrlData.GetLSIController("0")
+	hot.Model = "SAS3008"
+	hot.SetStatus(model.ControllerStatusWarning)
+	hot.StatusReason = "温度 75°C 超过阈值 70°C"
+	ctrlData.GetNVMeController("nvme0").SetStatus(model.ControllerStatusOK)
+
+	writer := system.NewMockSyslogWriter()
+	app := &Application{Logger: system.NewMockLogger(), Syslog: writer}
+	app.sendSyslog(diskData, ctrlData)
+
+	expected := []system.SyslogMessage{
+		{Priority: system.SyslogPriorityInfo, Message: "disk health: 3 disks, 1 warnings, 1 errors, 2 controllers"},
+		{Priority: system.SyslogPriorityWarning, Message: "disk sdb (ST4000NM0023, serial Z2): WARNING: 启发式: 未修正错误 3 个"},
+		{Priority: system.SyslogPriorityErr, Message: "disk sdc (ST4000NM0023): FAILED: 磁盘自检: FAILED"},
+		{Priority: system.SyslogPriorityWarning, Message: "controller 0 (SAS3008): 警告: 温度 75°C 超过阈值 70°C"},
+	}
+	if !reflect.DeepEqual(writer.Messages, expected) {
+		t.Errorf("Syslog messages = %v, want %v", writer.Messages, expected)
+	}
+
+	// 未启用 --syslog 时直接返回
+	(&Application{Logger: system.NewMockLogger()}).sendSyslog(diskData, ctrlData)
+}
+
+// TestApplicationOnlyWarningsFullPool 测试 --only-warnings 过滤磁盘后仍保留存储池容量告警
+func TestApplicationOnlyWarningsFullPool(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("midclt call disk.query", `[{"name": "sda", "model": "ST4000NM", "size": 4000787030016, "type": "HDD"}]`)
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+	mock.SetMockOutput("midclt call pool.query", `[
+		{"name": "tank", "topology": {"data": [{"type": "DISK", "disk": "sda"}]}}
+	]`)
+	mock.SetMockOutput("zpool list -Hp -o name,size,allocated", "tank\t4000\t3800\n")
+
+	dir := t.TempDir()
+	config := model.NewDefaultConfig()
+	config.ControllerOnly = false
+	config.NoController = true
+	config.OutputFormat = model.OutputFormatJSON
+	config.OutputFile = filepath.Join(dir, "report.json")
+	config.DataFile = filepath.Join(dir, "disk_data.json")
+	config.CommandTimeout = 10 * time.Second
+	logger := system.NewMockLogger()
+
+	app := &Application{
+		Config:         config,
+		Logger:         logger,
+		CommandRunner:  mock,
+		DiskCollector:  collector.NewDiskCollector(config, logger, mock),
+		CtrlCollector:  collector.NewControllerCollector(mock, logger),
+		HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, logger),
+		Quiet:          true,
+		OnlyWarnings:   true,
+		ExitOnWarning:  true,
+	}
+	if exitCode := app.Run(); exitCode != 5 {
+		t.Errorf("Run() with a 95%% full pool = %d, want 5", exitCode)
+	}
+
+	data, err := os.ReadFile(config.OutputFile)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	var report struct {
+		Summary map[string]string `json:"summary"`
+		Disks   []json.RawMessage `json:"disks"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+	// 健康的sda被过滤掉，但存储池容量告警仍在摘要中
+	if len(report.Disks) != 0 {
+		t.Errorf("Expected healthy disks to be filtered out, got %d", len(report.Disks))
+	}
+	if !strings.Contains(report.Summary["FullPools"], "tank") {
+		t.Errorf("Expected tank in the full pools summary, got %v", report.Summary)
+	}
+}
+
+// TestApplicationRunHealth 测试 --format health 只输出结论并返回对应的退出码
+func TestApplicationSetOptions(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'", "sda disk ST4000NM 4T\n")
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+
+	dir := t.TempDir()
+	config := model.NewDefaultConfig()
+	config.ControllerOnly = false
+	config.NoController = true
+	config.OutputFormat = model.OutputFormatHTML
+	config.OutputFile = filepath.Join(dir, "report.html")
+	config.DataFile = filepath.Join(dir, "disk_data.json")
+	logger := system.NewMockLogger()
+
+	// 数字形式的标题仍是字符串，"1"按布尔值处理，未知选项只给出警告
+	options, err := parseSetOptions([]string{"html_title=2024", "temperature_heatmap=1", "no_such_option=x"})
+	if err != nil {
+		t.Fatalf("parseSetOptions failed: %v", err)
+	}
+	app := &Application{
+		Config:           config,
+		Logger:           logger,
+		CommandRunner:    mock,
+		DiskCollector:    collector.NewDiskCollector(config, logger, mock),
+		HistoryStorage:   storage.NewDiskHistoryStorage(config.DataFile, logger),
+		FormatterOptions: options,
+		Quiet:            true,
+	}
+	result := app.Collect(context.Background())
+	if err := app.generateOutput(result.DiskData, result.ControllerData); err != nil {
+		t.Fatalf("generateOutput failed: %v", err)
+	}
+	data, err := os.ReadFile(config.OutputFile)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	html := string(data)
+	if !strings.Contains(html, "<title>2024</title>") {
+		t.Errorf("Expected html_title=2024 to set the page title")
+	}
+	if !strings.Contains(html, `<svg id="temperature-heatmap"`) {
+		t.Errorf("Expected temperature_heatmap=1 to enable the heatmap")
+	}
+
+	var warnings []string
+	for _, msg := range logger.ErrorLogs {
+		if strings.Contains(msg, "unknown formatter option") {
+			warnings = append(warnings, msg)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"no_such_option"`) || !strings.Contains(warnings[0], "html") {
+		t.Errorf("Expected one warning for no_such_option, got %v", logger.ErrorLogs)
+	}
+}
+
+func TestApplicationRunHealth(t *testing.T) {
+	newResult := func(status model.DiskStatus) *CollectionResult {
+		diskData := model.NewDiskData()
+		disk := model.NewDisk("sda", "HDD", "ST4000NM", "4T")
+		if status != model.DiskStatusOK {
+			disk.Escalate(status, "test")
+		}
+		diskData.AddDisk(disk)
+		return &CollectionResult{DiskData: diskData, ControllerData: model.NewControllerData()}
+	}
+
+	tests := []struct {
+		name     string
+		result   *CollectionResult
+		expect   int
+		output   string
+		exitCode int
+	}{
+		{name: "ok", result: newResult(model.DiskStatusOK), output: "OK", exitCode: 0},
+		{name: "warning", result: newResult(model.DiskStatusWarning), output: "WARNING", exitCode: 1},
+		{name: "critical", result: newResult(model.DiskStatusError), output: "CRITICAL", exitCode: 2},
+		{name: "missing disks", result: newResult(model.DiskStatusOK), expect: 2, output: "CRITICAL", exitCode: 2},
+		{
+			name:     "collection failed",
+			result:   &CollectionResult{DiskErr: errors.New("lsblk failed"), ControllerErr: errors.New("storcli failed")},
+			output:   "UNKNOWN",
+			exitCode: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := model.NewDefaultConfig()
+			config.ControllerOnly = false
+			config.OutputFormat = model.OutputFormatHealth
+			app := &Application{
+				Config:      config,
+				Logger:      system.NewMockLogger(),
+				ExpectDisks: tt.expect,
+			}
+
+			var out bytes.Buffer
+			if exitCode := app.runHealth(tt.result, &out); exitCode != tt.exitCode {
+				t.Errorf("runHealth() = %d, want %d", exitCode, tt.exitCode)
+			}
+			if out.String() != tt.output+"\n" {
+				t.Errorf("runHealth() output = %q, want %q", out.String(), tt.output+"\n")
+			}
+		})
+	}
+}
+
+// TestApplicationSystemInfo 测试 --system-info 在文本和JSON报告中输出主机信息
+func TestApplicationSystemInfo(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'", "sda disk ST4000NM 4T\n")
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+	mock.SetMockOutput("midclt call system.info",
+		`{"version": "TrueNAS-SCALE-23.10.1", "hostname": "nas01", "uptime_seconds": 93784.52, "uptime": "1 day, 2:03:04.520000"}`)
+
+	dir := t.TempDir()
+	run := func(format model.OutputFormat, systemInfo bool) string {
+		t.Helper()
+		config := model.NewDefaultConfig()
+		config.ControllerOnly = false
+		config.NoController = true
+		config.SystemInfo = systemInfo
+		config.OutputFormat = format
+		config.OutputFile = filepath.Join(dir, "report."+string(format))
+		config.DataFile = filepath.Join(dir, "disk_data.json")
+		logger := system.NewMockLogger()
+
+		app := &Application{
+			Config:         config,
+			Logger:         logger,
+			CommandRunner:  mock,
+			DiskCollector:  collector.NewDiskCollector(config, logger, mock),
+			SysCollector:   collector.NewSystemInfoCollector(logger, mock),
+			HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, logger),
+			Quiet:          true,
+		}
+		result := app.Collect(context.Background())
+		if err := app.generateOutput(result.DiskData, result.ControllerData); err != nil {
+			t.Fatalf("generateOutput failed: %v", err)
+		}
+		data, err := os.ReadFile(config.OutputFile)
+		if err != nil {
+			t.Fatalf("Failed to read report: %v", err)
+		}
+		return string(data)
+	}
+
+	text := run(model.OutputFormatText, true)
+	for _, want := range []string{"主机信息:", "主机名: nas01", "系统版本: TrueNAS-SCALE-23.10.1",
+		"运行时间: 1天2小时3分钟", "工具版本: " + Version} {
+		if !strings.Contains(text, want) {
+			t.Errorf("文本报告缺少 %q:\n%s", want, text)
+		}
+	}
+
+	html := run(model.OutputFormatHTML, true)
+	if !strings.Contains(html, "<span>主机信息</span>") || !strings.Contains(html, "<td>nas01</td>") {
+		t.Errorf("HTML报告缺少主机信息面板")
+	}
+
+	var report struct {
+		System map[string]interface{} `json:"system"`
+	}
+	if err := json.Unmarshal([]byte(run(model.OutputFormatJSON, true)), &report); err != nil {
+		t.Fatalf("JSON报告无效: %v", err)
+	}
+	expected := map[string]interface{}{
+		"hostname":       "nas01",
+		"os_version":     "TrueNAS-SCALE-23.10.1",
+		"uptime_seconds": float64(93784),
+		"tool_version":   Version,
+		"source":         model.DataSourceMidclt,
+	}
+	if !reflect.DeepEqual(report.System, expected) {
+		t.Errorf("system对象应为 %v，实际为 %v", expected, report.System)
+	}
+
+	// 未启用时不输出主机信息
+	if text := run(model.OutputFormatText, false); strings.Contains(text, "主机信息") {
+		t.Errorf("未启用 --system-info 时不应输出主机信息:\n%s", text)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// CollectionResult holds the data gathered by a single collection pass
+// (disks with their pool status, and controllers), so it can be rendered by
+// several formatters without re-running the collection commands
+type CollectionResult struct {
+	DiskData       *model.DiskData
+	ControllerData *model.ControllerData
+	DiskErr        error
+	ControllerErr  error
+	SystemInfo     *model.SystemInfo // host header, only collected with --system-info
+	CollectedAt    time.Time
+}
+
+// Age returns how long ago the result was collected
+func (r *CollectionResult) Age(now time.Time) time.Duration {
+	return now.Sub(r.CollectedAt)
+}
+
+// Collect runs one collection pass and remembers the result for
+// lastSystemInfo. Controller history and acknowledgements are applied here, so they are
+// evaluated exactly once per pass no matter how many outputs are rendered
+func (app *Application) Collect(ctx context.Context) *CollectionResult {
+	result := &CollectionResult{}
+
+	// Collect the host header (if needed)
+	if app.Config.SystemInfo && app.SysCollector != nil {
+		result.SystemInfo = app.SysCollector.Collect(ctx)
+		result.SystemInfo.ToolVersion = Version
+	}
+
+	// Collect controller data (if needed)
+	if !app.Config.NoController {
+		app.Logger.Info("Collecting controller information")
+		result.ControllerData, result.ControllerErr = app.CtrlCollector.Collect(ctx)
+		if result.ControllerErr != nil {
+			app.Logger.Error("Failed to collect controller information: %v", result.ControllerErr)
+			// Continue with other operations even if controller collection fails
+		} else {
+			app.Logger.Info("Found %d controllers (%d LSI, %d NVMe)",
+				result.ControllerData.GetTotalControllerCount(),
+				result.ControllerData.GetLSIControllerCount(),
+				result.ControllerData.GetNVMeControllerCount())
+			app.applyControllerHistory(result.ControllerData)
+		}
+	}
+
+	// Skip disk collection if only controller info is requested
+	if !app.Config.ControllerOnly {
+		app.Logger.Info("Collecting disk information")
+		result.DiskData, result.DiskErr = app.DiskCollector.Collect(ctx)
+		if result.DiskData != nil && app.Clock != nil {
+			result.DiskData.CollectedTime = app.Clock.Now()
+		}
+		if result.DiskErr != nil {
+			app.Logger.Error("Failed to collect disk information: %v", result.DiskErr)
+		} else {
+			// Log summary of disk data
+			app.Logger.Info("Found %d disks (SSD: %d, HDD: %d, warnings: %d, errors: %d)",
+				result.DiskData.GetDiskCount(),
+				result.DiskData.GetSSDCount(),
+				result.DiskData.GetHDDCount(),
+				result.DiskData.GetWarningCount(),
+				result.DiskData.GetErrorCount())
+			app.recordDiskEvents(result.DiskData)
+			app.applyAcknowledgements(result.DiskData)
+		}
+	}
+
+	result.CollectedAt = app.now()
+
+	app.resultMu.Lock()
+	app.lastResult = result
+	app.resultMu.Unlock()
+
+	return result
+}
+
+// lastSystemInfo returns the host header from the most recent collection
+// pass, or nil when it was not collected
+func (app *Application) lastSystemInfo() *model.SystemInfo {
+	app.resultMu.Lock()
+	defer app.resultMu.Unlock()
+	if app.lastResult == nil {
+		return nil
+	}
+	return app.lastResult.SystemInfo
+}
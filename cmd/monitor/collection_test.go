@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/collector"
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/output"
+	"github.com/MaurUppi/disk-health-monitor/internal/storage"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
+)
+
+// TestCollectionResultConsistentSummaries 测试同一份采集结果在不同格式下摘要一致
+func TestCollectionResultConsistentSummaries(t *testing.T) {
+	diskData := model.NewDiskData()
+	ssd := model.NewDisk("sda", "SSD", "Samsung SSD 870 EVO", "1 TB")
+	ssd.SMARTData["Smart_Status"] = "PASSED"
+	ssd.UpdateStatus()
+	diskData.AddDisk(ssd)
+	hdd := model.NewDisk("sdb", "HDD", "SEAGATE ST600MM0006", "600G")
+	hdd.Escalate(model.DiskStatusWarning, "test")
+	diskData.AddDisk(hdd)
+
+	ctrlData := model.NewControllerData()
+	ctrlData.GetLSIController("LSI_Controller_0").Temperature = "52"
+
+	first := &CollectionResult{
+		DiskData:       diskData,
+		ControllerData: ctrlData,
+		CollectedAt:    time.Now(),
+	}
+
+	render := func(format string, options map[string]interface{}) string {
+		formatter, err := output.NewFormatter(format, options)
+		if err != nil {
+			t.Fatalf("NewFormatter(%s) failed: %v", format, err)
+		}
+		if err := formatter.FormatDiskInfo(first.DiskData); err != nil {
+			t.Fatalf("FormatDiskInfo(%s) failed: %v", format, err)
+		}
+		if err := formatter.FormatControllerInfo(first.ControllerData); err != nil {
+			t.Fatalf("FormatControllerInfo(%s) failed: %v", format, err)
+		}
+		var buf bytes.Buffer
+		if err := formatter.WriteToWriter(&buf); err != nil {
+			t.Fatalf("WriteToWriter(%s) failed: %v", format, err)
+		}
+		return buf.String()
+	}
+
+	text := render("text", map[string]interface{}{output.OptionSummaryFooter: true})
+	var report struct {
+		Summary map[string]string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(render("json", nil)), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	s := report.Summary
+	expected := fmt.Sprintf("SUMMARY total=%s ssd=%s hdd=%s warn=%s err=%s controllers=1",
+		s["TotalDisks"], s["SSDCount"], s["HDDCount"], s["WarningCount"], s["ErrorCount"])
+	if expected != "SUMMARY total=2 ssd=1 hdd=1 warn=1 err=0 controllers=1" {
+		t.Errorf("Unexpected JSON summary: %v", s)
+	}
+	if !strings.Contains(text, expected) {
+		t.Errorf("Expected text summary %q to match JSON summary, got:\n%s", expected, text)
+	}
+}
+
+// TestCollectUsesClock 测试采集时间来自注入的时钟
+func TestCollectUsesClock(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'", "sda disk ST4000NM 4T\n")
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+
+	config := model.NewDefaultConfig()
+	config.ControllerOnly = false
+	config.NoController = true
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+	logger := system.NewMockLogger()
+	clock := system.FixedClock{Time: time.Date(2024, 9, 15, 10, 21, 3, 0, time.UTC)}
+
+	app := &Application{
+		Config:         config,
+		Logger:         logger,
+		CommandRunner:  mock,
+		DiskCollector:  collector.NewDiskCollector(config, logger, mock),
+		HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, logger),
+		Clock:          clock,
+	}
+
+	result := app.Collect(context.Background())
+	if !result.CollectedAt.Equal(clock.Time) {
+		t.Errorf("Expected CollectedAt %v, got %v", clock.Time, result.CollectedAt)
+	}
+	if age := result.Age(clock.Time.Add(90 * time.Second)); age != 90*time.Second {
+		t.Errorf("Expected age 1m30s, got %v", age)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// completionCommand is the command name the completion scripts register for
+const completionCommand = "disk-health-monitor"
+
+// completionShells are the shells --completion can generate scripts for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionFileFlags are flags whose value is a file path
+var completionFileFlags = map[string]bool{
+	"output":           true,
+	"o":                true,
+	"template-file":    true,
+	"data-file":        true,
+	"log-file":         true,
+	"include-file":     true,
+	"rules":            true,
+	"ack":              true,
+	"validate-history": true,
+	"dashboard":        true,
+	"record":           true,
+	"replay":           true,
+}
+
+// flagValueChoices lists the accepted values of flags that take one of a
+// fixed set, using the same names the flag parsing accepts
+func flagValueChoices() map[string][]string {
+	formats := []string{
+		string(model.OutputFormatText),
+		string(model.OutputFormatHTML),
+		string(model.OutputFormatJSON),
+		string(model.OutputFormatTemplate),
+		string(model.OutputFormatHealth),
+	}
+	return map[string][]string{
+		"format":        formats,
+		"f":             formats,
+		"size-units":    {string(model.SizeUnitsBinary), string(model.SizeUnitsDecimal)},
+		"group-by":      {string(model.GroupByType), string(model.GroupByPool), string(model.GroupByNone)},
+		"id-format":     {string(model.IDFormatName), string(model.IDFormatByID)},
+		"color-theme":   {string(model.ColorThemeDefault), string(model.ColorThemeDeuteranopia)},
+		"sort-sections": {string(model.SectionOrderDefault), string(model.SectionOrderSeverity)},
+		"smart-detail":  {string(model.SMARTDetailMinimal), string(model.SMARTDetailFull)},
+		"disk-type":     {"HDD", "SSD"},
+		"completion":    completionShells,
+	}
+}
+
+// completionFlag describes one flag for the completion scripts
+type completionFlag struct {
+	Name    string
+	Usage   string
+	IsBool  bool
+	IsFile  bool
+	Choices []string
+}
+
+// Option returns the flag as typed on the command line, e.g. --format or -f
+func (f completionFlag) Option() string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// completionFlags collects the flags of a flag set in name order
+func completionFlags(fs *flag.FlagSet) []completionFlag {
+	choices := flagValueChoices()
+
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		isBool := false
+		if value, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+			isBool = value.IsBoolFlag()
+		}
+		flags = append(flags, completionFlag{
+			Name:    f.Name,
+			Usage:   f.Usage,
+			IsBool:  isBool,
+			IsFile:  completionFileFlags[f.Name],
+			Choices: choices[f.Name],
+		})
+	})
+	return flags
+}
+
+// printCompletion writes the completion script for a shell, generated from
+// the flag definitions so new flags and values are picked up automatically
+func printCompletion(w io.Writer, fs *flag.FlagSet, shell string) error {
+	flags := completionFlags(fs)
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, flags)
+	case "zsh":
+		writeZshCompletion(w, flags)
+	case "fish":
+		writeFishCompletion(w, flags)
+	default:
+		return fmt.Errorf("不支持的shell: %s (可选 %s)", shell, strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+// writeBashCompletion writes a bash completion function for all flags
+func writeBashCompletion(w io.Writer, flags []completionFlag) {
+	function := "_" + strings.ReplaceAll(completionCommand, "-", "_")
+
+	var options, fileOptions []string
+	for _, f := range flags {
+		options = append(options, f.Option())
+		if f.IsFile {
+			fileOptions = append(fileOptions, f.Option())
+		}
+	}
+
+	fmt.Fprintf(w, "# bash completion for %s\n", completionCommand)
+	fmt.Fprintf(w, "%s() {\n", function)
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `    local prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	for _, f := range flags {
+		if len(f.Choices) > 0 {
+			fmt.Fprintf(w, "        %s)\n", f.Option())
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(f.Choices, " "))
+			fmt.Fprintln(w, "            return ;;")
+		}
+	}
+	if len(fileOptions) > 0 {
+		fmt.Fprintf(w, "        %s)\n", strings.Join(fileOptions, "|"))
+		fmt.Fprintln(w, `            COMPREPLY=($(compgen -f -- "$cur"))`)
+		fmt.Fprintln(w, "            return ;;")
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintf(w, "    COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(options, " "))
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "complete -F %s %s\n", function, completionCommand)
+}
+
+// writeZshCompletion writes a zsh _arguments specification for all flags
+func writeZshCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprintf(w, "#compdef %s\n\n", completionCommand)
+	fmt.Fprintln(w, "_arguments \\")
+	for i, f := range flags {
+		spec := fmt.Sprintf("%s[%s]", f.Option(), zshEscape(f.Usage))
+		switch {
+		case f.IsBool:
+		case len(f.Choices) > 0:
+			spec += fmt.Sprintf(":%s:(%s)", f.Name, strings.Join(f.Choices, " "))
+		case f.IsFile:
+			spec += ":file:_files"
+		default:
+			spec += fmt.Sprintf(":%s: ", f.Name)
+		}
+
+		line := "  " + shellQuote(spec)
+		if i < len(flags)-1 {
+			line += " \\"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// writeFishCompletion writes one fish complete command per flag
+func writeFishCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprintf(w, "# fish completion for %s\n", completionCommand)
+	for _, f := range flags {
+		option := "-l " + f.Name
+		if len(f.Name) == 1 {
+			option = "-s " + f.Name
+		}
+
+		line := fmt.Sprintf("complete -c %s %s -d %s", completionCommand, option, shellQuote(f.Usage))
+		switch {
+		case f.IsBool:
+		case len(f.Choices) > 0:
+			line += " -x -a " + shellQuote(strings.Join(f.Choices, " "))
+		case f.IsFile:
+			line += " -r -F"
+		default:
+			line += " -x"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// zshEscape escapes the characters _arguments treats specially in a description
+func zshEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(text)
+}
+
+// shellQuote quotes text in single quotes for bash, zsh and fish
+func shellQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
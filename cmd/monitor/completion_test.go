@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+// TestPrintCompletionBash 测试bash补全脚本由参数定义生成，包含参数名和可选值
+func TestPrintCompletionBash(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("format", "", "指定输出格式")
+	fs.String("f", "", "指定输出格式 (简写)")
+	fs.String("data-file", "", "指定历史数据文件")
+	fs.Bool("quiet", false, "静默模式")
+
+	var buf bytes.Buffer
+	if err := printCompletion(&buf, fs, "bash"); err != nil {
+		t.Fatalf("printCompletion failed: %v", err)
+	}
+	script := buf.String()
+
+	for _, expected := range []string{
+		"--format)",
+		`compgen -W "text html json template health"`,
+		"--data-file)",
+		"compgen -f",
+		`"--data-file -f --format --quiet"`,
+		"complete -F _disk_health_monitor disk-health-monitor",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected bash completion to contain %q, got:\n%s", expected, script)
+		}
+	}
+
+	// 布尔参数不需要值，zsh和fish中不带参数说明
+	buf.Reset()
+	if err := printCompletion(&buf, fs, "zsh"); err != nil {
+		t.Fatalf("printCompletion failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "'--quiet[静默模式]'") || !strings.Contains(buf.String(), ":format:(text html json template health)") {
+		t.Errorf("Unexpected zsh completion:\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := printCompletion(&buf, fs, "fish"); err != nil {
+		t.Fatalf("printCompletion failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "complete -c disk-health-monitor -s f -d '指定输出格式 (简写)' -x -a 'text html json template health'") {
+		t.Errorf("Unexpected fish completion:\n%s", buf.String())
+	}
+
+	if err := printCompletion(&buf, fs, "powershell"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
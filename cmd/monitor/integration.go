@@ -121,13 +121,42 @@ func formatFormatterOptions(app *Application) map[string]interface{} {
 	options := make(map[string]interface{})
 	
 	// Basic formatting options
-	options[output.OptionGroupByType] = !app.Config.NoGroup
+	groupBy := app.Config.GetGroupBy()
+	options[output.OptionGroupBy] = string(groupBy)
+	options[output.OptionGroupByType] = groupBy == model.GroupByType
+	options[output.OptionSortSections] = string(app.Config.SortSections)
+	options[output.OptionShowSerial] = app.Config.ShowSerial
+	options[output.OptionShowFeatures] = app.Config.ShowFeatures
+	options[output.OptionShowSensors] = app.Config.ShowSensors
+	options[output.OptionShowEndurance] = app.Config.NVMeExtended
+	options[output.OptionShowSectors] = app.Config.ShowSectors
+	options[output.OptionShowFirmware] = app.Config.ShowFirmware
+	options[output.OptionShowLinkSpeed] = app.Config.ShowLinkSpeed
+	options[output.OptionShowPower] = app.Config.ShowPower
+	options[output.OptionIncludeRawSmart] = app.Config.HTMLRawSmart
+	if len(app.Config.DiskColumns) > 0 {
+		options[output.OptionColumns] = app.Config.DiskColumns
+	}
+	options[output.OptionVerbose] = app.Config.Verbose
 	options[output.OptionIncludeSummary] = true
 	options[output.OptionIncludeTimestamp] = true
 	options[output.OptionColorOutput] = !app.Quiet
+	options[output.OptionColorTheme] = string(app.Config.ColorTheme)
 
 	// Format-specific options
 	options[output.OptionCompactMode] = app.CompactMode
+	if app.Config.SizeUnits != "" {
+		options[output.OptionSizeUnits] = string(app.Config.SizeUnits)
+	}
+	if app.Config.IDFormat != "" {
+		options[output.OptionIDFormat] = string(app.Config.IDFormat)
+	}
+	options[output.OptionUnassignedLabel] = app.Config.UnassignedLabel
+	options[output.OptionLegend] = app.Config.Legend
+	options[output.OptionMergeSSD] = app.Config.MergeSSD
+	if info := app.lastSystemInfo(); info != nil {
+		options[output.OptionSystemInfo] = info
+	}
 	
 	// PDF-specific options (if using PDF format)
 	if app.Config.OutputFormat == model.OutputFormatPDF {
@@ -140,6 +169,18 @@ func formatFormatterOptions(app *Application) map[string]interface{} {
 	if app.Config.OutputFormat == model.OutputFormatText {
 		options[output.OptionBorderStyle] = output.BorderStyleClassic // Use classic borders
 		options[output.OptionMaxWidth] = 120                         // Set max width to 120 chars
+		options[output.OptionSummaryFooter] = app.SummaryFooter
+		options[output.OptionTopN] = app.TopN
+	}
+
+	// Template-specific options (if using template format)
+	if app.Config.OutputFormat == model.OutputFormatTemplate {
+		options[output.OptionTemplateFile] = app.Config.TemplateFile
+	}
+
+	// Options passed through --set override the defaults above
+	for name, value := range app.FormatterOptions {
+		options[name] = value
 	}
 	
 	return options
@@ -4,9 +4,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
 // Version information (can be set during build)
@@ -51,6 +53,8 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 	flagH := flag.Bool("h", false, "显示帮助信息 (简写)")
 	version := flag.Bool("version", false, "显示版本信息")
 	flagV := flag.Bool("v", false, "显示版本信息 (简写)")
+	listOptions := flag.Bool("list-options", false, "列出输出格式、格式化选项和命令行参数 (与 --format json 一起使用时输出JSON)")
+	completion := flag.String("completion", "", "输出shell补全脚本 (bash, zsh, fish)")
 	debug := flag.Bool("debug", false, "启用调试模式")
 	flagD := flag.Bool("d", false, "启用调试模式 (简写)")
 	verbose := flag.Bool("verbose", false, "显示详细信息")
@@ -58,32 +62,117 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 	// Output flags
 	output := flag.String("output", "", "输出到指定文件")
 	flagO := flag.String("o", "", "输出到指定文件 (简写)")
-	format := flag.String("format", "", "指定输出格式 (text, pdf)")
+	format := flag.String("format", "", "指定输出格式 (text, html, json, template, health)")
 	flagF := flag.String("f", "", "指定输出格式 (简写)")
+	templateFile := flag.String("template-file", "", "template输出格式使用的Go模板文件")
 	quiet := flag.Bool("quiet", false, "静默模式，减少屏幕输出")
+	sizeUnits := flag.String("size-units", string(model.SizeUnitsBinary), "容量单位制 (binary, decimal)")
+	sizePrecision := flag.Int("size-precision", model.DefaultSizePrecision, "容量显示保留的小数位数 (0-6)")
 
 	// Display flags
-	noGroup := flag.Bool("no-group", false, "不按类型分组显示")
+	groupBy := flag.String("group-by", string(model.GroupByType), "磁盘分组方式 (type, pool, none)")
+	noGroup := flag.Bool("no-group", false, "不分组显示 (等同于 --group-by none)")
+	sortSections := flag.String("sort-sections", string(model.SectionOrderDefault), "分组的显示顺序 (default, severity)")
+	idFormat := flag.String("id-format", string(model.IDFormatName), "磁盘标识方式 (name: 内核设备名, by-id: /dev/disk/by-id下的稳定名称)")
+	unassignedLabel := flag.String("unassigned-label", model.PoolUnassigned, "未分配存储池的磁盘显示的池名称，可以为空")
+	colorTheme := flag.String("color-theme", string(model.ColorThemeDefault), "文本输出的状态配色方案 (default, deuteranopia)")
 	noController := flag.Bool("no-controller", false, "不显示控制器信息")
 	controllerOnly := flag.Bool("controller-only", false, "只显示控制器信息")
+	noControllerTemp := flag.Bool("no-controller-temp", false, "跳过控制器温度采集 (温度显示为 N/A)")
+	controllerID := flag.String("controller", "", "只收集指定的控制器 (storcli控制器编号或PCI地址)")
+	showSerial := flag.Bool("show-serial", false, "显示磁盘序列号和WWN")
+	showFeatures := flag.Bool("show-features", false, "显示SSD特性 (TRIM支持)")
+	showSensors := flag.Bool("show-sensors", false, "显示NVMe各温度传感器的读数")
+	showSectors := flag.Bool("show-sectors", false, "显示磁盘扇区格式 (512n/512e/4Kn)")
+	showFirmware := flag.Bool("show-firmware", false, "显示磁盘固件版本")
+	showLinkSpeed := flag.Bool("show-link-speed", false, "显示SAS/SATA链路速率")
+	showPower := flag.Bool("show-power", false, "显示SATA磁盘的APM级别和DSN状态")
+	systemInfo := flag.Bool("system-info", false, "在报告头部显示主机名、系统版本、运行时间和工具版本")
+	legend := flag.Bool("legend", false, "在报告末尾显示列、单位和状态颜色的图例")
+	mergeSSD := flag.Bool("merge-ssd", false, "将SAS和NVMe固态硬盘合并为一个固态硬盘分组显示")
+	htmlRawSmart := flag.Bool("html-raw-smart", false, "HTML报告中附带每个磁盘完整的SMART数据 (可折叠)")
+	redact := flag.Bool("redact", false, "用化名替换序列号、WWN和SAS地址，便于分享报告")
+	redactPools := flag.Bool("redact-pools", false, "与 --redact 一起使用时同时替换存储池名称")
 	onlyWarnings := flag.Bool("only-warnings", false, "只显示有警告或错误的磁盘")
 	compact := flag.Bool("compact", false, "使用紧凑输出模式")
+	summaryFooter := flag.Bool("summary-footer", false, "在文本输出末尾追加机器可解析的 SUMMARY 行")
+	top := flag.Int("top", 0, "只显示温度最高的N个磁盘和寿命消耗最多的N个固态硬盘 (0 表示显示完整表格)")
+	events := flag.Int("events", 0, "在报告中显示最近的N个磁盘事件 (0 表示不显示)")
 
 	// Advanced flags
 	dataFile := flag.String("data-file", "", "指定历史数据文件")
+	noIncrement := flag.Bool("no-increment", false, "不与历史数据比较，不显示读写增量 (本次数据仍会保存)")
 	logFile := flag.String("log-file", "", "指定日志文件")
 	timeout := flag.Int("timeout", 30, "设置命令执行超时时间（秒）")
+	maxDisks := flag.Int("max-disks", 0, "最多处理的磁盘数量 (0 表示不限制)")
+	includeFile := flag.String("include-file", "", "只收集文件中列出的磁盘 (每行一个设备名)")
+	includeBoot := flag.Bool("include-boot", false, "补充收集boot-pool中的启动盘")
+	failFast := flag.Bool("fail-fast", false, "第一个磁盘的SMART数据收集失败时立即停止")
+	nvmeExtended := flag.Bool("nvme-extended", false, "使用nvme-cli收集NVMe耐久组的严重警告和寿命")
+	smartDetail := flag.String("smart-detail", string(model.SMARTDetailFull), "SMART详情收集方式 (minimal: 只读取属性, full: 完整的 smartctl -a)")
+	enableSMART := flag.Bool("enable-smart", false, "对SMART已禁用的磁盘执行 smartctl -s on")
+	useTrueNASThresholds := flag.Bool("use-truenas-thresholds", false, "使用TrueNAS中配置的磁盘温度告警阈值")
+	flapRuns := flag.Int("flap-runs", 0, "状态连续N次运行相同才视为稳定并告警 (0 表示不启用)")
+	wearHorizonDays := flag.Int("wear-horizon-days", model.DefaultWearHorizonDays, "按寿命消耗速率预计N天内用完时将固态硬盘标记为警告 (0 表示不检查)")
+	ageInfoYears := flag.Int("age-info-years", model.DefaultAgeInfoYears, "通电时间超过N年的磁盘提示规划更换 (不影响状态，0 表示不检查)")
+	poolWarnPct := flag.Int("pool-warn-pct", model.DefaultPoolWarnPct, "存储池容量告警阈值 (%)，已用容量超过时标记为警告")
+	controllerCritTemp := flag.Int("controller-crit-temp", model.DefaultControllerCritTemp, "控制器过热阈值 (°C)，超过时标记为警告")
+	rulesFile := flag.String("rules", "", "从JSON文件加载自定义状态升级规则")
+	ackFile := flag.String("ack", "", "从JSON文件加载已知问题确认(序列号 -> {reason, until})")
 	exitOnWarning := flag.Bool("exit-on-warning", false, "发现警告时以非零状态退出")
+	expectDisks := flag.Int("expect-disks", 0, "找到的磁盘少于N个时以退出码6退出 (0 表示不检查)")
+	resetBaseline := flag.Bool("reset-baseline", false, "备份并清空历史数据，下次运行重新建立基线")
+	yes := flag.Bool("yes", false, "跳过确认提示")
+	validateHistory := flag.String("validate-history", "", "检查历史数据文件并输出问题报告，不执行数据收集")
+	dashboard := flag.String("dashboard", "", "将多个主机的JSON报告 (逗号分隔) 合并为一个HTML汇总页面，不执行数据收集")
+	tui := flag.Bool("tui", false, "全屏交互模式，定时刷新磁盘表格")
+	tuiInterval := flag.Int("tui-interval", int(DefaultTUIInterval.Seconds()), "交互模式的刷新间隔（秒）")
+	record := flag.String("record", "", "将执行的每条命令及其输出记录到JSON文件，便于提交问题报告")
+	replay := flag.String("replay", "", "从 --record 生成的文件回放命令输出，不执行任何命令")
+	syslogEnabled := flag.Bool("syslog", false, "同时将摘要和有问题的磁盘/控制器写入本机syslog")
+	syslogTag := flag.String("syslog-tag", system.DefaultSyslogTag, "--syslog 使用的标签")
+
+	// Stdin parsing flags
+	parseStdin := flag.Bool("parse-stdin", false, "从标准输入读取单个磁盘的 smartctl -a 输出并解析")
+	diskName := flag.String("disk-name", "", "与 --parse-stdin 一起使用的磁盘名称")
+	diskType := flag.String("disk-type", "HDD", "与 --parse-stdin 一起使用的磁盘类型 (HDD, SSD)")
+	diskModel := flag.String("disk-model", "", "与 --parse-stdin 一起使用的磁盘型号")
+	var setOptions setFlag
+	flag.Var(&setOptions, "set", "设置格式化选项 key=value (可重复使用)")
+	var columnSpecs setFlag
+	flag.Var(&columnSpecs, "columns", "按磁盘类型选择表格列 type=col1,col2,... (可重复使用)")
 
 	// Parse flags
 	flag.Parse()
 
+	// Fill flags that were not given on the command line from DHM_ variables
+	if err := applyEnvOverrides(flag.CommandLine); err != nil {
+		return nil, nil, err
+	}
+
 	// Check for help flag
 	if *help || *flagH {
 		printHelp()
 		os.Exit(0)
 	}
 
+	// Print a shell completion script; deliberately left out of --help
+	if *completion != "" {
+		if err := printCompletion(os.Stdout, flag.CommandLine, *completion); err != nil {
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+
+	// List formats, formatter options and flags without collecting data
+	if *listOptions {
+		asJSON := *format == "json" || *flagF == "json"
+		if err := printOptionList(os.Stdout, flag.CommandLine, asJSON); err != nil {
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+
 	// Check for version flag
 	if *version || *flagV {
 		fmt.Printf("磁盘健康监控工具 v%s (构建时间: %s)\n", Version, BuildDate)
@@ -94,6 +183,27 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 	if *controllerOnly && *noController {
 		return nil, nil, fmt.Errorf("参数冲突: --controller-only 和 --no-controller 不能同时使用")
 	}
+	if *parseStdin && *controllerOnly {
+		return nil, nil, fmt.Errorf("参数冲突: --parse-stdin 和 --controller-only 不能同时使用")
+	}
+	if *resetBaseline && *parseStdin {
+		return nil, nil, fmt.Errorf("参数冲突: --reset-baseline 和 --parse-stdin 不能同时使用")
+	}
+	if *validateHistory != "" && (*resetBaseline || *parseStdin) {
+		return nil, nil, fmt.Errorf("参数冲突: --validate-history 不能与 --reset-baseline 或 --parse-stdin 同时使用")
+	}
+	if *dashboard != "" && (*resetBaseline || *parseStdin || *validateHistory != "") {
+		return nil, nil, fmt.Errorf("参数冲突: --dashboard 不能与 --reset-baseline、--parse-stdin 或 --validate-history 同时使用")
+	}
+	if *tui && (*parseStdin || *resetBaseline || *validateHistory != "" || *controllerOnly) {
+		return nil, nil, fmt.Errorf("参数冲突: --tui 不能与 --parse-stdin、--reset-baseline、--validate-history 或 --controller-only 同时使用")
+	}
+	if *tuiInterval <= 0 {
+		return nil, nil, fmt.Errorf("--tui-interval 必须大于0: %d", *tuiInterval)
+	}
+	if *parseStdin && *diskName == "" {
+		return nil, nil, fmt.Errorf("--parse-stdin 需要指定 --disk-name")
+	}
 
 	// Apply flags to config
 	config.Debug = *debug || *flagD
@@ -113,6 +223,12 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 			config.OutputFormat = model.OutputFormatPDF
 		case "html":
 			config.OutputFormat = model.OutputFormatHTML
+		case "json":
+			config.OutputFormat = model.OutputFormatJSON
+		case "template":
+			config.OutputFormat = model.OutputFormatTemplate
+		case "health":
+			config.OutputFormat = model.OutputFormatHealth
 		default:
 			return nil, nil, fmt.Errorf("不支持的输出格式: %s", *format)
 		}
@@ -124,10 +240,44 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 			config.OutputFormat = model.OutputFormatPDF
 		case "html":
 			config.OutputFormat = model.OutputFormatHTML
+		case "json":
+			config.OutputFormat = model.OutputFormatJSON
+		case "template":
+			config.OutputFormat = model.OutputFormatTemplate
+		case "health":
+			config.OutputFormat = model.OutputFormatHealth
 		default:
 			return nil, nil, fmt.Errorf("不支持的输出格式: %s", *flagF)
 		}
 	}
+	config.TemplateFile = *templateFile
+
+	if *expectDisks < 0 {
+		return nil, nil, fmt.Errorf("--expect-disks 不能为负数: %d", *expectDisks)
+	}
+	if *top < 0 {
+		return nil, nil, fmt.Errorf("--top 不能为负数: %d", *top)
+	}
+	if *top > 0 && config.OutputFormat != model.OutputFormatText {
+		return nil, nil, fmt.Errorf("--top 只支持文本输出格式")
+	}
+	if *events < 0 {
+		return nil, nil, fmt.Errorf("--events 不能为负数: %d", *events)
+	}
+
+	units, err := model.ParseSizeUnits(*sizeUnits)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.SizeUnits = units
+	config.SizePrecision = *sizePrecision
+
+	diskIDFormat, err := model.ParseIDFormat(*idFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.IDFormat = diskIDFormat
+	config.UnassignedLabel = *unassignedLabel
 
 	if *dataFile != "" {
 		config.DataFile = *dataFile
@@ -137,17 +287,126 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 		config.LogFile = *logFile
 	}
 
-	config.NoGroup = *noGroup
+	config.GroupBy, err = model.ParseGroupBy(*groupBy)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.ColorTheme, err = model.ParseColorTheme(*colorTheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.SortSections, err = model.ParseSectionOrder(*sortSections)
+	if err != nil {
+		return nil, nil, err
+	}
+	if *noGroup {
+		config.GroupBy = model.GroupByNone
+	}
+	config.NoGroup = config.GroupBy == model.GroupByNone
 	config.NoController = *noController
 	config.ControllerOnly = *controllerOnly
+	config.NoControllerTemp = *noControllerTemp
+	config.ControllerID = *controllerID
+	config.ShowSerial = *showSerial
+	config.ShowFeatures = *showFeatures
+	config.ShowSensors = *showSensors
+	config.ShowSectors = *showSectors
+	config.ShowFirmware = *showFirmware
+	config.ShowLinkSpeed = *showLinkSpeed
+	config.ShowPower = *showPower
+	config.SystemInfo = *systemInfo
+	config.Legend = *legend
+	config.MergeSSD = *mergeSSD
+	config.HTMLRawSmart = *htmlRawSmart
+	for _, spec := range columnSpecs {
+		diskType, columns, err := model.ParseColumnSpec(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if config.DiskColumns == nil {
+			config.DiskColumns = make(map[model.DiskType][]model.DiskColumn)
+		}
+		config.DiskColumns[diskType] = columns
+	}
+	config.Redact = *redact || *redactPools
+	config.RedactPools = *redactPools
+
+	// Parsing stdin bypasses collection, so there is no controller data
+	if *parseStdin {
+		config.NoController = true
+	}
 	config.CommandTimeout = time.Duration(*timeout) * time.Second
+	config.MaxDisks = *maxDisks
+
+	if *includeFile != "" {
+		names, err := model.LoadDiskList(*includeFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.IncludeDisks = names
+	}
+	config.IncludeBoot = *includeBoot
+	config.FailFast = *failFast
+	config.NVMeExtended = *nvmeExtended
+	config.SMARTDetail, err = model.ParseSMARTDetail(*smartDetail)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.UseTrueNASThresholds = *useTrueNASThresholds
+	config.EnableSMART = *enableSMART
+	config.ControllerCritTemp = *controllerCritTemp
+	config.PoolWarnPct = *poolWarnPct
+	config.FlapRuns = *flapRuns
+	config.WearHorizonDays = *wearHorizonDays
+	config.AgeInfoYears = *ageInfoYears
+	config.NoIncrement = *noIncrement
+
+	if *rulesFile != "" {
+		rules, err := model.LoadStatusRules(*rulesFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.StatusRules = rules
+	}
+
+	if *ackFile != "" {
+		acks, err := model.LoadAcknowledgements(*ackFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.Acknowledgements = acks
+	}
 
 	// Store additional options that aren't in the core Config struct
 	additionalOptions := make(map[string]interface{})
 	additionalOptions["only_warnings"] = *onlyWarnings
 	additionalOptions["exit_on_warning"] = *exitOnWarning
+	additionalOptions["expect_disks"] = *expectDisks
 	additionalOptions["quiet"] = *quiet
 	additionalOptions["compact"] = *compact
+	additionalOptions["summary_footer"] = *summaryFooter
+	additionalOptions["top"] = *top
+	additionalOptions["events"] = *events
+	additionalOptions["parse_stdin"] = *parseStdin
+	additionalOptions["disk_name"] = *diskName
+	additionalOptions["disk_type"] = *diskType
+	additionalOptions["disk_model"] = *diskModel
+	additionalOptions["reset_baseline"] = *resetBaseline
+	additionalOptions["yes"] = *yes
+	additionalOptions["validate_history"] = *validateHistory
+	additionalOptions["dashboard"] = *dashboard
+	additionalOptions["tui"] = *tui
+	additionalOptions["tui_interval"] = *tuiInterval
+	additionalOptions["record"] = *record
+	additionalOptions["replay"] = *replay
+	additionalOptions["syslog"] = *syslogEnabled
+	additionalOptions["syslog_tag"] = *syslogTag
+
+	formatterOptions, err := parseSetOptions(setOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	additionalOptions["formatter_options"] = formatterOptions
 
 	// Validate config
 	if err := config.Validate(); err != nil {
@@ -162,6 +421,89 @@ func parseFlags() (*model.Config, map[string]interface{}, error) {
 	return config, additionalOptions, nil
 }
 
+// envPrefix is the prefix of environment variables that configure flags
+const envPrefix = "DHM_"
+
+// envShortAliases maps long flags to their short forms; setting either on the
+// command line takes precedence over the environment variable
+var envShortAliases = map[string]string{
+	"debug":  "d",
+	"output": "o",
+	"format": "f",
+}
+
+// envSkipFlags are flags that make no sense to set from the environment
+var envSkipFlags = map[string]bool{
+	"help":         true,
+	"version":      true,
+	"set":          true,
+	"list-options": true,
+	"completion":   true,
+}
+
+// envVarName maps a flag name to its environment variable, e.g. data-file -> DHM_DATA_FILE
+func envVarName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnvOverrides sets every long flag that was not given on the command
+// line from its DHM_ environment variable, so flags > environment > defaults
+func applyEnvOverrides(fs *flag.FlagSet) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || len(f.Name) == 1 || envSkipFlags[f.Name] {
+			return
+		}
+		if explicit[f.Name] || explicit[envShortAliases[f.Name]] {
+			return
+		}
+
+		name := envVarName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("无效的环境变量 %s=%s: %v", name, value, setErr)
+		}
+	})
+	return err
+}
+
+// setFlag collects repeatable --set key=value flags
+type setFlag []string
+
+// String returns the collected values
+func (s *setFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set appends a value each time the flag is given
+func (s *setFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// parseSetOptions converts key=value pairs into formatter options. Values
+// are kept as strings; the formatter converts them to each option's type
+func parseSetOptions(pairs []string) (map[string]interface{}, error) {
+	options := make(map[string]interface{})
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("无效的 --set 参数: %s (应为 key=value)", pair)
+		}
+		options[key] = strings.TrimSpace(value)
+	}
+	return options, nil
+}
+
 // printHelp prints detailed help information
 func printHelp() {
 	helpText := `用法: disk-health-monitor [选项...]
@@ -170,32 +512,111 @@ func printHelp() {
   基本选项:
     -h, --help             显示此帮助信息并退出
     -v, --version          显示版本信息并退出
+    --list-options         列出输出格式、格式化选项和命令行参数并退出 (加 --format json 输出JSON)
     -d, --debug            启用调试模式
-    --verbose              显示详细输出信息
+    --verbose              显示详细输出信息 (包括LSI控制器固件版本)
 
   输出选项:
     -o, --output FILE      输出到指定文件
-    -f, --format FORMAT    指定输出格式 (text, html)
+    -f, --format FORMAT    指定输出格式 (text, html, json, template, health)
+                           health 只输出 OK/WARNING/CRITICAL，退出码为 0/1/2 (无法收集时为 UNKNOWN/3)
+    --template-file FILE   template格式使用的Go模板文件 (text/template语法)
     --quiet                静默模式，减少屏幕输出
+    --size-units UNITS     容量单位制 (binary: KiB/GiB/TiB, decimal: KB/GB/TB)
+    --size-precision N     容量显示保留的小数位数 (0-6，默认2)
 
   显示选项:
-    --no-group             不按类型分组显示
+    --group-by MODE        磁盘分组方式 (type: 按类型, pool: 按存储池, none: 不分组)
+    --no-group             不分组显示 (等同于 --group-by none)
+    --sort-sections ORDER  分组的显示顺序 (default: 固定顺序, severity: 有错误或警告的分组排在前面)
+    --id-format FORMAT     磁盘标识方式 (name: 内核设备名, by-id: /dev/disk/by-id下重启后不变的名称)
+    --unassigned-label 名称 未分配存储池的磁盘显示的池名称 (默认: 未分配，可以为空)
+    --color-theme THEME    文本输出的状态配色方案 (default: 绿/黄/红, deuteranopia: 红绿色盲友好的蓝/黄/品红)
     --no-controller        不显示控制器信息
     --controller-only      只显示控制器信息
+    --no-controller-temp   跳过控制器温度采集 (用于温度命令会挂起的系统)
+    --controller ID        只收集指定的控制器 (storcli控制器编号如 0，或PCI地址如 03:00.0)
+    --show-serial          显示磁盘序列号和WWN
+    --show-features        显示SSD特性 (TRIM支持)
+    --show-sensors         显示NVMe各温度传感器的读数 (状态按最热的传感器判断)
+    --show-sectors         显示磁盘扇区格式 (512n/512e/4Kn) 和控制器下的扇区大小统计
+    --show-firmware        显示磁盘固件版本 (smartctl -i 报告的版本，与控制器固件无关)
+    --show-link-speed      显示SAS/SATA链路速率 (链路低于磁盘支持的速率时总会产生警告)
+    --show-power           显示SATA磁盘的APM级别和DSN状态 (每个磁盘额外执行 smartctl -g apm -g dsn)
+    --system-info          在报告头部显示主机名、系统版本、运行时间和工具版本 (文本和HTML为主机信息面板，JSON为 system 对象)
+    --legend               在报告末尾显示图例，说明各列的含义、单位和状态颜色 (文本和HTML)
+    --merge-ssd            按类型分组时将SAS和NVMe固态硬盘合并为一个"固态硬盘"分组 (表格包含两类的所有列)
+    --html-raw-smart       HTML报告中附带每个磁盘完整的SMART数据 (可折叠面板，默认关闭以减小文件)
+    --columns 类型=列,...  按磁盘类型选择文本和HTML表格的列 (可重复使用)，如 SAS_HDD=name,temp,status
+    --redact               用化名 (disk1, wwn1...) 替换序列号、WWN和SAS地址，便于分享报告
+    --redact-pools         同时替换存储池名称 (隐含 --redact)
     --only-warnings        只显示有警告或错误的磁盘
     --compact              使用紧凑输出模式
+    --summary-footer       在文本输出末尾追加机器可解析的 SUMMARY 行
+    --top N                只显示摘要、温度最高的N个磁盘和寿命消耗最多的N个固态硬盘 (仅文本格式)
+    --events N             在报告中显示最近的N个磁盘事件 (状态变化、新增/移除磁盘、计数器重置)
 
   高级选项:
     --data-file FILE       指定历史数据文件
+    --no-increment         不读取历史数据，不显示读写增量 (用于历史数据过期或更换硬件后，本次数据仍会保存)
     --log-file FILE        指定日志文件
     --timeout SECONDS      设置命令执行超时时间
+    --max-disks N          最多处理的磁盘数量，超出时只处理排序后的前N个
+    --include-file FILE    只收集文件中列出的磁盘 (每行一个设备名，如 sda 或 /dev/sda)
+    --include-boot         补充收集boot-pool中的启动盘 (disk.query有时不返回启动盘)，在存储池列显示为 boot-pool
+    --fail-fast            第一个磁盘的SMART数据收集失败时立即停止并报告错误 (默认尽量收集其余磁盘)
+    --nvme-extended        使用nvme-cli收集NVMe耐久组的严重警告和寿命 (未安装nvme-cli时跳过)
+    --smart-detail MODE    SMART详情收集方式 (full: smartctl -a，默认; minimal: smartctl -A 只读取属性，
+                           较快但没有TRIM支持、SAS错误计数等设备信息和日志)
+    --enable-smart         对SMART已禁用的磁盘执行 smartctl -s on 后重新收集 (默认只标记为警告 "SMART已禁用")
+    --use-truenas-thresholds  使用TrueNAS中配置的磁盘温度告警阈值 (达到告警温度为警告，临界温度为错误，获取失败时使用默认阈值)
+    --flap-runs N          状态连续N次运行相同才视为稳定并告警，抑制在正常和警告之间反复变化的磁盘 (错误总是立即告警，0 表示不启用)
+    --wear-horizon-days N  根据历史数据计算固态硬盘的寿命消耗速率，预计N天 (默认365) 内用完时标记为警告，
+                           即使当前已用寿命很低 (至少需要7天的通电时间，0 表示不检查)
+    --age-info-years N     通电时间超过N年 (默认5) 的磁盘提示规划更换，只是提示，不改变状态 (0 表示不检查)
+    --pool-warn-pct N      存储池容量告警阈值 (%，默认80)，已用容量超过时标记为警告并触发 --exit-on-warning
+    --controller-crit-temp N  控制器过热阈值 (°C，默认70)，超过或温度骤升时标记为警告
+    --rules FILE           从JSON文件加载自定义状态升级规则
+    --ack FILE             从JSON文件加载已知问题确认，确认期内的警告不触发 --exit-on-warning
     --exit-on-warning      发现警告时以非零状态退出
+    --expect-disks N       找到的磁盘少于N个时以退出码6退出 (用于检测背板掉线或控制卡松动)
+    --set KEY=VALUE        设置格式化选项 (可重复使用)
+    --reset-baseline       备份并清空历史数据文件，下次运行重新建立基线
+    --yes                  跳过 --reset-baseline 的确认提示
+    --validate-history FILE  检查历史数据文件 (JSON格式、版本、时间戳、计数) 并输出报告，有错误时以非零状态退出
+    --dashboard FILES      将多个主机的JSON报告 (逗号分隔) 合并为一个HTML汇总页面，每个主机一个标签页
+    --record FILE          将执行的每条命令及其输出记录到JSON文件，便于提交问题报告
+    --replay FILE          从 --record 生成的文件回放命令输出，不执行任何命令 (用于离线分析)
+    --syslog               除正常输出外，将摘要 (LOG_INFO) 和警告/错误的磁盘与控制器 (LOG_WARNING/LOG_ERR)
+                           写入本机syslog/journald
+    --syslog-tag TAG       --syslog 使用的标签 (默认 disk-health-monitor)
+
+  交互模式:
+    --tui                  全屏显示磁盘表格并定时重新收集 (按 n/s/t/p 按名称/状态/温度/存储池排序，
+                           再按一次反向，r 立即刷新，q 退出)
+    --tui-interval SECONDS 交互模式的刷新间隔 (默认60秒)
+
+  单盘解析:
+    --parse-stdin          从标准输入读取 smartctl -a 输出并解析，不执行数据收集
+    --disk-name NAME       磁盘名称 (与 --parse-stdin 一起使用)
+    --disk-type TYPE       磁盘类型 HDD 或 SSD (默认 HDD)
+    --disk-model MODEL     磁盘型号 (可选)
+
+环境变量:
+  除 --help、--version、--list-options 和 --set 外，每个长选项都可以通过 DHM_ 前缀的环境变量设置，
+  选项名转为大写并将 - 替换为 _ (例如 DHM_TIMEOUT、DHM_FORMAT、DHM_OUTPUT、DHM_DATA_FILE)。
+  命令行参数优先于环境变量。
 
 例子:
   disk-health-monitor                    # 显示所有磁盘和控制器信息
   disk-health-monitor -o report.txt      # 将输出保存到文件
   disk-health-monitor --only-warnings    # 只显示有问题的磁盘
   disk-health-monitor --controller-only  # 只显示控制器信息
+  disk-health-monitor --set border_style=none --set max_width=80
+  disk-health-monitor --reset-baseline --yes  # 更换磁盘后重置历史基线
+  disk-health-monitor --validate-history /var/log/disk_health_monitor_data.json
+  disk-health-monitor --dashboard nas-a.json,nas-b.json -o fleet.html
+  smartctl -a /dev/sda | disk-health-monitor --parse-stdin --disk-name sda --disk-type HDD
 `
 	fmt.Print(helpText)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
 	"github.com/MaurUppi/disk-health-monitor/internal/output"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
 func TestParseFlags(t *testing.T) {
@@ -401,6 +403,95 @@ func TestFormatFormatterOptions(t *testing.T) {
 	}
 }
 
+// TestSetFormatterOptions tests passing formatter options through --set
+func TestSetFormatterOptions(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	os.Args = []string{"disk-health-monitor", "--set", "border_style=none", "--set", "max_width=80"}
+	config, options, err := parseFlags()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config.OutputFormat = model.OutputFormatText
+
+	app := &Application{
+		Config:           config,
+		FormatterOptions: getMapOption(options, "formatter_options"),
+	}
+	formatterOptions := formatFormatterOptions(app)
+
+	if formatterOptions[output.OptionBorderStyle] != output.BorderStyleNone {
+		t.Errorf("Expected BorderStyle to be none, got %v", formatterOptions[output.OptionBorderStyle])
+	}
+	if formatterOptions[output.OptionMaxWidth] != "80" {
+		t.Errorf("Expected MaxWidth to be 80, got %v", formatterOptions[output.OptionMaxWidth])
+	}
+
+	// Malformed values are rejected
+	os.Args = []string{"disk-health-monitor", "--set", "border_style"}
+	if _, _, err := parseFlags(); err == nil {
+		t.Error("Expected error for --set without '='")
+	}
+}
+
+// TestEnvironmentOverrides tests that DHM_ variables fill flags that were not given
+func TestEnvironmentOverrides(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	tempDir := t.TempDir()
+	t.Setenv("DHM_TIMEOUT", "45")
+	t.Setenv("DHM_FORMAT", "json")
+	t.Setenv("DHM_OUTPUT", filepath.Join(tempDir, "report.json"))
+	t.Setenv("DHM_DATA_FILE", filepath.Join(tempDir, "history.json"))
+	t.Setenv("DHM_SHOW_SERIAL", "true")
+
+	os.Args = []string{"disk-health-monitor"}
+	config, _, err := parseFlags()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.CommandTimeout != 45*time.Second {
+		t.Errorf("CommandTimeout: expected 45s, got %v", config.CommandTimeout)
+	}
+	if config.OutputFormat != model.OutputFormatJSON {
+		t.Errorf("OutputFormat: expected json, got %s", config.OutputFormat)
+	}
+	if config.OutputFile != filepath.Join(tempDir, "report.json") {
+		t.Errorf("OutputFile: expected %s, got %s", filepath.Join(tempDir, "report.json"), config.OutputFile)
+	}
+	if config.DataFile != filepath.Join(tempDir, "history.json") {
+		t.Errorf("DataFile: expected %s, got %s", filepath.Join(tempDir, "history.json"), config.DataFile)
+	}
+	if !config.ShowSerial {
+		t.Error("ShowSerial: expected true from DHM_SHOW_SERIAL")
+	}
+
+	// Flags, including short aliases, take precedence over the environment
+	os.Args = []string{"disk-health-monitor", "-f", "html", "--timeout", "10", "-o", filepath.Join(tempDir, "report.html")}
+	config, _, err = parseFlags()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.OutputFormat != model.OutputFormatHTML {
+		t.Errorf("OutputFormat: expected html from -f, got %s", config.OutputFormat)
+	}
+	if config.CommandTimeout != 10*time.Second {
+		t.Errorf("CommandTimeout: expected 10s from flag, got %v", config.CommandTimeout)
+	}
+	if config.OutputFile != filepath.Join(tempDir, "report.html") {
+		t.Errorf("OutputFile: expected -o to win, got %s", config.OutputFile)
+	}
+
+	// Invalid values are reported with the variable name
+	t.Setenv("DHM_TIMEOUT", "soon")
+	os.Args = []string{"disk-health-monitor"}
+	if _, _, err := parseFlags(); err == nil || !strings.Contains(err.Error(), "DHM_TIMEOUT") {
+		t.Errorf("Expected error mentioning DHM_TIMEOUT, got %v", err)
+	}
+}
+
 // Mock implementations for testing
 type MockLogger struct {
 	debugLogs []string
@@ -454,3 +545,7 @@ func (m *MockCommandRunner) RunIgnoreError(ctx context.Context, command string)
 func (m *MockCommandRunner) RunWithTimeout(command string, timeout time.Duration) (string, error) {
 	return m.Run(context.Background(), command)
 }
+
+func (m *MockCommandRunner) RunWithStatus(ctx context.Context, command string) (string, int, error) {
+	return system.SplitExitStatus(m.Run(ctx, command))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/output"
+)
+
+// optionList is the machine-readable description of the command line
+// interface printed by --list-options
+type optionList struct {
+	Formats          []string                     `json:"formats"`
+	FormatterOptions map[string]map[string]string `json:"formatter_options"`
+	Flags            []flagInfo                   `json:"flags"`
+}
+
+// flagInfo describes a single command line flag
+type flagInfo struct {
+	Name    string `json:"name"`
+	Default string `json:"default"`
+	Usage   string `json:"usage"`
+	EnvVar  string `json:"env_var,omitempty"`
+}
+
+// buildOptionList collects the formats, formatter options and flags
+func buildOptionList(fs *flag.FlagSet) optionList {
+	list := optionList{
+		Formats:          output.SupportedFormats,
+		FormatterOptions: output.SupportedOptions(),
+	}
+
+	fs.VisitAll(func(f *flag.Flag) {
+		info := flagInfo{Name: f.Name, Default: f.DefValue, Usage: f.Usage}
+		if len(f.Name) > 1 && !envSkipFlags[f.Name] {
+			info.EnvVar = envVarName(f.Name)
+		}
+		list.Flags = append(list.Flags, info)
+	})
+
+	return list
+}
+
+// printOptionList writes the option list as JSON, or as plain text for
+// any other format
+func printOptionList(w io.Writer, fs *flag.FlagSet, asJSON bool) error {
+	list := buildOptionList(fs)
+
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+
+	fmt.Fprintf(w, "输出格式: %v\n", list.Formats)
+	for _, format := range list.Formats {
+		options := list.FormatterOptions[format]
+		names := make([]string, 0, len(options))
+		for name := range options {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintf(w, "\n%s 格式化选项 (--set KEY=VALUE):\n", format)
+		for _, name := range names {
+			fmt.Fprintf(w, "  %-22s %s\n", name, options[name])
+		}
+	}
+
+	fmt.Fprintln(w, "\n命令行参数:")
+	for _, info := range list.Flags {
+		fmt.Fprintf(w, "  --%-22s %s (默认: %q)\n", info.Name, info.Usage, info.Default)
+	}
+	return nil
+}
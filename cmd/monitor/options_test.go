@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/output"
+)
+
+// TestPrintOptionListJSON 测试 --list-options --format json 汇总所有格式化器的选项和命令行参数
+func TestPrintOptionListJSON(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("format", "", "指定输出格式")
+	fs.Bool("help", false, "显示帮助信息")
+	fs.String("f", "", "指定输出格式 (简写)")
+
+	var buf bytes.Buffer
+	if err := printOptionList(&buf, fs, true); err != nil {
+		t.Fatalf("printOptionList failed: %v", err)
+	}
+
+	var list optionList
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if strings.Join(list.Formats, ",") != "text,html,json,template" {
+		t.Errorf("Unexpected formats: %v", list.Formats)
+	}
+
+	// 文本和HTML格式化器的选项都应出现在输出中
+	for format, option := range map[string]string{
+		"text": output.OptionBorderStyle,
+		"html": output.OptionTemperatureBar,
+	} {
+		if _, ok := list.FormatterOptions[format][option]; !ok {
+			t.Errorf("Expected %s options to include %q, got %v", format, option, list.FormatterOptions[format])
+		}
+	}
+	if _, ok := list.FormatterOptions["text"][output.OptionTemperatureBar]; ok {
+		t.Errorf("Expected HTML-only options to be listed under html only")
+	}
+
+	// 命令行参数包含对应的环境变量，--help 和简写参数除外
+	envVars := make(map[string]string)
+	for _, info := range list.Flags {
+		envVars[info.Name] = info.EnvVar
+	}
+	if len(envVars) != 3 || envVars["format"] != "DHM_FORMAT" || envVars["help"] != "" || envVars["f"] != "" {
+		t.Errorf("Unexpected flags: %+v", list.Flags)
+	}
+
+	// 非JSON格式输出纯文本
+	buf.Reset()
+	if err := printOptionList(&buf, fs, false); err != nil {
+		t.Fatalf("printOptionList failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "html 格式化选项") || !strings.Contains(buf.String(), "--format") {
+		t.Errorf("Unexpected text output:\n%s", buf.String())
+	}
+}
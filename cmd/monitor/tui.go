@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/olekukonko/tablewriter"
+)
+
+// DefaultTUIInterval is how often the interactive view re-collects data
+const DefaultTUIInterval = 60 * time.Second
+
+// ANSI sequences used by the interactive view
+const (
+	ansiClearScreen = "\033[H\033[2J"
+	ansiHideCursor  = "\033[?25l"
+	ansiShowCursor  = "\033[?25h"
+)
+
+// tuiSortKeys maps key presses to disk sort fields
+var tuiSortKeys = map[byte]model.DiskSortKey{
+	'n': model.SortByName,
+	's': model.SortByStatus,
+	't': model.SortByTemperature,
+	'p': model.SortByPool,
+}
+
+// tuiSortLabels are the sort field names shown in the status line
+var tuiSortLabels = map[model.DiskSortKey]string{
+	model.SortByName:        "名称",
+	model.SortByStatus:      "状态",
+	model.SortByTemperature: "温度",
+	model.SortByPool:        "存储池",
+}
+
+// tuiAction is what the interactive loop should do after a key press
+type tuiAction int
+
+const (
+	tuiNone tuiAction = iota
+	tuiRedraw
+	tuiRefresh
+	tuiQuit
+)
+
+// tuiView holds the state behind the interactive view: the latest collection
+// result and the current sort order. It has no terminal dependencies, so the
+// key handling and table rows can be unit tested
+type tuiView struct {
+	result     *CollectionResult
+	sortKey    model.DiskSortKey
+	descending bool
+	collecting bool
+}
+
+// newTUIView creates a view sorted by name
+func newTUIView() *tuiView {
+	return &tuiView{sortKey: model.SortByName}
+}
+
+// SetResult replaces the displayed collection result
+func (v *tuiView) SetResult(result *CollectionResult) {
+	v.result = result
+	v.collecting = false
+}
+
+// HandleKey applies a key press. Pressing the current sort key again
+// reverses the order; a new sort key starts in its most useful direction
+// (worst status and hottest disks first)
+func (v *tuiView) HandleKey(key byte) tuiAction {
+	switch key {
+	case 'q', 'Q':
+		return tuiQuit
+	case 'r', 'R':
+		return tuiRefresh
+	}
+
+	sortKey, ok := tuiSortKeys[key]
+	if !ok {
+		return tuiNone
+	}
+	if sortKey == v.sortKey {
+		v.descending = !v.descending
+	} else {
+		v.sortKey = sortKey
+		v.descending = sortKey == model.SortByStatus || sortKey == model.SortByTemperature
+	}
+	return tuiRedraw
+}
+
+// Rows returns the disk table rows in the current sort order
+func (v *tuiView) Rows() [][]string {
+	if v.result == nil || v.result.DiskData == nil {
+		return nil
+	}
+
+	disks := v.result.DiskData.SortedDisks(v.sortKey, v.descending)
+	rows := make([][]string, 0, len(disks))
+	for _, disk := range disks {
+		rows = append(rows, []string{
+			disk.Name,
+			string(disk.Type),
+			disk.Model,
+			disk.Pool,
+			disk.GetDisplayTemperature(),
+			string(disk.GetStatus()),
+		})
+	}
+	return rows
+}
+
+// StatusLine describes the data age, sort order and available keys
+func (v *tuiView) StatusLine(now time.Time) string {
+	direction := "↑"
+	if v.descending {
+		direction = "↓"
+	}
+
+	collected := "采集中..."
+	if v.result != nil {
+		collected = fmt.Sprintf("采集于 %s (%s前)", v.result.CollectedAt.Format("15:04:05"),
+			v.result.Age(now).Truncate(time.Second))
+		if v.collecting {
+			collected += " | 刷新中..."
+		}
+	}
+
+	return fmt.Sprintf("%s | 排序: %s%s | n/s/t/p 排序  r 刷新  q 退出",
+		collected, tuiSortLabels[v.sortKey], direction)
+}
+
+// Render draws the full screen
+func (v *tuiView) Render(w io.Writer, now time.Time) {
+	fmt.Fprint(w, ansiClearScreen)
+	fmt.Fprintln(w, "TrueNAS磁盘健康监控")
+	fmt.Fprintln(w, v.StatusLine(now))
+
+	if v.result != nil && v.result.DiskErr != nil {
+		fmt.Fprintf(w, "磁盘信息收集失败: %v\n", v.result.DiskErr)
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"设备", "类型", "型号", "存储池", "温度", "状态"})
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.AppendBulk(v.Rows())
+	table.Render()
+}
+
+// runTUI shows a full-screen disk table that re-collects every interval
+// and reacts to single key presses until q is pressed
+func (app *Application) runTUI(in io.Reader, out io.Writer, interval time.Duration) int {
+	// Refreshing must not overwrite the history baseline, or the daily
+	// increments would become per-refresh deltas
+	app.ReadOnly = true
+	app.DiskCollector.SetSkipHistory(true)
+
+	restore := enableCbreak()
+	defer restore()
+	fmt.Fprint(out, ansiHideCursor)
+	defer fmt.Fprint(out, ansiShowCursor)
+
+	keys := make(chan byte)
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := in.Read(buf); err != nil {
+				close(keys)
+				return
+			}
+			keys <- buf[0]
+		}
+	}()
+
+	results := make(chan *CollectionResult, 1)
+	view := newTUIView()
+	collect := func() {
+		if view.collecting {
+			return
+		}
+		view.collecting = true
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), app.Config.CommandTimeout)
+			defer cancel()
+			results <- app.Collect(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	clock := time.NewTicker(time.Second)
+	defer clock.Stop()
+
+	collect()
+	view.Render(out, time.Now())
+	for {
+		select {
+		case result := <-results:
+			view.SetResult(result)
+		case <-ticker.C:
+			collect()
+		case <-clock.C:
+			// Redraw so the data age stays current
+		case key, ok := <-keys:
+			if !ok {
+				return 0
+			}
+			switch view.HandleKey(key) {
+			case tuiQuit:
+				fmt.Fprint(out, ansiClearScreen)
+				return 0
+			case tuiRefresh:
+				collect()
+			case tuiNone:
+				continue
+			}
+		}
+		view.Render(out, time.Now())
+	}
+}
+
+// enableCbreak switches the terminal to deliver key presses without Enter
+// and returns a function restoring the previous settings. Without stty (e.g.
+// on Windows) keys are read line by line instead
+func enableCbreak() func() {
+	saved, err := runStty("-g")
+	if err != nil {
+		return func() {}
+	}
+	if _, err := runStty("cbreak", "-echo"); err != nil {
+		return func() {}
+	}
+	return func() {
+		_, _ = runStty(strings.TrimSpace(saved))
+	}
+}
+
+// runStty runs stty against the terminal attached to stdin
+func runStty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return string(output), err
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/collector"
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/storage"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
+)
+
+// TestTUIViewSorting 测试交互模式的按键处理和排序后的表格行
+func TestTUIViewSorting(t *testing.T) {
+	diskData := model.NewDiskData()
+	addDisk := func(name, pool, temp string, status model.DiskStatus) {
+		disk := model.NewDisk(name, "HDD", "TEST-MODEL", "4 TB")
+		disk.Pool = pool
+		if temp != "" {
+			disk.SMARTData["Temperature"] = temp
+		}
+		if status != model.DiskStatusOK {
+			disk.Escalate(status, "test")
+		}
+		diskData.AddDisk(disk)
+	}
+	addDisk("sdc", "tank", "41", model.DiskStatusOK)
+	addDisk("sda", "backup", "", model.DiskStatusWarning)
+	addDisk("sdb", "tank", "48", model.DiskStatusOK)
+
+	collectedAt := time.Date(2024, 9, 15, 10, 0, 0, 0, time.UTC)
+	view := newTUIView()
+	if rows := view.Rows(); rows != nil {
+		t.Errorf("Expected no rows before the first collection, got %v", rows)
+	}
+	if line := view.StatusLine(collectedAt); !strings.Contains(line, "采集中") {
+		t.Errorf("Expected the status line to show collection in progress, got %q", line)
+	}
+
+	view.SetResult(&CollectionResult{DiskData: diskData, CollectedAt: collectedAt})
+
+	names := func() []string {
+		var result []string
+		for _, row := range view.Rows() {
+			result = append(result, row[0])
+		}
+		return result
+	}
+
+	tests := []struct {
+		key    byte
+		action tuiAction
+		want   []string
+	}{
+		{'x', tuiNone, []string{"sda", "sdb", "sdc"}},    // 默认按名称升序
+		{'n', tuiRedraw, []string{"sdc", "sdb", "sda"}},  // 再按一次反向
+		{'t', tuiRedraw, []string{"sdb", "sdc", "sda"}},  // 温度默认从高到低，无温度排最后
+		{'t', tuiRedraw, []string{"sda", "sdc", "sdb"}},  // 反向
+		{'s', tuiRedraw, []string{"sda", "sdb", "sdc"}},  // 状态默认最严重在前，同级按名称
+		{'p', tuiRedraw, []string{"sda", "sdb", "sdc"}},  // 存储池升序
+		{'r', tuiRefresh, []string{"sda", "sdb", "sdc"}}, // 刷新不改变排序
+		{'q', tuiQuit, []string{"sda", "sdb", "sdc"}},
+	}
+	for _, tt := range tests {
+		if action := view.HandleKey(tt.key); action != tt.action {
+			t.Errorf("HandleKey(%q) = %v, want %v", tt.key, action, tt.action)
+		}
+		if got := names(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("After %q rows = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	// 行内容应包含温度和状态
+	row := view.Rows()[0]
+	if row[3] != "backup" || row[5] != string(model.DiskStatusWarning) {
+		t.Errorf("Unexpected row for sda: %v", row)
+	}
+
+	line := view.StatusLine(collectedAt.Add(90 * time.Second))
+	if !strings.Contains(line, "1m30s") || !strings.Contains(line, "存储池↑") {
+		t.Errorf("Unexpected status line: %q", line)
+	}
+}
+
+// syncBuffer 可在交互循环写入时并发读取的输出缓冲区
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// frames 返回已完整绘制的画面，每个画面以表格的底边结束
+func (b *syncBuffer) frames() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	frames := strings.Split(b.buf.String(), ansiClearScreen)
+	if last := frames[len(frames)-1]; !strings.HasSuffix(last, "+\n") {
+		frames = frames[:len(frames)-1]
+	}
+	return frames
+}
+
+// TestTUIReadOnly 测试交互模式刷新时不改写历史文件
+func TestTUIReadOnly(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'", "sda disk ST4000NM 4T\n")
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+
+	dir := t.TempDir()
+	config := model.NewDefaultConfig()
+	config.ControllerOnly = false
+	config.NoController = true
+	config.DataFile = filepath.Join(dir, "disk_data.json")
+	logger := system.NewMockLogger()
+
+	app := &Application{
+		Config:         config,
+		Logger:         logger,
+		CommandRunner:  mock,
+		DiskCollector:  collector.NewDiskCollector(config, logger, mock),
+		HistoryStorage: storage.NewDiskHistoryStorage(config.DataFile, logger),
+		Quiet:          true,
+	}
+
+	// 普通运行写入的历史数据
+	app.Collect(context.Background())
+	snapshot := func() map[string]string {
+		t.Helper()
+		files := make(map[string]string)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("Failed to list %s: %v", dir, err)
+		}
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", entry.Name(), err)
+			}
+			files[entry.Name()] = string(data)
+		}
+		return files
+	}
+	before := snapshot()
+
+	in, keys := io.Pipe()
+	defer keys.Close()
+	out := &syncBuffer{}
+	done := make(chan int)
+	go func() {
+		done <- app.runTUI(in, out, time.Hour)
+	}()
+
+	// 等待第from个画面之后出现满足条件的画面，返回已绘制的画面数
+	waitFrame := func(from int, match func(frame string) bool) int {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			frames := out.frames()
+			for i := from; i < len(frames); i++ {
+				if match(frames[i]) {
+					return i + 1
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Timed out waiting for the view to update")
+		return 0
+	}
+	collected := func(frame string) bool {
+		return strings.Contains(frame, "sda") && !strings.Contains(frame, "刷新中")
+	}
+	refreshing := func(frame string) bool {
+		return strings.Contains(frame, "刷新中")
+	}
+
+	// 首次采集后再按r刷新两次
+	seen := waitFrame(0, collected)
+	for i := 0; i < 2; i++ {
+		if _, err := keys.Write([]byte("r")); err != nil {
+			t.Fatalf("Failed to send key: %v", err)
+		}
+		seen = waitFrame(waitFrame(seen, refreshing), collected)
+	}
+	if _, err := keys.Write([]byte("q")); err != nil {
+		t.Fatalf("Failed to send key: %v", err)
+	}
+	if code := <-done; code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+
+	if after := snapshot(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the history files not to change:\nbefore: %v\nafter:  %v", before, after)
+	}
+}
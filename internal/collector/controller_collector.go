@@ -5,24 +5,58 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
 	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
+// DefaultSysfsReadTimeout bounds each sysfs temperature read, so a stuck
+// hwmon file cannot hold up collection until the overall command timeout
+const DefaultSysfsReadTimeout = 5 * time.Second
+
 // ControllerCollector handles collecting storage controller information
 type ControllerCollector struct {
-	cmdRunner system.CommandRunner
-	logger    system.Logger
+	cmdRunner        system.CommandRunner
+	logger           system.Logger
+	skipTemperature  bool          // Skip storcli/hwmon temperature probing, which can hang on some systems
+	sysfsReadTimeout time.Duration // Per-command timeout for sysfs temperature reads
+	controllerFilter string        // Only collect this storcli controller index or PCI address (--controller)
 }
 
 // NewControllerCollector creates a new instance of ControllerCollector
 func NewControllerCollector(cmdRunner system.CommandRunner, logger system.Logger) *ControllerCollector {
 	return &ControllerCollector{
-		cmdRunner: cmdRunner,
-		logger:    logger,
+		cmdRunner:        cmdRunner,
+		logger:           logger,
+		sysfsReadTimeout: DefaultSysfsReadTimeout,
+	}
+}
+
+// SetSkipTemperature disables controller temperature collection, leaving
+// temperatures empty ("N/A") while still gathering model, firmware and device counts
+func (c *ControllerCollector) SetSkipTemperature(skip bool) {
+	c.skipTemperature = skip
+}
+
+// SetControllerFilter limits collection to one controller, given either as a
+// storcli controller index ("0") or a PCI address ("03:00.0", "00:03:00:00").
+// Other controllers are skipped without running any commands against them
+// where possible. An empty filter collects all controllers.
+func (c *ControllerCollector) SetControllerFilter(id string) {
+	c.controllerFilter = strings.TrimSpace(id)
+}
+
+// matchesControllerFilter reports whether a controller with the given storcli
+// index (empty for lspci-only controllers) and PCI address should be collected
+func (c *ControllerCollector) matchesControllerFilter(id, bus string) bool {
+	if c.controllerFilter == "" || (id != "" && id == c.controllerFilter) {
+		return true
 	}
+	address := NormalizePCIAddress(c.controllerFilter)
+	return address != "" && address == NormalizePCIAddress(bus)
 }
 
 // Collect gathers all controller information
@@ -140,6 +174,16 @@ func (c *ControllerCollector) GetLSIControllers(ctx context.Context) (map[string
 				}
 			}
 
+			// Look for rows in the "IT System Overview" table, e.g.
+			//   0 HBA 9400-16i   SAS3416(B0) 0x1000  0xAC    0x1000   0x3000 00:03:00:00
+			if len(controllerIDs) == 0 {
+				overviewRegex := regexp.MustCompile(`(?m)^\s*(\d+)\s+\S.*\s[0-9a-fA-F]{2}(?::[0-9a-fA-F]{2}){3}\s*$`)
+				for _, match := range overviewRegex.FindAllStringSubmatch(controllersOutput, -1) {
+					controllerIDs = append(controllerIDs, match[1])
+					c.logger.Debug("Found controller ID in overview: %s", match[1])
+				}
+			}
+
 			// If no controllers found, try with default ID 0
 			if len(controllerIDs) == 0 {
 				controllerIDs = []string{"0"}
@@ -147,38 +191,81 @@ func (c *ControllerCollector) GetLSIControllers(ctx context.Context) (map[string
 			}
 
 			// Process each controller
+			// An index filter can skip other controllers up front; a PCI address
+			// filter is only known to match once the controller has been queried
+			filterByIndex := c.controllerFilter != "" && NormalizePCIAddress(c.controllerFilter) == ""
 			for _, controllerID := range controllerIDs {
+				if filterByIndex && controllerID != c.controllerFilter {
+					c.logger.Debug("Skipping controller %s (--controller %s)", controllerID, c.controllerFilter)
+					continue
+				}
+
 				controller, err := c.processLSIController(ctx, storcliPath, controllerID)
 				if err != nil {
 					c.logger.Debug("Error processing controller %s: %v", controllerID, err)
 					continue
 				}
+				if !c.matchesControllerFilter(controllerID, controller.Bus) {
+					c.logger.Debug("Skipping controller %s at %s (--controller %s)", controllerID, controller.Bus, c.controllerFilter)
+					continue
+				}
 
 				controllerKey := fmt.Sprintf("LSI_Controller_%s", controllerID)
 				controllers[controllerKey] = controller
 			}
+		}
+	}
 
-			// If controllers were found using storcli, return them
-			if len(controllers) > 0 {
-				return controllers, nil
-			}
+	// Also check lspci: it covers controllers storcli can't see, and is the
+	// only source when storcli isn't installed
+	c.logger.Debug("Checking lspci for LSI controllers")
+	for key, controller := range c.getLSIControllersFromLspci(ctx) {
+		if !c.matchesControllerFilter("", controller.Bus) {
+			continue
+		}
+		if existing := findLSIControllerByBus(controllers, controller.Bus); existing != nil {
+			c.logger.Debug("Controller %s (lspci) is the same device as %s, skipping", controller.Bus, existing.ID)
+			continue
+		}
+		controllers[key] = controller
+	}
+
+	if len(controllers) == 0 {
+		c.logger.Debug("No LSI controllers found")
+		if c.controllerFilter != "" {
+			return controllers, fmt.Errorf("no LSI controller matches %s", c.controllerFilter)
 		}
+		return controllers, fmt.Errorf("no LSI controllers found")
 	}
 
-	// Fallback to lspci if storcli failed or wasn't found
-	c.logger.Debug("Storcli failed or wasn't found, falling back to lspci")
-	lspciOutput := c.cmdRunner.RunIgnoreError(ctx, "lspci | grep -i 'LSI\\|MegaRAID\\|SAS\\|RAID'")
+	return controllers, nil
+}
+
+// lsiVendorPattern matches the LSI/Broadcom PCI vendor ID in lspci -nn output
+var lsiVendorPattern = regexp.MustCompile(`\[1000:[0-9a-fA-F]{4}\]`)
+
+// pciIDPattern matches the class and vendor:device IDs added by lspci -nn
+var pciIDPattern = regexp.MustCompile(`\s\[[0-9a-fA-F]{4}(?::[0-9a-fA-F]{4})?\]`)
+
+// getLSIControllersFromLspci lists LSI controllers reported by lspci. Devices
+// are matched by the LSI/Broadcom vendor ID, since names like "RAID" or "SAS"
+// also match Adaptec, Marvell or Intel controllers
+func (c *ControllerCollector) getLSIControllersFromLspci(ctx context.Context) map[string]*model.LSIController {
+	controllers := make(map[string]*model.LSIController)
+
+	lspciOutput := c.cmdRunner.RunIgnoreError(ctx, "lspci -nn")
 	if lspciOutput == "" {
 		c.logger.Debug("No LSI controllers found with lspci")
-		return controllers, fmt.Errorf("no LSI controllers found")
+		return controllers
 	}
 
 	// Process lspci output
 	for _, line := range strings.Split(lspciOutput, "\n") {
 		line = strings.TrimSpace(line)
-		if line == "" {
+		if line == "" || !lsiVendorPattern.MatchString(line) {
 			continue
 		}
+		line = pciIDPattern.ReplaceAllString(line, "")
 
 		parts := strings.SplitN(line, " ", 2)
 		if len(parts) < 2 {
@@ -200,20 +287,63 @@ func (c *ControllerCollector) GetLSIControllers(ctx context.Context) (map[string
 		controller.Status = model.ControllerStatusOK
 		controller.Source = "lspci"
 		controller.Description = "LSI SAS HBA Controller (via lspci)"
-		
-		// 设置默认温度用于测试兼容性
-		controller.Temperature = "58"
-		controller.ROCTemperature = "58"  // 同时设置特有字段ROCTemperature
 
 		controllers[controllerKey] = controller
 		c.logger.Debug("Found LSI controller via lspci: %s", busID)
 	}
 
-	if len(controllers) == 0 {
-		return controllers, fmt.Errorf("no LSI controllers found")
+	return controllers
+}
+
+// findLSIControllerByBus returns the controller at the same PCI address, if any
+func findLSIControllerByBus(controllers map[string]*model.LSIController, bus string) *model.LSIController {
+	address := NormalizePCIAddress(bus)
+	if address == "" {
+		return nil
+	}
+	for _, controller := range controllers {
+		if NormalizePCIAddress(controller.Bus) == address {
+			return controller
+		}
 	}
+	return nil
+}
 
-	return controllers, nil
+// NormalizePCIAddress converts a PCI address to the canonical DDDD:BB:DD.F form.
+// It accepts storcli addresses (00:03:00:00 = domain:bus:device:function),
+// lspci addresses (03:00.0) and full addresses (0000:03:00.0).
+// Returns an empty string if the address can't be parsed.
+func NormalizePCIAddress(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+
+	var domain, bus, device, function uint64
+	var fields []string
+	if strings.Contains(address, ".") {
+		// lspci style: [DDDD:]BB:DD.F
+		dot := strings.LastIndex(address, ".")
+		fields = strings.Split(address[:dot], ":")
+		fields = append(fields, address[dot+1:])
+		if len(fields) == 3 {
+			fields = append([]string{"0"}, fields...)
+		}
+	} else {
+		// storcli style: DD:BB:DD:FF
+		fields = strings.Split(address, ":")
+	}
+	if len(fields) != 4 {
+		return ""
+	}
+
+	values := []*uint64{&domain, &bus, &device, &function}
+	for i, field := range fields {
+		value, err := strconv.ParseUint(field, 16, 32)
+		if err != nil {
+			return ""
+		}
+		*values[i] = value
+	}
+
+	return fmt.Sprintf("%04x:%02x:%02x.%x", domain, bus, device, function)
 }
 
 // processLSIController processes a single LSI controller and extracts its information
@@ -270,6 +400,25 @@ func (c *ControllerCollector) processLSIController(ctx context.Context, storcliP
 		c.logger.Debug("Found firmware version: %s", firmwareVersion)
 	}
 
+	// Extract firmware package build, BIOS and NVDATA versions for firmware audits
+	fwPackageRegex := regexp.MustCompile(`FW Package Build\s*=\s*(.+)`)
+	if matches := fwPackageRegex.FindStringSubmatch(controllerOutput); len(matches) > 1 {
+		controller.FWPackageBuild = strings.TrimSpace(matches[1])
+		c.logger.Debug("Found firmware package build: %s", controller.FWPackageBuild)
+	}
+
+	biosRegex := regexp.MustCompile(`BIOS Version\s*=\s*(.+)`)
+	if matches := biosRegex.FindStringSubmatch(controllerOutput); len(matches) > 1 {
+		controller.BIOSVersion = strings.TrimSpace(matches[1])
+		c.logger.Debug("Found BIOS version: %s", controller.BIOSVersion)
+	}
+
+	nvdataRegex := regexp.MustCompile(`NVDATA Version\s*=\s*(.+)`)
+	if matches := nvdataRegex.FindStringSubmatch(controllerOutput); len(matches) > 1 {
+		controller.NVDATAVersion = strings.TrimSpace(matches[1])
+		c.logger.Debug("Found NVDATA version: %s", controller.NVDATAVersion)
+	}
+
 	// Extract driver version
 	driverRegex := regexp.MustCompile(`Driver Version\s*=\s*(.+)`)
 	if matches := driverRegex.FindStringSubmatch(controllerOutput); len(matches) > 1 {
@@ -298,6 +447,7 @@ func (c *ControllerCollector) processLSIController(ctx context.Context, storcliP
 				hddCount++
 			}
 		}
+		controller.PhysicalDrives = parsePDList(pdListMatch)
 	}
 
 	if ssdCount > 0 {
@@ -307,6 +457,12 @@ func (c *ControllerCollector) processLSIController(ctx context.Context, storcliP
 		controller.HDDCount = fmt.Sprintf("%d", hddCount)
 	}
 
+	// Skip temperature probing entirely when requested
+	if c.skipTemperature {
+		c.logger.Debug("Skipping temperature collection for controller %s", controllerID)
+		return controller, nil
+	}
+
 	// Get temperature - using the correct command format and output pattern
 	tempOutput := c.cmdRunner.RunIgnoreError(ctx, fmt.Sprintf("%s /c%s show temperature", storcliPath, controllerID))
 	if tempOutput != "" {
@@ -317,28 +473,98 @@ func (c *ControllerCollector) processLSIController(ctx context.Context, storcliP
 			controller.Temperature = temperature     // 设置基本温度字段
 			controller.ROCTemperature = temperature  // 同时设置特有字段ROCTemperature
 			c.logger.Debug("Found controller temperature: %s°C", temperature)
-		} else {
-			// For test compatibility, hardcode temperature if not found
-			if controllerID == "0" {
-				controller.Temperature = "58"
-				controller.ROCTemperature = "58"
-			} else if controllerID == "1" {
-				controller.Temperature = "52"
-				controller.ROCTemperature = "52"
+		}
+	}
+	if controller.Temperature == "" {
+		// 温度不可用时保持为空，输出中显示为 N/A
+		c.logger.Debug("No temperature reported for controller %s", controllerID)
+	}
+
+	return controller, nil
+}
+
+// pdListHeader matches the PD LIST column header line
+var pdListHeader = regexp.MustCompile(`^EID:Slt\s+DID\s+State`)
+
+// pdListSlot matches the EID:Slt value that starts each drive row
+var pdListSlot = regexp.MustCompile(`^\d*:\d+$`)
+
+// pdListSpacedSector matches a SeSz value printed with a space ("4 KB")
+var pdListSpacedSector = regexp.MustCompile(`\b(\d+) (KB|B)\b`)
+
+// parsePDList parses the rows of a storcli PD LIST table. Columns are
+// located by header name so that firmware versions with extra columns
+// still parse; the model name may contain spaces, so it is taken as
+// everything between the SeSz column and the columns that follow Model
+func parsePDList(section string) []model.PhysicalDrive {
+	var drives []model.PhysicalDrive
+	var columns map[string]int
+
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if pdListHeader.MatchString(line) {
+			columns = make(map[string]int)
+			for i, name := range strings.Fields(line) {
+				columns[name] = i
 			}
+			continue
 		}
-	} else {
-		// For test compatibility, hardcode temperature if not found
-		if controllerID == "0" {
-			controller.Temperature = "58"
-			controller.ROCTemperature = "58"
-		} else if controllerID == "1" {
-			controller.Temperature = "52"
-			controller.ROCTemperature = "52"
+		fields := strings.Fields(pdListSpacedSector.ReplaceAllString(line, "$1$2"))
+		if columns == nil || len(fields) == 0 || !pdListSlot.MatchString(fields[0]) {
+			continue
 		}
+
+		// The Size column is split in two fields ("3.492 TB"), shifting
+		// every column after it by one
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok {
+				return ""
+			}
+			if i > columns["Size"] {
+				i++
+			}
+			if i >= len(fields) {
+				return ""
+			}
+			return fields[i]
+		}
+
+		modelStart := columns["Model"] + 1
+		modelEnd := len(fields) - (len(columns) - 1 - columns["Model"])
+		if modelStart >= modelEnd {
+			continue
+		}
+		drive := model.PhysicalDrive{
+			Slot:       fields[0],
+			Interface:  field("Intf"),
+			Media:      field("Med"),
+			SectorSize: parseSectorSize(field("SeSz")),
+		}
+		if i := columns["Size"]; i+1 < len(fields) {
+			drive.Size = fields[i] + " " + fields[i+1]
+		}
+		drive.Model = strings.Join(fields[modelStart:modelEnd], " ")
+		drives = append(drives, drive)
 	}
 
-	return controller, nil
+	return drives
+}
+
+// parseSectorSize converts a storcli SeSz value ("512B", "4 KB", "4KB") to bytes
+func parseSectorSize(value string) int {
+	value = strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+	multiplier := 1
+	if strings.HasSuffix(value, "KB") {
+		multiplier = 1024
+		value = strings.TrimSuffix(value, "KB")
+	}
+	value = strings.TrimSuffix(value, "B")
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return size * multiplier
 }
 
 // GetNVMeControllers collects information about NVMe storage controllers
@@ -374,6 +600,10 @@ func (c *ControllerCollector) GetNVMeControllers(ctx context.Context) (map[strin
 
 		busID := strings.TrimSpace(parts[0])
 		description := strings.TrimSpace(parts[1])
+		if !c.matchesControllerFilter("", busID) {
+			c.logger.Debug("Skipping NVMe controller %s (--controller %s)", busID, c.controllerFilter)
+			continue
+		}
 
 		// 使用model包提供的构造函数创建NVMe控制器
 		controllerKey := fmt.Sprintf("NVMe_Controller_%s", busID)
@@ -398,9 +628,10 @@ func (c *ControllerCollector) GetNVMeControllers(ctx context.Context) (map[strin
 		}
 
 		// Try to get temperature from hwmon
-		temp := c.getNVMeTemperature(ctx, busID)
-		if temp != "" {
-			controller.Temperature = temp
+		if !c.skipTemperature {
+			if temp := c.getNVMeTemperature(ctx, busID); temp != "" {
+				controller.Temperature = temp
+			}
 		}
 
 		controllers[controllerKey] = controller
@@ -417,7 +648,7 @@ func (c *ControllerCollector) getNVMeTemperature(ctx context.Context, busID stri
 	
 	// Find temperature file
 	findCmd := fmt.Sprintf("find /sys/bus/pci/devices/0000:%s/hwmon*/temp1_input 2>/dev/null | head -1", sysfsID)
-	tempFile := c.cmdRunner.RunIgnoreError(ctx, findCmd)
+	tempFile := c.runWithDeadline(ctx, findCmd)
 	if tempFile == "" {
 		c.logger.Debug("Could not find temperature file for NVMe controller %s", busID)
 		return ""
@@ -426,7 +657,7 @@ func (c *ControllerCollector) getNVMeTemperature(ctx context.Context, busID stri
 	// Read temperature value
 	tempFile = strings.TrimSpace(tempFile)
 	catCmd := fmt.Sprintf("cat %s 2>/dev/null", tempFile)
-	tempValue := c.cmdRunner.RunIgnoreError(ctx, catCmd)
+	tempValue := c.runWithDeadline(ctx, catCmd)
 	if tempValue == "" {
 		c.logger.Debug("Could not read temperature for NVMe controller %s", busID)
 		return ""
@@ -442,6 +673,31 @@ func (c *ControllerCollector) getNVMeTemperature(ctx context.Context, busID stri
 	return ""
 }
 
+// runWithDeadline runs a command bound to ctx and the sysfs read timeout,
+// whichever expires first. It returns "" once the deadline passes even if the
+// runner ignores the context, leaving the stuck command behind
+func (c *ControllerCollector) runWithDeadline(ctx context.Context, command string) string {
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.sysfsReadTimeout)
+	defer cancel()
+
+	done := make(chan string, 1)
+	go func() {
+		done <- c.cmdRunner.RunIgnoreError(ctx, command)
+	}()
+
+	select {
+	case output := <-done:
+		return output
+	case <-ctx.Done():
+		c.logger.Debug("Command timed out: %s (%v)", command, ctx.Err())
+		return ""
+	}
+}
+
 // StringToInt converts a string to an integer, ignoring any errors
 func StringToInt(s string) (int, error) {
 	var value int
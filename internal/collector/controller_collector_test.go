@@ -9,12 +9,14 @@ import (
 	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
 // MockCommandRunner is a mock implementation of the CommandRunner interface
 type MockCommandRunner struct {
 	responses map[string]string
 	errors    map[string]error
+	commands  []string // Commands executed, in call order
 }
 
 // NewMockCommandRunner creates a new instance of MockCommandRunner
@@ -27,6 +29,7 @@ func NewMockCommandRunner() *MockCommandRunner {
 
 // Run executes a mock command
 func (m *MockCommandRunner) Run(ctx context.Context, command string) (string, error) {
+	m.commands = append(m.commands, command)
 	if err, ok := m.errors[command]; ok && err != nil {
 		return "", err
 	}
@@ -49,6 +52,11 @@ func (m *MockCommandRunner) RunWithTimeout(command string, timeout time.Duration
 	return m.Run(ctx, command)
 }
 
+// RunWithStatus executes a mock command and returns its exit code
+func (m *MockCommandRunner) RunWithStatus(ctx context.Context, command string) (string, int, error) {
+	return system.SplitExitStatus(m.Run(ctx, command))
+}
+
 // SetResponse sets a mock response for a command
 func (m *MockCommandRunner) SetResponse(command, response string) {
 	m.responses[command] = response
@@ -173,16 +181,16 @@ EID:Slt DID State DG       Size Intf Med SED PI SeSz Model            Sp
 -------------------------------------------------------------------------
 `)
 	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show temperature", `
-ROC temperature(Degree Celsius) = 58
+ROC temperature(Degree Celsius) 58
 `)
 	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c1 show temperature", `
-ROC temperature(Degree Celsius) = 52
+ROC temperature(Degree Celsius) 52
 `)
 
 	// Set up fallback lspci response
-	cmdRunner.SetResponse("lspci | grep -i 'LSI\\|MegaRAID\\|SAS\\|RAID'", `
-03:00.0 RAID bus controller: LSI Logic / Symbios Logic MegaRAID SAS 2208 [Thunderbolt] (rev 05)
-04:00.0 Serial Attached SCSI controller: LSI Logic / Symbios Logic SAS2308 PCI-Express Fusion-MPT SAS-2 (rev 05)
+	cmdRunner.SetResponse("lspci -nn", `
+03:00.0 RAID bus controller [0104]: LSI Logic / Symbios Logic MegaRAID SAS 2208 [Thunderbolt] [1000:005b] (rev 05)
+04:00.0 Serial Attached SCSI controller [0107]: LSI Logic / Symbios Logic SAS2308 PCI-Express Fusion-MPT SAS-2 [1000:0087] (rev 05)
 `)
 
 	// Create the controller collector
@@ -262,8 +270,8 @@ ROC temperature(Degree Celsius) = 52
 	cmdRunner.SetResponse("which storcli64 2>/dev/null", "")
 	cmdRunner.SetResponse("command -v storcli64 >/dev/null 2>&1 && echo 'exists'", "")
 	cmdRunner.SetResponse("command -v storcli >/dev/null 2>&1 && echo 'exists'", "")
-	cmdRunner.SetResponse("lspci | grep -i 'LSI\\|MegaRAID\\|SAS\\|RAID'", `
-03:00.0 RAID bus controller: LSI Logic / Symbios Logic MegaRAID SAS 2208 [Thunderbolt] (rev 05)
+	cmdRunner.SetResponse("lspci -nn", `
+03:00.0 RAID bus controller [0104]: LSI Logic / Symbios Logic MegaRAID SAS 2208 [Thunderbolt] [1000:005b] (rev 05)
 `)
 
 	collector = NewControllerCollector(cmdRunner, logger)
@@ -423,7 +431,7 @@ Driver Version = 43.100.00.00
 Physical Drives = 14
 `)
 
-	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show temperature", `ROC temperature(Degree Celsius) = 58`)
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show temperature", `ROC temperature(Degree Celsius) 58`)
 
 	cmdRunner.SetResponse("command -v lspci >/dev/null 2>&1 && echo 'exists'", "exists")
 	cmdRunner.SetResponse("lspci | grep -i 'nvme\\|non-volatile memory'", `01:00.0 Non-Volatile memory controller: Samsung Electronics Co Ltd NVMe SSD Controller 980 PRO`)
@@ -451,7 +459,7 @@ Physical Drives = 14
 	cmdRunner.SetResponse("which storcli64 2>/dev/null", "")
 	cmdRunner.SetResponse("command -v storcli64 >/dev/null 2>&1 && echo 'exists'", "")
 	cmdRunner.SetResponse("command -v storcli >/dev/null 2>&1 && echo 'exists'", "")
-	cmdRunner.SetResponse("lspci | grep -i 'LSI\\|MegaRAID\\|SAS\\|RAID'", "")
+	cmdRunner.SetResponse("lspci -nn", "")
 
 	cmdRunner.SetResponse("command -v lspci >/dev/null 2>&1 && echo 'exists'", "exists")
 	cmdRunner.SetResponse("lspci | grep -i 'nvme\\|non-volatile memory'", `01:00.0 Non-Volatile memory controller: Samsung`)
@@ -476,7 +484,7 @@ Physical Drives = 14
 	cmdRunner.SetResponse("which storcli64 2>/dev/null", "")
 	cmdRunner.SetResponse("command -v storcli64 >/dev/null 2>&1 && echo 'exists'", "")
 	cmdRunner.SetResponse("command -v storcli >/dev/null 2>&1 && echo 'exists'", "")
-	cmdRunner.SetResponse("lspci | grep -i 'LSI\\|MegaRAID\\|SAS\\|RAID'", "")
+	cmdRunner.SetResponse("lspci -nn", "")
 
 	cmdRunner.SetResponse("command -v lspci >/dev/null 2>&1 && echo 'exists'", "")
 
@@ -489,3 +497,433 @@ Physical Drives = 14
 		t.Errorf("Expected empty controller maps when both collections fail")
 	}
 }
+
+func TestNormalizePCIAddress(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"00:03:00:00", "0000:03:00.0"}, // storcli
+		{"03:00.0", "0000:03:00.0"},     // lspci
+		{"0000:03:00.0", "0000:03:00.0"},
+		{"0000:AF:00.1", "0000:af:00.1"},
+		{"00:af:00:01", "0000:af:00.1"},
+		{"", ""},
+		{"invalid", ""},
+	}
+
+	for _, tc := range testCases {
+		if result := NormalizePCIAddress(tc.input); result != tc.expected {
+			t.Errorf("NormalizePCIAddress(%q): expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+}
+
+func TestControllerCollector_MergeStorcliAndLspci(t *testing.T) {
+	cmdRunner := NewMockCommandRunner()
+	logger := &MockLogger{}
+
+	// storcli and lspci both report the controller at PCI address 03:00.0
+	cmdRunner.SetResponse("which storcli 2>/dev/null", "/usr/local/sbin/storcli")
+	cmdRunner.SetResponse("/usr/local/sbin/storcli show", `
+Number of Controllers = 1
+
+---------------------------------------------------------------------------
+Ctl Model        AdapterType   VendId DevId SubVendId SubDevId PCI Address
+---------------------------------------------------------------------------
+  0 HBA 9400-16i   SAS3416(B0) 0x1000  0xAC    0x1000   0x3000 00:03:00:00
+---------------------------------------------------------------------------
+`)
+	cmdRunner.SetResponse("/usr/local/sbin/storcli /c0 show", `
+Controller = 0
+Product Name = HBA 9400-16i
+PCI Address = 00:03:00:00
+FW Version = 24.00.00.00
+`)
+	cmdRunner.SetResponse("lspci -nn", `
+03:00.0 Serial Attached SCSI controller [0107]: Broadcom / LSI SAS3416 Fusion-MPT Tri-Mode I/O Controller Chip (IOC) [1000:00ac] (rev 01)
+`)
+
+	collector := NewControllerCollector(cmdRunner, logger)
+	controllers, err := collector.GetLSIControllers(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(controllers) != 1 {
+		t.Fatalf("Expected a single merged controller, got: %d", len(controllers))
+	}
+	controller, ok := controllers["LSI_Controller_0"]
+	if !ok {
+		t.Fatalf("Expected storcli controller LSI_Controller_0 to be kept, got: %v", controllers)
+	}
+	if controller.Source != "storcli" {
+		t.Errorf("Expected controller source 'storcli', got '%s'", controller.Source)
+	}
+}
+
+func TestControllerCollector_LspciNonLSIControllers(t *testing.T) {
+	cmdRunner := NewMockCommandRunner()
+	logger := &MockLogger{}
+
+	// No storcli; lspci lists RAID and SAS controllers from several vendors
+	cmdRunner.SetResponse("which storcli 2>/dev/null", "")
+	cmdRunner.SetResponse("which storcli64 2>/dev/null", "")
+	cmdRunner.SetResponse("lspci -nn", `
+00:17.0 RAID bus controller [0104]: Intel Corporation SATA Controller [RAID mode] [8086:2822]
+01:00.0 Serial Attached SCSI controller [0107]: Broadcom / LSI SAS3008 PCI-Express Fusion-MPT SAS-3 [1000:0097] (rev 02)
+02:00.0 RAID bus controller [0104]: Adaptec Smart Storage PQI SAS [9005:028f] (rev 01)
+05:00.0 SATA controller [0106]: Marvell Technology Group Ltd. 88SE9230 PCIe SATA 6Gb/s Controller [1b4b:9230] (rev 11)
+06:00.0 Ethernet controller [0200]: Intel Corporation I210 Gigabit Network Connection [8086:1533] (rev 03)
+`)
+
+	collector := NewControllerCollector(cmdRunner, logger)
+	controllers, err := collector.GetLSIControllers(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(controllers) != 1 {
+		t.Fatalf("Expected only the LSI controller, got: %v", controllers)
+	}
+	controller, ok := controllers["LSI_Controller_01:00.0"]
+	if !ok {
+		t.Fatalf("Expected LSI_Controller_01:00.0, got: %v", controllers)
+	}
+	expectedModel := "Serial Attached SCSI controller: Broadcom / LSI SAS3008 PCI-Express Fusion-MPT SAS-3 (rev 02)"
+	if controller.Model != expectedModel {
+		t.Errorf("Expected model '%s', got '%s'", expectedModel, controller.Model)
+	}
+}
+
+func TestControllerCollector_ControllerFilter(t *testing.T) {
+	// Two storcli controllers, at PCI addresses 03:00.0 and 81:00.0
+	newRunner := func() *MockCommandRunner {
+		cmdRunner := NewMockCommandRunner()
+		cmdRunner.SetResponse("which storcli 2>/dev/null", "/usr/local/sbin/storcli")
+		cmdRunner.SetResponse("/usr/local/sbin/storcli show", `
+Number of Controllers = 2
+
+---------------------------------------------------------------------------
+Ctl Model        AdapterType   VendId DevId SubVendId SubDevId PCI Address
+---------------------------------------------------------------------------
+  0 HBA 9400-16i   SAS3416(B0) 0x1000  0xAC    0x1000   0x3000 00:03:00:00
+  1 HBA 9300-8i    SAS3008(C0) 0x1000  0x97    0x1000   0x30E0 00:81:00:00
+---------------------------------------------------------------------------
+`)
+		cmdRunner.SetResponse("/usr/local/sbin/storcli /c0 show", `
+Controller = 0
+Product Name = HBA 9400-16i
+PCI Address = 00:03:00:00
+`)
+		cmdRunner.SetResponse("/usr/local/sbin/storcli /c1 show", `
+Controller = 1
+Product Name = HBA 9300-8i
+PCI Address = 00:81:00:00
+`)
+		return cmdRunner
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected string
+	}{
+		{"storcli index", "1", "LSI_Controller_1"},
+		{"lspci address", "81:00.0", "LSI_Controller_1"},
+		{"storcli address", "00:03:00:00", "LSI_Controller_0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmdRunner := newRunner()
+			collector := NewControllerCollector(cmdRunner, &MockLogger{})
+			collector.SetSkipTemperature(true)
+			collector.SetControllerFilter(tt.filter)
+
+			controllers, err := collector.GetLSIControllers(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(controllers) != 1 {
+				t.Fatalf("Expected only the requested controller, got: %v", controllers)
+			}
+			if _, ok := controllers[tt.expected]; !ok {
+				t.Errorf("Expected controller %s, got: %v", tt.expected, controllers)
+			}
+		})
+	}
+
+	// An index filter must not run any commands against the other controller
+	cmdRunner := newRunner()
+	collector := NewControllerCollector(cmdRunner, &MockLogger{})
+	collector.SetSkipTemperature(true)
+	collector.SetControllerFilter("1")
+	if _, err := collector.GetLSIControllers(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, command := range cmdRunner.commands {
+		if strings.Contains(command, "/c0") {
+			t.Errorf("Expected controller 0 to be skipped, but ran: %s", command)
+		}
+	}
+
+	// A filter that matches nothing is an error
+	collector = NewControllerCollector(newRunner(), &MockLogger{})
+	collector.SetControllerFilter("5")
+	if _, err := collector.GetLSIControllers(context.Background()); err == nil {
+		t.Error("Expected an error when no controller matches the filter")
+	}
+}
+
+func TestControllerCollector_ProcessLSIControllerVersions(t *testing.T) {
+	cmdRunner := NewMockCommandRunner()
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show", `
+CLI Version = 007.2807.0000.0000 Dec 22, 2023
+Operating system = Linux 6.6.44-production+truenas
+Controller = 0
+Status = Success
+Description = None
+
+Product Name = HBA 9400-16i
+Board Name = HBA 9400-16i
+Board Assembly = 03-50008-15005
+Board Tracer Number = SP81928512
+Board Revision = 00005
+Chip Name = SAS3416
+Chip Revision = B0
+Package Build = 24.00.00.00
+FW Package Build = 24.00.00.00
+FW Version = 24.00.00.00
+BIOS Version = 09.47.00.00_24.00.00.00
+NVDATA Version = 24.00.00.24
+Driver Name = mpt3sas
+Driver Version = 43.100.00.00
+SAS Address = 500605b00f0c1a20
+Serial Number = SP81928512
+Controller Time(LocalTime yyyy/mm/dd hh:mm:sec) = 2024/09/15 10:21:03
+System Time = 2024/09/15 10:21:03
+Board Mfg Date(yyyy/mm/dd) = 2021/03/10
+Controller Personality = HBA
+Max PCIe Link Rate = 0x08 (8GT/s)
+Max PCIe Port Width = 8
+PCI Address = 00:03:00:00
+SAS Width = 16
+Physical Drives = 14
+`)
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show temperature", `
+--------------------------------------
+Ctrl_Prop                       Value
+--------------------------------------
+ROC temperature(Degree Celsius) 61
+--------------------------------------
+`)
+
+	collector := NewControllerCollector(cmdRunner, &MockLogger{})
+	controller, err := collector.processLSIController(context.Background(), "/usr/local/sbin/storcli64", "0")
+	if err != nil {
+		t.Fatalf("processLSIController failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"Model":           "HBA 9400-16i",
+		"FWPackageBuild":  "24.00.00.00",
+		"FirmwareVersion": "24.00.00.00",
+		"BIOSVersion":     "09.47.00.00_24.00.00.00",
+		"NVDATAVersion":   "24.00.00.24",
+		"DriverVersion":   "43.100.00.00",
+		"Temperature":     "61",
+	}
+	actual := map[string]string{
+		"Model":           controller.Model,
+		"FWPackageBuild":  controller.FWPackageBuild,
+		"FirmwareVersion": controller.FirmwareVersion,
+		"BIOSVersion":     controller.BIOSVersion,
+		"NVDATAVersion":   controller.NVDATAVersion,
+		"DriverVersion":   controller.DriverVersion,
+		"Temperature":     controller.Temperature,
+	}
+	for field, want := range expected {
+		if actual[field] != want {
+			t.Errorf("Expected %s %q, got %q", field, want, actual[field])
+		}
+	}
+}
+
+func TestControllerCollector_SkipTemperature(t *testing.T) {
+	cmdRunner := NewMockCommandRunner()
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show", `
+Product Name = HBA 9400-16i
+FW Version = 24.00.00.00
+Driver Version = 43.100.00.00
+`)
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show temperature", `
+ROC temperature(Degree Celsius) 61
+`)
+	cmdRunner.SetResponse("command -v lspci >/dev/null 2>&1 && echo 'exists'", "exists")
+	cmdRunner.SetResponse("lspci | grep -i 'nvme\\|non-volatile memory'", `
+01:00.0 Non-Volatile memory controller: Samsung Electronics Co Ltd NVMe SSD Controller 980 PRO
+`)
+	cmdRunner.SetResponse("find /sys/bus/pci/devices/0000:01:00.0/hwmon*/temp1_input 2>/dev/null | head -1", "/sys/bus/pci/devices/0000:01:00.0/hwmon0/temp1_input")
+	cmdRunner.SetResponse("cat /sys/bus/pci/devices/0000:01:00.0/hwmon0/temp1_input 2>/dev/null", "42000")
+
+	collector := NewControllerCollector(cmdRunner, &MockLogger{})
+	collector.SetSkipTemperature(true)
+
+	lsi, err := collector.processLSIController(context.Background(), "/usr/local/sbin/storcli64", "0")
+	if err != nil {
+		t.Fatalf("processLSIController failed: %v", err)
+	}
+	if lsi.Model != "HBA 9400-16i" || lsi.FirmwareVersion != "24.00.00.00" {
+		t.Errorf("Expected model and firmware to be collected, got %q/%q", lsi.Model, lsi.FirmwareVersion)
+	}
+	if lsi.Temperature != "" {
+		t.Errorf("Expected empty LSI temperature, got %q", lsi.Temperature)
+	}
+
+	nvme, err := collector.GetNVMeControllers(context.Background())
+	if err != nil {
+		t.Fatalf("GetNVMeControllers failed: %v", err)
+	}
+	if temp := nvme["NVMe_Controller_01:00.0"].Temperature; temp != "" {
+		t.Errorf("Expected empty NVMe temperature, got %q", temp)
+	}
+
+	for _, command := range cmdRunner.commands {
+		if strings.Contains(command, "show temperature") || strings.Contains(command, "hwmon") {
+			t.Errorf("Expected no temperature commands, got %q", command)
+		}
+	}
+}
+
+func TestControllerCollector_NoTemperatureOutput(t *testing.T) {
+	cmdRunner := NewMockCommandRunner()
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show", `
+Product Name = HBA 9400-16i
+FW Version = 24.00.00.00
+`)
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c1 show", `
+Product Name = HBA 9300-8i
+FW Version = 16.00.01.00
+`)
+	// storcli 不输出温度行时不应伪造温度；c1 完全没有温度输出
+	cmdRunner.SetResponse("/usr/local/sbin/storcli64 /c0 show temperature", `
+Controller = 0
+Status = Success
+`)
+
+	collector := NewControllerCollector(cmdRunner, &MockLogger{})
+	for _, id := range []string{"0", "1"} {
+		controller, err := collector.processLSIController(context.Background(), "/usr/local/sbin/storcli64", id)
+		if err != nil {
+			t.Fatalf("processLSIController(%s) failed: %v", id, err)
+		}
+		if controller.Temperature != "" || controller.ROCTemperature != "" {
+			t.Errorf("Controller %s: expected no temperature, got %q/%q", id, controller.Temperature, controller.ROCTemperature)
+		}
+		if display := controller.GetDisplayTemperature(); display != "N/A" {
+			t.Errorf("Controller %s: expected display temperature N/A, got %q", id, display)
+		}
+	}
+}
+
+// blockingCommandRunner blocks on "cat" commands to simulate a stuck sysfs read
+type blockingCommandRunner struct {
+	*MockCommandRunner
+	honorContext bool          // Return when the context is cancelled
+	cancelled    chan struct{} // Closed once a blocked command saw its context cancelled
+	release      chan struct{} // Unblocks commands that ignore the context
+}
+
+// RunIgnoreError blocks "cat" commands until cancelled or released
+func (b *blockingCommandRunner) RunIgnoreError(ctx context.Context, command string) string {
+	if !strings.HasPrefix(command, "cat ") {
+		return b.MockCommandRunner.RunIgnoreError(ctx, command)
+	}
+	if b.honorContext {
+		<-ctx.Done()
+		close(b.cancelled)
+		return ""
+	}
+	<-b.release
+	return "42000"
+}
+
+func TestControllerCollector_NVMeTemperatureTimeout(t *testing.T) {
+	for _, honorContext := range []bool{true, false} {
+		mock := NewMockCommandRunner()
+		mock.SetResponse("find /sys/bus/pci/devices/0000:01.00.0/hwmon*/temp1_input 2>/dev/null | head -1", "/sys/bus/pci/devices/0000:01:00.0/hwmon0/temp1_input")
+		runner := &blockingCommandRunner{
+			MockCommandRunner: mock,
+			honorContext:      honorContext,
+			cancelled:         make(chan struct{}),
+			release:           make(chan struct{}),
+		}
+
+		collector := NewControllerCollector(runner, &MockLogger{})
+		collector.sysfsReadTimeout = 50 * time.Millisecond
+
+		start := time.Now()
+		temp := collector.getNVMeTemperature(context.Background(), "01:00.0")
+		elapsed := time.Since(start)
+		close(runner.release)
+
+		if temp != "" {
+			t.Errorf("honorContext=%v: expected no temperature from a stuck read, got %q", honorContext, temp)
+		}
+		if elapsed > time.Second {
+			t.Errorf("honorContext=%v: expected read to bail promptly, took %v", honorContext, elapsed)
+		}
+		if honorContext {
+			select {
+			case <-runner.cancelled:
+			case <-time.After(time.Second):
+				t.Errorf("Expected the blocked cat command to be cancelled")
+			}
+		}
+	}
+
+	// No commands run once the context is already cancelled
+	mock := NewMockCommandRunner()
+	collector := NewControllerCollector(mock, &MockLogger{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if temp := collector.getNVMeTemperature(ctx, "01:00.0"); temp != "" || len(mock.commands) != 0 {
+		t.Errorf("Expected no commands after cancellation, got %q and %v", temp, mock.commands)
+	}
+}
+
+func TestParsePDList_SectorSizes(t *testing.T) {
+	section := `PD LIST :
+=======
+
+---------------------------------------------------------------------------------
+EID:Slt DID State DG       Size Intf Med SED PI SeSz Model                   Sp Type
+---------------------------------------------------------------------------------
+0:2       7 JBOD  -    3.492 TB SAS  SSD -   -  512B PA33N3T8 EMC3840        -  -
+0:3       6 JBOD  -   14.551 TB SAS  HDD -   -  4 KB HGST HUH721010AL4200    -  -
+0:4       3 JBOD  -   14.551 TB SAS  HDD -   -  4KB  ST16000NM002G           -  -
+---------------------------------------------------------------------------------
+`
+	drives := parsePDList(section)
+	if len(drives) != 3 {
+		t.Fatalf("Expected 3 drives, got %d: %+v", len(drives), drives)
+	}
+
+	expected := []model.PhysicalDrive{
+		{Slot: "0:2", Size: "3.492 TB", Interface: "SAS", Media: "SSD", SectorSize: 512, Model: "PA33N3T8 EMC3840"},
+		{Slot: "0:3", Size: "14.551 TB", Interface: "SAS", Media: "HDD", SectorSize: 4096, Model: "HGST HUH721010AL4200"},
+		{Slot: "0:4", Size: "14.551 TB", Interface: "SAS", Media: "HDD", SectorSize: 4096, Model: "ST16000NM002G"},
+	}
+	for i, want := range expected {
+		if drives[i] != want {
+			t.Errorf("drive %d: expected %+v, got %+v", i, want, drives[i])
+		}
+	}
+
+	controller := model.NewLSIController("LSI_Controller_0")
+	controller.PhysicalDrives = drives
+	if got := controller.GetDisplaySectorSizes(); got != "512B×1, 4096B×2" {
+		t.Errorf("Expected '512B×1, 4096B×2', got '%s'", got)
+	}
+}
@@ -3,17 +3,32 @@ package collector
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
-	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/storage"
 	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
+// 纠错计数增长告警参数
+const (
+	// correctedErrorMinDelta 两次运行之间纠错计数的最小增量，低于该值不告警
+	correctedErrorMinDelta = 1000
+	// correctedErrorRateFactor 区间纠错速率超过生命周期平均速率的倍数时告警
+	correctedErrorRateFactor = 10.0
+)
+
+// pathPowerOnHoursTolerance 同一序列号的多个路径报告的通电时间允许的最大差值(小时)
+// 各路径在同一次运行中读取，超过该值说明设备名与磁盘的对应关系有误
+const pathPowerOnHoursTolerance = 24
+
 // DiskCollector 实现磁盘信息收集
 type DiskCollector struct {
 	config         *model.Config
@@ -21,6 +36,8 @@ type DiskCollector struct {
 	commandRunner  system.CommandRunner
 	smartCollector *SMARTCollector
 	poolCollector  *PoolCollector
+	history        *storage.DiskHistoryStorage // 历史数据文件，读写加锁且原子替换
+	skipHistory    bool                        // 不读写历史数据文件(回放记录时使用)
 }
 
 // NewDiskCollector 创建一个新的磁盘收集器
@@ -34,14 +51,27 @@ func NewDiskCollector(config *model.Config, logger system.Logger, runner system.
 		commandRunner:  runner,
 		smartCollector: smartCollector,
 		poolCollector:  poolCollector,
+		history:        storage.NewDiskHistoryStorage(config.DataFile, logger),
 	}
 }
 
+// SetHistoryStorage 设置历史数据存储
+// 应用与收集器共用同一个存储，写入使用同一把锁，时间戳使用同一个时钟
+func (d *DiskCollector) SetHistoryStorage(history *storage.DiskHistoryStorage) {
+	d.history = history
+}
+
+// SetSkipHistory 设置是否跳过历史数据文件的读取和保存
+// 回放记录时本机的历史数据与记录无关，读取会产生不同的增量，保存会污染本机历史
+func (d *DiskCollector) SetSkipHistory(skip bool) {
+	d.skipHistory = skip
+}
+
 // Collect 收集所有磁盘信息
 func (d *DiskCollector) Collect(ctx context.Context) (*model.DiskData, error) {
 	// 创建磁盘数据对象
 	diskData := model.NewDiskData()
-	var collectionErrors []error
+	collectionErr := &CollectionError{}
 
 	// 获取磁盘列表
 	disks, err := d.GetDisksFromMidclt(ctx)
@@ -50,36 +80,89 @@ func (d *DiskCollector) Collect(ctx context.Context) (*model.DiskData, error) {
 		disks, err = d.GetDisksFromLsblk(ctx)
 		if err != nil || len(disks) == 0 {
 			d.logger.Error("无法获取磁盘列表: %v", err)
-			collectionErrors = append(collectionErrors, fmt.Errorf("disk list collection failed: %w", err))
+			if err == nil {
+				err = fmt.Errorf("no disks found")
+			}
+			// 没有磁盘列表无法继续收集
+			collectionErr.add(StageList, err)
+			return diskData, collectionErr
 		}
 	}
 
-	// 如果无法获取磁盘列表，返回错误
+	// 补充disk.query没有返回的启动盘
+	if d.config.IncludeBoot {
+		disks = d.addBootDisks(ctx, disks)
+	}
+
+	// 只保留包含列表中的磁盘，再限制处理的磁盘数量
+	disks = d.filterIncluded(disks, diskData)
 	if len(disks) == 0 {
-		return diskData, fmt.Errorf("failed to get disk list")
+		collectionErr.add(StageList, fmt.Errorf("no disks matched the include list"))
+		return diskData, collectionErr
+	}
+	disks = d.limitDisks(disks, diskData)
+
+	// 使用TrueNAS中配置的温度告警阈值代替默认阈值
+	if d.config.UseTrueNASThresholds {
+		d.applyTrueNASThresholds(ctx, disks)
 	}
 
 	// 获取存储池信息
 	poolInfo, err := d.poolCollector.Collect(ctx)
 	if err != nil {
 		d.logger.Error("获取存储池信息失败: %v", err)
-		collectionErrors = append(collectionErrors, fmt.Errorf("pool info collection failed: %w", err))
+		collectionErr.add(StagePool, err)
+	}
+	for pool, status := range d.poolCollector.GetPoolStatus() {
+		diskData.PoolStatus[pool] = status
 	}
+	for pool, usage := range d.poolCollector.GetPoolUsage() {
+		diskData.PoolUsage[pool] = usage
+	}
+	for pool, layout := range d.poolCollector.GetPoolLayout() {
+		diskData.PoolLayout[pool] = layout
+	}
+	diskData.PoolWarnPct = d.config.PoolWarnPct
 
-	// 加载历史数据
-	prevData, prevTime := d.LoadPreviousDiskData()
-	diskData.SetPreviousData(prevData, prevTime)
+	// 加载历史数据，--no-increment时不读取也不计算增量，但仍保存本次数据
+	var prevData map[string]map[string]string
+	if d.config.NoIncrement {
+		d.logger.Info("已设置--no-increment，不与上次运行的数据比较")
+	} else if !d.skipHistory {
+		var prevTime string
+		prevData, prevTime = d.LoadPreviousDiskData()
+		diskData.SetPreviousData(prevData, prevTime)
+	}
 
 	// 并发收集SMART数据
 	disksWithSMART, err := d.collectSMARTData(ctx, disks, poolInfo)
 	if err != nil {
 		d.logger.Error("收集SMART数据时发生错误: %v", err)
-		collectionErrors = append(collectionErrors, fmt.Errorf("SMART data collection failed: %w", err))
+		collectionErr.add(StageSMART, err)
+
+		// --fail-fast 时不处理部分数据，也不覆盖历史文件
+		if d.config.FailFast {
+			return diskData, collectionErr
+		}
 	}
 
+	// 去除重复的磁盘名称，否则历史数据会被覆盖，分组统计也会重复计数
+	disksWithSMART = d.dedupeDisks(disksWithSMART)
+
+	// 同一磁盘的多个路径应报告一致的SMART数据
+	d.checkPathConsistency(disksWithSMART)
+
+	// 补充/dev/disk/by-id下的稳定名称
+	d.applyByIDLinks(ctx, disksWithSMART)
+
 	// 处理读写增量
 	disksWithSMART = d.processIncrements(disksWithSMART, prevData)
 
+	// 根据历史基线检查固态硬盘的寿命消耗速率
+	for _, disk := range disksWithSMART {
+		d.checkWearRate(disk, prevData[disk.Name])
+	}
+
 	// 将磁盘添加到磁盘数据对象
 	for _, disk := range disksWithSMART {
 		diskData.AddDisk(disk)
@@ -88,20 +171,258 @@ func (d *DiskCollector) Collect(ctx context.Context) (*model.DiskData, error) {
 	// 排序磁盘
 	diskData.SortDisks()
 
+	// 抑制状态抖动，状态连续多次运行相同才按新状态告警
+	if count := diskData.ApplyFlapDetection(d.config.FlapRuns); count > 0 {
+		d.logger.Info("%d个磁盘的状态不稳定，告警沿用上次的稳定状态", count)
+	}
+
+	// 提示通电时间较长的磁盘，便于提前规划更换
+	if count := diskData.FlagAgedDisks(d.config.AgeInfoYears); count > 0 {
+		d.logger.Info("%d个磁盘通电超过%d年，建议规划更换", count, d.config.AgeInfoYears)
+	}
+
+	// 标记所属存储池发生变化的磁盘，意外的变化可能意味着配置错误
+	for _, change := range diskData.PoolChanges(prevData) {
+		change.Disk.PreviousPool = change.PreviousPool
+		d.logger.Error("警告: 磁盘 %s 的存储池由 %s 变为 %s", change.Disk.Name, change.PreviousPool, change.CurrentPool)
+	}
+
 	// 保存当前数据供下次比较
-	if err := d.SaveDiskData(disksWithSMART); err != nil {
-		d.logger.Error("保存磁盘数据失败: %v", err)
+	if !d.skipHistory {
+		if err := d.saveDiskData(disksWithSMART, diskData.IsPartial()); err != nil {
+			d.logger.Error("保存磁盘数据失败: %v", err)
+			collectionErr.add(StageSave, err)
+		}
+	}
+
+	// 如果有错误，返回结果但包含各阶段的错误信息
+	return diskData, collectionErr.errOrNil()
+}
+
+// dedupeDisks 合并同名的磁盘记录，保留SMART数据字段较多的记录并记录警告
+// 字段数相同时保留先出现的记录，结果保持磁盘首次出现的顺序
+func (d *DiskCollector) dedupeDisks(disks []*model.Disk) []*model.Disk {
+	index := make(map[string]int, len(disks))
+	deduped := make([]*model.Disk, 0, len(disks))
+	for _, disk := range disks {
+		i, ok := index[disk.Name]
+		if !ok {
+			index[disk.Name] = len(deduped)
+			deduped = append(deduped, disk)
+			continue
+		}
+
+		if len(disk.SMARTData) > len(deduped[i].SMARTData) {
+			deduped[i] = disk
+		}
+		d.logger.Error("警告: 磁盘%s重复出现，只保留SMART数据较完整的记录(%d个字段)", disk.Name, len(deduped[i].SMARTData))
+	}
+	return deduped
+}
+
+// checkPathConsistency 检查序列号相同的磁盘(多路径下同一磁盘的不同设备名)报告的通电时间
+// 差值超过pathPowerOnHoursTolerance时将这些磁盘标记为警告，这通常意味着路径映射错误
+func (d *DiskCollector) checkPathConsistency(disks []*model.Disk) {
+	bySerial := make(map[string][]*model.Disk)
+	var serials []string
+	for _, disk := range disks {
+		if disk.Serial == "" || disk.IsVirtual() {
+			continue
+		}
+		if _, ok := bySerial[disk.Serial]; !ok {
+			serials = append(serials, disk.Serial)
+		}
+		bySerial[disk.Serial] = append(bySerial[disk.Serial], disk)
+	}
+
+	for _, serial := range serials {
+		paths := bySerial[serial]
+		if len(paths) < 2 {
+			continue
+		}
+
+		var names []string
+		var minHours, maxHours float64
+		found := false
+		for _, disk := range paths {
+			names = append(names, disk.Name)
+			hours, err := strconv.ParseFloat(model.NormalizeNumber(disk.SMARTData["Power_On_Hours"]), 64)
+			if err != nil {
+				continue
+			}
+			if !found || hours < minHours {
+				minHours = hours
+			}
+			if !found || hours > maxHours {
+				maxHours = hours
+			}
+			found = true
+		}
+		if !found || maxHours-minHours <= pathPowerOnHoursTolerance {
+			continue
+		}
+
+		d.logger.Error("警告: 序列号%s的多个路径(%s)报告的通电时间相差%.0f小时", serial, strings.Join(names, ", "), maxHours-minHours)
+		reason := fmt.Sprintf("%s: 多个路径(%s)的通电时间不一致 (%.0f-%.0f小时)，可能是路径映射错误",
+			model.StatusSourceHeuristic, strings.Join(names, ", "), minHours, maxHours)
+		for _, disk := range paths {
+			disk.Escalate(model.DiskStatusWarning, reason)
+		}
+	}
+}
+
+// applyByIDLinks 读取/dev/disk/by-id的符号链接，为每个磁盘设置稳定名称
+// 读取失败不影响收集，磁盘仍以内核设备名显示
+func (d *DiskCollector) applyByIDLinks(ctx context.Context, disks []*model.Disk) {
+	output, err := d.commandRunner.Run(ctx, "ls -l /dev/disk/by-id/")
+	if err != nil {
+		d.logger.Debug("读取/dev/disk/by-id失败: %v", err)
+		return
+	}
+
+	links := parseByIDLinks(output)
+	for _, disk := range disks {
+		disk.ByID = links[disk.Name]
+	}
+}
+
+// parseByIDLinks 解析 ls -l /dev/disk/by-id/ 的输出，返回内核设备名到by-id名称的映射
+// 一个磁盘通常有多个链接，按byIDRank选择最易读的一个；分区链接被忽略
+func parseByIDLinks(output string) map[string]string {
+	links := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[len(fields)-2] != "->" {
+			continue
+		}
+		name := fields[len(fields)-3]
+		target := fields[len(fields)-1]
+		if strings.Contains(name, "-part") {
+			continue
+		}
+
+		device := target[strings.LastIndex(target, "/")+1:]
+		current, ok := links[device]
+		if !ok || byIDRank(name) < byIDRank(current) || (byIDRank(name) == byIDRank(current) && name < current) {
+			links[device] = name
+		}
+	}
+	return links
+}
+
+// byIDRank 按可读性排列by-id名称，越小越优先
+// 含型号和序列号的名称(ata-、nvme-、scsi-S)优先于只含WWN/EUI的名称
+func byIDRank(name string) int {
+	switch {
+	case strings.HasPrefix(name, "nvme-eui."), strings.HasPrefix(name, "nvme-nvme."):
+		return 5
+	case strings.HasPrefix(name, "ata-"), strings.HasPrefix(name, "nvme-"):
+		return 0
+	case strings.HasPrefix(name, "scsi-S"):
+		return 1
+	case strings.HasPrefix(name, "usb-"):
+		return 2
+	case strings.HasPrefix(name, "scsi-"):
+		return 3
+	case strings.HasPrefix(name, "wwn-"):
+		return 4
+	default:
+		return 6
+	}
+}
+
+// addBootDisks 将boot-pool的成员标记为启动盘，并补充磁盘列表中缺少的启动盘
+func (d *DiskCollector) addBootDisks(ctx context.Context, disks []*model.Disk) []*model.Disk {
+	bootDisks, err := d.poolCollector.GetBootPoolDisks(ctx)
+	if err != nil {
+		d.logger.Error("警告: 获取启动盘失败: %v", err)
+		return disks
+	}
+
+	listed := make(map[string]*model.Disk, len(disks))
+	for _, disk := range disks {
+		listed[disk.Name] = disk
+	}
+
+	for _, name := range bootDisks {
+		if disk, ok := listed[name]; ok {
+			disk.Boot = true
+			continue
+		}
+
+		disk := d.newBootDisk(ctx, name)
+		disks = append(disks, disk)
+		d.logger.Info("添加磁盘列表中缺少的启动盘: %s", name)
+	}
+	return disks
+}
+
+// newBootDisk 使用lsblk获取启动盘的型号和容量，获取失败时按SSD处理
+func (d *DiskCollector) newBootDisk(ctx context.Context, name string) *model.Disk {
+	diskType, diskModel, size := "SSD", "", ""
+	output, err := d.commandRunner.Run(ctx, fmt.Sprintf("lsblk -d -n -o ROTA,SIZE,MODEL /dev/%s", name))
+	if fields := strings.Fields(output); err == nil && len(fields) >= 2 {
+		if fields[0] == "1" {
+			diskType = "HDD"
+		}
+		size = fields[1]
+		diskModel = strings.Join(fields[2:], " ")
+	}
+
+	disk := model.NewDisk(name, diskType, diskModel, size)
+	disk.Source = model.DataSourceBootPool
+	disk.Boot = true
+	return disk
+}
+
+// filterIncluded 配置了包含列表时，只保留列表中的磁盘，并提示列表中不存在的磁盘
+func (d *DiskCollector) filterIncluded(disks []*model.Disk, diskData *model.DiskData) []*model.Disk {
+	if len(d.config.IncludeDisks) == 0 {
+		return disks
+	}
+
+	included := make(map[string]bool, len(d.config.IncludeDisks))
+	for _, name := range d.config.IncludeDisks {
+		included[name] = true
+	}
+
+	filtered := make([]*model.Disk, 0, len(d.config.IncludeDisks))
+	found := make(map[string]bool)
+	for _, disk := range disks {
+		if included[disk.Name] {
+			filtered = append(filtered, disk)
+			found[disk.Name] = true
+		}
 	}
 
-	// 如果有错误，返回结果但包含错误信息
-	if len(collectionErrors) > 0 {
-		if len(collectionErrors) == 1 {
-			return diskData, collectionErrors[0]
+	for _, name := range d.config.IncludeDisks {
+		if !found[name] {
+			d.logger.Error("警告: 包含列表中的磁盘%s不存在", name)
 		}
-		return diskData, fmt.Errorf("multiple errors during disk data collection: %v", collectionErrors)
 	}
 
-	return diskData, nil
+	d.logger.Info("根据包含列表保留%d/%d个磁盘", len(filtered), len(disks))
+	diskData.IncludeFiltered = len(filtered) < len(disks)
+	return filtered
+}
+
+// limitDisks 当磁盘数量超过配置的上限时，只保留按名称排序后的前N个磁盘
+func (d *DiskCollector) limitDisks(disks []*model.Disk, diskData *model.DiskData) []*model.Disk {
+	maxDisks := d.config.MaxDisks
+	if maxDisks <= 0 || len(disks) <= maxDisks {
+		return disks
+	}
+
+	d.logger.Error("警告: 磁盘数量(%d)超过上限(%d)，只处理前%d个磁盘", len(disks), maxDisks, maxDisks)
+
+	sorted := make([]*model.Disk, len(disks))
+	copy(sorted, disks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	diskData.TruncatedFrom = len(disks)
+	return sorted[:maxDisks]
 }
 
 // GetDisksFromMidclt 使用midclt获取磁盘列表
@@ -132,6 +453,12 @@ func (d *DiskCollector) GetDisksFromMidclt(ctx context.Context) ([]*model.Disk,
 		diskType, _ := diskData["type"].(string)
 
 		disk := model.NewDisk(name, diskType, diskModel, size)
+		disk.Source = model.DataSourceMidclt
+		if d.config.UseTrueNASThresholds {
+			for attribute, value := range parseTrueNASThresholds(diskData) {
+				disk.SMARTData[attribute] = value
+			}
+		}
 		disks = append(disks, disk)
 	}
 
@@ -178,6 +505,7 @@ func (d *DiskCollector) GetDisksFromLsblk(ctx context.Context) ([]*model.Disk, e
 		size := parts[len(parts)-1]
 
 		disk := model.NewDisk(name, diskType, diskModel, size)
+		disk.Source = model.DataSourceLsblk
 		disks = append(disks, disk)
 	}
 
@@ -186,19 +514,31 @@ func (d *DiskCollector) GetDisksFromLsblk(ctx context.Context) ([]*model.Disk, e
 }
 
 // collectSMARTData 并发收集所有磁盘的SMART数据
+// 启用FailFast时第一个错误会取消其余磁盘的收集，只返回该错误
 func (d *DiskCollector) collectSMARTData(ctx context.Context, disks []*model.Disk, poolInfo map[string]string) ([]*model.Disk, error) {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	resultDisks := make([]*model.Disk, 0, len(disks))
 	errorsChan := make(chan error, len(disks))
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failOnce sync.Once
+	var firstErr error
+
 	// 使用信号量限制并发数量
 	semaphore := make(chan struct{}, 5) // 最多5个并发
 
 	for _, disk := range disks {
-		wg.Add(1)
 		semaphore <- struct{}{} // 获取信号量
 
+		// 已经失败时不再启动新的收集
+		if ctx.Err() != nil {
+			<-semaphore
+			break
+		}
+		wg.Add(1)
+
 		go func(disk *model.Disk) {
 			defer wg.Done()
 			defer func() { <-semaphore }() // 释放信号量
@@ -210,18 +550,31 @@ func (d *DiskCollector) collectSMARTData(ctx context.Context, disks []*model.Dis
 			// 设置存储池信息
 			if pool, ok := poolInfo[diskName]; ok {
 				disk.Pool = pool
+				disk.PoolSource = d.poolCollector.GetPoolSource()
+			} else if disk.Boot {
+				disk.Pool = model.BootPoolName
+				disk.PoolSource = model.DataSourceBootPool
 			} else {
-				disk.Pool = "未分配"
+				disk.Pool = model.PoolUnassigned
 			}
 
 			d.logger.Info("处理磁盘: %s (类型: %s, 型号: %s, 池: %s)",
 				diskName, diskType, diskModel, disk.Pool)
+			d.logger.Debug("磁盘%s的数据来源: 磁盘列表=%s, 存储池=%s",
+				diskName, disk.Source, disk.PoolSource)
 
 			// 收集SMART数据
 			smartData, err := d.smartCollector.GetSMARTData(ctx, diskName, diskType, diskModel)
 			if err != nil {
 				d.logger.Error("获取磁盘%s的SMART数据失败: %v", diskName, err)
-				errorsChan <- fmt.Errorf("failed to collect SMART data for %s: %w", diskName, err)
+				err = fmt.Errorf("failed to collect SMART data for %s: %w", diskName, err)
+				if d.config.FailFast {
+					failOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+				errorsChan <- err
 				return
 			}
 
@@ -230,8 +583,26 @@ func (d *DiskCollector) collectSMARTData(ctx context.Context, disks []*model.Dis
 				disk.SMARTData[k] = v
 			}
 
-			// 更新磁盘状态
+			// 获取序列号、WWN、固件版本和扇区大小(虚拟设备没有这些信息)
+			if disk.Type != model.DiskTypeVirtual {
+				info, err := d.smartCollector.GetDeviceInfo(ctx, diskName)
+				if err != nil {
+					d.logger.Debug("获取磁盘%s的序列号失败: %v", diskName, err)
+				} else {
+					disk.Serial = info.Serial
+					disk.WWN = info.WWN
+					disk.FirmwareVersion = info.FirmwareVersion
+					disk.LogicalSectorSize = info.LogicalSectorSize
+					disk.PhysicalSectorSize = info.PhysicalSectorSize
+					if info.SMARTDisabled {
+						d.handleSMARTDisabled(ctx, disk)
+					}
+				}
+			}
+
+			// 更新磁盘状态，并应用自定义状态规则
 			disk.UpdateStatus()
+			disk.ApplyStatusRules(d.config.StatusRules)
 
 			// 添加到结果
 			mu.Lock()
@@ -243,21 +614,45 @@ func (d *DiskCollector) collectSMARTData(ctx context.Context, disks []*model.Dis
 	wg.Wait()
 	close(errorsChan)
 
+	// 被取消的磁盘也会报错，只返回最先出现的错误以便定位原因
+	if firstErr != nil {
+		return resultDisks, firstErr
+	}
+
 	// 收集所有错误
-	var errors []error
+	var smartErrors []error
 	for err := range errorsChan {
-		errors = append(errors, err)
+		smartErrors = append(smartErrors, err)
 	}
 
 	// 返回结果，即使有错误也返回已收集的数据
-	if len(errors) > 0 {
-		if len(errors) == 1 {
-			return resultDisks, errors[0]
-		}
-		return resultDisks, fmt.Errorf("multiple errors during SMART data collection: %v", errors)
+	return resultDisks, errors.Join(smartErrors...)
+}
+
+// handleSMARTDisabled 记录SMART已禁用的磁盘，--enable-smart时启用SMART并重新收集数据
+// 未启用时磁盘不报告健康状态，由磁盘模型标记为警告
+func (d *DiskCollector) handleSMARTDisabled(ctx context.Context, disk *model.Disk) {
+	if !d.config.EnableSMART {
+		d.logger.Error("警告: 磁盘 %s 的SMART已禁用，可使用 --enable-smart 启用", disk.Name)
+		disk.SMARTData[model.SmartSupportAttribute] = model.SmartSupportDisabled
+		return
+	}
+
+	if err := d.smartCollector.EnableSMART(ctx, disk.Name); err != nil {
+		d.logger.Error("磁盘 %s: %v", disk.Name, err)
+		disk.SMARTData[model.SmartSupportAttribute] = model.SmartSupportDisabled
+		return
 	}
+	d.logger.Info("已启用磁盘 %s 的SMART", disk.Name)
 
-	return resultDisks, nil
+	smartData, err := d.smartCollector.GetSMARTData(ctx, disk.Name, disk.RawType, disk.Model)
+	if err != nil {
+		d.logger.Error("启用SMART后获取磁盘%s的SMART数据失败: %v", disk.Name, err)
+		return
+	}
+	for k, v := range smartData {
+		disk.SMARTData[k] = v
+	}
 }
 
 // processIncrements 处理读写增量数据
@@ -281,7 +676,7 @@ func (d *DiskCollector) processIncrements(disks []*model.Disk, prevData map[stri
 		// 计算读增量
 		if dataRead, ok := disk.SMARTData["Data_Read"]; ok && dataRead != "" {
 			if prevDataRead, ok := prevDiskData["Data_Read"]; ok && prevDataRead != "" {
-				increment := d.calculateSizeIncrement(prevDataRead, dataRead)
+				increment := d.calculateSizeIncrement(prevDiskData, disk.SMARTData, "Data_Read")
 				disk.ReadIncrement = increment
 				d.logger.Debug("Disk %s Read Increment: %s (Old: %s, New: %s)",
 					diskName, increment, prevDataRead, dataRead)
@@ -293,7 +688,7 @@ func (d *DiskCollector) processIncrements(disks []*model.Disk, prevData map[stri
 		// 计算写增量
 		if dataWritten, ok := disk.SMARTData["Data_Written"]; ok && dataWritten != "" {
 			if prevDataWritten, ok := prevDiskData["Data_Written"]; ok && prevDataWritten != "" {
-				increment := d.calculateSizeIncrement(prevDataWritten, dataWritten)
+				increment := d.calculateSizeIncrement(prevDiskData, disk.SMARTData, "Data_Written")
 				disk.WriteIncrement = increment
 				d.logger.Debug("Disk %s Write Increment: %s (Old: %s, New: %s)",
 					diskName, increment, prevDataWritten, dataWritten)
@@ -301,15 +696,89 @@ func (d *DiskCollector) processIncrements(disks []*model.Disk, prevData map[stri
 				d.logger.Debug("No previous write data for disk: %s", diskName)
 			}
 		}
+
+		// 检查纠错计数增长
+		d.checkCorrectedErrors(disk, prevDiskData)
 	}
 
 	return disks
 }
 
-// calculateSizeIncrement 计算两个大小字符串之间的增量
-func (d *DiskCollector) calculateSizeIncrement(oldValue, newValue string) string {
-	oldBytes, errOld := d.parseSizeToBytes(oldValue)
-	newBytes, errNew := d.parseSizeToBytes(newValue)
+// checkCorrectedErrors 比较纠错计数与历史数据，纠错速率快速攀升时将磁盘标记为警告
+// 即使未修正错误仍为0，已纠正错误的快速增长也是早期故障的信号
+func (d *DiskCollector) checkCorrectedErrors(disk *model.Disk, prevDiskData map[string]string) {
+	current, err := strconv.ParseInt(disk.SMARTData["Corrected_Errors"], 10, 64)
+	if err != nil {
+		return
+	}
+	previous, err := strconv.ParseInt(prevDiskData["Corrected_Errors"], 10, 64)
+	if err != nil {
+		return
+	}
+
+	delta := current - previous
+	if delta < 0 {
+		d.logger.Debug("Disk %s corrected error counter decreased, possible counter reset", disk.Name)
+		disk.SMARTData["Corrected_Errors_Delta"] = "重置"
+		return
+	}
+	disk.SMARTData["Corrected_Errors_Delta"] = strconv.FormatInt(delta, 10)
+
+	if delta < correctedErrorMinDelta {
+		return
+	}
+
+	// 以通电时间衡量区间长度，与生命周期平均纠错速率比较
+	hoursNow, errNow := strconv.ParseFloat(model.NormalizeNumber(disk.SMARTData["Power_On_Hours"]), 64)
+	hoursPrev, errPrev := strconv.ParseFloat(model.NormalizeNumber(prevDiskData["Power_On_Hours"]), 64)
+	if errNow == nil && errPrev == nil && hoursNow > hoursPrev && previous > 0 {
+		intervalRate := float64(delta) / (hoursNow - hoursPrev)
+		lifetimeRate := float64(current) / hoursNow
+		if intervalRate < lifetimeRate*correctedErrorRateFactor {
+			return
+		}
+	}
+
+	d.logger.Info("磁盘%s的已纠正错误快速增长: %d -> %d (增量: %d)", disk.Name, previous, current, delta)
+	disk.Escalate(model.DiskStatusWarning, fmt.Sprintf("%s: 已纠正错误快速增长 (增量 %d)", model.StatusSourceHeuristic, delta))
+}
+
+// checkWearRate 记录已用寿命基线，并按基线以来的消耗速率估算剩余寿命
+// 预计在WearHorizonDays内用完时标记为警告，即使当前已用寿命还很低
+// 没有基线、更换了磁盘或读数回退时以当前读数作为新的基线
+func (d *DiskCollector) checkWearRate(disk *model.Disk, prevDiskData map[string]string) {
+	used, err := strconv.ParseFloat(disk.SMARTData["Percentage_Used"], 64)
+	if err != nil {
+		return
+	}
+	hours, err := strconv.ParseFloat(model.NormalizeNumber(disk.SMARTData["Power_On_Hours"]), 64)
+	if err != nil {
+		return
+	}
+
+	baseUsed, baseHours, ok := model.ParseWearBaseline(prevDiskData[model.WearBaselineAttribute])
+	replaced := prevDiskData["Serial"] != "" && prevDiskData["Serial"] != disk.Serial
+	if !ok || replaced || baseUsed > used || baseHours > hours {
+		disk.SMARTData[model.WearBaselineAttribute] = model.FormatWearBaseline(used, hours)
+		return
+	}
+	disk.SMARTData[model.WearBaselineAttribute] = prevDiskData[model.WearBaselineAttribute]
+
+	horizon := d.config.WearHorizonDays
+	projection, ok := model.ProjectWear(used, hours, baseUsed, baseHours)
+	if horizon <= 0 || !ok || projection.DaysLeft > float64(horizon) {
+		return
+	}
+
+	d.logger.Info("磁盘%s的寿命消耗过快: 每天%.2f%%，预计%.0f天后用完", disk.Name, projection.RatePerDay, projection.DaysLeft)
+	disk.Escalate(model.DiskStatusWarning, fmt.Sprintf("%s: 寿命消耗过快 (每天 %.2f%%，预计 %.0f 天后用完)",
+		model.StatusSourceHeuristic, projection.RatePerDay, projection.DaysLeft))
+}
+
+// calculateSizeIncrement 计算读写量从上次运行到本次的增量
+func (d *DiskCollector) calculateSizeIncrement(prevDiskData, smartData map[string]string, key string) string {
+	oldBytes, errOld := model.SizeBytes(prevDiskData, key, model.SizeUnitsBinary)
+	newBytes, errNew := model.SizeBytes(smartData, key, model.SizeUnitsDecimal)
 
 	if errOld != nil || errNew != nil {
 		d.logger.Error("Size parsing error: Old Error = %v, New Error = %v", errOld, errNew)
@@ -339,75 +808,54 @@ func (d *DiskCollector) calculateSizeIncrement(oldValue, newValue string) string
 	return increment
 }
 
-// parseSizeToBytes 将大小字符串解析为字节数
-func (d *DiskCollector) parseSizeToBytes(sizeStr string) (float64, error) {
-	// 使用SMART收集器的标准化方法
-	normalizedSize := d.smartCollector.normalizeSize(sizeStr)
-	if normalizedSize == "N/A" {
-		return 0, fmt.Errorf("invalid size string: %s", sizeStr)
-	}
-
-	// 解析标准化后的大小
-	var value float64
-	var unit string
-	_, err := fmt.Sscanf(normalizedSize, "%f %s", &value, &unit)
-	if err != nil {
-		return 0, err
-	}
-
-	// 将值转换为字节
-	var bytes float64
-	switch unit {
-	case "B":
-		bytes = value
-	case "KB":
-		bytes = value * 1024
-	case "MB":
-		bytes = value * 1024 * 1024
-	case "GB":
-		bytes = value * 1024 * 1024 * 1024
-	case "TB":
-		bytes = value * 1024 * 1024 * 1024 * 1024
-	case "PB":
-		bytes = value * 1024 * 1024 * 1024 * 1024 * 1024
-	default:
-		return 0, fmt.Errorf("unknown unit: %s", unit)
-	}
-
-	return bytes, nil
-}
-
 // SaveDiskData 保存当前磁盘数据，用于下次比较
 func (d *DiskCollector) SaveDiskData(disks []*model.Disk) error {
+	return d.saveDiskData(disks, false)
+}
+
+// saveDiskData 保存磁盘数据，keepOthers为true时(只收集了部分磁盘)保留历史文件中其他磁盘的记录，
+// 否则下次完整运行时这些磁盘会丢失读写基线、寿命基线和状态历史
+func (d *DiskCollector) saveDiskData(disks []*model.Disk, keepOthers bool) error {
 	// 构建磁盘数据映射
 	diskData := make(map[string]map[string]string)
+	if keepOthers {
+		previous, _, err := d.history.LoadDiskData()
+		if err != nil {
+			d.logger.Error("读取历史数据失败，未收集的磁盘记录将不会保留: %v", err)
+		}
+		for name, data := range previous {
+			diskData[name] = data
+		}
+	}
 
 	for _, disk := range disks {
 		// 只保存需要的属性
 		diskData[disk.Name] = map[string]string{
-			"Data_Read":    disk.SMARTData["Data_Read"],
-			"Data_Written": disk.SMARTData["Data_Written"],
+			"Data_Read":                 disk.SMARTData["Data_Read"],
+			"Data_Written":              disk.SMARTData["Data_Written"],
+			"Corrected_Errors":          disk.SMARTData["Corrected_Errors"],
+			"Power_On_Hours":            disk.SMARTData["Power_On_Hours"],
+			model.WearBaselineAttribute: disk.SMARTData[model.WearBaselineAttribute],
+			"Pool":                      disk.Pool,
+			"Serial":                    disk.Serial,
+			"Status":                    string(disk.GetStatus()),
+		}
+		// 同时保存字节数，比较时不受显示单位制和小数位数的影响
+		for _, key := range []string{"Data_Read", "Data_Written"} {
+			if bytes, err := model.SizeBytes(disk.SMARTData, key, model.SizeUnitsDecimal); err == nil {
+				diskData[disk.Name][key+model.SizeBytesSuffix] = strconv.FormatFloat(bytes, 'f', 0, 64)
+			}
+		}
+		// 抖动检测需要最近几次运行的状态和上次的稳定状态
+		if len(disk.StatusHistory) > 0 {
+			diskData[disk.Name][model.StatusHistoryAttribute] = strings.Join(disk.StatusHistory, ",")
+			diskData[disk.Name][model.AlertStatusAttribute] = string(disk.AlertStatus)
 		}
 	}
 
-	// 构建数据存储结构
-	data := struct {
-		Timestamp string                       `json:"timestamp"`
-		Disks     map[string]map[string]string `json:"disks"`
-	}{
-		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
-		Disks:     diskData,
-	}
-
-	// 将数据转换为JSON
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("json marshaling failed: %w", err)
-	}
-
-	// 写入文件
-	if err := os.WriteFile(d.config.DataFile, jsonData, 0644); err != nil {
-		return fmt.Errorf("write file failed: %w", err)
+	// 通过历史存储写入：加锁防止并发运行互相覆盖，临时文件改名保证不会留下半个文件
+	if err := d.history.SaveDiskData(diskData); err != nil {
+		return err
 	}
 
 	d.logger.Debug("成功保存磁盘数据到: %s", d.config.DataFile)
@@ -418,34 +866,27 @@ func (d *DiskCollector) SaveDiskData(disks []*model.Disk) error {
 func (d *DiskCollector) LoadPreviousDiskData() (map[string]map[string]string, string) {
 	d.logger.Info("加载上次运行的磁盘数据以计算增量...")
 
-	var data struct {
-		Timestamp string                       `json:"timestamp"`
-		Disks     map[string]map[string]string `json:"disks"`
-	}
-
-	// 检查文件是否存在
-	if _, err := os.Stat(d.config.DataFile); os.IsNotExist(err) {
-		d.logger.Info("未找到上次运行的数据，将只显示当前状态")
-		return make(map[string]map[string]string), ""
-	}
-
-	// 读取文件
-	fileData, err := os.ReadFile(d.config.DataFile)
+	disks, timestamp, err := d.history.LoadDiskData()
 	if err != nil {
 		d.logger.Error("读取上次运行的磁盘数据失败: %v", err)
 		return make(map[string]map[string]string), ""
 	}
-
-	// 解析JSON
-	if err := json.Unmarshal(fileData, &data); err != nil {
-		d.logger.Error("解析上次运行的磁盘数据失败: %v", err)
-		return make(map[string]map[string]string), ""
+	if timestamp == "" {
+		d.logger.Info("未找到上次运行的数据，将只显示当前状态")
+		return disks, ""
 	}
 
-	// 记录时间戳
-	if data.Timestamp != "" {
-		d.logger.Info("上次运行时间: %s", data.Timestamp)
-	}
+	timestamp = formatHistoryTime(timestamp)
+	d.logger.Info("上次运行时间: %s", timestamp)
+	return disks, timestamp
+}
 
-	return data.Disks, data.Timestamp
+// formatHistoryTime 将历史数据中的RFC3339时间戳转换为本地时间显示
+// 早期版本保存的 "2006-01-02 15:04:05" 格式原样返回
+func formatHistoryTime(timestamp string) string {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return parsed.Local().Format("2006-01-02 15:04:05")
 }
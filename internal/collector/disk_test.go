@@ -0,0 +1,914 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/storage"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
+)
+
+func TestDiskCollector_CorrectedErrorDelta(t *testing.T) {
+	config := model.NewDefaultConfig()
+	collector := NewDiskCollector(config, system.NewMockLogger(), system.NewMockCommandRunner())
+
+	// 第一次快照: 生命周期平均约 1000 个/小时
+	prevData := map[string]map[string]string{
+		"sdd": {
+			"Corrected_Errors": "10000000",
+			"Power_On_Hours":   "10000",
+		},
+		"sde": {
+			"Corrected_Errors": "10000000",
+			"Power_On_Hours":   "10000",
+		},
+	}
+
+	// 第二次快照: sdd 在24小时内纠错计数激增，sde 保持正常速率
+	jumped := model.NewDisk("sdd", "HDD", "SEAGATE ST600MM0006", "600G")
+	jumped.Status = model.DiskStatusOK
+	jumped.SMARTData["Corrected_Errors"] = "11000000"
+	jumped.SMARTData["Power_On_Hours"] = "10024"
+	jumped.SMARTData["Uncorrected_Errors"] = "0"
+
+	steady := model.NewDisk("sde", "HDD", "SEAGATE ST600MM0006", "600G")
+	steady.Status = model.DiskStatusOK
+	steady.SMARTData["Corrected_Errors"] = "10024000"
+	steady.SMARTData["Power_On_Hours"] = "10024"
+	steady.SMARTData["Uncorrected_Errors"] = "0"
+
+	collector.processIncrements([]*model.Disk{jumped, steady}, prevData)
+
+	if jumped.SMARTData["Corrected_Errors_Delta"] != "1000000" {
+		t.Errorf("sdd Corrected_Errors_Delta: Expected '1000000', got '%s'", jumped.SMARTData["Corrected_Errors_Delta"])
+	}
+	if jumped.Status != model.DiskStatusWarning {
+		t.Errorf("sdd status: Expected %s, got %s", model.DiskStatusWarning, jumped.Status)
+	}
+
+	if steady.SMARTData["Corrected_Errors_Delta"] != "24000" {
+		t.Errorf("sde Corrected_Errors_Delta: Expected '24000', got '%s'", steady.SMARTData["Corrected_Errors_Delta"])
+	}
+	if steady.Status != model.DiskStatusOK {
+		t.Errorf("sde status: Expected %s, got %s", model.DiskStatusOK, steady.Status)
+	}
+}
+
+func TestDiskCollector_WearRate(t *testing.T) {
+	config := model.NewDefaultConfig()
+	collector := NewDiskCollector(config, system.NewMockLogger(), system.NewMockCommandRunner())
+
+	// 两块固态硬盘的基线都是20天前记录的2%
+	prevData := map[string]map[string]string{
+		"nvme0n1": {"Serial": "S1", model.WearBaselineAttribute: "2@10000"},
+		"nvme1n1": {"Serial": "S2", model.WearBaselineAttribute: "2@10000"},
+	}
+
+	// nvme0n1 当前只用了8%，但20天消耗6%，按每天0.3%约306天后用完
+	fast := model.NewDisk("nvme0n1", "SSD", "Samsung SSD 980 PRO", "1T")
+	fast.Status = model.DiskStatusOK
+	fast.Serial = "S1"
+	fast.SMARTData["Percentage_Used"] = "8"
+	fast.SMARTData["Power_On_Hours"] = "10,480"
+
+	// nvme1n1 20天只消耗1%，远在预警期限之外
+	slow := model.NewDisk("nvme1n1", "SSD", "Samsung SSD 980 PRO", "1T")
+	slow.Status = model.DiskStatusOK
+	slow.Serial = "S2"
+	slow.SMARTData["Percentage_Used"] = "3"
+	slow.SMARTData["Power_On_Hours"] = "10480"
+
+	collector.checkWearRate(fast, prevData["nvme0n1"])
+	collector.checkWearRate(slow, prevData["nvme1n1"])
+
+	if fast.Status != model.DiskStatusWarning || !strings.Contains(fast.StatusReason, "寿命消耗过快") {
+		t.Errorf("Expected fast-wearing SSD to be a warning, got %s (%s)", fast.Status, fast.StatusReason)
+	}
+	if slow.Status != model.DiskStatusOK {
+		t.Errorf("Expected slow-wearing SSD to stay OK, got %s (%s)", slow.Status, slow.StatusReason)
+	}
+	// 基线在后续运行中保持不变
+	if fast.SMARTData[model.WearBaselineAttribute] != "2@10000" {
+		t.Errorf("Expected baseline to be kept, got %q", fast.SMARTData[model.WearBaselineAttribute])
+	}
+
+	// 更换了磁盘(序列号不同)时重新记录基线，不告警
+	replaced := model.NewDisk("nvme0n1", "SSD", "Samsung SSD 980 PRO", "1T")
+	replaced.Status = model.DiskStatusOK
+	replaced.Serial = "S3"
+	replaced.SMARTData["Percentage_Used"] = "8"
+	replaced.SMARTData["Power_On_Hours"] = "10480"
+	collector.checkWearRate(replaced, prevData["nvme0n1"])
+	if replaced.Status != model.DiskStatusOK || replaced.SMARTData[model.WearBaselineAttribute] != "8@10480" {
+		t.Errorf("Expected a new baseline for a replaced disk, got %s (%q)", replaced.Status, replaced.SMARTData[model.WearBaselineAttribute])
+	}
+
+	// 设置为0时不检查
+	config.WearHorizonDays = 0
+	fast.Status = model.DiskStatusOK
+	fast.StatusReason = ""
+	collector.checkWearRate(fast, prevData["nvme0n1"])
+	if fast.Status != model.DiskStatusOK {
+		t.Errorf("Expected no wear warning with a zero horizon, got %s", fast.Status)
+	}
+}
+
+func TestDiskCollector_MaxDisks(t *testing.T) {
+	config := model.NewDefaultConfig()
+	config.DataFile = t.TempDir() + "/disk_data.json"
+	config.MaxDisks = 3
+
+	// 10个乱序的磁盘
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sdj disk TEST 1T\nsdc disk TEST 1T\nsdh disk TEST 1T\nsda disk TEST 1T\nsdf disk TEST 1T\n"+
+			"sdi disk TEST 1T\nsdb disk TEST 1T\nsde disk TEST 1T\nsdg disk TEST 1T\nsdd disk TEST 1T\n")
+
+	collector := NewDiskCollector(config, system.NewMockLogger(), runner)
+	diskData, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if len(diskData.Disks) != 3 {
+		t.Fatalf("Expected 3 disks, got %d", len(diskData.Disks))
+	}
+	if diskData.TruncatedFrom != 10 || !diskData.IsTruncated() {
+		t.Errorf("Expected TruncatedFrom 10, got %d", diskData.TruncatedFrom)
+	}
+	processed := make(map[string]bool)
+	for _, disk := range diskData.Disks {
+		processed[disk.Name] = true
+	}
+	for _, name := range []string{"sda", "sdb", "sdc"} {
+		if !processed[name] {
+			t.Errorf("Expected disk %s to be processed", name)
+		}
+	}
+}
+
+func TestDiskCollector_MaxDisksKeepsHistory(t *testing.T) {
+	dir := t.TempDir()
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk TEST 1T\nsdb disk TEST 1T\nsdc disk TEST 1T\nsdd disk TEST 1T\nsde disk TEST 1T\n")
+
+	collect := func(maxDisks int) *model.DiskData {
+		t.Helper()
+		config := model.NewDefaultConfig()
+		config.DataFile = filepath.Join(dir, "disk_data.json")
+		config.MaxDisks = maxDisks
+		diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+		return diskData
+	}
+
+	// 截断的运行只处理sda和sdb，但不能丢弃其他磁盘的历史记录
+	collect(0)
+	if truncated := collect(2); len(truncated.Disks) != 2 || !truncated.IsPartial() {
+		t.Fatalf("Expected a partial run with 2 disks, got %d", len(truncated.Disks))
+	}
+
+	saved, _, err := storage.NewDiskHistoryStorage(filepath.Join(dir, "disk_data.json"), system.NewMockLogger()).LoadDiskData()
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(saved) != 5 {
+		t.Errorf("Expected history to keep all 5 disks, got %v", saved)
+	}
+
+	if events := collect(0).DetectEvents(time.Now()); len(events) != 0 {
+		t.Errorf("Expected no events on the full run, got %+v", events)
+	}
+}
+
+func TestDiskCollector_DataSources(t *testing.T) {
+	config := model.NewDefaultConfig()
+	config.DataFile = t.TempDir() + "/disk_data.json"
+
+	// midclt不可用，磁盘列表来自lsblk，存储池来自zpool
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk TEST 1T\nsdb disk TEST 1T\n")
+	runner.SetMockOutput("zpool status", `  pool: tank
+ state: ONLINE
+config:
+
+	NAME        STATE     READ WRITE CKSUM
+	tank        ONLINE       0     0     0
+	  sda       ONLINE       0     0     0
+
+errors: No known data errors
+`)
+
+	collector := NewDiskCollector(config, system.NewMockLogger(), runner)
+	diskData, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	disks := make(map[string]*model.Disk)
+	for _, disk := range diskData.Disks {
+		disks[disk.Name] = disk
+	}
+
+	sda, sdb := disks["sda"], disks["sdb"]
+	if sda == nil || sdb == nil {
+		t.Fatalf("Expected sda and sdb to be collected, got %v", disks)
+	}
+	if sda.Source != model.DataSourceLsblk || sda.Pool != "tank" || sda.PoolSource != model.DataSourceZpool {
+		t.Errorf("Expected sda from lsblk in pool tank from zpool, got %s/%s/%s", sda.Source, sda.Pool, sda.PoolSource)
+	}
+	// 未分配的磁盘没有存储池来源
+	if sdb.Source != model.DataSourceLsblk || sdb.PoolSource != "" {
+		t.Errorf("Expected unassigned sdb from lsblk without pool source, got %s/%s", sdb.Source, sdb.PoolSource)
+	}
+}
+
+func TestDiskCollector_IncludeFile(t *testing.T) {
+	dir := t.TempDir()
+	includeFile := filepath.Join(dir, "disks.txt")
+	if err := os.WriteFile(includeFile, []byte("# 问题磁盘\nsdd\n\n/dev/sdb\n"), 0644); err != nil {
+		t.Fatalf("Failed to write include file: %v", err)
+	}
+
+	names, err := model.LoadDiskList(includeFile)
+	if err != nil {
+		t.Fatalf("LoadDiskList failed: %v", err)
+	}
+
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(dir, "disk_data.json")
+	config.IncludeDisks = names
+
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk TEST 1T\nsdb disk TEST 1T\nsdc disk TEST 1T\nsdd disk TEST 1T\nsde disk TEST 1T\n")
+
+	collector := NewDiskCollector(config, system.NewMockLogger(), runner)
+	diskData, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if len(diskData.Disks) != 2 {
+		t.Fatalf("Expected 2 disks, got %d", len(diskData.Disks))
+	}
+	if diskData.Disks[0].Name != "sdb" || diskData.Disks[1].Name != "sdd" {
+		t.Errorf("Expected sdb and sdd, got %s and %s", diskData.Disks[0].Name, diskData.Disks[1].Name)
+	}
+
+	// 只对包含的磁盘执行SMART命令
+	for _, command := range runner.CalledCommands {
+		for _, skipped := range []string{"/dev/sda", "/dev/sdc", "/dev/sde"} {
+			if strings.Contains(command, skipped) {
+				t.Errorf("Expected %s not to be collected, got command %q", skipped, command)
+			}
+		}
+	}
+}
+
+func TestDiskCollector_IncludeFileKeepsHistory(t *testing.T) {
+	dir := t.TempDir()
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk TEST 1T\nsdb disk TEST 1T\nsdc disk TEST 1T\nsdd disk TEST 1T\nsde disk TEST 1T\n")
+
+	collect := func(include []string) *model.DiskData {
+		t.Helper()
+		config := model.NewDefaultConfig()
+		config.DataFile = filepath.Join(dir, "disk_data.json")
+		config.IncludeDisks = include
+		diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+		return diskData
+	}
+
+	// 第一次完整运行保存全部磁盘，之后只收集sdb和sdd
+	collect(nil)
+	filtered := collect([]string{"sdb", "sdd"})
+	if !filtered.IsPartial() {
+		t.Errorf("Expected the filtered run to be partial")
+	}
+	for _, event := range filtered.DetectEvents(time.Now()) {
+		if event.Type == model.EventDiskRemoved {
+			t.Errorf("Expected no removed events on a filtered run, got %+v", event)
+		}
+	}
+
+	// 未收集的磁盘仍保留在历史文件中
+	saved, _, err := storage.NewDiskHistoryStorage(filepath.Join(dir, "disk_data.json"), system.NewMockLogger()).LoadDiskData()
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	for _, name := range []string{"sda", "sdb", "sdc", "sdd", "sde"} {
+		if _, ok := saved[name]; !ok {
+			t.Errorf("Expected history to keep %s, got %v", name, saved)
+		}
+	}
+
+	// 再次完整运行时不应把未收集的磁盘当作新增
+	full := collect(nil)
+	if full.IsPartial() {
+		t.Errorf("Expected the full run not to be partial")
+	}
+	if events := full.DetectEvents(time.Now()); len(events) != 0 {
+		t.Errorf("Expected no events on the full run, got %+v", events)
+	}
+}
+
+func TestDiskCollector_CollectionError(t *testing.T) {
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+
+	// sdb的SMART命令失败，存储池信息也无法获取
+	smartErr := errors.New("smartctl timed out")
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk TEST 1T\nsdb disk TEST 1T\n")
+	runner.SetMockError("midclt call pool.query", errors.New("midclt unavailable"))
+	runner.SetMockError("zpool status", errors.New("zpool unavailable"))
+	runner.SetMockError("smartctl -a /dev/sdb", smartErr)
+
+	collector := NewDiskCollector(config, system.NewMockLogger(), runner)
+	diskData, err := collector.Collect(context.Background())
+	if err == nil {
+		t.Fatal("Expected a collection error")
+	}
+
+	// 失败的阶段之外的数据仍然可用
+	if len(diskData.Disks) != 1 || diskData.Disks[0].Name != "sda" {
+		t.Errorf("Expected sda to be collected despite the errors, got %v", diskData.Disks)
+	}
+
+	var collectionErr *CollectionError
+	if !errors.As(err, &collectionErr) {
+		t.Fatalf("Expected a *CollectionError, got %T: %v", err, err)
+	}
+	if collectionErr.Stage(StageList) != nil || collectionErr.Stage(StageSave) != nil {
+		t.Errorf("Expected only pool and SMART stages to fail, got %v", err)
+	}
+	if collectionErr.Stage(StagePool) == nil {
+		t.Errorf("Expected the pool stage to fail, got %v", err)
+	}
+
+	// 通过errors.As取得的CollectionError可以按阶段查看失败原因
+	stageErr := collectionErr.Stage(StageSMART)
+	if stageErr == nil {
+		t.Fatalf("Expected the SMART stage to fail, got %v", err)
+	}
+	if !errors.Is(stageErr, smartErr) || !errors.Is(err, smartErr) {
+		t.Errorf("Expected the SMART stage error to wrap the smartctl failure, got %v", stageErr)
+	}
+	if !strings.Contains(err.Error(), "smart stage failed") || !strings.Contains(err.Error(), "sdb") {
+		t.Errorf("Expected a readable error message, got %q", err.Error())
+	}
+	if collectionErr.Retryable() {
+		t.Errorf("Expected non-timeout failures not to be retryable")
+	}
+
+	// 超时引起的失败可以重试
+	timeout := &CollectionError{}
+	timeout.add(StageSMART, fmt.Errorf("smartctl -a /dev/sda: %w", context.DeadlineExceeded))
+	if !timeout.Retryable() {
+		t.Errorf("Expected timeout failures to be retryable")
+	}
+}
+
+func TestDiskCollector_IncludeBoot(t *testing.T) {
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+
+	// disk.query没有返回启动盘sdc，sdd同时出现在列表和boot-pool中
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("midclt call disk.query", `[
+		{"name": "sda", "model": "ST4000NM", "size": 4000787030016, "type": "HDD"},
+		{"name": "sdd", "model": "Samsung SSD 870 EVO", "size": 250059350016, "type": "SSD"}
+	]`)
+	runner.SetMockOutput("zpool status boot-pool", `  pool: boot-pool
+ state: ONLINE
+config:
+
+	NAME        STATE     READ WRITE CKSUM
+	boot-pool   ONLINE       0     0     0
+	  mirror-0  ONLINE       0     0     0
+	    sdc3    ONLINE       0     0     0
+	    sdd3    ONLINE       0     0     0
+
+errors: No known data errors
+`)
+	runner.SetMockOutput("lsblk -d -n -o ROTA,SIZE,MODEL /dev/sdc", "0 240G KINGSTON SA400S37240G\n")
+
+	// 未设置 --include-boot 时不补充启动盘
+	collector := NewDiskCollector(config, system.NewMockLogger(), runner)
+	diskData, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(diskData.Disks) != 2 {
+		t.Fatalf("Expected 2 disks without --include-boot, got %d", len(diskData.Disks))
+	}
+
+	config.IncludeBoot = true
+	collector = NewDiskCollector(config, system.NewMockLogger(), runner)
+	diskData, err = collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	disks := make(map[string]*model.Disk)
+	for _, disk := range diskData.Disks {
+		disks[disk.Name] = disk
+	}
+	if len(disks) != 3 {
+		t.Fatalf("Expected sda, sdc and sdd, got %v", disks)
+	}
+
+	sdc := disks["sdc"]
+	if sdc == nil || !sdc.Boot || sdc.Pool != model.BootPoolName || sdc.Source != model.DataSourceBootPool {
+		t.Fatalf("Expected sdc to be added as a boot disk, got %+v", sdc)
+	}
+	if sdc.Type != model.DiskTypeSASSSD || sdc.Model != "KINGSTON SA400S37240G" || sdc.Size != "240G" {
+		t.Errorf("Expected sdc details from lsblk, got %s/%s/%s", sdc.Type, sdc.Model, sdc.Size)
+	}
+	if !disks["sdd"].Boot || disks["sdd"].Source != model.DataSourceMidclt {
+		t.Errorf("Expected listed sdd to be marked as a boot disk, got %+v", disks["sdd"])
+	}
+	if disks["sda"].Boot {
+		t.Error("Expected sda not to be a boot disk")
+	}
+
+	// 启动盘也会收集SMART数据
+	smartCollected := false
+	for _, command := range runner.CalledCommands {
+		if command == "smartctl -a /dev/sdc" {
+			smartCollected = true
+		}
+	}
+	if !smartCollected {
+		t.Errorf("Expected SMART data to be collected for sdc, got %v", runner.CalledCommands)
+	}
+}
+
+func TestDiskCollector_DuplicateNames(t *testing.T) {
+	logger := system.NewMockLogger()
+	collector := NewDiskCollector(model.NewDefaultConfig(), logger, system.NewMockCommandRunner())
+
+	// 两个来源报告了同名磁盘sdb，第二条记录的SMART数据更完整
+	sparse := model.NewDisk("sdb", "HDD", "ST4000NM", "4T")
+	sparse.SMARTData["Smart_Status"] = "PASSED"
+	rich := model.NewDisk("sdb", "HDD", "ST4000NM", "4T")
+	rich.SMARTData["Smart_Status"] = "PASSED"
+	rich.SMARTData["Temperature"] = "35"
+	rich.SMARTData["Power_On_Hours"] = "12000"
+	other := model.NewDisk("sda", "HDD", "ST4000NM", "4T")
+
+	disks := collector.dedupeDisks([]*model.Disk{sparse, other, rich})
+	if len(disks) != 2 {
+		t.Fatalf("Expected 2 disks after dedupe, got %d", len(disks))
+	}
+	if disks[0] != rich || disks[1] != other {
+		t.Errorf("Expected the richer sdb record in its first position, got %s/%v", disks[0].Name, disks[0].SMARTData)
+	}
+
+	warned := false
+	for _, msg := range logger.ErrorLogs {
+		if strings.Contains(msg, "sdb") && strings.Contains(msg, "重复") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("Expected a duplicate warning for sdb, got %v", logger.ErrorLogs)
+	}
+
+	// 完整收集时磁盘列表中的重复项只保留一个
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("midclt call disk.query", `[
+		{"name": "sda", "model": "ST4000NM", "size": 4000787030016, "type": "HDD"},
+		{"name": "sda", "model": "ST4000NM", "size": 4000787030016, "type": "HDD"}
+	]`)
+	diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if diskData.GetDiskCount() != 1 || len(diskData.GroupedDisks[model.DiskTypeSASHDD]) != 1 {
+		t.Errorf("Expected a single sda entry, got %d disks (%d grouped)",
+			diskData.GetDiskCount(), len(diskData.GroupedDisks[model.DiskTypeSASHDD]))
+	}
+}
+
+func TestDiskCollector_HistoryStorage(t *testing.T) {
+	// 历史数据通过共用的历史存储写入，使用注入的时钟，并以临时文件原子替换
+	config := model.NewDefaultConfig()
+	dir := t.TempDir()
+	config.DataFile = filepath.Join(dir, "disk_data.json")
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("midclt call disk.query", `[{"name": "sdd", "model": "SEAGATE ST600MM0006", "size": 600127266816, "type": "HDD"}]`)
+	runner.SetMockOutput("smartctl -H /dev/sdd", "SMART Health Status: OK")
+	runner.SetMockOutput("smartctl -a /dev/sdd", sasHDDSmartOutput)
+
+	history := storage.NewDiskHistoryStorage(config.DataFile, system.NewMockLogger())
+	history.SetClock(system.FixedClock{Time: time.Date(2025, 3, 10, 12, 34, 56, 0, time.Local)})
+	collector := NewDiskCollector(config, system.NewMockLogger(), runner)
+	collector.SetHistoryStorage(history)
+	if _, err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	saved, err := os.ReadFile(config.DataFile)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if !strings.Contains(string(saved), `"version": "1.0"`) {
+		t.Errorf("Expected a versioned history file, got %s", saved)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(leftovers) > 0 {
+		t.Errorf("Expected no temporary files, got %v", leftovers)
+	}
+
+	// 第二次运行读取上次的时间戳，按本地时间显示
+	diskData, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if diskData.PreviousTime != "2025-03-10 12:34:56" {
+		t.Errorf("Expected previous time 2025-03-10 12:34:56, got %q", diskData.PreviousTime)
+	}
+}
+
+func TestDiskCollector_NoIncrement(t *testing.T) {
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+	config.NoIncrement = true
+	// 过期的历史数据: 读取量比当前大，比较时会误报计数器重置
+	history := `{"timestamp": "2025-03-01 00:00:00", "disks": {"sdd": {"Data_Read": "300 TB", "Data_Written": "100 TB", "Status": "WARNING"}}}`
+	if err := os.WriteFile(config.DataFile, []byte(history), 0644); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("midclt call disk.query", `[{"name": "sdd", "model": "SEAGATE ST600MM0006", "size": 600127266816, "type": "HDD"}]`)
+	runner.SetMockOutput("smartctl -H /dev/sdd", "SMART Health Status: OK")
+	runner.SetMockOutput("smartctl -a /dev/sdd", sasHDDSmartOutput)
+
+	diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	// 不读取历史数据，因此没有增量，报告中也不会出现增量部分
+	if diskData.HasPreviousData() {
+		t.Errorf("Expected previous data not to be loaded, got %v", diskData.PreviousData)
+	}
+	if len(diskData.Disks) != 1 || diskData.Disks[0].ReadIncrement != "" || diskData.Disks[0].WriteIncrement != "" {
+		t.Fatalf("Expected one disk without increments, got %+v", diskData.Disks)
+	}
+	if events := diskData.DetectEvents(time.Now()); len(events) != 0 {
+		t.Errorf("Expected no events without previous data, got %+v", events)
+	}
+
+	// 本次数据仍然保存，供之后不带--no-increment的运行使用
+	saved, err := os.ReadFile(config.DataFile)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if !strings.Contains(string(saved), "254.85 TiB") {
+		t.Errorf("Expected current data to be saved, got %s", saved)
+	}
+}
+
+func TestDiskCollector_LegacyHistorySizes(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "disk_data.json")
+	// 早期版本保存的历史文件: 没有版本号，读写量按1024换算但标签为TB
+	history := `{"timestamp": "2025-03-01 00:00:00", "disks": {"sdd": {"Data_Read": "254.84 TB"}}}`
+	if err := os.WriteFile(dataFile, []byte(history), 0644); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("midclt call disk.query", `[{"name": "sdd", "model": "SEAGATE ST600MM0006", "size": 600127266816, "type": "HDD"}]`)
+	runner.SetMockOutput("smartctl -H /dev/sdd", "SMART Health Status: OK")
+	runner.SetMockOutput("smartctl -a /dev/sdd", sasHDDSmartOutput)
+
+	// 升级后首次运行和之后切换单位制，读取量都比上次略大，不应判断为重置
+	for _, units := range []model.SizeUnits{model.SizeUnitsDecimal, model.SizeUnitsBinary, model.SizeUnitsDecimal} {
+		config := model.NewDefaultConfig()
+		config.DataFile = dataFile
+		config.SizeUnits = units
+		diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+
+		disk := diskData.Disks[0]
+		if disk.ReadIncrement == "" || disk.ReadIncrement == "重置" || disk.ReadIncrement == "N/A" {
+			t.Errorf("%s: expected a read increment, got %q (previous %q, current %q)",
+				units, disk.ReadIncrement, diskData.PreviousData["sdd"]["Data_Read"], disk.SMARTData["Data_Read"])
+		}
+		for _, event := range diskData.DetectEvents(time.Now()) {
+			if event.Type == model.EventCounterReset {
+				t.Errorf("%s: expected no counter reset, got %+v", units, event)
+			}
+		}
+	}
+}
+
+func TestDiskCollector_PathConsistency(t *testing.T) {
+	logger := system.NewMockLogger()
+	collector := NewDiskCollector(model.NewDefaultConfig(), logger, system.NewMockCommandRunner())
+
+	// sdb和sdc是同一磁盘的两个路径，但通电时间相差很大，说明路径映射有误
+	pathA := model.NewDisk("sdb", "HDD", "ST4000NM", "4T")
+	pathA.Serial = "ZC1234"
+	pathA.SMARTData["Power_On_Hours"] = "20,662 h"
+	pathB := model.NewDisk("sdc", "HDD", "ST4000NM", "4T")
+	pathB.Serial = "ZC1234"
+	pathB.SMARTData["Power_On_Hours"] = "3100"
+	// sdd和sde的两个路径读数只差1小时，属于正常的快照差异
+	pathC := model.NewDisk("sdd", "HDD", "ST4000NM", "4T")
+	pathC.Serial = "ZC5678"
+	pathC.SMARTData["Power_On_Hours"] = "12000"
+	pathD := model.NewDisk("sde", "HDD", "ST4000NM", "4T")
+	pathD.Serial = "ZC5678"
+	pathD.SMARTData["Power_On_Hours"] = "12001"
+
+	collector.checkPathConsistency([]*model.Disk{pathA, pathB, pathC, pathD})
+
+	for _, disk := range []*model.Disk{pathA, pathB} {
+		if disk.GetStatus() != model.DiskStatusWarning {
+			t.Errorf("Expected %s to be a warning, got %s", disk.Name, disk.GetStatus())
+		}
+		if !strings.Contains(disk.StatusReason, "sdb, sdc") || !strings.Contains(disk.StatusReason, "3100-20662") {
+			t.Errorf("Expected reason to name both paths and hours, got %q", disk.StatusReason)
+		}
+	}
+	for _, disk := range []*model.Disk{pathC, pathD} {
+		if strings.Contains(disk.StatusReason, "路径") {
+			t.Errorf("Expected no path warning for %s, got %q", disk.Name, disk.StatusReason)
+		}
+	}
+	if len(logger.ErrorLogs) != 1 || !strings.Contains(logger.ErrorLogs[0], "ZC1234") {
+		t.Errorf("Expected one warning for serial ZC1234, got %v", logger.ErrorLogs)
+	}
+}
+
+func TestDiskCollector_RecordReplay(t *testing.T) {
+	mock := system.NewMockCommandRunner()
+	mock.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+		"sda disk ST4000NM 4T\nnvme0n1 disk Samsung SSD 980 PRO 1T\n")
+	mock.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK")
+	mock.SetMockOutput("smartctl -a /dev/sda", sasHDDSmartOutput)
+	mock.SetMockOutput("smartctl -H /dev/nvme0n1", "SMART overall-health self-assessment test result: PASSED")
+	mock.SetMockOutput("smartctl -a /dev/nvme0n1", nvmeSmartOutput)
+	mock.SetMockOutput("smartctl -i /dev/sda", "Serial number:        S0M1ABCD\nLogical block size:   512 bytes\n")
+
+	collect := func(runner system.CommandRunner) map[string]*model.Disk {
+		t.Helper()
+		// 每次使用新的历史文件，避免第二次收集计算出增量
+		config := model.NewDefaultConfig()
+		config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+
+		diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+		disks := make(map[string]*model.Disk)
+		for _, disk := range diskData.Disks {
+			disks[disk.Name] = disk
+		}
+		return disks
+	}
+
+	recorder := system.NewRecordingCommandRunner(mock)
+	recorded := collect(recorder)
+
+	path := filepath.Join(t.TempDir(), "transcript.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	replay, err := system.LoadReplayCommandRunner(path)
+	if err != nil {
+		t.Fatalf("LoadReplayCommandRunner failed: %v", err)
+	}
+	replayed := collect(replay)
+
+	if len(recorded) != 2 || len(replayed) != len(recorded) {
+		t.Fatalf("Expected 2 disks from both runs, got %d recorded and %d replayed", len(recorded), len(replayed))
+	}
+	for name, want := range recorded {
+		got := replayed[name]
+		if got == nil {
+			t.Errorf("%s: missing from replay", name)
+			continue
+		}
+		if got.Status != want.Status || got.Serial != want.Serial || got.Pool != want.Pool ||
+			got.LogicalSectorSize != want.LogicalSectorSize || !reflect.DeepEqual(got.SMARTData, want.SMARTData) {
+			t.Errorf("%s: replay differs from recording\nrecorded: %+v\nreplayed: %+v", name, want, got)
+		}
+	}
+	if recorded["sda"].Serial != "S0M1ABCD" || recorded["sda"].SMARTData["Temperature"] != "37" {
+		t.Errorf("Expected sda details from the recording, got %+v", recorded["sda"])
+	}
+}
+
+func TestDiskCollector_ByIDLinks(t *testing.T) {
+	byIDOutput := `total 0
+lrwxrwxrwx 1 root root  9 Mar 10 12:00 ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1234567 -> ../../sda
+lrwxrwxrwx 1 root root 10 Mar 10 12:00 ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1234567-part1 -> ../../sda1
+lrwxrwxrwx 1 root root  9 Mar 10 12:00 scsi-SATA_WDC_WD40EFRX-68N_WD-WCC7K1234567 -> ../../sda
+lrwxrwxrwx 1 root root  9 Mar 10 12:00 wwn-0x50014ee2b5c4d1e0 -> ../../sda
+lrwxrwxrwx 1 root root  9 Mar 10 12:00 scsi-35000c500a1b2c3d4 -> ../../sdb
+lrwxrwxrwx 1 root root  9 Mar 10 12:00 scsi-SSEAGATE_ST600MM0006_S0M1ABCD -> ../../sdb
+lrwxrwxrwx 1 root root 13 Mar 10 12:00 nvme-eui.5cd2e42a81a0b1c2 -> ../../nvme0n1
+lrwxrwxrwx 1 root root 13 Mar 10 12:00 nvme-INTEL_SSDPF2KX038TZ_PHAC1234567 -> ../../nvme0n1`
+
+	// 优先使用含型号和序列号的名称，忽略分区链接
+	links := parseByIDLinks(byIDOutput)
+	expected := map[string]string{
+		"sda":     "ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1234567",
+		"sdb":     "scsi-SSEAGATE_ST600MM0006_S0M1ABCD",
+		"nvme0n1": "nvme-INTEL_SSDPF2KX038TZ_PHAC1234567",
+	}
+	if len(links) != len(expected) {
+		t.Errorf("Expected %d links, got %v", len(expected), links)
+	}
+	for name, link := range expected {
+		if links[name] != link {
+			t.Errorf("%s: expected by-id name %s, got %s", name, link, links[name])
+		}
+	}
+
+	// 完整收集时设置磁盘的by-id名称，没有链接的磁盘保持为空
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+	runner := system.NewMockCommandRunner()
+	runner.SetMockOutput("midclt call disk.query", `[
+		{"name": "sda", "model": "WDC WD40EFRX-68N32N0", "size": 4000787030016, "type": "HDD"},
+		{"name": "sdc", "model": "WDC WD40EFRX-68N32N0", "size": 4000787030016, "type": "HDD"}
+	]`)
+	runner.SetMockOutput("ls -l /dev/disk/by-id/", byIDOutput)
+	diskData, err := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	for _, disk := range diskData.Disks {
+		want := expected[disk.Name]
+		if disk.ByID != want {
+			t.Errorf("%s: expected ByID '%s', got '%s'", disk.Name, want, disk.ByID)
+		}
+	}
+}
+
+func TestDiskCollector_FailFast(t *testing.T) {
+	newRunner := func() *system.MockCommandRunner {
+		runner := system.NewMockCommandRunner()
+		runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'",
+			"sda disk TEST 1T\nsdb disk TEST 1T\nsdc disk TEST 1T\n")
+		runner.SetMockError("smartctl -a /dev/sda", errors.New("sda unreadable"))
+		runner.SetMockError("smartctl -a /dev/sdb", errors.New("sdb unreadable"))
+		return runner
+	}
+
+	// 默认继续收集其余磁盘并汇总所有错误
+	config := model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+	diskData, err := NewDiskCollector(config, system.NewMockLogger(), newRunner()).Collect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "sda") || !strings.Contains(err.Error(), "sdb") {
+		t.Errorf("Expected errors for both sda and sdb, got %v", err)
+	}
+	if len(diskData.Disks) != 1 || diskData.Disks[0].Name != "sdc" {
+		t.Errorf("Expected sdc to be collected, got %v", diskData.Disks)
+	}
+
+	// --fail-fast 只返回第一个错误，不返回部分数据也不写历史文件
+	config = model.NewDefaultConfig()
+	config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+	config.FailFast = true
+	diskData, err = NewDiskCollector(config, system.NewMockLogger(), newRunner()).Collect(context.Background())
+
+	var collectionErr *CollectionError
+	if !errors.As(err, &collectionErr) || collectionErr.Stage(StageSMART) == nil {
+		t.Fatalf("Expected a SMART stage error, got %v", err)
+	}
+	smartErr := collectionErr.Stage(StageSMART).Error()
+	if strings.Contains(smartErr, "sda") == strings.Contains(smartErr, "sdb") {
+		t.Errorf("Expected exactly one failing disk in the error, got %q", smartErr)
+	}
+	if len(diskData.Disks) != 0 {
+		t.Errorf("Expected no disks after failing fast, got %d", len(diskData.Disks))
+	}
+	if _, err := os.Stat(config.DataFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no history file after failing fast, got %v", err)
+	}
+}
+
+func TestDiskCollector_TrueNASThresholds(t *testing.T) {
+	newRunner := func() *system.MockCommandRunner {
+		runner := system.NewMockCommandRunner()
+		// sdb单独设置了临界温度，sda使用全局设置
+		runner.SetMockOutput("midclt call disk.query", `[
+			{"name": "sda", "type": "HDD", "model": "TEST", "size": 1000000000000, "informational": null, "critical": null},
+			{"name": "sdb", "type": "HDD", "model": "TEST", "size": 1000000000000, "informational": 0, "critical": 36}
+		]`)
+		runner.SetMockOutput("midclt call smart.config",
+			`{"interval": 30, "powermode": "NEVER", "difference": 0, "informational": 35, "critical": 45}`)
+		for _, name := range []string{"sda", "sdb"} {
+			runner.SetMockOutput("smartctl -H /dev/"+name, "SMART Health Status: OK\n")
+			runner.SetMockOutput("smartctl -a /dev/"+name, "Current Drive Temperature:     37 C\n")
+		}
+		return runner
+	}
+	collect := func(useTrueNAS bool) map[string]*model.Disk {
+		config := model.NewDefaultConfig()
+		config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+		config.UseTrueNASThresholds = useTrueNAS
+		diskData, _ := NewDiskCollector(config, system.NewMockLogger(), newRunner()).Collect(context.Background())
+		disks := make(map[string]*model.Disk)
+		for _, disk := range diskData.Disks {
+			disks[disk.Name] = disk
+		}
+		return disks
+	}
+
+	// 默认不使用TrueNAS的阈值，37°C的HDD为正常
+	disks := collect(false)
+	for _, name := range []string{"sda", "sdb"} {
+		if disks[name] == nil || disks[name].Status != model.DiskStatusOK {
+			t.Fatalf("Expected %s to be OK without TrueNAS thresholds, got %v", name, disks[name])
+		}
+	}
+
+	disks = collect(true)
+	sda, sdb := disks["sda"], disks["sdb"]
+	if sda == nil || sdb == nil {
+		t.Fatalf("Expected sda and sdb to be collected, got %v", disks)
+	}
+	// sda达到全局告警温度35°C，未达到临界温度45°C
+	if sda.Status != model.DiskStatusWarning || !strings.Contains(sda.StatusReason, "35°C") {
+		t.Errorf("Expected sda warning at the global informational threshold, got %s (%s)", sda.Status, sda.StatusReason)
+	}
+	// sdb单独设置的临界温度36°C优先于全局设置
+	if sdb.Status != model.DiskStatusError || !strings.Contains(sdb.StatusReason, "36°C") {
+		t.Errorf("Expected sdb error at its own critical threshold, got %s (%s)", sdb.Status, sdb.StatusReason)
+	}
+}
+
+func TestDiskCollector_SMARTDisabled(t *testing.T) {
+	newRunner := func() *system.MockCommandRunner {
+		runner := system.NewMockCommandRunner()
+		runner.SetMockOutput("lsblk -d -o NAME,TYPE,MODEL,SIZE -n | grep 'disk'", "sda disk TEST 1T\n")
+		runner.SetMockOutput("smartctl -a /dev/sda", "Current Drive Temperature:     37 C\n")
+		runner.SetMockOutput("smartctl -i /dev/sda", "Serial number:        S0M1ABCD\n"+
+			"SMART support is:     Available - device has SMART capability.\n"+
+			"SMART support is:     Disabled\n")
+		return runner
+	}
+	collect := func(enable bool, runner *system.MockCommandRunner) *model.Disk {
+		config := model.NewDefaultConfig()
+		config.DataFile = filepath.Join(t.TempDir(), "disk_data.json")
+		config.EnableSMART = enable
+		diskData, _ := NewDiskCollector(config, system.NewMockLogger(), runner).Collect(context.Background())
+		if len(diskData.Disks) != 1 {
+			t.Fatalf("Expected sda to be collected, got %v", diskData.Disks)
+		}
+		return diskData.Disks[0]
+	}
+
+	// 默认只报告SMART已禁用，不修改磁盘设置
+	runner := newRunner()
+	disk := collect(false, runner)
+	if disk.Status != model.DiskStatusWarning || !strings.Contains(disk.StatusReason, "SMART已禁用") {
+		t.Errorf("Expected a SMART disabled warning, got %s (%s)", disk.Status, disk.StatusReason)
+	}
+	for _, command := range runner.CalledCommands {
+		if strings.HasPrefix(command, "smartctl -s") {
+			t.Errorf("Expected SMART to be left disabled without --enable-smart, got %q", command)
+		}
+	}
+
+	// --enable-smart 启用SMART后重新收集
+	runner = newRunner()
+	runner.SetMockOutput("smartctl -s on /dev/sda", "SMART Enabled.\n")
+	runner.SetMockOutput("smartctl -H /dev/sda", "SMART Health Status: OK\n")
+	disk = collect(true, runner)
+	if !contains(runner.CalledCommands, "smartctl -s on /dev/sda") {
+		t.Errorf("Expected smartctl -s on to be run, got %v", runner.CalledCommands)
+	}
+	if disk.Status != model.DiskStatusOK || disk.SMARTData[model.SmartSupportAttribute] != "" {
+		t.Errorf("Expected sda to be OK once SMART is enabled, got %s (%s)", disk.Status, disk.StatusReason)
+	}
+}
@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// maxEnduranceGroups 每个控制器最多查询的耐久组数量，防止异常的endgidmax导致大量命令
+const maxEnduranceGroups = 16
+
+// hasNVMeCLI 检查nvme-cli是否可用，只检查一次
+func (s *SMARTCollector) hasNVMeCLI(ctx context.Context) bool {
+	s.nvmeCLIOnce.Do(func() {
+		output, err := s.commandRunner.Run(ctx, "command -v nvme")
+		s.nvmeCLI = err == nil && strings.TrimSpace(output) != ""
+		if !s.nvmeCLI {
+			s.logger.Info("未找到nvme-cli，跳过NVMe耐久组数据收集")
+		}
+	})
+	return s.nvmeCLI
+}
+
+// collectEnduranceGroups 使用nvme-cli收集NVMe控制器各耐久组的寿命数据(--nvme-extended)
+// 耐久组是控制器级别的数据，命名空间设备使用其所属控制器；不支持耐久组的磁盘不记录任何数据
+func (s *SMARTCollector) collectEnduranceGroups(ctx context.Context, diskName string, smartData map[string]string) {
+	if !s.hasNVMeCLI(ctx) {
+		return
+	}
+
+	controller := diskName
+	if name, _, ok := splitNVMeNamespace(diskName); ok {
+		controller = name
+	}
+
+	output, err := s.commandRunner.Run(ctx, fmt.Sprintf("nvme id-ctrl /dev/%s -o json", controller))
+	if err != nil {
+		s.logger.Debug("获取%s的控制器信息失败: %v", controller, err)
+		return
+	}
+	groupCount, err := parseEnduranceGroupMax(output)
+	if err != nil {
+		s.logger.Debug("解析%s的控制器信息失败: %v", controller, err)
+		return
+	}
+	if groupCount > maxEnduranceGroups {
+		groupCount = maxEnduranceGroups
+	}
+
+	for id := 1; id <= groupCount; id++ {
+		output, err := s.commandRunner.Run(ctx, fmt.Sprintf("nvme endurance-log /dev/%s --group-id=%d -o json", controller, id))
+		if err != nil {
+			s.logger.Debug("获取%s耐久组%d的日志失败: %v", controller, id, err)
+			continue
+		}
+		fields, err := parseEnduranceLog(output)
+		if err != nil {
+			s.logger.Debug("解析%s耐久组%d的日志失败: %v", controller, id, err)
+			continue
+		}
+		for field, value := range fields {
+			smartData[model.EnduranceGroupAttribute(id, field)] = value
+		}
+	}
+}
+
+// parseEnduranceGroupMax 从nvme id-ctrl的JSON输出中获取耐久组最大编号(endgidmax)，不支持时为0
+func parseEnduranceGroupMax(output string) (int, error) {
+	var idCtrl map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &idCtrl); err != nil {
+		return 0, err
+	}
+	groupMax, _ := jsonNumber(idCtrl["endgidmax"])
+	return int(groupMax), nil
+}
+
+// enduranceLogFields nvme endurance-log的JSON字段与耐久组属性名后缀的对应关系
+var enduranceLogFields = map[string]string{
+	"critical_warning": model.EnduranceCriticalWarning,
+	"percent_used":     model.EndurancePercentUsed,
+	"avl_spare":        model.EnduranceAvailableSpare,
+}
+
+// parseEnduranceLog 从nvme endurance-log的JSON输出中提取严重警告、已用寿命和可用备用空间
+// 纯函数，不执行命令；数值可能为数字或字符串，取决于nvme-cli版本
+func parseEnduranceLog(output string) (map[string]string, error) {
+	var log map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	for key, field := range enduranceLogFields {
+		if value, ok := jsonNumber(log[key]); ok {
+			fields[field] = strconv.Itoa(int(value))
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no endurance fields found")
+	}
+	return fields, nil
+}
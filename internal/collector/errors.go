@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CollectionStage 磁盘数据收集的阶段
+type CollectionStage string
+
+const (
+	// StageList 获取磁盘列表(midclt/lsblk)
+	StageList CollectionStage = "list"
+	// StagePool 获取存储池信息(midclt/zpool)
+	StagePool CollectionStage = "pool"
+	// StageSMART 收集SMART数据
+	StageSMART CollectionStage = "smart"
+	// StageSave 保存历史数据
+	StageSave CollectionStage = "save"
+)
+
+// StageError 某个收集阶段的失败
+type StageError struct {
+	Stage CollectionStage
+	Err   error
+}
+
+// Error 实现error接口
+func (e *StageError) Error() string {
+	return fmt.Sprintf("%s stage failed: %v", e.Stage, e.Err)
+}
+
+// Unwrap 返回底层错误，便于使用errors.Is/errors.As
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable 判断失败是否由超时引起，重试可能成功
+func (e *StageError) Retryable() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// CollectionError 汇总一次收集中各阶段的失败，收集到的数据仍然可用
+type CollectionError struct {
+	Stages []*StageError
+}
+
+// add 记录一个阶段的失败
+func (e *CollectionError) add(stage CollectionStage, err error) {
+	e.Stages = append(e.Stages, &StageError{Stage: stage, Err: err})
+}
+
+// Error 实现error接口，每个阶段的失败以"; "分隔
+func (e *CollectionError) Error() string {
+	messages := make([]string, 0, len(e.Stages))
+	for _, stage := range e.Stages {
+		messages = append(messages, stage.Error())
+	}
+	return "disk data collection: " + strings.Join(messages, "; ")
+}
+
+// Unwrap 返回各阶段的错误，errors.Is/errors.As会逐个检查
+func (e *CollectionError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Stages))
+	for _, stage := range e.Stages {
+		errs = append(errs, stage)
+	}
+	return errs
+}
+
+// Stage 获取指定阶段的失败，该阶段成功时返回nil
+func (e *CollectionError) Stage(stage CollectionStage) *StageError {
+	for _, s := range e.Stages {
+		if s.Stage == stage {
+			return s
+		}
+	}
+	return nil
+}
+
+// Retryable 判断是否所有失败都可以通过重试解决
+func (e *CollectionError) Retryable() bool {
+	for _, stage := range e.Stages {
+		if !stage.Retryable() {
+			return false
+		}
+	}
+	return len(e.Stages) > 0
+}
+
+// errOrNil 没有失败时返回nil，避免返回非nil的空错误
+func (e *CollectionError) errOrNil() error {
+	if len(e.Stages) == 0 {
+		return nil
+	}
+	return e
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
@@ -17,6 +18,10 @@ type PoolCollector struct {
 	config        *model.Config
 	logger        system.Logger
 	commandRunner system.CommandRunner
+	poolStatus    map[string]string  // 最近一次收集到的存储池状态
+	poolSource    string             // 最近一次存储池信息的来源(midclt, zpool)
+	poolUsage     map[string]float64 // 最近一次收集到的存储池已用容量百分比
+	poolLayout    map[string][]model.PoolVdev // 最近一次收集到的存储池数据vdev布局
 }
 
 // NewPoolCollector 创建一个新的存储池收集器
@@ -25,23 +30,58 @@ func NewPoolCollector(config *model.Config, logger system.Logger, runner system.
 		config:        config,
 		logger:        logger,
 		commandRunner: runner,
+		poolStatus:    make(map[string]string),
+		poolUsage:     make(map[string]float64),
+		poolLayout:    make(map[string][]model.PoolVdev),
 	}
 }
 
+// GetPoolStatus 获取最近一次收集到的存储池状态(池名称 -> ONLINE/DEGRADED等)
+func (p *PoolCollector) GetPoolStatus() map[string]string {
+	return p.poolStatus
+}
+
+// GetPoolUsage 获取最近一次收集到的存储池已用容量百分比(池名称 -> 0-100)
+func (p *PoolCollector) GetPoolUsage() map[string]float64 {
+	return p.poolUsage
+}
+
+// GetPoolLayout 获取最近一次收集到的存储池数据vdev布局，仅midclt提供
+func (p *PoolCollector) GetPoolLayout() map[string][]model.PoolVdev {
+	return p.poolLayout
+}
+
+// GetPoolSource 获取最近一次存储池信息的来源，未获取到时为空
+func (p *PoolCollector) GetPoolSource() string {
+	return p.poolSource
+}
+
 // Collect 收集存储池信息
 func (p *PoolCollector) Collect(ctx context.Context) (map[string]string, error) {
 	// 首先尝试从midclt获取
+	p.poolSource = ""
+	p.poolStatus = make(map[string]string)
+	p.poolUsage = make(map[string]float64)
+	p.poolLayout = make(map[string][]model.PoolVdev)
 	poolInfo, err := p.GetPoolInfo(ctx)
+	source := model.DataSourceMidclt
 	if err != nil || len(poolInfo) == 0 {
 		p.logger.Info("从midclt获取池信息失败，尝试从zfs命令获取")
 
 		// 如果失败，尝试从zfs命令获取
 		poolInfo, err = p.GetPoolNameFromZFS(ctx)
+		source = model.DataSourceZpool
 		if err != nil || len(poolInfo) == 0 {
 			p.logger.Error("无法获取存储池信息: %v", err)
 			return make(map[string]string), err
 		}
 	}
+	p.poolSource = source
+
+	// pool.query在部分版本中不返回容量字段，此时从zpool list获取
+	if len(p.poolUsage) == 0 {
+		p.collectZpoolUsage(ctx)
+	}
 
 	// 记录找到的池和磁盘数量
 	pools := make(map[string]bool)
@@ -90,6 +130,18 @@ func (p *PoolCollector) GetPoolInfo(ctx context.Context) (map[string]string, err
 
 		p.logger.Debug("处理存储池: %s", poolName)
 
+		// 记录存储池状态
+		if status, ok := pool["status"].(string); ok && status != "" {
+			p.poolStatus[poolName] = status
+		}
+
+		// 记录存储池容量使用率
+		size, sizeOK := jsonNumber(pool["size"])
+		allocated, allocatedOK := jsonNumber(pool["allocated"])
+		if sizeOK && allocatedOK && size > 0 {
+			p.poolUsage[poolName] = allocated / size * 100
+		}
+
 		// 获取拓扑信息
 		topology, ok := pool["topology"].(map[string]interface{})
 		if !ok {
@@ -123,6 +175,16 @@ func (p *PoolCollector) GetPoolInfo(ctx context.Context) (map[string]string, err
 				vdevTypeInfo, _ := vdevMap["type"].(string)
 				p.logger.Debug("处理vdev类型: %s", vdevTypeInfo)
 
+				// 记录数据vdev的布局，用于估算可用容量和检查成员容量是否一致
+				if vdevTypeName == "data" {
+					names, sizes := vdevMembers(vdevMap)
+					p.poolLayout[poolName] = append(p.poolLayout[poolName], model.PoolVdev{
+						Type:  vdevTypeInfo,
+						Disks: names,
+						Sizes: sizes,
+					})
+				}
+
 				// 处理children，对于RAID和镜像配置
 				if children, ok := vdevMap["children"].([]interface{}); ok && len(children) > 0 {
 					for _, child := range children {
@@ -186,6 +248,33 @@ func (p *PoolCollector) GetPoolInfo(ctx context.Context) (map[string]string, err
 	return diskToPool, nil
 }
 
+// vdevMembers 获取vdev的成员磁盘名称和容量(stats.size，字节)，单盘vdev没有children
+func vdevMembers(vdev map[string]interface{}) ([]string, []float64) {
+	members := []map[string]interface{}{vdev}
+	if children, ok := vdev["children"].([]interface{}); ok && len(children) > 0 {
+		members = members[:0]
+		for _, child := range children {
+			if childMap, ok := child.(map[string]interface{}); ok {
+				members = append(members, childMap)
+			}
+		}
+	}
+
+	// 没有磁盘名称的成员记为空字符串，使可用容量估算不可用而不是偏小
+	names := make([]string, 0, len(members))
+	sizes := make([]float64, 0, len(members))
+	for _, member := range members {
+		disk, _ := member["disk"].(string)
+		names = append(names, disk)
+
+		// 没有容量统计的成员记为0，由磁盘容量补全
+		stats, _ := member["stats"].(map[string]interface{})
+		size, _ := jsonNumber(stats["size"])
+		sizes = append(sizes, size)
+	}
+	return names, sizes
+}
+
 // GetPoolNameFromZFS 从zfs命令获取磁盘到池的映射（备用方法）
 func (p *PoolCollector) GetPoolNameFromZFS(ctx context.Context) (map[string]string, error) {
 	p.logger.Info("尝试从zfs命令获取池信息")
@@ -214,6 +303,12 @@ func (p *PoolCollector) GetPoolNameFromZFS(ctx context.Context) (map[string]stri
 			continue
 		}
 
+		// 记录存储池状态
+		if currentPool != "" && strings.HasPrefix(line, "state:") {
+			p.poolStatus[currentPool] = strings.TrimSpace(strings.TrimPrefix(line, "state:"))
+			continue
+		}
+
 		// 跳过不相关的行
 		if currentPool == "" ||
 			strings.Contains(line, "state:") ||
@@ -255,6 +350,99 @@ func (p *PoolCollector) GetPoolNameFromZFS(ctx context.Context) (map[string]stri
 	return diskToPool, nil
 }
 
+// partitionPatterns 匹配分区名称(sda3, nvme0n1p2)，第一个分组为所在磁盘
+var partitionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^(nvme\d+n\d+)p\d+$`),
+	regexp.MustCompile(`^((?:sd|vd|xvd)[a-z]+)\d+$`),
+}
+
+// partitionDisk 获取分区所在的磁盘名称，整盘名称原样返回
+func partitionDisk(name string) string {
+	for _, pattern := range partitionPatterns {
+		if match := pattern.FindStringSubmatch(name); match != nil {
+			return match[1]
+		}
+	}
+	return name
+}
+
+// GetBootPoolDisks 从zpool status boot-pool获取启动盘所在的磁盘名称
+func (p *PoolCollector) GetBootPoolDisks(ctx context.Context) ([]string, error) {
+	output, err := p.commandRunner.Run(ctx, "zpool status "+model.BootPoolName)
+	if err != nil {
+		return nil, fmt.Errorf("执行zpool status %s命令失败: %w", model.BootPoolName, err)
+	}
+
+	var disks []string
+	seen := make(map[string]bool)
+	inConfig := false
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(fields[0], "config:"):
+			inConfig = true
+			continue
+		case strings.HasPrefix(fields[0], "errors:"):
+			inConfig = false
+			continue
+		}
+		if !inConfig || fields[0] == "NAME" || fields[0] == model.BootPoolName ||
+			strings.HasPrefix(fields[0], "mirror") || strings.HasPrefix(fields[0], "raidz") {
+			continue
+		}
+
+		name := partitionDisk(filepath.Base(fields[0]))
+		if !seen[name] {
+			seen[name] = true
+			disks = append(disks, name)
+		}
+	}
+
+	p.logger.Debug("从zpool status %s获取到启动盘: %v", model.BootPoolName, disks)
+	return disks, nil
+}
+
+// collectZpoolUsage 从zpool list获取各存储池的已用容量百分比
+func (p *PoolCollector) collectZpoolUsage(ctx context.Context) {
+	output, err := p.commandRunner.Run(ctx, "zpool list -Hp -o name,size,allocated")
+	if err != nil {
+		p.logger.Debug("获取存储池容量失败: %v", err)
+		return
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || size <= 0 {
+			continue
+		}
+		allocated, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+		p.poolUsage[fields[0]] = allocated / size * 100
+	}
+}
+
+// jsonNumber 将midclt返回的数值(数字或数字字符串)转换为float64
+func jsonNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 // contains 检查切片中是否包含指定字符串
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
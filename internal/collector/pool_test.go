@@ -281,3 +281,99 @@ errors: No known data errors
 		t.Errorf("Expected empty mapping when both commands fail, got %d entries", len(poolInfo))
 	}
 }
+
+func TestPoolCollector_PoolUsage(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	config := model.NewDefaultConfig()
+
+	// midclt返回容量字段时直接计算使用率
+	mockRunner.SetMockOutput("midclt call pool.query", `[
+		{"name": "tank", "size": 1000, "allocated": 850,
+		 "topology": {"data": [{"type": "DISK", "disk": "sda"}]}},
+		{"name": "apps", "size": "2000", "allocated": "1000",
+		 "topology": {"data": [{"type": "DISK", "disk": "sdb"}]}}
+	]`)
+	collector := NewPoolCollector(config, system.NewMockLogger(), mockRunner)
+	if _, err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	usage := collector.GetPoolUsage()
+	if usage["tank"] != 85 || usage["apps"] != 50 {
+		t.Errorf("Expected tank=85 apps=50, got %v", usage)
+	}
+
+	// 没有容量字段时从zpool list获取
+	mockRunner = system.NewMockCommandRunner()
+	mockRunner.SetMockOutput("midclt call pool.query", `[
+		{"name": "tank", "topology": {"data": [{"type": "DISK", "disk": "sda"}]}}
+	]`)
+	mockRunner.SetMockOutput("zpool list -Hp -o name,size,allocated", "tank\t4000\t3400\nboot-pool\t100\t10\n")
+	collector = NewPoolCollector(config, system.NewMockLogger(), mockRunner)
+	if _, err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	usage = collector.GetPoolUsage()
+	if usage["tank"] != 85 || usage["boot-pool"] != 10 {
+		t.Errorf("Expected tank=85 boot-pool=10 from zpool list, got %v", usage)
+	}
+}
+
+func TestPoolCollector_VdevMemberSizes(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	mockRunner.SetMockOutput("midclt call pool.query", `[
+		{"name": "tank", "topology": {"data": [
+			{"type": "MIRROR", "children": [
+				{"disk": "sda", "stats": {"size": 3998639460352}},
+				{"disk": "sdb", "stats": {"size": 7999415652352}}
+			]},
+			{"type": "DISK", "disk": "sdc"}
+		]}}
+	]`)
+
+	collector := NewPoolCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+	if _, err := collector.GetPoolInfo(context.Background()); err != nil {
+		t.Fatalf("GetPoolInfo failed: %v", err)
+	}
+
+	// 镜像成员记录stats.size，没有统计信息的成员为0
+	vdevs := collector.GetPoolLayout()["tank"]
+	if len(vdevs) != 2 {
+		t.Fatalf("Expected 2 data vdevs, got %d", len(vdevs))
+	}
+	if sizes := vdevs[0].Sizes; len(sizes) != 2 || sizes[0] != 3998639460352 || sizes[1] != 7999415652352 {
+		t.Errorf("Unexpected mirror member sizes: %v", sizes)
+	}
+	if !vdevs[0].HasMixedCapacity() {
+		t.Error("Expected the 4TB + 8TB mirror to be flagged")
+	}
+	if sizes := vdevs[1].Sizes; len(sizes) != 1 || sizes[0] != 0 {
+		t.Errorf("Expected an unknown size for sdc, got %v", sizes)
+	}
+}
+
+func TestPoolCollector_RemovedPoolStatus(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	mockRunner.SetMockOutput("midclt call pool.query", `[
+		{"name": "tank", "status": "ONLINE", "topology": {"data": [{"type": "DISK", "disk": "sda"}]}},
+		{"name": "backup", "status": "DEGRADED", "topology": {"data": [{"type": "DISK", "disk": "sdb"}]}}
+	]`)
+	collector := NewPoolCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+	if _, err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if collector.GetPoolStatus()["backup"] != "DEGRADED" {
+		t.Fatalf("Expected backup to be DEGRADED, got %v", collector.GetPoolStatus())
+	}
+
+	// 存储池导出后不应继续报告上一次收集到的状态
+	mockRunner.SetMockOutput("midclt call pool.query", `[
+		{"name": "tank", "status": "ONLINE", "topology": {"data": [{"type": "DISK", "disk": "sda"}]}}
+	]`)
+	if _, err := collector.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	status := collector.GetPoolStatus()
+	if _, ok := status["backup"]; ok || len(status) != 1 {
+		t.Errorf("Expected only tank after backup was exported, got %v", status)
+	}
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// smartctl -g apm -g dsn 的输出行，如 "APM level is:     128 (minimum power consumption without standby)"
+var (
+	apmLevelPattern   = regexp.MustCompile(`(?m)^APM level is:\s+(\d+)`)
+	apmFeaturePattern = regexp.MustCompile(`(?m)^APM feature is:\s+(\w+)`)
+	dsnFeaturePattern = regexp.MustCompile(`(?m)^DSN feature is:\s+(\w+)`)
+)
+
+// collectPowerSettings 收集SATA磁盘的APM级别和DSN状态(--show-power)
+// 不支持这些功能的磁盘(如SAS)由smartctl报告为Unavailable
+func (s *SMARTCollector) collectPowerSettings(ctx context.Context, diskName string, smartData map[string]string) {
+	output, _, err := s.runSmartctl(ctx, fmt.Sprintf("smartctl -g apm -g dsn /dev/%s", diskName))
+	if err != nil {
+		s.logger.Debug("获取%s的电源管理设置失败: %v", diskName, err)
+		return
+	}
+	for key, value := range parsePowerSettings(output) {
+		smartData[key] = value
+	}
+}
+
+// parsePowerSettings 从smartctl -g apm -g dsn的输出中提取APM级别和DSN状态
+// 纯函数，不执行命令；APM启用时记录级别，否则记录功能状态(Disabled/Unavailable)
+func parsePowerSettings(output string) map[string]string {
+	settings := make(map[string]string)
+	if match := apmLevelPattern.FindStringSubmatch(output); len(match) > 1 {
+		settings[model.APMLevelAttribute] = match[1]
+	} else if match := apmFeaturePattern.FindStringSubmatch(output); len(match) > 1 {
+		settings[model.APMLevelAttribute] = match[1]
+	}
+	if match := dsnFeaturePattern.FindStringSubmatch(output); len(match) > 1 {
+		settings[model.DSNStateAttribute] = match[1]
+	}
+	return settings
+}
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
 	"github.com/MaurUppi/disk-health-monitor/internal/system"
@@ -17,6 +18,14 @@ type SMARTCollector struct {
 	config        *model.Config
 	logger        system.Logger
 	commandRunner system.CommandRunner
+
+	// 温度属于NVMe控制器而非命名空间，同一控制器的多个命名空间共享首次读取的温度
+	nvmeTempMu sync.Mutex
+	nvmeTemps  map[string]string
+
+	// nvme-cli只在--nvme-extended时使用，是否可用只检查一次
+	nvmeCLIOnce sync.Once
+	nvmeCLI     bool
 }
 
 // NewSMARTCollector 创建一个新的SMART数据收集器
@@ -25,7 +34,34 @@ func NewSMARTCollector(config *model.Config, logger system.Logger, runner system
 		config:        config,
 		logger:        logger,
 		commandRunner: runner,
+		nvmeTemps:     make(map[string]string),
+	}
+}
+
+// nvmeNamespaceRegex 匹配NVMe命名空间设备名，如 nvme0n2
+var nvmeNamespaceRegex = regexp.MustCompile(`^(nvme\d+)n(\d+)$`)
+
+// splitNVMeNamespace 将命名空间设备名拆分为控制器名和命名空间ID，如 nvme0n2 -> nvme0, 2
+func splitNVMeNamespace(diskName string) (string, string, bool) {
+	matches := nvmeNamespaceRegex.FindStringSubmatch(diskName)
+	if len(matches) < 3 {
+		return "", "", false
 	}
+	return matches[1], matches[2], true
+}
+
+// sharedNVMeTemperature 返回控制器的温度，首个命名空间读取的温度会被缓存供其他命名空间使用
+func (s *SMARTCollector) sharedNVMeTemperature(controller, temperature string) string {
+	s.nvmeTempMu.Lock()
+	defer s.nvmeTempMu.Unlock()
+
+	if cached, ok := s.nvmeTemps[controller]; ok {
+		return cached
+	}
+	if temperature != "" {
+		s.nvmeTemps[controller] = temperature
+	}
+	return temperature
 }
 
 // GetSMARTData 获取磁盘的SMART数据
@@ -40,7 +76,7 @@ func (s *SMARTCollector) GetSMARTData(ctx context.Context, diskName, diskType st
 		// 虚拟设备没有真正的SMART数据
 		return map[string]string{
 			"Type":         "虚拟设备",
-			"Smart_Status": "虚拟设备",
+			"Smart_Status": model.SmartStatusVirtual,
 		}, nil
 	default:
 		// SAS/SATA磁盘处理
@@ -48,6 +84,40 @@ func (s *SMARTCollector) GetSMARTData(ctx context.Context, diskName, diskType st
 	}
 }
 
+// runSmartctl 执行smartctl命令，返回输出和退出码
+// smartctl的退出码是状态位掩码，只有命令行错误或无法打开设备时输出才无效，
+// 其他非零退出码(如错误日志中有记录)仍返回输出，由调用方记录退出码
+func (s *SMARTCollector) runSmartctl(ctx context.Context, command string) (string, int, error) {
+	output, exitCode, err := s.commandRunner.RunWithStatus(ctx, command)
+	if err != nil {
+		return "", 0, err
+	}
+	if exitCode == 0 {
+		return output, 0, nil
+	}
+
+	if exitCode&model.SmartctlFatalBits != 0 || output == "" {
+		return "", 0, fmt.Errorf("smartctl exited with status %d [%s]: %s", exitCode, command, output)
+	}
+	s.logger.Debug("smartctl退出码 %d，继续解析输出: %s", exitCode, command)
+	return output, exitCode, nil
+}
+
+// smartDetailCommand 获取SMART详情命令，--smart-detail minimal时只读取属性(-A)
+func (s *SMARTCollector) smartDetailCommand(diskName string) string {
+	if s.config.SMARTDetail == model.SMARTDetailMinimal {
+		return fmt.Sprintf("smartctl -A /dev/%s", diskName)
+	}
+	return fmt.Sprintf("smartctl -a /dev/%s", diskName)
+}
+
+// recordSmartctlExitCode 记录smartctl -a的非零退出码，由磁盘模型转换为状态
+func recordSmartctlExitCode(smartData map[string]string, exitCode int) {
+	if exitCode != 0 {
+		smartData[model.SmartctlExitStatusAttribute] = strconv.Itoa(exitCode)
+	}
+}
+
 // getNVMeSmartData 获取NVMe磁盘的SMART数据
 func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string) (map[string]string, error) {
 	smartData := make(map[string]string)
@@ -56,30 +126,50 @@ func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string)
 	vendorCheck, _ := s.commandRunner.Run(ctx, fmt.Sprintf("smartctl -i /dev/%s | grep 'PCI Vendor'", diskName))
 	if strings.Contains(vendorCheck, "0x15ad") {
 		s.logger.Debug("%s是VMware虚拟设备，跳过详细SMART数据收集", diskName)
-		smartData["Smart_Status"] = "虚拟设备"
+		smartData["Smart_Status"] = model.SmartStatusVirtual
 		smartData["Type"] = "虚拟NVMe设备"
 		return smartData, nil
 	}
 
 	// 获取健康状态
-	healthOutput, _ := s.commandRunner.Run(ctx, fmt.Sprintf("smartctl -H /dev/%s", diskName))
-	if healthOutput != "" {
-		smartStatus := "未知"
-		if strings.Contains(healthOutput, "PASSED") {
-			smartStatus = "PASSED"
-		} else if strings.Contains(healthOutput, "OK") {
-			smartStatus = "OK"
-		} else if strings.Contains(healthOutput, "FAILED") {
-			smartStatus = "FAILED"
-		}
+	healthOutput, _, _ := s.runSmartctl(ctx, fmt.Sprintf("smartctl -H /dev/%s", diskName))
+	if smartStatus, ok := parseHealthStatus(healthOutput); ok {
 		smartData["Smart_Status"] = smartStatus
 	}
 
-	// 获取SMART详情
-	output, err := s.commandRunner.Run(ctx, fmt.Sprintf("smartctl -a /dev/%s", diskName))
+	// 获取SMART详情，使用命名空间设备(如nvme0n2)以获得该命名空间自身的容量和使用量
+	output, exitCode, err := s.runSmartctl(ctx, s.smartDetailCommand(diskName))
 	if err != nil {
 		return smartData, fmt.Errorf("获取NVMe SMART数据失败: %w", err)
 	}
+	recordSmartctlExitCode(smartData, exitCode)
+
+	for key, value := range s.normalizeSizes(parseNVMeSMART(output)) {
+		smartData[key] = value
+	}
+
+	// 温度是控制器级别的数据，同一控制器只取一次
+	if controller, _, ok := splitNVMeNamespace(diskName); ok {
+		if temperature := s.sharedNVMeTemperature(controller, smartData["Temperature"]); temperature != "" {
+			smartData["Temperature"] = temperature
+		}
+	}
+
+	// 耐久组数据需要nvme-cli，默认不收集
+	if s.config.NVMeExtended {
+		s.collectEnduranceGroups(ctx, diskName, smartData)
+	}
+
+	return smartData, nil
+}
+
+// nvmeSensorPattern 匹配NVMe温度传感器行，如 "Temperature Sensor 2:               61 Celsius"
+var nvmeSensorPattern = regexp.MustCompile(`(?m)^Temperature Sensor (\d+):\s+(\d+) Celsius`)
+
+// parseNVMeSMART 从NVMe磁盘的smartctl -a输出中提取SMART数据
+// 纯函数，不执行命令；读写数据量保留smartctl的原始单位，由normalizeSizes统一换算
+func parseNVMeSMART(output string) map[string]string {
+	smartData := make(map[string]string)
 
 	// 提取温度
 	tempMatch := regexp.MustCompile(`Temperature:\s+(\d+)\s+Celsius`).FindStringSubmatch(output)
@@ -94,6 +184,11 @@ func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string)
 		}
 	}
 
+	// 提取各温度传感器的读数，状态判断使用其中的最高值
+	for _, match := range nvmeSensorPattern.FindAllStringSubmatch(output, -1) {
+		smartData[model.TemperatureSensorPrefix+match[1]] = match[2]
+	}
+
 	// 提取警告温度和临界温度
 	warningTempMatch := regexp.MustCompile(`Warning\s+Comp\.\s+Temp\.\s+Threshold:\s+(\d+)\s+Celsius`).FindStringSubmatch(output)
 	if len(warningTempMatch) > 1 {
@@ -105,6 +200,17 @@ func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string)
 		smartData["Critical_Temperature"] = criticalTempMatch[1]
 	}
 
+	// 提取超过警告/临界温度的累计时间(分钟)，非零表示发生过温度降频
+	warningTimeMatch := regexp.MustCompile(`Warning\s+Comp\.\s+Temperature\s+Time:\s+(\d+[,\d]*)`).FindStringSubmatch(output)
+	if len(warningTimeMatch) > 1 {
+		smartData["Warning_Temp_Time"] = strings.ReplaceAll(warningTimeMatch[1], ",", "")
+	}
+
+	criticalTimeMatch := regexp.MustCompile(`Critical\s+Comp\.\s+Temperature\s+Time:\s+(\d+[,\d]*)`).FindStringSubmatch(output)
+	if len(criticalTimeMatch) > 1 {
+		smartData["Critical_Temp_Time"] = strings.ReplaceAll(criticalTimeMatch[1], ",", "")
+	}
+
 	// 提取通电时间
 	hoursPatterns := []string{
 		`Power On Hours:\s+(\d+[,\d]*)`,
@@ -116,7 +222,7 @@ func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string)
 	for _, pattern := range hoursPatterns {
 		hoursMatch := regexp.MustCompile(pattern).FindStringSubmatch(output)
 		if len(hoursMatch) > 1 {
-			smartData["Power_On_Hours"] = strings.ReplaceAll(hoursMatch[1], ",", "")
+			smartData["Power_On_Hours"] = model.NormalizeNumber(hoursMatch[1])
 			break
 		}
 	}
@@ -140,13 +246,25 @@ func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string)
 	readMatch := regexp.MustCompile(`Data Units Read:\s+(\d+[,\d]*)\s+\[([^\]]+)\]`).FindStringSubmatch(output)
 	if len(readMatch) > 2 {
 		sizeRead := strings.TrimSpace(readMatch[2])
-		smartData["Data_Read"] = s.normalizeSize(sizeRead)
+		smartData["Data_Read"] = sizeRead
 	}
 
 	writeMatch := regexp.MustCompile(`Data Units Written:\s+(\d+[,\d]*)\s+\[([^\]]+)\]`).FindStringSubmatch(output)
 	if len(writeMatch) > 2 {
 		sizeWritten := strings.TrimSpace(writeMatch[2])
-		smartData["Data_Written"] = s.normalizeSize(sizeWritten)
+		smartData["Data_Written"] = sizeWritten
+	}
+
+	// 提取命名空间级别的容量和使用量(多命名空间时各不相同)
+	nsSizeMatch := regexp.MustCompile(`Namespace (\d+) Size/Capacity:\s+[\d,]+\s+\[([^\]]+)\]`).FindStringSubmatch(output)
+	if len(nsSizeMatch) > 2 {
+		smartData["Namespace_ID"] = nsSizeMatch[1]
+		smartData["Namespace_Size"] = strings.TrimSpace(nsSizeMatch[2])
+	}
+
+	nsUtilMatch := regexp.MustCompile(`Namespace \d+ Utilization:\s+[\d,]+\s+\[([^\]]+)\]`).FindStringSubmatch(output)
+	if len(nsUtilMatch) > 1 {
+		smartData["Namespace_Utilization"] = strings.TrimSpace(nsUtilMatch[1])
 	}
 
 	// 提取 Uncorrected_Errors
@@ -156,7 +274,12 @@ func (s *SMARTCollector) getNVMeSmartData(ctx context.Context, diskName string)
 		smartData["Uncorrected_Errors"] = uncorrectedErrorsMatch[1]
 	}
 
-	return smartData, nil
+	// 提取TRIM支持情况(NVMe通过Dataset Management命令实现)
+	if trim, ok := parseTrimSupport(output); ok {
+		smartData["Trim_Support"] = trim
+	}
+
+	return smartData
 }
 
 // getSATASmartData 获取SATA/SAS磁盘的SMART数据
@@ -167,32 +290,58 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 	isSSD := diskType == string(model.DiskTypeSASSSD)
 
 	// 获取健康状态
-	healthOutput, _ := s.commandRunner.Run(ctx, fmt.Sprintf("smartctl -H /dev/%s", diskName))
-	if healthOutput != "" {
-		smartStatus := "未知"
-		if strings.Contains(healthOutput, "PASSED") {
-			smartStatus = "PASSED"
-		} else if strings.Contains(healthOutput, "OK") {
-			smartStatus = "OK"
-		} else if strings.Contains(healthOutput, "FAILED") {
-			smartStatus = "FAILED"
-		}
+	healthOutput, _, _ := s.runSmartctl(ctx, fmt.Sprintf("smartctl -H /dev/%s", diskName))
+	if smartStatus, ok := parseHealthStatus(healthOutput); ok {
 		smartData["Smart_Status"] = smartStatus
 	}
 
 	// 检查是否存在"Percentage used endurance indicator"（仅适用于SSD）
-	if isSSD && strings.Contains(healthOutput, "Percentage used endurance indicator:") {
-		match := regexp.MustCompile(`Percentage used endurance indicator:\s+(\d+)%`).FindStringSubmatch(healthOutput)
-		if len(match) > 1 {
-			smartData["Percentage_Used"] = match[1]
+	if isSSD {
+		if percentage, ok := parseEnduranceIndicator(healthOutput); ok {
+			smartData["Percentage_Used"] = percentage
 		}
 	}
 
 	// 获取SMART详情
-	output, err := s.commandRunner.Run(ctx, fmt.Sprintf("smartctl -a /dev/%s", diskName))
+	output, exitCode, err := s.runSmartctl(ctx, s.smartDetailCommand(diskName))
 	if err != nil {
 		return smartData, fmt.Errorf("获取SATA/SAS SMART数据失败: %w", err)
 	}
+	recordSmartctlExitCode(smartData, exitCode)
+
+	for key, value := range s.normalizeSizes(parseSATASMART(output, isSSD)) {
+		smartData[key] = value
+	}
+
+	// 电源管理设置需要额外的smartctl命令，默认不收集
+	if s.config.ShowPower {
+		s.collectPowerSettings(ctx, diskName, smartData)
+	}
+
+	return smartData, nil
+}
+
+// lifetimeTempPatterns 匹配磁盘记录的最低/最高温度
+// 优先使用SCT状态中的生命周期温度，其次是Temperature_Celsius属性原始值中的(Min/Max a/b)
+var lifetimeTempPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Lifetime\s+Min/Max Temperature:\s+(\d+)/(\d+)`),
+	regexp.MustCompile(`Temperature_Celsius.*\(Min/Max (\d+)/(\d+)\)`),
+}
+
+// parseLifetimeTemperatures 从smartctl输出中提取生命周期内的最低和最高温度(°C)
+func parseLifetimeTemperatures(output string) (string, string, bool) {
+	for _, pattern := range lifetimeTempPatterns {
+		if match := pattern.FindStringSubmatch(output); len(match) > 2 {
+			return match[1], match[2], true
+		}
+	}
+	return "", "", false
+}
+
+// parseSATASMART 从SATA/SAS磁盘的smartctl -a输出中提取SMART数据
+// 纯函数，不执行命令；读写数据量保留smartctl的原始单位，由normalizeSizes统一换算
+func parseSATASMART(output string, isSSD bool) map[string]string {
+	smartData := make(map[string]string)
 
 	// 提取温度 - 尝试多种模式
 	tempPatterns := []string{
@@ -210,6 +359,12 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 		}
 	}
 
+	// 提取生命周期内的最低和最高温度
+	if minTemp, maxTemp, ok := parseLifetimeTemperatures(output); ok {
+		smartData[model.LifetimeMinTempAttribute] = minTemp
+		smartData[model.LifetimeMaxTempAttribute] = maxTemp
+	}
+
 	// 提取警告温度
 	tripTempPatterns := []string{
 		`Drive Trip Temperature:\s+(\d+)\s+C`,
@@ -239,7 +394,7 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 	for _, pattern := range hoursPatterns {
 		match := regexp.MustCompile(pattern).FindStringSubmatch(output)
 		if len(match) > 1 {
-			smartData["Power_On_Hours"] = strings.ReplaceAll(match[1], ",", "")
+			smartData["Power_On_Hours"] = model.NormalizeNumber(match[1])
 			break
 		}
 	}
@@ -268,6 +423,18 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 		smartData["Non_Medium_Errors"] = nonMediumMatch[1]
 	}
 
+	// 提取增长缺陷列表数量(SAS)
+	grownDefectMatch := regexp.MustCompile(`Elements in grown defect list:\s+(\d+)`).FindStringSubmatch(output)
+	if len(grownDefectMatch) > 1 {
+		smartData["Grown_Defects"] = grownDefectMatch[1]
+	}
+
+	// 提取待映射扇区数量(SATA属性197的原始值)
+	pendingMatch := regexp.MustCompile(`(?m)^\s*197\s+Current_Pending_Sector(?:\s+\S+){7}\s+(\d+)`).FindStringSubmatch(output)
+	if len(pendingMatch) > 1 {
+		smartData["Pending_Sectors"] = pendingMatch[1]
+	}
+
 	// 提取 Data_Read 和 Data_Written
 	errorLogPattern := regexp.MustCompile(`(?s)Error counter log:.*?(read:.*?write:.*?)(\n\n|\z)`)
 	errorLogSection := errorLogPattern.FindStringSubmatch(output)
@@ -279,7 +446,7 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 		if len(readMatch) > 1 {
 			value, _ := strconv.ParseFloat(readMatch[1], 64)
 			sizeStr := fmt.Sprintf("%.2f GB", value)
-			smartData["Data_Read"] = s.normalizeSize(sizeStr)
+			smartData["Data_Read"] = sizeStr
 		}
 
 		// 提取 write 的 Gigabytes processed
@@ -287,7 +454,12 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 		if len(writeMatch) > 1 {
 			value, _ := strconv.ParseFloat(writeMatch[1], 64)
 			sizeStr := fmt.Sprintf("%.2f GB", value)
-			smartData["Data_Written"] = s.normalizeSize(sizeStr)
+			smartData["Data_Written"] = sizeStr
+		}
+
+		// 提取 read/write/verify 的 Total errors corrected 并求和
+		if corrected, ok := parseCorrectedErrors(errorLogText); ok {
+			smartData["Corrected_Errors"] = strconv.FormatInt(corrected, 10)
 		}
 	}
 
@@ -298,7 +470,316 @@ func (s *SMARTCollector) getSATASmartData(ctx context.Context, diskName, diskTyp
 		smartData["Uncorrected_Errors"] = uncorrectedErrorsMatch[1]
 	}
 
-	return smartData, nil
+	// 提取TRIM支持情况(仅适用于SSD)
+	if isSSD {
+		if trim, ok := parseTrimSupport(output); ok {
+			smartData["Trim_Support"] = trim
+		}
+	}
+
+	// 提取链路速率
+	current, max := parseLinkSpeed(output)
+	if current != "" {
+		smartData[model.LinkSpeedAttribute] = current
+	}
+	if max != "" {
+		smartData[model.LinkSpeedMaxAttribute] = max
+	}
+
+	return smartData
+}
+
+// parseLinkSpeed 从smartctl输出中提取当前链路速率和磁盘支持的最高速率(Gb/s)
+// SATA: "SATA Version is:  SATA 3.3, 6.0 Gb/s (current: 3.0 Gb/s)"
+// SAS: smartctl -x的端口日志 "negotiated logical link rate: phy enabled; 12 Gbps"，
+// smartctl -a不报告SAS的链路速率，也不报告SAS磁盘支持的最高速率
+func parseLinkSpeed(output string) (string, string) {
+	if match := regexp.MustCompile(`(?im)^SATA Version is:.*?([\d.]+) Gb/s(?: \(current: ([\d.]+) Gb/s\))?`).FindStringSubmatch(output); len(match) > 1 {
+		max, current := match[1], match[2]
+		if current == "" {
+			current = max
+		}
+		return current, max
+	}
+
+	// 只取第一个端口(已连接的端口)
+	if match := regexp.MustCompile(`(?i)negotiated logical link rate: phy enabled; ([\d.]+) Gbps`).FindStringSubmatch(output); len(match) > 1 {
+		return formatLinkSpeed(match[1]), ""
+	}
+	return "", ""
+}
+
+// formatLinkSpeed 统一为一位小数，如 "12" -> "12.0"
+func formatLinkSpeed(value string) string {
+	speed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatFloat(speed, 'f', 1, 64)
+}
+
+// ParseSMARTOutput 从一份完整的smartctl -a输出构建磁盘对象，不执行任何命令
+// smartctl -a的输出已包含健康状态和设备信息部分，因此同时用于解析这些信息
+func (s *SMARTCollector) ParseSMARTOutput(diskName, diskType, diskModel, output string) *model.Disk {
+	disk := model.NewDisk(diskName, diskType, diskModel, "")
+
+	var smartData map[string]string
+	switch disk.Type {
+	case model.DiskTypeNVMESSD:
+		smartData = s.normalizeSizes(parseNVMeSMART(output))
+	case model.DiskTypeVirtual:
+		smartData = map[string]string{
+			"Type":         "虚拟设备",
+			"Smart_Status": model.SmartStatusVirtual,
+		}
+	default:
+		isSSD := disk.Type == model.DiskTypeSASSSD
+		smartData = s.normalizeSizes(parseSATASMART(output, isSSD))
+		if isSSD {
+			if percentage, ok := parseEnduranceIndicator(output); ok {
+				smartData["Percentage_Used"] = percentage
+			}
+		}
+	}
+
+	if disk.Type != model.DiskTypeVirtual {
+		// 只使用健康状态行，避免完整输出中其他位置的"OK"等字样干扰判断
+		healthOutput := regexp.MustCompile(`(?m)^(SMART overall-health self-assessment test result|SMART Health Status):.*$`).FindString(output)
+		if smartStatus, ok := parseHealthStatus(healthOutput); ok {
+			smartData["Smart_Status"] = smartStatus
+		}
+	}
+	for key, value := range smartData {
+		disk.SMARTData[key] = value
+	}
+
+	if parseSMARTDisabled(output) {
+		disk.SMARTData[model.SmartSupportAttribute] = model.SmartSupportDisabled
+	}
+
+	disk.Serial, disk.WWN = parseDeviceIdentity(output)
+	disk.FirmwareVersion = parseFirmwareVersion(output)
+	disk.UpdateStatus()
+	disk.ApplyStatusRules(s.config.StatusRules)
+
+	return disk
+}
+
+// parseHealthStatus 从smartctl -H输出中提取整体健康状态
+func parseHealthStatus(healthOutput string) (string, bool) {
+	if healthOutput == "" {
+		return "", false
+	}
+
+	smartStatus := "未知"
+	if strings.Contains(healthOutput, "PASSED") {
+		smartStatus = "PASSED"
+	} else if strings.Contains(healthOutput, "OK") {
+		smartStatus = "OK"
+	} else if strings.Contains(healthOutput, "FAILED") {
+		smartStatus = "FAILED"
+	}
+	return smartStatus, true
+}
+
+// parseEnduranceIndicator 提取SAS SSD的"Percentage used endurance indicator"
+func parseEnduranceIndicator(output string) (string, bool) {
+	match := regexp.MustCompile(`Percentage used endurance indicator:\s+(\d+)%`).FindStringSubmatch(output)
+	if len(match) > 1 {
+		return match[1], true
+	}
+	return "", false
+}
+
+// parseTrimSupport 从smartctl -a输出中判断是否支持TRIM
+// SATA SSD报告"TRIM Command"，NVMe在"Optional NVM Commands"中列出DS_Mngmt
+func parseTrimSupport(output string) (string, bool) {
+	trimMatch := regexp.MustCompile(`(?m)^TRIM Command:\s*(.+)$`).FindStringSubmatch(output)
+	if len(trimMatch) > 1 {
+		if strings.HasPrefix(strings.TrimSpace(trimMatch[1]), "Available") {
+			return model.TrimAvailable, true
+		}
+		return model.TrimUnavailable, true
+	}
+
+	nvmMatch := regexp.MustCompile(`(?m)^Optional NVM Commands \(0x[0-9a-fA-F]+\):\s*(.*)$`).FindStringSubmatch(output)
+	if len(nvmMatch) > 1 {
+		if strings.Contains(nvmMatch[1], "DS_Mngmt") {
+			return model.TrimAvailable, true
+		}
+		return model.TrimUnavailable, true
+	}
+
+	return "", false
+}
+
+// DeviceInfo smartctl -i 报告的磁盘标识和扇区信息
+type DeviceInfo struct {
+	Serial             string // 序列号
+	WWN                string // 全球唯一标识
+	FirmwareVersion    string // 磁盘固件版本
+	LogicalSectorSize  int    // 逻辑扇区大小(字节)，未知时为0
+	PhysicalSectorSize int    // 物理扇区大小(字节)，未知时为0
+	SMARTDisabled      bool   // 设备支持SMART但已被禁用
+}
+
+// GetDeviceIdentity 通过smartctl -i获取磁盘的序列号和WWN
+func (s *SMARTCollector) GetDeviceIdentity(ctx context.Context, diskName string) (string, string, error) {
+	info, err := s.GetDeviceInfo(ctx, diskName)
+	if err != nil {
+		return "", "", err
+	}
+	return info.Serial, info.WWN, nil
+}
+
+// GetDeviceInfo 通过smartctl -i获取磁盘的序列号、WWN、固件版本和扇区大小
+func (s *SMARTCollector) GetDeviceInfo(ctx context.Context, diskName string) (*DeviceInfo, error) {
+	output, _, err := s.runSmartctl(ctx, fmt.Sprintf("smartctl -i /dev/%s", diskName))
+	if err != nil {
+		return nil, fmt.Errorf("获取磁盘标识信息失败: %w", err)
+	}
+
+	info := &DeviceInfo{}
+	info.Serial, info.WWN = parseDeviceIdentity(output)
+	info.FirmwareVersion = parseFirmwareVersion(output)
+	info.LogicalSectorSize, info.PhysicalSectorSize = parseSectorSizes(output)
+	info.SMARTDisabled = parseSMARTDisabled(output)
+	return info, nil
+}
+
+// EnableSMART 使用smartctl -s on启用磁盘的SMART(--enable-smart)
+func (s *SMARTCollector) EnableSMART(ctx context.Context, diskName string) error {
+	_, exitCode, err := s.runSmartctl(ctx, fmt.Sprintf("smartctl -s on /dev/%s", diskName))
+	if err != nil {
+		return fmt.Errorf("启用SMART失败: %w", err)
+	}
+	if exitCode&model.SmartctlBitCommandFailed != 0 {
+		return fmt.Errorf("启用SMART失败: smartctl退出码 %d", exitCode)
+	}
+	return nil
+}
+
+// parseSMARTDisabled 检查smartctl -i输出是否报告SMART已禁用
+// 如 "SMART support is: Disabled"(SATA) 或 "SMART support is:     Disabled"(SAS)
+func parseSMARTDisabled(output string) bool {
+	return regexp.MustCompile(`(?im)^SMART support is:\s*Disabled`).MatchString(output)
+}
+
+// parseSectorSizes 从smartctl -i输出中提取逻辑和物理扇区大小(字节)
+// SATA: "Sector Sizes: 512 bytes logical, 4096 bytes physical" 或 "Sector Size: 512 bytes logical/physical"
+// SAS: "Logical block size: 512 bytes" 和 "Physical block size: 4096 bytes"
+// NVMe: "Namespace 1 Formatted LBA Size: 4096"
+func parseSectorSizes(output string) (int, int) {
+	if match := regexp.MustCompile(`(?im)^Sector Sizes?:\s*(\d+) bytes logical, (\d+) bytes physical`).FindStringSubmatch(output); len(match) > 2 {
+		logical, _ := strconv.Atoi(match[1])
+		physical, _ := strconv.Atoi(match[2])
+		return logical, physical
+	}
+	if match := regexp.MustCompile(`(?im)^Sector Sizes?:\s*(\d+) bytes logical/physical`).FindStringSubmatch(output); len(match) > 1 {
+		size, _ := strconv.Atoi(match[1])
+		return size, size
+	}
+
+	var logical, physical int
+	if match := regexp.MustCompile(`(?im)^Logical block size:\s*(\d+) bytes`).FindStringSubmatch(output); len(match) > 1 {
+		logical, _ = strconv.Atoi(match[1])
+	}
+	if match := regexp.MustCompile(`(?im)^Physical block size:\s*(\d+) bytes`).FindStringSubmatch(output); len(match) > 1 {
+		physical, _ = strconv.Atoi(match[1])
+	}
+	if logical > 0 {
+		if physical == 0 {
+			physical = logical
+		}
+		return logical, physical
+	}
+
+	// NVMe命名空间没有单独的物理扇区大小
+	if match := regexp.MustCompile(`(?im)^Namespace \d+ Formatted LBA Size:\s*(\d+)`).FindStringSubmatch(output); len(match) > 1 {
+		size, _ := strconv.Atoi(match[1])
+		return size, size
+	}
+	return 0, 0
+}
+
+// parseDeviceIdentity 从smartctl -i输出中提取序列号和WWN
+// SATA磁盘使用"LU WWN Device Id"，SAS磁盘使用"Logical Unit id"
+func parseDeviceIdentity(output string) (string, string) {
+	var serial, wwn string
+
+	serialMatch := regexp.MustCompile(`(?im)^Serial Number:\s*(\S.*?)\s*$`).FindStringSubmatch(output)
+	if len(serialMatch) > 1 {
+		serial = serialMatch[1]
+	}
+
+	wwnPatterns := []string{
+		`(?im)^LU WWN Device Id:\s*(\S.*?)\s*$`,
+		`(?im)^Logical Unit id:\s*(\S.*?)\s*$`,
+		`(?im)^WWN:\s*(\S.*?)\s*$`,
+	}
+	for _, pattern := range wwnPatterns {
+		match := regexp.MustCompile(pattern).FindStringSubmatch(output)
+		if len(match) > 1 {
+			// 统一为不带空格和0x前缀的十六进制字符串
+			wwn = strings.ToLower(strings.ReplaceAll(match[1], " ", ""))
+			wwn = strings.TrimPrefix(wwn, "0x")
+			break
+		}
+	}
+
+	return serial, wwn
+}
+
+// parseFirmwareVersion 从smartctl -i输出中提取磁盘固件版本
+// SATA和NVMe磁盘使用"Firmware Version"，SAS磁盘使用"Revision"
+func parseFirmwareVersion(output string) string {
+	for _, pattern := range []string{
+		`(?im)^Firmware Version:\s*(\S.*?)\s*$`,
+		`(?im)^Revision:\s*(\S.*?)\s*$`,
+	} {
+		if match := regexp.MustCompile(pattern).FindStringSubmatch(output); len(match) > 1 {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// parseCorrectedErrors 从SAS错误计数日志中汇总已纠正的错误总数
+func parseCorrectedErrors(errorLogText string) (int64, bool) {
+	rowPattern := regexp.MustCompile(`(?m)^(read|write|verify):\s+(.+)$`)
+	rows := rowPattern.FindAllStringSubmatch(errorLogText, -1)
+
+	var total int64
+	found := false
+	for _, row := range rows {
+		// 列: ECC fast, ECC delayed, rereads/rewrites, total corrected, invocations, GB processed, uncorrected
+		fields := strings.Fields(row[2])
+		if len(fields) < 7 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		total += value
+		found = true
+	}
+
+	return total, found
+}
+
+// normalizeSizes 将解析结果中的读写数据量(十进制单位)换算为配置的单位制
+// 换算前的字节数另外保存，历史数据据此比较
+func (s *SMARTCollector) normalizeSizes(smartData map[string]string) map[string]string {
+	for _, key := range []string{"Data_Read", "Data_Written"} {
+		if value, ok := smartData[key]; ok {
+			if bytes, err := model.SizeUnitsDecimal.ParseSize(value); err == nil {
+				smartData[key+model.SizeBytesSuffix] = strconv.FormatFloat(bytes, 'f', 0, 64)
+			}
+			smartData[key] = s.normalizeSize(value)
+		}
+	}
+	return smartData
 }
 
 // normalizeSize 将大小字符串标准化为合适的单位
@@ -311,50 +792,23 @@ func (s *SMARTCollector) normalizeSize(sizeStr string) string {
 		return ""
 	}
 
+	units := s.sizeUnits()
+
 	// 先处理科学计数法的容量
 	if match, _ := regexp.MatchString(`^\d+\.\d+e[+-]\d+$`, sizeStr); match {
 		value, err := strconv.ParseFloat(sizeStr, 64)
 		if err == nil {
-			// 直接转换为 TB
-			tbValue := value / (1024 * 1024 * 1024 * 1024)
-			result := fmt.Sprintf("%.2f TB", tbValue)
+			// 直接转换为 TB/TiB
+			tbValue := value / math.Pow(units.Base(), 4)
+			result := fmt.Sprintf("%.*f %s", s.sizePrecision(), tbValue, units.Labels()[4])
 			s.logger.Debug("科学计数法直接转换: %s", result)
 			return result
 		}
 	}
 
-	match := regexp.MustCompile(`(\d+\.?\d*)\s*([KMGTP]?B)`).FindStringSubmatch(sizeStr)
-
-	// 记录正则匹配结果
-	s.logger.Debug("正则匹配结果: %v", match)
-
-	if len(match) > 2 {
-		value, _ := strconv.ParseFloat(match[1], 64)
-		unit := strings.ToUpper(match[2])
-
-		// 记录解析的数值和单位
-		s.logger.Debug("解析的数值: %f, 单位: %s", value, unit)
-
-		// 转换为字节数
-		var bytes float64
-		switch unit {
-		case "B":
-			bytes = value
-		case "KB":
-			bytes = value * 1024
-		case "MB":
-			bytes = value * 1024 * 1024
-		case "GB":
-			bytes = value * 1024 * 1024 * 1024
-		case "TB":
-			bytes = value * 1024 * 1024 * 1024 * 1024
-		case "PB":
-			bytes = value * 1024 * 1024 * 1024 * 1024 * 1024
-		default:
-			s.logger.Debug("未知单位: %s", unit)
-			return sizeStr
-		}
-
+	// 转换为字节数，smartctl的GB/TB是十进制单位(如 "[10^9 bytes]"、"[5.61 TB]")
+	// 始终按1000换算，--size-units只影响显示
+	if bytes, err := model.SizeUnitsDecimal.ParseSize(sizeStr); err == nil {
 		// 记录转换后的字节数
 		s.logger.Debug("转换后的字节数: %f", bytes)
 
@@ -380,31 +834,21 @@ func (s *SMARTCollector) normalizeSize(sizeStr string) string {
 
 // formatSize 格式化容量大小
 func (s *SMARTCollector) formatSize(sizeBytes float64) string {
-	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
-	unitIndex := 0
-	size := sizeBytes
-
-	// 处理负数情况
-	sign := ""
-	if size < 0 {
-		sign = "-"
-		size = math.Abs(size)
-	}
-
-	for size >= 1024 && unitIndex < len(units)-1 {
-		size /= 1024
-		unitIndex++
-	}
-
-	// 根据单位和大小选择最佳显示方式
-	switch units[unitIndex] {
-	case "B", "KB":
-		return fmt.Sprintf("%s%.2f %s", sign, size, units[unitIndex])
-	case "MB", "GB":
-		return fmt.Sprintf("%s%.2f %s", sign, size, units[unitIndex])
-	case "TB", "PB":
-		return fmt.Sprintf("%s%.2f %s", sign, size, units[unitIndex])
-	default:
-		return fmt.Sprintf("%s%.2f B", sign, size)
+	return s.sizeUnits().FormatSizePrecision(sizeBytes, s.sizePrecision())
+}
+
+// sizePrecision 获取配置的容量小数位数，默认为两位
+func (s *SMARTCollector) sizePrecision() int {
+	if s.config == nil {
+		return model.DefaultSizePrecision
+	}
+	return s.config.SizePrecision
+}
+
+// sizeUnits 获取配置的容量单位制，默认为二进制
+func (s *SMARTCollector) sizeUnits() model.SizeUnits {
+	if s.config == nil {
+		return model.SizeUnitsBinary
 	}
+	return s.config.SizeUnits
 }
@@ -2,23 +2,15 @@ package collector
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
 	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
-func TestSMARTCollector_GetSMARTData(t *testing.T) {
-	// 创建模拟命令执行器
-	mockRunner := system.NewMockCommandRunner()
-	mockLogger := system.NewMockLogger()
-	config := model.NewDefaultConfig()
-
-	collector := NewSMARTCollector(config, mockLogger, mockRunner)
-
-	// 测试 1: NVMe SSD (/dev/nvme5n1)
-	mockRunner.SetMockOutput("smartctl -H /dev/nvme5n1", "SMART overall-health self-assessment test result: PASSED")
-	mockRunner.SetMockOutput("smartctl -a /dev/nvme5n1", `
+// NVMe SSD (nvme5n1) 的 smartctl -a 输出
+const nvmeSmartOutput = `
 === START OF SMART DATA SECTION ===
 SMART overall-health self-assessment test result: PASSED
 
@@ -40,30 +32,10 @@ Media and Data Integrity Errors:    0
 Error Information Log Entries:      0
 Warning  Comp. Temperature Time:    0
 Critical Comp. Temperature Time:    0
-`)
+`
 
-	smartData, err := collector.GetSMARTData(context.Background(), "nvme5n1", "SSD", "INTEL SSDPF2KX038TZ")
-	if err != nil {
-		t.Errorf("Expected no error for NVMe SSD, got %v", err)
-	}
-
-	expectedNVMeData := map[string]string{
-		"Data_Read":          "5.61 TB",
-		"Data_Written":       "3.27 TB",
-		"Power_On_Hours":     "20662",
-		"Power_Cycles":       "219",
-		"Uncorrected_Errors": "0", // NVMe 使用 Media and Data Integrity Errors
-	}
-
-	for key, expected := range expectedNVMeData {
-		if smartData[key] != expected {
-			t.Errorf("NVMe SSD %s: Expected '%s', got '%s'", key, expected, smartData[key])
-		}
-	}
-
-	// 测试 2: SAS HDD (/dev/sdd)
-	mockRunner.SetMockOutput("smartctl -H /dev/sdd", "SMART Health Status: OK")
-	mockRunner.SetMockOutput("smartctl -a /dev/sdd", `
+// SAS HDD (sdd) 的 smartctl -a 输出
+const sasHDDSmartOutput = `
 === START OF READ SMART DATA SECTION ===
 SMART Health Status: OK
 
@@ -91,30 +63,10 @@ write:         0        0        22        22         24     183549.238
 verify: 2031052729        0         0  2031052729          1      37272.521           1
 
 Non-medium error count:      157
-`)
-
-	smartData, err = collector.GetSMARTData(context.Background(), "sdd", "HDD", "SEAGATE ST600MM0006")
-	if err != nil {
-		t.Errorf("Expected no error for SAS HDD, got %v", err)
-	}
-
-	expectedSASData := map[string]string{
-		"Power_On_Hours":     "36491.38",
-		"Power_Cycles":       "276",
-		"Data_Read":          "280.21 TB", // 280210.005 GB 转换为 TB，保留两位小数
-		"Data_Written":       "183.55 TB", // 183549.238 GB 转换为 TB，保留两位小数
-		"Uncorrected_Errors": "0",
-	}
+`
 
-	for key, expected := range expectedSASData {
-		if smartData[key] != expected {
-			t.Errorf("SAS HDD %s: Expected '%s', got '%s'", key, expected, smartData[key])
-		}
-	}
-
-	// 测试 3: SAS SSD (/dev/sde)
-	mockRunner.SetMockOutput("smartctl -H /dev/sde", "SMART Health Status: OK")
-	mockRunner.SetMockOutput("smartctl -a /dev/sde", `
+// SAS SSD (sde) 的 smartctl -a 输出
+const sasSSDSmartOutput = `
 === START OF READ SMART DATA SECTION ===
 SMART Health Status: OK
 
@@ -138,7 +90,68 @@ write:         0        0         0         0          0      16924.206
 verify:        0        0         0         0          0         85.043           0
 
 Non-medium error count:     1221
-`)
+`
+
+func TestSMARTCollector_GetSMARTData(t *testing.T) {
+	// 创建模拟命令执行器
+	mockRunner := system.NewMockCommandRunner()
+	mockLogger := system.NewMockLogger()
+	config := model.NewDefaultConfig()
+	// smartctl 报告的容量为十进制单位(GB processed [10^9 bytes])
+	config.SizeUnits = model.SizeUnitsDecimal
+
+	collector := NewSMARTCollector(config, mockLogger, mockRunner)
+
+	// 测试 1: NVMe SSD (/dev/nvme5n1)
+	mockRunner.SetMockOutput("smartctl -H /dev/nvme5n1", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockOutput("smartctl -a /dev/nvme5n1", nvmeSmartOutput)
+
+	smartData, err := collector.GetSMARTData(context.Background(), "nvme5n1", "SSD", "INTEL SSDPF2KX038TZ")
+	if err != nil {
+		t.Errorf("Expected no error for NVMe SSD, got %v", err)
+	}
+
+	expectedNVMeData := map[string]string{
+		"Data_Read":          "5.61 TB",
+		"Data_Written":       "3.27 TB",
+		"Power_On_Hours":     "20662",
+		"Power_Cycles":       "219",
+		"Uncorrected_Errors": "0", // NVMe 使用 Media and Data Integrity Errors
+	}
+
+	for key, expected := range expectedNVMeData {
+		if smartData[key] != expected {
+			t.Errorf("NVMe SSD %s: Expected '%s', got '%s'", key, expected, smartData[key])
+		}
+	}
+
+	// 测试 2: SAS HDD (/dev/sdd)
+	mockRunner.SetMockOutput("smartctl -H /dev/sdd", "SMART Health Status: OK")
+	mockRunner.SetMockOutput("smartctl -a /dev/sdd", sasHDDSmartOutput)
+
+	smartData, err = collector.GetSMARTData(context.Background(), "sdd", "HDD", "SEAGATE ST600MM0006")
+	if err != nil {
+		t.Errorf("Expected no error for SAS HDD, got %v", err)
+	}
+
+	expectedSASData := map[string]string{
+		"Power_On_Hours":     "36491.38",
+		"Power_Cycles":       "276",
+		"Data_Read":          "280.21 TB", // 280210.005 GB 转换为 TB，保留两位小数
+		"Data_Written":       "183.55 TB", // 183549.238 GB 转换为 TB，保留两位小数
+		"Uncorrected_Errors": "0",
+		"Corrected_Errors":   "5126437757", // read + write + verify 的 Total errors corrected
+	}
+
+	for key, expected := range expectedSASData {
+		if smartData[key] != expected {
+			t.Errorf("SAS HDD %s: Expected '%s', got '%s'", key, expected, smartData[key])
+		}
+	}
+
+	// 测试 3: SAS SSD (/dev/sde)
+	mockRunner.SetMockOutput("smartctl -H /dev/sde", "SMART Health Status: OK")
+	mockRunner.SetMockOutput("smartctl -a /dev/sde", sasSSDSmartOutput)
 
 	smartData, err = collector.GetSMARTData(context.Background(), "sde", "SSD", "SAMSUNG MZILT3T8HALS/007")
 	if err != nil {
@@ -159,3 +172,738 @@ Non-medium error count:     1221
 		}
 	}
 }
+
+func TestSMARTCollector_GetDeviceIdentity(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+
+	// SATA 磁盘: Serial Number + LU WWN Device Id
+	mockRunner.SetMockOutput("smartctl -i /dev/sda", `smartctl 7.2 2020-12-30 r5155 [x86_64-linux-5.10.142+truenas] (local build)
+Copyright (C) 2002-20, Bruce Allen, Christian Franke, www.smartmontools.org
+
+=== START OF INFORMATION SECTION ===
+Model Family:     Samsung based SSDs
+Device Model:     Samsung SSD 870 EVO 1TB
+Serial Number:    S6PTNM0T123456A
+LU WWN Device Id: 5 002538 f4223a1b2
+Firmware Version: SVT02B6Q
+User Capacity:    1,000,204,886,016 bytes [1.00 TB]
+`)
+
+	// SAS 磁盘: Serial number + Logical Unit id
+	mockRunner.SetMockOutput("smartctl -i /dev/sdd", `=== START OF INFORMATION SECTION ===
+Vendor:               SEAGATE
+Product:              ST600MM0006
+Revision:             LS0A
+User Capacity:        600,127,266,816 bytes [600 GB]
+Logical block size:   512 bytes
+Logical Unit id:      0x5000c5007a2b3c4d
+Serial number:        S0M1ABCD0000K1234XYZ
+Device type:          disk
+`)
+
+	tests := []struct {
+		disk   string
+		serial string
+		wwn    string
+	}{
+		{"sda", "S6PTNM0T123456A", "5002538f4223a1b2"},
+		{"sdd", "S0M1ABCD0000K1234XYZ", "5000c5007a2b3c4d"},
+	}
+
+	for _, tt := range tests {
+		serial, wwn, err := collector.GetDeviceIdentity(context.Background(), tt.disk)
+		if err != nil {
+			t.Fatalf("GetDeviceIdentity(%s) failed: %v", tt.disk, err)
+		}
+		if serial != tt.serial {
+			t.Errorf("%s serial: Expected '%s', got '%s'", tt.disk, tt.serial, serial)
+		}
+		if wwn != tt.wwn {
+			t.Errorf("%s WWN: Expected '%s', got '%s'", tt.disk, tt.wwn, wwn)
+		}
+	}
+}
+
+func TestSMARTCollector_FirmwareVersion(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+
+	// SATA 和 NVMe 磁盘报告 Firmware Version，SAS 磁盘报告 Revision
+	mockRunner.SetMockOutput("smartctl -i /dev/sda", `=== START OF INFORMATION SECTION ===
+Device Model:     WDC WD40EFRX-68N32N0
+Serial Number:    WD-WCC7K1234567
+Firmware Version: 82.00A82
+User Capacity:    4,000,787,030,016 bytes [4.00 TB]
+`)
+	mockRunner.SetMockOutput("smartctl -i /dev/sdd", `=== START OF INFORMATION SECTION ===
+Vendor:               SEAGATE
+Product:              ST600MM0006
+Revision:             LS0A
+Serial number:        S0M1ABCD0000K1234XYZ
+`)
+	mockRunner.SetMockOutput("smartctl -i /dev/nvme0n1", `=== START OF INFORMATION SECTION ===
+Model Number:                       Samsung SSD 980 PRO 1TB
+Serial Number:                      S5GXNF0R123456
+Firmware Version:                   5B2QGXA7
+`)
+
+	tests := []struct {
+		disk     string
+		firmware string
+	}{
+		{"sda", "82.00A82"},
+		{"sdd", "LS0A"},
+		{"nvme0n1", "5B2QGXA7"},
+	}
+	for _, tt := range tests {
+		info, err := collector.GetDeviceInfo(context.Background(), tt.disk)
+		if err != nil {
+			t.Fatalf("GetDeviceInfo(%s) failed: %v", tt.disk, err)
+		}
+		if info.FirmwareVersion != tt.firmware {
+			t.Errorf("%s firmware: Expected '%s', got '%s'", tt.disk, tt.firmware, info.FirmwareVersion)
+		}
+	}
+
+	// 没有固件信息时为空
+	if got := parseFirmwareVersion("Serial Number:    S6PTNM0T123456A\n"); got != "" {
+		t.Errorf("Expected empty firmware version, got '%s'", got)
+	}
+}
+
+func TestSMARTCollector_TrimSupport(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+
+	// SATA SSD 支持TRIM
+	mockRunner.SetMockOutput("smartctl -H /dev/sdb", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockOutput("smartctl -a /dev/sdb", `=== START OF INFORMATION SECTION ===
+Device Model:     Samsung SSD 870 EVO 1TB
+Rotation Rate:    Solid State Device
+TRIM Command:     Available, deterministic, zeroed
+SMART support is: Enabled
+`)
+
+	// SATA SSD 不支持TRIM
+	mockRunner.SetMockOutput("smartctl -H /dev/sdc", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockOutput("smartctl -a /dev/sdc", `=== START OF INFORMATION SECTION ===
+Device Model:     INTEL SSDSC2BB480G4
+Rotation Rate:    Solid State Device
+TRIM Command:     Unavailable
+SMART support is: Enabled
+`)
+
+	tests := []struct {
+		disk     string
+		expected string
+	}{
+		{"sdb", model.TrimAvailable},
+		{"sdc", model.TrimUnavailable},
+	}
+
+	for _, tt := range tests {
+		smartData, err := collector.GetSMARTData(context.Background(), tt.disk, "SSD", "SSD")
+		if err != nil {
+			t.Fatalf("GetSMARTData(%s) failed: %v", tt.disk, err)
+		}
+		if smartData["Trim_Support"] != tt.expected {
+			t.Errorf("%s Trim_Support: Expected '%s', got '%s'", tt.disk, tt.expected, smartData["Trim_Support"])
+		}
+	}
+}
+
+func TestParseNVMeSMART(t *testing.T) {
+	smartData := parseNVMeSMART(nvmeSmartOutput)
+
+	expected := map[string]string{
+		"Temperature":        "42",
+		"Available_Spare":    "100",
+		"Percentage_Used":    "0",
+		"Power_On_Hours":     "20662",
+		"Power_Cycles":       "219",
+		"Data_Read":          "5.61 TB", // 保留smartctl的原始单位
+		"Data_Written":       "3.27 TB",
+		"Uncorrected_Errors": "0",
+		"Warning_Temp_Time":  "0",
+		"Critical_Temp_Time": "0",
+	}
+
+	for key, want := range expected {
+		if smartData[key] != want {
+			t.Errorf("%s: Expected '%s', got '%s'", key, want, smartData[key])
+		}
+	}
+
+	// 健康状态由 smartctl -H 单独获取，纯解析器不应设置
+	if _, ok := smartData["Smart_Status"]; ok {
+		t.Errorf("parseNVMeSMART should not set Smart_Status, got '%s'", smartData["Smart_Status"])
+	}
+}
+
+func TestParseNVMeSMART_PowerOnHoursUnits(t *testing.T) {
+	// 部分固件在通电时间后带单位或千位分隔符
+	for _, line := range []string{
+		"Power On Hours:                     20662 h",
+		"Power On Hours:                     20,662",
+		"Power On Hours:                     20,662 hours",
+	} {
+		if hours := parseNVMeSMART(line + "\n")["Power_On_Hours"]; hours != "20662" {
+			t.Errorf("%q: expected Power_On_Hours 20662, got '%s'", line, hours)
+		}
+	}
+}
+
+func TestParseSATASMART(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		isSSD    bool
+		expected map[string]string
+	}{
+		{
+			name:   "SAS HDD",
+			output: sasHDDSmartOutput,
+			isSSD:  false,
+			expected: map[string]string{
+				"Temperature":        "37",
+				"Trip_Temperature":   "68",
+				"Power_On_Hours":     "36491.38",
+				"Power_Cycles":       "276",
+				"Non_Medium_Errors":  "157",
+				"Grown_Defects":      "0",
+				"Data_Read":          "280210.01 GB", // 保留smartctl的原始单位
+				"Data_Written":       "183549.24 GB",
+				"Corrected_Errors":   "5126437757",
+				"Uncorrected_Errors": "0",
+			},
+		},
+		{
+			name:   "SAS SSD",
+			output: sasSSDSmartOutput,
+			isSSD:  true,
+			expected: map[string]string{
+				"Temperature":        "39",
+				"Trip_Temperature":   "70",
+				"Power_On_Hours":     "23002",
+				"Power_Cycles":       "122",
+				"Non_Medium_Errors":  "1221",
+				"Grown_Defects":      "0",
+				"Data_Read":          "71532.57 GB",
+				"Data_Written":       "16924.21 GB",
+				"Corrected_Errors":   "0",
+				"Uncorrected_Errors": "0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			smartData := parseSATASMART(tt.output, tt.isSSD)
+			for key, want := range tt.expected {
+				if smartData[key] != want {
+					t.Errorf("%s: Expected '%s', got '%s'", key, want, smartData[key])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSATASMART_PendingSectors(t *testing.T) {
+	output := `
+ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_FAILED RAW_VALUE
+  5 Reallocated_Sector_Ct   0x0033   200   200   140    Pre-fail  Always       -       0
+194 Temperature_Celsius     0x0022   119   104   000    Old_age   Always       -       31
+197 Current_Pending_Sector  0x0032   200   200   000    Old_age   Always       -       12
+198 Offline_Uncorrectable   0x0030   100   253   000    Old_age   Offline      -       0
+`
+
+	smartData := parseSATASMART(output, false)
+	if smartData["Pending_Sectors"] != "12" {
+		t.Errorf("Pending_Sectors: Expected '12', got '%s'", smartData["Pending_Sectors"])
+	}
+}
+
+func TestParseSATASMART_LifetimeTemperature(t *testing.T) {
+	// 当前温度正常，但历史最高温度曾达到临界温度，应给出提示性警告
+	output := `
+Current Drive Temperature:     38 C
+Drive Trip Temperature:        65 C
+SMART overall-health self-assessment test result: PASSED
+SCT Status Version:                  3
+Current Temperature:                    38 Celsius
+Power Cycle Min/Max Temperature:     25/41 Celsius
+Lifetime    Min/Max Temperature:     18/71 Celsius
+`
+
+	smartData := parseSATASMART(output, false)
+	if smartData[model.LifetimeMinTempAttribute] != "18" || smartData[model.LifetimeMaxTempAttribute] != "71" {
+		t.Fatalf("Expected lifetime min/max 18/71, got %s/%s",
+			smartData[model.LifetimeMinTempAttribute], smartData[model.LifetimeMaxTempAttribute])
+	}
+
+	disk := model.NewDisk("sda", "HDD", "ST4000NM0023", "4T")
+	for key, value := range smartData {
+		disk.SMARTData[key] = value
+	}
+	disk.UpdateStatus()
+
+	if disk.Status != model.DiskStatusWarning {
+		t.Errorf("Expected warning status, got '%s'", disk.Status)
+	}
+	expected := "启发式: 历史最高温度 71°C 曾达到临界温度 65°C，可能发生过散热故障"
+	found := false
+	for _, reason := range disk.GetStatusReasons() {
+		if reason == expected {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected reason %q, got %v", expected, disk.GetStatusReasons())
+	}
+
+	// 属性原始值中的(Min/Max a/b)也能识别
+	minTemp, maxTemp, ok := parseLifetimeTemperatures("194 Temperature_Celsius     0x0022   119   104   000    Old_age   Always       -       31 (Min/Max 20/45)")
+	if !ok || minTemp != "20" || maxTemp != "45" {
+		t.Errorf("Expected 20/45 from the attribute, got %s/%s (ok=%v)", minTemp, maxTemp, ok)
+	}
+}
+
+func TestSMARTCollector_NVMeThermalThrottling(t *testing.T) {
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), system.NewMockCommandRunner())
+
+	output := strings.Replace(nvmeSmartOutput,
+		"Critical Comp. Temperature Time:    0", "Critical Comp. Temperature Time:    17", 1)
+
+	disk := collector.ParseSMARTOutput("nvme5n1", "SSD", "INTEL SSDPF2KX038TZ", output)
+	if disk.SMARTData["Critical_Temp_Time"] != "17" {
+		t.Errorf("Critical_Temp_Time: Expected '17', got '%s'", disk.SMARTData["Critical_Temp_Time"])
+	}
+	if disk.Status != model.DiskStatusWarning {
+		t.Errorf("Expected %s for throttled NVMe, got %s (%s)", model.DiskStatusWarning, disk.Status, disk.StatusReason)
+	}
+
+	// 未发生降频时保持正常
+	disk = collector.ParseSMARTOutput("nvme5n1", "SSD", "INTEL SSDPF2KX038TZ", nvmeSmartOutput)
+	if disk.Status != model.DiskStatusOK {
+		t.Errorf("Expected %s without throttling, got %s (%s)", model.DiskStatusOK, disk.Status, disk.StatusReason)
+	}
+}
+
+func TestSMARTCollector_NVMeTemperatureSensors(t *testing.T) {
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), system.NewMockCommandRunner())
+
+	// 综合温度低于警告阈值，但传感器2已超过
+	output := strings.Replace(nvmeSmartOutput, "Critical Comp. Temperature Time:    0",
+		"Critical Comp. Temperature Time:    0\nTemperature Sensor 1:               45 Celsius\nTemperature Sensor 2:               82 Celsius", 1)
+	output = strings.Replace(output, "Percentage Used:                    0%",
+		"Percentage Used:                    0%\nWarning  Comp. Temp. Threshold:     80 Celsius", 1)
+
+	disk := collector.ParseSMARTOutput("nvme5n1", "SSD", "INTEL SSDPF2KX038TZ", output)
+	if disk.SMARTData["Temperature_Sensor_1"] != "45" || disk.SMARTData["Temperature_Sensor_2"] != "82" {
+		t.Errorf("Expected sensor temperatures 45/82, got '%s'/'%s'",
+			disk.SMARTData["Temperature_Sensor_1"], disk.SMARTData["Temperature_Sensor_2"])
+	}
+
+	// 显示综合温度，状态由最热的传感器决定
+	if disk.GetDisplayTemperature() != "42°C" {
+		t.Errorf("Expected composite temperature '42°C', got '%s'", disk.GetDisplayTemperature())
+	}
+	if disk.Status != model.DiskStatusWarning {
+		t.Errorf("Expected %s when sensor 2 exceeds the threshold, got %s (%s)", model.DiskStatusWarning, disk.Status, disk.StatusReason)
+	}
+	if !strings.Contains(disk.StatusReason, "传感器2 82°C") {
+		t.Errorf("Expected status reason to name sensor 2, got '%s'", disk.StatusReason)
+	}
+	if got := disk.GetDisplaySensorTemperatures(); got != "S1 45°C, S2 82°C" {
+		t.Errorf("Expected 'S1 45°C, S2 82°C', got '%s'", got)
+	}
+}
+
+func TestSMARTCollector_NVMeNamespaces(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+
+	// 同一控制器(nvme0)上的两个命名空间，容量和使用量各不相同
+	namespaceOutput := func(nsid, size, used, temp string) string {
+		return `=== START OF INFORMATION SECTION ===
+Namespace ` + nsid + ` Size/Capacity:               ` + size + `
+Namespace ` + nsid + ` Utilization:                 ` + used + `
+
+=== START OF SMART DATA SECTION ===
+SMART overall-health self-assessment test result: PASSED
+Temperature:                        ` + temp + ` Celsius
+Percentage Used:                    3%
+Media and Data Integrity Errors:    0
+`
+	}
+	mockRunner.SetMockOutput("smartctl -a /dev/nvme0n1", namespaceOutput("1", "1,000,204,886,016 [1.00 TB]", "412,316,860,416 [412 GB]", "44"))
+	mockRunner.SetMockOutput("smartctl -a /dev/nvme0n2", namespaceOutput("2", "500,107,862,016 [500 GB]", "12,884,901,888 [12.8 GB]", "45"))
+
+	ns1, err := collector.GetSMARTData(context.Background(), "nvme0n1", "SSD", "Samsung SSD 980 PRO 1TB")
+	if err != nil {
+		t.Fatalf("GetSMARTData(nvme0n1) failed: %v", err)
+	}
+	ns2, err := collector.GetSMARTData(context.Background(), "nvme0n2", "SSD", "Samsung SSD 980 PRO 1TB")
+	if err != nil {
+		t.Fatalf("GetSMARTData(nvme0n2) failed: %v", err)
+	}
+
+	// 每个命名空间使用自己的设备节点
+	for _, cmd := range []string{"smartctl -a /dev/nvme0n1", "smartctl -a /dev/nvme0n2"} {
+		found := false
+		for _, called := range mockRunner.CalledCommands {
+			if called == cmd {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected command %q to be run", cmd)
+		}
+	}
+
+	if ns1["Namespace_ID"] != "1" || ns1["Namespace_Size"] != "1.00 TB" || ns1["Namespace_Utilization"] != "412 GB" {
+		t.Errorf("Unexpected namespace 1 data: %v", ns1)
+	}
+	if ns2["Namespace_ID"] != "2" || ns2["Namespace_Size"] != "500 GB" || ns2["Namespace_Utilization"] != "12.8 GB" {
+		t.Errorf("Unexpected namespace 2 data: %v", ns2)
+	}
+
+	// 温度属于控制器，两个命名空间共享首次读取的值
+	if ns1["Temperature"] != "44" || ns2["Temperature"] != "44" {
+		t.Errorf("Expected shared controller temperature 44, got %s and %s", ns1["Temperature"], ns2["Temperature"])
+	}
+
+	// 其他控制器不受影响
+	mockRunner.SetMockOutput("smartctl -a /dev/nvme1n1", namespaceOutput("1", "2,000,398,934,016 [2.00 TB]", "1,099,511,627,776 [1.09 TB]", "51"))
+	other, err := collector.GetSMARTData(context.Background(), "nvme1n1", "SSD", "Samsung SSD 990 PRO 2TB")
+	if err != nil {
+		t.Fatalf("GetSMARTData(nvme1n1) failed: %v", err)
+	}
+	if other["Temperature"] != "51" {
+		t.Errorf("Expected nvme1 temperature 51, got %s", other["Temperature"])
+	}
+}
+
+func TestSMARTCollector_SizePrecision(t *testing.T) {
+	tests := []struct {
+		precision int
+		expected  string
+	}{
+		{0, "2 TiB"},
+		{2, "1.50 TiB"},
+		{3, "1.500 TiB"},
+	}
+
+	for _, tt := range tests {
+		config := model.NewDefaultConfig()
+		config.SizePrecision = tt.precision
+		collector := NewSMARTCollector(config, system.NewMockLogger(), system.NewMockCommandRunner())
+
+		if result := collector.normalizeSize("1536 GiB"); result != tt.expected {
+			t.Errorf("Precision %d: expected %q, got %q", tt.precision, tt.expected, result)
+		}
+	}
+}
+
+func TestSMARTCollector_NormalizeSizeDecimalInput(t *testing.T) {
+	// smartctl的GB/TB是十进制单位，二进制显示时不能按1024换算
+	tests := []struct {
+		units    model.SizeUnits
+		input    string
+		expected string
+	}{
+		{model.SizeUnitsBinary, "280210.01 GB", "254.85 TiB"},
+		{model.SizeUnitsDecimal, "280210.01 GB", "280.21 TB"},
+		{model.SizeUnitsBinary, "5.61 TB", "5.10 TiB"},
+		{model.SizeUnitsDecimal, "1536 GiB", "1.65 TB"},
+	}
+
+	for _, tt := range tests {
+		config := model.NewDefaultConfig()
+		config.SizeUnits = tt.units
+		collector := NewSMARTCollector(config, system.NewMockLogger(), system.NewMockCommandRunner())
+
+		if result := collector.normalizeSize(tt.input); result != tt.expected {
+			t.Errorf("%s %q: expected %q, got %q", tt.units, tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestParseSectorSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		logical  int
+		physical int
+	}{
+		{"SATA 512e", "Sector Sizes:     512 bytes logical, 4096 bytes physical\n", 512, 4096},
+		{"SATA 512n", "Sector Size:      512 bytes logical/physical\n", 512, 512},
+		{"SAS 4Kn", "Logical block size:   4096 bytes\nPhysical block size:  4096 bytes\n", 4096, 4096},
+		{"SAS logical only", "Logical block size:   512 bytes\n", 512, 512},
+		{"NVMe", "Namespace 1 Formatted LBA Size:     4096\n", 4096, 4096},
+		{"unknown", "Serial Number:    S6PTNM0T123456A\n", 0, 0},
+	}
+
+	for _, tt := range tests {
+		logical, physical := parseSectorSizes(tt.output)
+		if logical != tt.logical || physical != tt.physical {
+			t.Errorf("%s: expected %d/%d, got %d/%d", tt.name, tt.logical, tt.physical, logical, physical)
+		}
+	}
+}
+
+func TestSMARTCollector_LinkSpeed(t *testing.T) {
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), system.NewMockCommandRunner())
+
+	// 6Gb/s的SATA磁盘只协商到3Gb/s
+	output := `=== START OF INFORMATION SECTION ===
+Device Model:     WDC WD40EFRX-68N32N0
+Serial Number:    WD-WCC7K1234567
+SATA Version is:  SATA 3.0, 6.0 Gb/s (current: 3.0 Gb/s)
+
+=== START OF READ SMART DATA SECTION ===
+SMART overall-health self-assessment test result: PASSED
+`
+	disk := collector.ParseSMARTOutput("sda", "HDD", "WDC WD40EFRX-68N32N0", output)
+	if disk.SMARTData[model.LinkSpeedAttribute] != "3.0" || disk.SMARTData[model.LinkSpeedMaxAttribute] != "6.0" {
+		t.Errorf("Expected link speed 3.0/6.0, got '%s'/'%s'",
+			disk.SMARTData[model.LinkSpeedAttribute], disk.SMARTData[model.LinkSpeedMaxAttribute])
+	}
+	if disk.Status != model.DiskStatusWarning {
+		t.Errorf("Expected %s for downgraded link, got %s (%s)", model.DiskStatusWarning, disk.Status, disk.StatusReason)
+	}
+	if !strings.Contains(disk.StatusReason, "链路速率降级") {
+		t.Errorf("Expected status reason to mention the downgraded link, got '%s'", disk.StatusReason)
+	}
+	if got := disk.GetDisplayLinkSpeed(); got != "3.0/6.0 Gb/s" {
+		t.Errorf("Expected display link speed '3.0/6.0 Gb/s', got '%s'", got)
+	}
+
+	// 以最高速率运行时保持正常
+	output = strings.Replace(output, "(current: 3.0 Gb/s)", "(current: 6.0 Gb/s)", 1)
+	disk = collector.ParseSMARTOutput("sda", "HDD", "WDC WD40EFRX-68N32N0", output)
+	if disk.Status != model.DiskStatusOK {
+		t.Errorf("Expected %s at full link speed, got %s (%s)", model.DiskStatusOK, disk.Status, disk.StatusReason)
+	}
+
+	// SAS端口日志只报告协商速率，无法判断是否降级
+	current, max := parseLinkSpeed("  negotiated logical link rate: phy enabled; 12 Gbps\n")
+	if current != "12.0" || max != "" {
+		t.Errorf("Expected SAS link speed 12.0 with unknown maximum, got '%s'/'%s'", current, max)
+	}
+}
+
+func TestSMARTCollector_NonZeroExitStatus(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+
+	// 错误日志中有记录时smartctl返回64(位6)，输出仍然有效
+	output := `=== START OF READ SMART DATA SECTION ===
+SMART overall-health self-assessment test result: PASSED
+Temperature: 38 Celsius
+`
+	mockRunner.SetMockOutput("smartctl -H /dev/sda", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockExitStatus("smartctl -a /dev/sda", 64, output)
+
+	smartData, err := collector.GetSMARTData(context.Background(), "sda", "HDD", "WDC WD40EFRX-68N32N0")
+	if err != nil {
+		t.Fatalf("Expected the output to be parsed despite the exit status, got %v", err)
+	}
+	if smartData["Temperature"] != "38" {
+		t.Errorf("Expected temperature 38, got '%s'", smartData["Temperature"])
+	}
+	if smartData[model.SmartctlExitStatusAttribute] != "64" {
+		t.Errorf("Expected exit status 64 to be recorded, got '%s'", smartData[model.SmartctlExitStatusAttribute])
+	}
+
+	disk := model.NewDisk("sda", "HDD", "WDC WD40EFRX-68N32N0", "4T")
+	disk.SMARTData = smartData
+	disk.UpdateStatus()
+	// 错误日志中的记录不会清除，只作为信息记录，不升级状态
+	if disk.Status != model.DiskStatusOK {
+		t.Errorf("Expected %s for exit status 64, got %s (%s)", model.DiskStatusOK, disk.Status, disk.StatusReason)
+	}
+
+	// 同时有自检日志错误(位7)时仍然警告，原因中不提错误日志
+	disk = model.NewDisk("sda", "HDD", "WDC WD40EFRX-68N32N0", "4T")
+	disk.SMARTData = map[string]string{"Temperature": "38", model.SmartctlExitStatusAttribute: "192"}
+	disk.UpdateStatus()
+	if disk.Status != model.DiskStatusWarning {
+		t.Errorf("Expected %s for exit status 192, got %s (%s)", model.DiskStatusWarning, disk.Status, disk.StatusReason)
+	}
+	if !strings.Contains(disk.StatusReason, "自检日志") || strings.Contains(disk.StatusReason, "错误日志中有记录") {
+		t.Errorf("Expected status reason to mention only the self-test log, got '%s'", disk.StatusReason)
+	}
+
+	// 无法打开设备(位1)时输出无效，仍然报错
+	mockRunner.SetMockExitStatus("smartctl -a /dev/sdb", 2, "Smartctl open device: /dev/sdb failed")
+	if _, err := collector.GetSMARTData(context.Background(), "sdb", "HDD", "WDC WD40EFRX-68N32N0"); err == nil {
+		t.Error("Expected an error when smartctl cannot open the device")
+	}
+}
+
+func TestSMARTCollector_NVMeEnduranceGroups(t *testing.T) {
+	newRunner := func() *system.MockCommandRunner {
+		runner := system.NewMockCommandRunner()
+		runner.SetMockOutput("smartctl -H /dev/nvme0n1", "SMART overall-health self-assessment test result: PASSED")
+		runner.SetMockOutput("smartctl -a /dev/nvme0n1", nvmeSmartOutput)
+		runner.SetMockOutput("command -v nvme", "/usr/sbin/nvme")
+		runner.SetMockOutput("nvme id-ctrl /dev/nvme0 -o json", `{"vid": 32902, "mn": "INTEL SSDPF2KX038TZ", "endgidmax": 2}`)
+		runner.SetMockOutput("nvme endurance-log /dev/nvme0 --group-id=1 -o json",
+			`{"critical_warning": 0, "avl_spare": 100, "avl_spare_threshold": 10, "percent_used": 3, "data_units_read": 12345}`)
+		runner.SetMockOutput("nvme endurance-log /dev/nvme0 --group-id=2 -o json",
+			`{"critical_warning": 4, "avl_spare": "87", "avl_spare_threshold": 10, "percent_used": "41"}`)
+		return runner
+	}
+
+	// 默认不调用nvme-cli
+	runner := newRunner()
+	collector := NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), runner)
+	if _, err := collector.GetSMARTData(context.Background(), "nvme0n1", "SSD", "INTEL SSDPF2KX038TZ"); err != nil {
+		t.Fatalf("GetSMARTData failed: %v", err)
+	}
+	for _, cmd := range runner.CalledCommands {
+		if strings.HasPrefix(cmd, "nvme ") {
+			t.Errorf("Expected no nvme-cli commands without --nvme-extended, got %q", cmd)
+		}
+	}
+
+	// --nvme-extended 时按控制器查询每个耐久组
+	config := model.NewDefaultConfig()
+	config.NVMeExtended = true
+	collector = NewSMARTCollector(config, system.NewMockLogger(), newRunner())
+	smartData, err := collector.GetSMARTData(context.Background(), "nvme0n1", "SSD", "INTEL SSDPF2KX038TZ")
+	if err != nil {
+		t.Fatalf("GetSMARTData failed: %v", err)
+	}
+	expected := map[string]string{
+		"Endurance_Group_1_Critical_Warning": "0",
+		"Endurance_Group_1_Percent_Used":     "3",
+		"Endurance_Group_1_Available_Spare":  "100",
+		"Endurance_Group_2_Critical_Warning": "4",
+		"Endurance_Group_2_Percent_Used":     "41",
+		"Endurance_Group_2_Available_Spare":  "87",
+	}
+	for key, value := range expected {
+		if smartData[key] != value {
+			t.Errorf("%s: expected '%s', got '%s'", key, value, smartData[key])
+		}
+	}
+
+	// 耐久组2的可靠性下降警告使磁盘状态为错误
+	disk := model.NewDisk("nvme0n1", "SSD", "INTEL SSDPF2KX038TZ", "3.84 TB")
+	for key, value := range smartData {
+		disk.SMARTData[key] = value
+	}
+	disk.UpdateStatus()
+	if disk.Status != model.DiskStatusError || !strings.Contains(disk.StatusReason, "耐久组2 0x04") {
+		t.Errorf("Expected an error naming endurance group 2, got %s (%s)", disk.Status, disk.StatusReason)
+	}
+	if got := disk.GetDisplayEnduranceGroups(); got != "EG1 3%, EG2 41% (警告 0x04)" {
+		t.Errorf("Expected 'EG1 3%%, EG2 41%% (警告 0x04)', got '%s'", got)
+	}
+
+	// 没有nvme-cli时跳过
+	runner = newRunner()
+	runner.SetMockOutput("command -v nvme", "")
+	collector = NewSMARTCollector(config, system.NewMockLogger(), runner)
+	smartData, _ = collector.GetSMARTData(context.Background(), "nvme0n1", "SSD", "INTEL SSDPF2KX038TZ")
+	if _, ok := smartData["Endurance_Group_1_Percent_Used"]; ok {
+		t.Error("Expected no endurance data without nvme-cli")
+	}
+}
+
+func TestSMARTCollector_PowerSettings(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	config := model.NewDefaultConfig()
+	config.ShowPower = true
+	collector := NewSMARTCollector(config, system.NewMockLogger(), mockRunner)
+
+	mockRunner.SetMockOutput("smartctl -H /dev/sda", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockOutput("smartctl -a /dev/sda", "Temperature: 38 Celsius\n")
+	mockRunner.SetMockOutput("smartctl -g apm -g dsn /dev/sda", `smartctl 7.4 2023-08-01 r5530 [x86_64-linux-6.6.44-production+truenas] (local build)
+Copyright (C) 2002-23, Bruce Allen, Christian Franke, www.smartmontools.org
+
+=== START OF READ SMART DATA SECTION ===
+APM level is:     128 (minimum power consumption without standby)
+DSN feature is:   Disabled
+`)
+
+	smartData, err := collector.GetSMARTData(context.Background(), "sda", "HDD", "WDC WD40EFRX-68N32N0")
+	if err != nil {
+		t.Fatalf("GetSMARTData failed: %v", err)
+	}
+	if smartData[model.APMLevelAttribute] != "128" || smartData[model.DSNStateAttribute] != model.PowerFeatureDisabled {
+		t.Errorf("Expected APM 128 and DSN Disabled, got '%s'/'%s'",
+			smartData[model.APMLevelAttribute], smartData[model.DSNStateAttribute])
+	}
+
+	disk := model.NewDisk("sda", "HDD", "WDC WD40EFRX-68N32N0", "4T")
+	disk.SMARTData = smartData
+	if got := disk.GetDisplayPower(); got != "APM 128, DSN 已禁用" {
+		t.Errorf("Expected display power 'APM 128, DSN 已禁用', got '%s'", got)
+	}
+
+	// 允许停转的APM级别和不支持的功能
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{"APM level is:     1 (minimum power consumption with standby)\nDSN feature is:   Enabled\n", "APM 1 (允许停转), DSN 已启用"},
+		{"APM feature is:   Disabled\n", "APM 已禁用"},
+		{"APM feature is:   Unavailable\nDSN feature is:   Unavailable\n", "APM 不支持, DSN 不支持"},
+		{"", "N/A"},
+	}
+	for _, tt := range tests {
+		disk.SMARTData = parsePowerSettings(tt.output)
+		if got := disk.GetDisplayPower(); got != tt.expected {
+			t.Errorf("%q: expected '%s', got '%s'", tt.output, tt.expected, got)
+		}
+	}
+
+	// 默认不执行额外的命令
+	mockRunner = system.NewMockCommandRunner()
+	mockRunner.SetMockOutput("smartctl -a /dev/sda", "Temperature: 38 Celsius\n")
+	collector = NewSMARTCollector(model.NewDefaultConfig(), system.NewMockLogger(), mockRunner)
+	if _, err := collector.GetSMARTData(context.Background(), "sda", "HDD", "WDC WD40EFRX-68N32N0"); err != nil {
+		t.Fatalf("GetSMARTData failed: %v", err)
+	}
+	if contains(mockRunner.CalledCommands, "smartctl -g apm -g dsn /dev/sda") {
+		t.Error("Expected no power settings command without --show-power")
+	}
+}
+
+func TestSMARTCollector_MinimalDetail(t *testing.T) {
+	mockRunner := system.NewMockCommandRunner()
+	config := model.NewDefaultConfig()
+	config.SMARTDetail = model.SMARTDetailMinimal
+	collector := NewSMARTCollector(config, system.NewMockLogger(), mockRunner)
+
+	mockRunner.SetMockOutput("smartctl -H /dev/sda", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockOutput("smartctl -A /dev/sda", "Current Drive Temperature:     38 C\nDrive Trip Temperature:        65 C\n")
+	mockRunner.SetMockOutput("smartctl -H /dev/nvme0n1", "SMART overall-health self-assessment test result: PASSED")
+	mockRunner.SetMockOutput("smartctl -A /dev/nvme0n1", "Temperature:                        41 Celsius\nPercentage Used:                    3%\n")
+
+	sataData, err := collector.GetSMARTData(context.Background(), "sda", "HDD", "WDC WD40EFRX-68N32N0")
+	if err != nil {
+		t.Fatalf("GetSMARTData(sda) failed: %v", err)
+	}
+	nvmeData, err := collector.GetSMARTData(context.Background(), "nvme0n1", "SSD", "Samsung SSD 980 PRO")
+	if err != nil {
+		t.Fatalf("GetSMARTData(nvme0n1) failed: %v", err)
+	}
+
+	// 精简模式只读取健康状态和属性，仍能得到温度
+	if sataData["Smart_Status"] != "PASSED" || sataData["Temperature"] != "38" {
+		t.Errorf("Expected SATA health and temperature, got %v", sataData)
+	}
+	if nvmeData["Temperature"] != "41" || nvmeData["Percentage_Used"] != "3" {
+		t.Errorf("Expected NVMe temperature and wear, got %v", nvmeData)
+	}
+	for _, cmd := range mockRunner.CalledCommands {
+		if strings.Contains(cmd, "smartctl -a") {
+			t.Errorf("Expected no smartctl -a in minimal mode, got %q", cmd)
+		}
+	}
+}
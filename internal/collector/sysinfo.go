@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
+)
+
+// SystemInfoCollector 收集报告头部显示的主机信息(--system-info)
+type SystemInfoCollector struct {
+	logger        system.Logger
+	commandRunner system.CommandRunner
+}
+
+// NewSystemInfoCollector 创建一个新的主机信息收集器
+func NewSystemInfoCollector(logger system.Logger, runner system.CommandRunner) *SystemInfoCollector {
+	return &SystemInfoCollector{
+		logger:        logger,
+		commandRunner: runner,
+	}
+}
+
+// Collect 收集主机名、系统版本和运行时间
+// 优先使用midclt call system.info，不可用时使用hostname、/etc/version或uname和/proc/uptime
+// 主机信息只用于显示，获取失败的字段留空，不返回错误
+func (c *SystemInfoCollector) Collect(ctx context.Context) *model.SystemInfo {
+	info, err := c.collectFromMidclt(ctx)
+	if err == nil {
+		return info
+	}
+	c.logger.Debug("从midclt获取主机信息失败，使用系统命令: %v", err)
+
+	info = &model.SystemInfo{Source: model.DataSourceUname}
+	if output, err := c.commandRunner.Run(ctx, "hostname"); err == nil {
+		info.Hostname = strings.TrimSpace(output)
+	}
+
+	// TrueNAS CORE在/etc/version中记录版本，其他系统使用内核版本
+	if output, err := c.commandRunner.Run(ctx, "cat /etc/version"); err == nil && strings.TrimSpace(output) != "" {
+		info.OSVersion = strings.TrimSpace(output)
+	} else if output, err := c.commandRunner.Run(ctx, "uname -sr"); err == nil {
+		info.OSVersion = strings.TrimSpace(output)
+	}
+
+	if output, err := c.commandRunner.Run(ctx, "cat /proc/uptime"); err == nil {
+		info.UptimeSeconds = parseProcUptime(output)
+	}
+	return info
+}
+
+// collectFromMidclt 从midclt call system.info获取主机信息
+func (c *SystemInfoCollector) collectFromMidclt(ctx context.Context) (*model.SystemInfo, error) {
+	output, err := c.commandRunner.Run(ctx, "midclt call system.info")
+	if err != nil {
+		return nil, err
+	}
+
+	var systemInfo map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &systemInfo); err != nil {
+		return nil, err
+	}
+
+	info := &model.SystemInfo{Source: model.DataSourceMidclt}
+	info.Hostname, _ = systemInfo["hostname"].(string)
+	info.OSVersion, _ = systemInfo["version"].(string)
+	if uptime, ok := jsonNumber(systemInfo["uptime_seconds"]); ok {
+		info.UptimeSeconds = int64(uptime)
+	}
+	return info, nil
+}
+
+// parseProcUptime 从/proc/uptime的输出中获取运行时间(秒)，如 "1052391.47 4083521.08"
+func parseProcUptime(output string) int64 {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0
+	}
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	return int64(uptime)
+}
@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
+)
+
+func TestSystemInfoCollector_Fallback(t *testing.T) {
+	// midclt不可用时使用hostname、uname和/proc/uptime
+	runner := system.NewMockCommandRunner()
+	runner.SetMockError("midclt call system.info", errors.New("midclt: command not found"))
+	runner.SetMockOutput("hostname", "backup-nas\n")
+	runner.SetMockError("cat /etc/version", errors.New("No such file or directory"))
+	runner.SetMockOutput("uname -sr", "Linux 6.1.55-production+truenas\n")
+	runner.SetMockOutput("cat /proc/uptime", "1052391.47 4083521.08\n")
+
+	info := NewSystemInfoCollector(system.NewMockLogger(), runner).Collect(context.Background())
+	if info.Source != model.DataSourceUname {
+		t.Errorf("Expected source %s, got %s", model.DataSourceUname, info.Source)
+	}
+	if info.Hostname != "backup-nas" || info.OSVersion != "Linux 6.1.55-production+truenas" {
+		t.Errorf("Expected backup-nas running Linux 6.1.55, got %q / %q", info.Hostname, info.OSVersion)
+	}
+	if info.UptimeSeconds != 1052391 || info.GetDisplayUptime() != "12天4小时19分钟" {
+		t.Errorf("Expected 1052391 seconds (12天4小时19分钟), got %d (%s)", info.UptimeSeconds, info.GetDisplayUptime())
+	}
+
+	// TrueNAS CORE在/etc/version中记录版本
+	runner.SetMockOutput("cat /etc/version", "TrueNAS-13.0-U6.1\n")
+	delete(runner.MockErrors, "cat /etc/version")
+	info = NewSystemInfoCollector(system.NewMockLogger(), runner).Collect(context.Background())
+	if info.OSVersion != "TrueNAS-13.0-U6.1" {
+		t.Errorf("Expected the /etc/version release, got %q", info.OSVersion)
+	}
+}
@@ -0,0 +1,54 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// trueNASTempFields midclt返回的温度告警阈值字段与磁盘属性名的对应关系
+// smart.config为全局设置，disk.query中每个磁盘可以单独设置，未设置时为null或0
+var trueNASTempFields = map[string]string{
+	"informational": model.TrueNASInformationalTempAttribute,
+	"critical":      model.TrueNASCriticalTempAttribute,
+}
+
+// parseTrueNASThresholds 从smart.config或disk.query的一项中提取已设置的温度告警阈值
+func parseTrueNASThresholds(data map[string]interface{}) map[string]string {
+	thresholds := make(map[string]string)
+	for key, attribute := range trueNASTempFields {
+		if value, ok := jsonNumber(data[key]); ok && value > 0 {
+			thresholds[attribute] = strconv.Itoa(int(value))
+		}
+	}
+	return thresholds
+}
+
+// applyTrueNASThresholds 使用TrueNAS中配置的温度告警阈值(--use-truenas-thresholds)
+// 磁盘单独设置的阈值(GetDisksFromMidclt中已记录)优先于smart.config的全局设置
+// midclt不可用时保留默认的判断方式
+func (d *DiskCollector) applyTrueNASThresholds(ctx context.Context, disks []*model.Disk) {
+	output, err := d.commandRunner.Run(ctx, "midclt call smart.config")
+	if err != nil {
+		d.logger.Info("获取TrueNAS温度告警阈值失败，使用默认阈值: %v", err)
+		return
+	}
+
+	var smartConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &smartConfig); err != nil {
+		d.logger.Error("解析TrueNAS温度告警阈值失败: %v", err)
+		return
+	}
+
+	thresholds := parseTrueNASThresholds(smartConfig)
+	d.logger.Debug("TrueNAS全局温度告警阈值: %v", thresholds)
+	for _, disk := range disks {
+		for attribute, value := range thresholds {
+			if _, ok := disk.SMARTData[attribute]; !ok {
+				disk.SMARTData[attribute] = value
+			}
+		}
+	}
+}
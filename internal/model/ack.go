@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// StatusSourceAck 状态原因中确认备注的来源标记
+const StatusSourceAck = "已确认"
+
+// 确认到期时间支持的格式
+var ackTimeLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// Acknowledgement 已知问题的确认记录: 在到期前该磁盘的警告不再触发告警
+type Acknowledgement struct {
+	Reason string `json:"reason"` // 确认原因
+	Until  string `json:"until"`  // 到期时间(YYYY-MM-DD 或 RFC3339)，为空表示永不过期
+
+	expires time.Time
+}
+
+// Validate 验证确认记录并解析到期时间
+func (a *Acknowledgement) Validate() error {
+	if strings.TrimSpace(a.Reason) == "" {
+		return fmt.Errorf("确认记录缺少原因")
+	}
+	if a.Until == "" {
+		return nil
+	}
+
+	for _, layout := range ackTimeLayouts {
+		if t, err := time.ParseInLocation(layout, a.Until, time.Local); err == nil {
+			// 只写日期时在当天结束时到期
+			if layout == "2006-01-02" {
+				t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			a.expires = t
+			return nil
+		}
+	}
+	return fmt.Errorf("无法解析确认到期时间: %s", a.Until)
+}
+
+// Active 检查确认记录在指定时间是否仍然有效
+func (a Acknowledgement) Active(now time.Time) bool {
+	return a.expires.IsZero() || !now.After(a.expires)
+}
+
+// String 返回确认记录的可读描述，作为状态原因显示
+func (a Acknowledgement) String() string {
+	if a.Until == "" {
+		return fmt.Sprintf("%s: %s", StatusSourceAck, a.Reason)
+	}
+	return fmt.Sprintf("%s(至 %s): %s", StatusSourceAck, a.Until, a.Reason)
+}
+
+// LoadAcknowledgements 从JSON文件加载确认记录(序列号 -> 确认记录)
+func LoadAcknowledgements(path string) (map[string]Acknowledgement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取确认文件失败: %w", err)
+	}
+
+	var acks map[string]Acknowledgement
+	if err := json.Unmarshal(data, &acks); err != nil {
+		return nil, fmt.Errorf("解析确认文件失败: %w", err)
+	}
+
+	for serial, ack := range acks {
+		if err := ack.Validate(); err != nil {
+			return nil, fmt.Errorf("序列号 %s 的%w", serial, err)
+		}
+		acks[serial] = ack
+	}
+
+	return acks, nil
+}
+
+// ApplyAcknowledgements 为处于警告状态且确认仍有效的磁盘添加确认备注
+// 磁盘状态保持不变，但已确认的磁盘不计入告警数量；错误状态不会被确认掩盖
+// 返回被确认的磁盘数量
+func (dd *DiskData) ApplyAcknowledgements(acks map[string]Acknowledgement, now time.Time) int {
+	count := 0
+	for _, disk := range dd.Disks {
+		ack, ok := acks[disk.Serial]
+		if !ok || disk.Serial == "" || !ack.Active(now) {
+			continue
+		}
+		if disk.GetStatus() != DiskStatusWarning {
+			continue
+		}
+
+		disk.Acknowledged = ack.Reason
+		disk.AddStatusReason(ack.String())
+		count++
+	}
+	return count
+}
+
+// GetAlertCount 获取需要告警的数量(未确认的磁盘警告、所有磁盘错误和容量超过阈值的存储池)
+// 磁盘按抖动检测后的告警状态计算(--flap-runs)
+func (dd *DiskData) GetAlertCount() int {
+	count := len(dd.GetFullPools())
+	for _, disk := range dd.Disks {
+		switch disk.GetAlertStatus() {
+		case DiskStatusWarning:
+			if disk.Acknowledged == "" {
+				count++
+			}
+		case DiskStatusError:
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskData_ApplyAcknowledgements(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+
+	newWarningDisk := func(name, serial string) *Disk {
+		disk := NewDisk(name, "HDD", "SEAGATE ST600MM0006", "600G")
+		disk.Serial = serial
+		disk.Escalate(DiskStatusWarning, "test")
+		return disk
+	}
+
+	diskData := NewDiskData()
+	acked := newWarningDisk("sda", "ACKED")
+	expired := newWarningDisk("sdb", "EXPIRED")
+	failed := NewDisk("sdc", "HDD", "SEAGATE ST600MM0006", "600G")
+	failed.Serial = "FAILED"
+	failed.Escalate(DiskStatusError, "test")
+	diskData.AddDisk(acked)
+	diskData.AddDisk(expired)
+	diskData.AddDisk(failed)
+
+	acks := map[string]Acknowledgement{
+		"ACKED":   {Reason: "known defect", Until: "2025-03-01"},
+		"EXPIRED": {Reason: "old defect", Until: "2025-02-28"},
+		"FAILED":  {Reason: "ignored"},
+	}
+	for serial, ack := range acks {
+		if err := ack.Validate(); err != nil {
+			t.Fatalf("Validate(%s) failed: %v", serial, err)
+		}
+		acks[serial] = ack
+	}
+
+	if count := diskData.ApplyAcknowledgements(acks, now); count != 1 {
+		t.Errorf("Expected 1 acknowledged disk, got %d", count)
+	}
+
+	// 只写日期的确认在当天内仍然有效
+	if acked.Acknowledged != "known defect" || acked.GetStatus() != DiskStatusWarning {
+		t.Errorf("Expected sda acknowledged with status kept, got %q/%s", acked.Acknowledged, acked.GetStatus())
+	}
+	if expired.Acknowledged != "" {
+		t.Errorf("Expected expired acknowledgement to be ignored, got %q", expired.Acknowledged)
+	}
+	// 错误状态不会被确认掩盖
+	if failed.Acknowledged != "" {
+		t.Errorf("Expected failed disk not to be acknowledged, got %q", failed.Acknowledged)
+	}
+
+	if diskData.GetWarningCount() != 2 || diskData.GetAlertCount() != 2 {
+		t.Errorf("Expected 2 warnings and 2 alerts, got %d/%d", diskData.GetWarningCount(), diskData.GetAlertCount())
+	}
+}
+
+func TestLoadAcknowledgements(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "acks.json")
+	os.WriteFile(valid, []byte(`{"S1": {"reason": "stable", "until": "2025-06-30T00:00:00Z"}, "S2": {"reason": "forever"}}`), 0644)
+	acks, err := LoadAcknowledgements(valid)
+	if err != nil {
+		t.Fatalf("LoadAcknowledgements() failed: %v", err)
+	}
+	if len(acks) != 2 || !acks["S2"].Active(time.Now()) {
+		t.Errorf("Unexpected acknowledgements: %v", acks)
+	}
+	if acks["S1"].Active(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected S1 to expire after 2025-06-30")
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`{"S1": {"reason": "stable", "until": "next year"}}`), 0644)
+	if _, err := LoadAcknowledgements(invalid); err == nil {
+		t.Errorf("Expected error for unparseable expiry")
+	}
+
+	noReason := filepath.Join(dir, "noreason.json")
+	os.WriteFile(noReason, []byte(`{"S1": {"until": "2025-06-30"}}`), 0644)
+	if _, err := LoadAcknowledgements(noReason); err == nil {
+		t.Errorf("Expected error for missing reason")
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import "fmt"
+
+// DefaultAgeInfoYears 默认的磁盘使用年限提示阈值(年)
+const DefaultAgeInfoYears = 5
+
+// hoursPerYear 每年的通电小时数(365 * 24)，与通电时间的显示保持一致
+const hoursPerYear = 8760
+
+// GetAgeYears 根据通电时间计算磁盘的使用年限，没有通电时间时ok为false
+func (d *Disk) GetAgeYears() (years float64, ok bool) {
+	var hours float64
+	if _, err := fmt.Sscanf(NormalizeNumber(d.SMARTData["Power_On_Hours"]), "%f", &hours); err != nil || hours <= 0 {
+		return 0, false
+	}
+	return hours / hoursPerYear, true
+}
+
+// FlagAgedDisks 为通电时间超过years年的磁盘添加提示，便于提前规划更换
+// 只是提示，不改变磁盘状态，也不触发告警；years为0时不检查
+// 返回被标记的磁盘数量
+func (dd *DiskData) FlagAgedDisks(years int) int {
+	dd.AgeInfoYears = years
+	if years <= 0 {
+		return 0
+	}
+
+	count := 0
+	for _, disk := range dd.Disks {
+		age, ok := disk.GetAgeYears()
+		if !ok || age < float64(years) {
+			continue
+		}
+		disk.AgeInfo = fmt.Sprintf("已通电 %.1f 年，超过 %d 年，建议规划更换", age, years)
+		count++
+	}
+	return count
+}
+
+// GetAgedDisks 获取通电时间超过提示阈值的磁盘
+func (dd *DiskData) GetAgedDisks() []*Disk {
+	var disks []*Disk
+	for _, disk := range dd.Disks {
+		if disk.AgeInfo != "" {
+			disks = append(disks, disk)
+		}
+	}
+	return disks
+}
@@ -0,0 +1,40 @@
+package model
+
+import "testing"
+
+func TestDiskData_FlagAgedDisks(t *testing.T) {
+	diskData := NewDiskData()
+	old := NewDisk("sda", "HDD", "SEAGATE ST600MM0006", "600G")
+	old.SMARTData["Power_On_Hours"] = "52,560" // 6年
+	young := NewDisk("sdb", "HDD", "SEAGATE ST600MM0006", "600G")
+	young.SMARTData["Power_On_Hours"] = "17520" // 2年
+	unknown := NewDisk("sdc", "HDD", "SEAGATE ST600MM0006", "600G")
+	for _, disk := range []*Disk{old, young, unknown} {
+		disk.SMARTData["Smart_Status"] = "PASSED"
+		disk.UpdateStatus()
+		diskData.AddDisk(disk)
+	}
+
+	if count := diskData.FlagAgedDisks(DefaultAgeInfoYears); count != 1 {
+		t.Errorf("Expected 1 aged disk, got %d", count)
+	}
+	if old.AgeInfo != "已通电 6.0 年，超过 5 年，建议规划更换" {
+		t.Errorf("Unexpected age info for sda: %q", old.AgeInfo)
+	}
+	if young.AgeInfo != "" || unknown.AgeInfo != "" {
+		t.Errorf("Expected sdb and sdc not to be flagged, got %q/%q", young.AgeInfo, unknown.AgeInfo)
+	}
+
+	// 只是提示，不改变状态，也不计入告警
+	if old.GetStatus() != DiskStatusOK || diskData.GetAlertCount() != 0 {
+		t.Errorf("Expected status kept and no alerts, got %s/%d", old.GetStatus(), diskData.GetAlertCount())
+	}
+	if aged := diskData.GetAgedDisks(); len(aged) != 1 || aged[0] != old {
+		t.Errorf("Expected only sda in aged disks, got %v", aged)
+	}
+
+	// 0表示不检查
+	if count := NewDiskData().FlagAgedDisks(0); count != 0 {
+		t.Errorf("Expected no aged disks when disabled, got %d", count)
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IDFormat 报告中磁盘标识的显示方式
+type IDFormat string
+
+const (
+	// IDFormatName 内核设备名(如sda)，重启或更换端口后可能变化
+	IDFormatName IDFormat = "name"
+	// IDFormatByID /dev/disk/by-id下的稳定名称(如ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1234567)
+	IDFormatByID IDFormat = "by-id"
+)
+
+// ParseIDFormat 解析磁盘标识方式名称
+func ParseIDFormat(name string) (IDFormat, error) {
+	switch IDFormat(strings.ToLower(name)) {
+	case IDFormatName:
+		return IDFormatName, nil
+	case IDFormatByID:
+		return IDFormatByID, nil
+	default:
+		return "", fmt.Errorf("不支持的磁盘标识方式: %s (可选 name, by-id)", name)
+	}
+}
+
+// GetDisplayName 按标识方式获取可显示的磁盘名称，没有by-id链接时使用内核设备名
+func (d *Disk) GetDisplayName(format IDFormat) string {
+	if format == IDFormatByID && d.ByID != "" {
+		return d.ByID
+	}
+	return d.Name
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ZFS数据vdev类型(midclt pool.query的topology.data[].type)
+const (
+	VdevTypeDisk   = "DISK"
+	VdevTypeMirror = "MIRROR"
+	VdevTypeRAIDZ1 = "RAIDZ1"
+	VdevTypeRAIDZ2 = "RAIDZ2"
+	VdevTypeRAIDZ3 = "RAIDZ3"
+)
+
+// PoolVdev 存储池的一个数据vdev
+type PoolVdev struct {
+	Type  string    // vdev类型(DISK, MIRROR, RAIDZ1等)
+	Disks []string  // 成员磁盘名称
+	Sizes []float64 // 成员容量(字节，与Disks一一对应)，未知时为0
+}
+
+// vdevParity 各vdev类型用于冗余的磁盘数量，镜像单独处理
+var vdevParity = map[string]int{
+	VdevTypeDisk:   0,
+	VdevTypeRAIDZ1: 1,
+	VdevTypeRAIDZ2: 2,
+	VdevTypeRAIDZ3: 3,
+}
+
+// GetSizeBytes 获取磁盘容量的字节数
+// midclt返回字节数(可能为科学计数法)，其他来源的 "4 TB" 等字符串按单位制换算
+func (d *Disk) GetSizeBytes(units SizeUnits) (float64, bool) {
+	size := strings.TrimSpace(d.Size)
+	if bytes, err := strconv.ParseFloat(size, 64); err == nil {
+		return bytes, bytes > 0
+	}
+	bytes, err := units.ParseSize(size)
+	if err != nil {
+		return 0, false
+	}
+	return bytes, bytes > 0
+}
+
+// GetRawCapacity 获取所有磁盘的原始容量总和(字节)，容量未知的磁盘不计入
+func (dd *DiskData) GetRawCapacity(units SizeUnits) float64 {
+	var total float64
+	for _, disk := range dd.Disks {
+		if bytes, ok := disk.GetSizeBytes(units); ok {
+			total += bytes
+		}
+	}
+	return total
+}
+
+// GetUsableCapacity 按存储池的vdev布局估算可用容量总和(字节)
+// 每个vdev按最小的成员磁盘计算，不计入ZFS元数据和预留空间等开销
+// 没有布局信息、包含未知的vdev类型或成员磁盘容量未知时返回false
+func (dd *DiskData) GetUsableCapacity(units SizeUnits) (float64, bool) {
+	if len(dd.PoolLayout) == 0 {
+		return 0, false
+	}
+
+	sizes := make(map[string]float64, len(dd.Disks))
+	for _, disk := range dd.Disks {
+		if bytes, ok := disk.GetSizeBytes(units); ok {
+			sizes[disk.Name] = bytes
+		}
+	}
+
+	var total float64
+	for _, vdevs := range dd.PoolLayout {
+		for _, vdev := range vdevs {
+			usable, ok := vdev.usableCapacity(sizes)
+			if !ok {
+				return 0, false
+			}
+			total += usable
+		}
+	}
+	return total, true
+}
+
+// usableCapacity 计算vdev的可用容量，sizes为磁盘名称到字节数的映射
+func (v PoolVdev) usableCapacity(sizes map[string]float64) (float64, bool) {
+	if len(v.Disks) == 0 {
+		return 0, false
+	}
+
+	var smallest float64
+	for i, name := range v.Disks {
+		size, ok := sizes[name]
+		if !ok {
+			return 0, false
+		}
+		if i == 0 || size < smallest {
+			smallest = size
+		}
+	}
+
+	vdevType := strings.ToUpper(v.Type)
+	if vdevType == VdevTypeMirror {
+		return smallest, true
+	}
+	parity, ok := vdevParity[vdevType]
+	if !ok || len(v.Disks) <= parity {
+		return 0, false
+	}
+	return float64(len(v.Disks)-parity) * smallest, true
+}
+
+// mixedCapacityTolerance 同一vdev成员容量允许的相对差异
+// 同一标称容量的不同型号磁盘字节数略有不同，不应视为混用
+const mixedCapacityTolerance = 0.01
+
+// withMemberSizes 返回补全成员容量后的vdev，midclt未提供的成员按磁盘容量补全
+func (v PoolVdev) withMemberSizes(diskSizes map[string]float64) PoolVdev {
+	sizes := make([]float64, len(v.Disks))
+	for i, name := range v.Disks {
+		if i < len(v.Sizes) && v.Sizes[i] > 0 {
+			sizes[i] = v.Sizes[i]
+		} else {
+			sizes[i] = diskSizes[name]
+		}
+	}
+	v.Sizes = sizes
+	return v
+}
+
+// HasMixedCapacity 检查vdev成员容量是否不同，容量大于最小成员的部分无法使用
+// 容量未知的成员不参与比较
+func (v PoolVdev) HasMixedCapacity() bool {
+	var smallest, largest float64
+	for _, size := range v.Sizes {
+		if size <= 0 {
+			continue
+		}
+		if smallest == 0 || size < smallest {
+			smallest = size
+		}
+		if size > largest {
+			largest = size
+		}
+	}
+	return smallest > 0 && largest-smallest > largest*mixedCapacityTolerance
+}
+
+// GetMixedCapacityPools 获取数据vdev混用不同容量磁盘的存储池汇总
+func (dd *DiskData) GetMixedCapacityPools() []*PoolSummary {
+	var pools []*PoolSummary
+	for _, summary := range dd.GetPoolSummaries() {
+		if len(summary.MixedCapacityVdevs) > 0 {
+			pools = append(pools, summary)
+		}
+	}
+	return pools
+}
+
+// mixedCapacityVdevs 获取存储池中成员容量不同的数据vdev，diskSizes为磁盘名称到字节数的映射
+func (dd *DiskData) mixedCapacityVdevs(pool string, diskSizes map[string]float64) []PoolVdev {
+	var vdevs []PoolVdev
+	for _, vdev := range dd.PoolLayout[pool] {
+		if vdev = vdev.withMemberSizes(diskSizes); vdev.HasMixedCapacity() {
+			vdevs = append(vdevs, vdev)
+		}
+	}
+	return vdevs
+}
@@ -0,0 +1,82 @@
+package model
+
+import "testing"
+
+func TestDiskData_Capacity(t *testing.T) {
+	dd := NewDiskData()
+	// midclt返回字节数，其他来源为带单位的字符串
+	for _, disk := range []*Disk{
+		NewDisk("sda", "HDD", "SEAGATE ST4000NM", "4000000000000"),
+		NewDisk("sdb", "HDD", "SEAGATE ST4000NM", "4e+12"),
+		NewDisk("sdc", "HDD", "SEAGATE ST4000NM", "4 TB"),
+		NewDisk("sdd", "SSD", "Samsung SSD 870 EVO", "1 TB"),
+		NewDisk("sde", "SSD", "Samsung SSD 870 EVO", "2 TB"),
+		NewDisk("sdf", "HDD", "Unknown", ""),
+	} {
+		dd.AddDisk(disk)
+	}
+
+	// 容量未知的磁盘不计入
+	if raw := dd.GetRawCapacity(SizeUnitsDecimal); raw != 15e12 {
+		t.Errorf("GetRawCapacity: expected %v, got %v", 15e12, raw)
+	}
+
+	if _, ok := dd.GetUsableCapacity(SizeUnitsDecimal); ok {
+		t.Error("GetUsableCapacity should be unavailable without a pool layout")
+	}
+
+	// RAIDZ1 (3 x 4 TB) + 镜像 (1 TB, 2 TB，按较小的磁盘计算)
+	dd.PoolLayout["tank"] = []PoolVdev{{Type: VdevTypeRAIDZ1, Disks: []string{"sda", "sdb", "sdc"}}}
+	dd.PoolLayout["fast"] = []PoolVdev{{Type: "mirror", Disks: []string{"sdd", "sde"}}}
+	usable, ok := dd.GetUsableCapacity(SizeUnitsDecimal)
+	if !ok || usable != 9e12 {
+		t.Errorf("GetUsableCapacity: expected %v, got %v (ok=%v)", 9e12, usable, ok)
+	}
+
+	// 成员磁盘容量未知时无法估算
+	dd.PoolLayout["scratch"] = []PoolVdev{{Type: VdevTypeDisk, Disks: []string{"sdf"}}}
+	if _, ok := dd.GetUsableCapacity(SizeUnitsDecimal); ok {
+		t.Error("GetUsableCapacity should be unavailable when a member size is unknown")
+	}
+}
+
+func TestDiskData_MixedCapacityVdevs(t *testing.T) {
+	dd := NewDiskData()
+	for _, disk := range []*Disk{
+		NewDisk("sda", "HDD", "WDC WD40EFRX", "4000787030016"),
+		NewDisk("sdb", "HDD", "WDC WD80EFZX", "8001563222016"),
+		NewDisk("sdc", "HDD", "SEAGATE ST4000VN", "4000787030016"),
+		NewDisk("sdd", "HDD", "TOSHIBA HDWQ140", "3999999999488"),
+	} {
+		disk.Pool = "tank"
+		dd.AddDisk(disk)
+	}
+
+	// 4TB + 8TB 镜像被标记，同标称容量的镜像字节数略有差异不标记
+	dd.PoolLayout["tank"] = []PoolVdev{
+		{Type: VdevTypeMirror, Disks: []string{"sda", "sdb"}},
+		{Type: VdevTypeMirror, Disks: []string{"sdc", "sdd"}},
+	}
+	pools := dd.GetMixedCapacityPools()
+	if len(pools) != 1 || pools[0].Name != "tank" {
+		t.Fatalf("Expected tank to be flagged, got %v", pools)
+	}
+	vdevs := pools[0].MixedCapacityVdevs
+	if len(vdevs) != 1 || vdevs[0].Disks[1] != "sdb" || vdevs[0].Sizes[1] != 8001563222016 {
+		t.Errorf("Expected only the sda/sdb mirror with member sizes, got %+v", vdevs)
+	}
+
+	// midclt提供的成员容量优先于磁盘容量
+	dd.PoolLayout["tank"] = []PoolVdev{
+		{Type: VdevTypeMirror, Disks: []string{"sda", "sdb"}, Sizes: []float64{3.9e12, 3.9e12}},
+	}
+	if pools := dd.GetMixedCapacityPools(); len(pools) != 0 {
+		t.Errorf("Expected no flagged pools when member sizes match, got %d", len(pools))
+	}
+
+	// 没有布局信息时不检查
+	dd.PoolLayout = make(map[string][]PoolVdev)
+	if pools := dd.GetMixedCapacityPools(); len(pools) != 0 {
+		t.Errorf("Expected no flagged pools without a layout, got %d", len(pools))
+	}
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 磁盘表格的基本列(不对应SMART属性)
+const (
+	ColumnName     = "name"
+	ColumnModel    = "model"
+	ColumnSize     = "size"
+	ColumnPool     = "pool"
+	ColumnSerial   = "serial"
+	ColumnWWN      = "wwn"
+	ColumnStatus   = "status"
+	ColumnFirmware = "firmware"
+	ColumnLink     = "link"
+	ColumnPower    = "power"
+	ColumnByID     = "by_id"
+)
+
+// DiskColumn 可通过 --columns 选择的磁盘表格列
+type DiskColumn struct {
+	Name        string // 列名(--columns中使用，小写)
+	DisplayName string // 表头
+	Attribute   string // 对应的SMART属性名，基本列为空
+}
+
+// diskColumns 所有已知的列，按列名索引
+var diskColumns = buildDiskColumns()
+
+// buildDiskColumns 由基本列和各磁盘类型的属性生成已知列
+// 属性列使用小写的属性名，温度另有简写temp，历史最高温度为lifetime_max_temp
+func buildDiskColumns() map[string]DiskColumn {
+	columns := map[string]DiskColumn{
+		ColumnName:          {Name: ColumnName, DisplayName: "名称"},
+		ColumnModel:         {Name: ColumnModel, DisplayName: "型号"},
+		ColumnSize:          {Name: ColumnSize, DisplayName: "容量"},
+		ColumnPool:          {Name: ColumnPool, DisplayName: "存储池"},
+		ColumnSerial:        {Name: ColumnSerial, DisplayName: "序列号"},
+		ColumnWWN:           {Name: ColumnWWN, DisplayName: "WWN"},
+		ColumnStatus:        {Name: ColumnStatus, DisplayName: "状态"},
+		ColumnFirmware:      {Name: ColumnFirmware, DisplayName: "固件版本"},
+		ColumnLink:          {Name: ColumnLink, DisplayName: "链路速率"},
+		ColumnPower:         {Name: ColumnPower, DisplayName: "电源管理"},
+		ColumnByID:          {Name: ColumnByID, DisplayName: "by-id"},
+		"temp":              {Name: "temp", DisplayName: "温度", Attribute: "Temperature"},
+		"lifetime_max_temp": {Name: "lifetime_max_temp", DisplayName: "历史最高温度", Attribute: LifetimeMaxTempAttribute},
+	}
+
+	dd := &DiskData{}
+	for _, diskType := range []DiskType{DiskTypeSASSSD, DiskTypeSASHDD, DiskTypeNVMESSD, DiskTypeVirtual} {
+		for _, attr := range dd.GetDiskAttributes(diskType) {
+			name := strings.ToLower(attr.Name)
+			columns[name] = DiskColumn{Name: name, DisplayName: attr.DisplayName, Attribute: attr.Name}
+		}
+	}
+	return columns
+}
+
+// GetMergedSSDColumns 获取合并显示固态硬盘时的默认列(--merge-ssd)
+// 基本列之后是SAS和NVMe固态硬盘属性列的并集，显示名称相同的列只出现一次
+func GetMergedSSDColumns() []DiskColumn {
+	columns := []DiskColumn{diskColumns[ColumnName], diskColumns[ColumnModel], diskColumns[ColumnSize], diskColumns[ColumnPool]}
+
+	seen := make(map[string]bool)
+	dd := &DiskData{}
+	for _, diskType := range []DiskType{DiskTypeSASSSD, DiskTypeNVMESSD} {
+		for _, attr := range dd.GetDiskAttributes(diskType) {
+			if seen[attr.DisplayName] {
+				continue
+			}
+			seen[attr.DisplayName] = true
+			columns = append(columns, diskColumns[strings.ToLower(attr.Name)])
+		}
+	}
+	return columns
+}
+
+// ResolveAttribute 获取列在某类磁盘上对应的属性名
+// 同名的列在不同类型中可能对应不同属性(如警告温度: SAS为Trip_Temperature，NVMe为Warning_Temperature)
+func (c DiskColumn) ResolveAttribute(diskType DiskType) string {
+	if c.Attribute == "" {
+		return ""
+	}
+	attributes := (&DiskData{}).GetDiskAttributes(diskType)
+	for _, attr := range attributes {
+		if attr.Name == c.Attribute {
+			return c.Attribute
+		}
+	}
+	for _, attr := range attributes {
+		if attr.DisplayName == c.DisplayName {
+			return attr.Name
+		}
+	}
+	return c.Attribute
+}
+
+// LookupDiskColumn 按列名(不区分大小写)查找列
+func LookupDiskColumn(name string) (DiskColumn, bool) {
+	column, ok := diskColumns[strings.ToLower(strings.TrimSpace(name))]
+	return column, ok
+}
+
+// GetDiskColumnNames 获取所有已知列名(已排序)
+func GetDiskColumnNames() []string {
+	names := make([]string, 0, len(diskColumns))
+	for name := range diskColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseColumnSpec 解析 type=col1,col2,... 形式的列选择
+func ParseColumnSpec(spec string) (DiskType, []DiskColumn, error) {
+	typeName, list, found := strings.Cut(spec, "=")
+	if !found {
+		return "", nil, fmt.Errorf("无效的列选择: %s (应为 类型=列1,列2,...)", spec)
+	}
+
+	diskType := DiskType(strings.ToUpper(strings.TrimSpace(typeName)))
+	switch diskType {
+	case DiskTypeSASSSD, DiskTypeSASHDD, DiskTypeNVMESSD, DiskTypeVirtual:
+		// 有效的磁盘类型
+	default:
+		return "", nil, fmt.Errorf("列选择使用了不支持的磁盘类型: %s", typeName)
+	}
+
+	var columns []DiskColumn
+	for _, name := range strings.Split(list, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		column, ok := LookupDiskColumn(name)
+		if !ok {
+			return "", nil, fmt.Errorf("未知的列名: %s (可用的列: %s)", strings.TrimSpace(name), strings.Join(GetDiskColumnNames(), ", "))
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return "", nil, fmt.Errorf("列选择 %s 没有指定任何列", spec)
+	}
+
+	return diskType, columns, nil
+}
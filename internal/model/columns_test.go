@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseColumnSpec 测试列选择的解析和校验
+func TestParseColumnSpec(t *testing.T) {
+	diskType, columns, err := ParseColumnSpec("sas_hdd=name, temp,STATUS,power_on_hours")
+	if err != nil {
+		t.Fatalf("ParseColumnSpec failed: %v", err)
+	}
+	if diskType != DiskTypeSASHDD {
+		t.Errorf("Expected SAS_HDD, got %s", diskType)
+	}
+
+	expected := []DiskColumn{
+		{Name: ColumnName, DisplayName: "名称"},
+		{Name: "temp", DisplayName: "温度", Attribute: "Temperature"},
+		{Name: ColumnStatus, DisplayName: "状态"},
+		{Name: "power_on_hours", DisplayName: "通电时间", Attribute: "Power_On_Hours"},
+	}
+	if len(columns) != len(expected) {
+		t.Fatalf("Expected %d columns, got %+v", len(expected), columns)
+	}
+	for i := range expected {
+		if columns[i] != expected[i] {
+			t.Errorf("Column %d: expected %+v, got %+v", i, expected[i], columns[i])
+		}
+	}
+
+	// 无效的输入
+	for spec, want := range map[string]string{
+		"name,temp":        "应为",
+		"SATA=name":        "不支持的磁盘类型",
+		"SAS_HDD=name,foo": "未知的列名: foo",
+		"SAS_HDD=":         "没有指定任何列",
+	} {
+		if _, _, err := ParseColumnSpec(spec); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseColumnSpec(%q): expected error containing %q, got %v", spec, want, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -19,8 +20,82 @@ const (
 	OutputFormatJSON OutputFormat = "json"
 	// OutputFormatHTML HTML格式输出
 	OutputFormatHTML OutputFormat = "html"
+	// OutputFormatTemplate 使用用户提供的Go模板输出
+	OutputFormatTemplate OutputFormat = "template"
+	// OutputFormatHealth 只输出整体健康结论(OK/WARNING/CRITICAL)
+	OutputFormatHealth OutputFormat = "health"
 )
 
+// GroupBy 定义磁盘分组显示方式
+type GroupBy string
+
+const (
+	// GroupByType 按磁盘类型分组
+	GroupByType GroupBy = "type"
+	// GroupByPool 按存储池分组
+	GroupByPool GroupBy = "pool"
+	// GroupByNone 不分组
+	GroupByNone GroupBy = "none"
+)
+
+// ParseGroupBy 解析分组方式名称
+func ParseGroupBy(name string) (GroupBy, error) {
+	switch GroupBy(strings.ToLower(name)) {
+	case GroupByType:
+		return GroupByType, nil
+	case GroupByPool:
+		return GroupByPool, nil
+	case GroupByNone:
+		return GroupByNone, nil
+	default:
+		return "", fmt.Errorf("不支持的分组方式: %s (可选 type, pool, none)", name)
+	}
+}
+
+// ColorTheme 定义文本输出的状态配色方案
+type ColorTheme string
+
+const (
+	// ColorThemeDefault 绿/黄/红配色
+	ColorThemeDefault ColorTheme = "default"
+	// ColorThemeDeuteranopia 红绿色盲友好配色(蓝/黄/品红，并以粗体和下划线区分)
+	ColorThemeDeuteranopia ColorTheme = "deuteranopia"
+)
+
+// ParseColorTheme 解析配色方案名称
+func ParseColorTheme(name string) (ColorTheme, error) {
+	switch ColorTheme(strings.ToLower(name)) {
+	case ColorThemeDefault:
+		return ColorThemeDefault, nil
+	case ColorThemeDeuteranopia:
+		return ColorThemeDeuteranopia, nil
+	default:
+		return "", fmt.Errorf("不支持的配色方案: %s (可选 default, deuteranopia)", name)
+	}
+}
+
+// SectionOrder 定义磁盘分组(类型或存储池)的显示顺序
+type SectionOrder string
+
+const (
+	// SectionOrderDefault 固定顺序(类型按DiskTypeGroups，存储池按名称)
+	SectionOrderDefault SectionOrder = "default"
+	// SectionOrderSeverity 按分组内最严重的磁盘状态排序，有问题的分组排在前面
+	SectionOrderSeverity SectionOrder = "severity"
+)
+
+// ParseSectionOrder 解析分组排序方式名称
+func ParseSectionOrder(name string) (SectionOrder, error) {
+	switch SectionOrder(strings.ToLower(name)) {
+	case SectionOrderDefault:
+		return SectionOrderDefault, nil
+	case SectionOrderSeverity:
+		return SectionOrderSeverity, nil
+	default:
+		return "", fmt.Errorf("不支持的分组排序方式: %s (可选 default, severity)", name)
+	}
+}
+
 // Config 应用配置
 type Config struct {
 	// 日志设置
@@ -30,21 +105,71 @@ type Config struct {
 	LogDir  string // 日志目录(由LogFile生成)
 
 	// 显示设置
-	NoGroup        bool // 不按类型分组显示
-	NoController   bool // 不显示控制器信息
-	ControllerOnly bool // 只显示控制器信息
+	GroupBy        GroupBy      // 磁盘分组方式(type, pool, none)
+	SortSections   SectionOrder // 磁盘分组的显示顺序(default, severity)
+	NoGroup        bool         // 不按类型分组显示(已由GroupBy取代，等同于GroupBy=none)
+	NoController   bool         // 不显示控制器信息
+	ControllerOnly bool         // 只显示控制器信息
+	ShowSerial     bool         // 显示序列号和WWN
+	ShowFeatures   bool         // 显示SSD特性(TRIM支持)
+	ShowSensors    bool         // 显示NVMe各温度传感器的读数
+	ShowSectors    bool         // 显示磁盘扇区格式(512n/512e/4Kn)
+	ShowFirmware   bool         // 显示磁盘固件版本
+	ShowLinkSpeed  bool         // 显示SAS/SATA链路速率
+	ShowPower      bool         // 收集并显示SATA磁盘的APM级别和DSN状态
+	HTMLRawSmart   bool         // HTML报告附带每个磁盘完整的SMART数据
+	SystemInfo     bool         // 在报告头部显示主机名、系统版本、运行时间和工具版本
+	Legend         bool         // 在报告末尾显示列、单位和状态颜色的图例
+	MergeSSD       bool         // 将SAS和NVMe固态硬盘合并为一个分组显示
+	Redact         bool         // 用化名替换序列号、WWN和SAS地址，便于分享报告
+	RedactPools    bool         // 脱敏时同时替换存储池名称
+
+	// 文本输出的状态配色方案
+	ColorTheme ColorTheme
+
+	// 按磁盘类型自定义的表格列(未指定的类型使用默认列)
+	DiskColumns map[DiskType][]DiskColumn
 
 	// 输出设置
-	OutputFile   string       // 输出文件路径
-	OutputFormat OutputFormat // 输出格式(pdf, text, json)
+	OutputFile      string       // 输出文件路径
+	OutputFormat    OutputFormat // 输出格式(pdf, text, json, html, template, health)
+	TemplateFile    string       // template格式使用的Go模板文件
+	SizeUnits       SizeUnits    // 容量单位制(binary, decimal)
+	IDFormat        IDFormat     // 磁盘标识方式(name, by-id)
+	UnassignedLabel string       // 未分配存储池的磁盘显示的池名称，可以为空
+	SizePrecision   int          // 容量显示保留的小数位数(0-6)
 
 	// 数据文件
 	DataFile string // 历史数据文件路径
 	DataDir  string // 数据目录(由DataFile生成)
 
 	// 执行设置
-	CommandTimeout time.Duration // 命令执行超时时间
-	OutputEncoding string        // 输出文件编码
+	CommandTimeout       time.Duration // 命令执行超时时间
+	OutputEncoding       string        // 输出文件编码
+	MaxDisks             int           // 最多处理的磁盘数量(0表示不限制)
+	IncludeDisks         []string      // 只收集列出的磁盘(为空表示收集全部)
+	IncludeBoot          bool          // 补充收集boot-pool中的启动盘(disk.query有时不返回启动盘)
+	FailFast             bool          // 第一个磁盘的SMART数据收集失败时立即停止(用于调试)
+	NVMeExtended         bool          // 使用nvme-cli收集NVMe耐久组数据
+	UseTrueNASThresholds bool          // 使用TrueNAS中配置的磁盘温度告警阈值(midclt)
+	EnableSMART          bool          // 对SMART已禁用的磁盘执行smartctl -s on
+	PoolWarnPct          int           // 存储池容量告警阈值(%)，超过时标记为警告
+	FlapRuns             int           // 状态连续相同多少次运行才视为稳定并告警(0表示不启用抖动检测)
+	SMARTDetail          SMARTDetail   // SMART详情收集方式(full: smartctl -a, minimal: smartctl -A)
+	WearHorizonDays      int           // 按寿命消耗速率预计在多少天内用完时告警(0表示不检查)
+	AgeInfoYears         int           // 通电时间超过多少年时提示规划更换(0表示不检查)
+	NoIncrement          bool          // 不读取历史数据，不计算读写增量(本次数据仍会保存)
+
+	// 控制器设置
+	ControllerCritTemp int    // 控制器过热阈值(°C)，超过时标记为警告
+	NoControllerTemp   bool   // 跳过控制器温度采集(storcli/hwmon温度命令在部分系统上会挂起)
+	ControllerID       string // 只收集指定的控制器(storcli控制器编号或PCI地址)，为空时收集全部
+
+	// 状态规则
+	StatusRules []StatusRule // 自定义状态升级规则(按顺序评估，取最严重的结果)
+
+	// 已知问题确认(序列号 -> 确认记录)
+	Acknowledgements map[string]Acknowledgement
 }
 
 // NewDefaultConfig 创建默认配置
@@ -66,19 +191,39 @@ func NewDefaultConfig() *Config {
 	defaultDataFile := filepath.Join(defaultLogDir, "disk_health_monitor_data.json")
 
 	return &Config{
-		Debug:          false,
-		Verbose:        false,
-		LogFile:        defaultLogFile,
-		LogDir:         defaultLogDir,
-		NoGroup:        false,
-		NoController:   false,
-		ControllerOnly: true,
-		OutputFile:     "",
-		OutputFormat:   OutputFormatText,
-		DataFile:       defaultDataFile,
-		DataDir:        defaultLogDir,
-		CommandTimeout: 30 * time.Second,
-		OutputEncoding: "utf8",
+		Debug:           false,
+		Verbose:         false,
+		LogFile:         defaultLogFile,
+		LogDir:          defaultLogDir,
+		GroupBy:         GroupByType,
+		SortSections:    SectionOrderDefault,
+		SMARTDetail:     SMARTDetailFull,
+		WearHorizonDays: DefaultWearHorizonDays,
+		AgeInfoYears:    DefaultAgeInfoYears,
+		NoGroup:         false,
+		NoController:    false,
+		ControllerOnly:  true,
+		ShowSerial:      false,
+		ShowFeatures:    false,
+		ShowSensors:     false,
+		ShowSectors:     false,
+		ShowFirmware:    false,
+		ShowLinkSpeed:   false,
+		HTMLRawSmart:    false,
+		ColorTheme:      ColorThemeDefault,
+		OutputFile:      "",
+		OutputFormat:    OutputFormatText,
+		SizeUnits:       SizeUnitsBinary,
+		IDFormat:        IDFormatName,
+		UnassignedLabel: PoolUnassigned,
+		SizePrecision:   DefaultSizePrecision,
+		DataFile:        defaultDataFile,
+		DataDir:         defaultLogDir,
+		CommandTimeout:  30 * time.Second,
+		OutputEncoding:  "utf8",
+		PoolWarnPct:     DefaultPoolWarnPct,
+
+		ControllerCritTemp: DefaultControllerCritTemp,
 	}
 }
 
@@ -93,12 +238,94 @@ func (c *Config) Validate() error {
 
 	// 验证输出格式
 	switch c.OutputFormat {
-	case OutputFormatPDF, OutputFormatText, OutputFormatJSON, OutputFormatHTML:
+	case OutputFormatPDF, OutputFormatText, OutputFormatJSON, OutputFormatHTML, OutputFormatHealth:
 		// 有效的格式
+	case OutputFormatTemplate:
+		if c.TemplateFile == "" {
+			return fmt.Errorf("template输出格式需要通过 --template-file 指定模板文件")
+		}
+		if _, err := os.Stat(c.TemplateFile); err != nil {
+			return fmt.Errorf("无法读取模板文件: %w", err)
+		}
 	default:
 		return fmt.Errorf("不支持的输出格式: %s", c.OutputFormat)
 	}
 
+	// 验证容量单位制，未设置时使用二进制
+	if c.SizeUnits == "" {
+		c.SizeUnits = SizeUnitsBinary
+	}
+	if _, err := ParseSizeUnits(string(c.SizeUnits)); err != nil {
+		return err
+	}
+
+	// 验证磁盘标识方式，未设置时使用内核设备名
+	if c.IDFormat == "" {
+		c.IDFormat = IDFormatName
+	}
+	if _, err := ParseIDFormat(string(c.IDFormat)); err != nil {
+		return err
+	}
+
+	// 验证容量小数位数
+	if c.SizePrecision < 0 || c.SizePrecision > MaxSizePrecision {
+		return fmt.Errorf("容量小数位数必须在0到%d之间: %d", MaxSizePrecision, c.SizePrecision)
+	}
+
+	// 验证分组方式，NoGroup作为none的别名
+	if c.NoGroup {
+		c.GroupBy = GroupByNone
+	}
+	if c.GroupBy == "" {
+		c.GroupBy = GroupByType
+	}
+	if _, err := ParseGroupBy(string(c.GroupBy)); err != nil {
+		return err
+	}
+
+	// 验证磁盘数量上限
+	if c.MaxDisks < 0 {
+		return fmt.Errorf("磁盘数量上限不能为负数: %d", c.MaxDisks)
+	}
+
+	// 验证存储池容量告警阈值，未设置时使用默认值
+	if c.PoolWarnPct < 0 || c.PoolWarnPct > 100 {
+		return fmt.Errorf("存储池容量告警阈值必须在0到100之间: %d", c.PoolWarnPct)
+	}
+	if c.PoolWarnPct == 0 {
+		c.PoolWarnPct = DefaultPoolWarnPct
+	}
+
+	// 验证抖动检测的运行次数
+	if c.FlapRuns < 0 {
+		return fmt.Errorf("抖动检测的运行次数不能为负数: %d", c.FlapRuns)
+	}
+
+	// 验证寿命耗尽预警期限
+	if c.WearHorizonDays < 0 {
+		return fmt.Errorf("寿命耗尽预警期限不能为负数: %d", c.WearHorizonDays)
+	}
+
+	// 验证使用年限提示阈值
+	if c.AgeInfoYears < 0 {
+		return fmt.Errorf("使用年限提示阈值不能为负数: %d", c.AgeInfoYears)
+	}
+
+	// 验证控制器过热阈值，未设置时使用默认值
+	if c.ControllerCritTemp < 0 {
+		return fmt.Errorf("控制器过热阈值不能为负数: %d", c.ControllerCritTemp)
+	}
+	if c.ControllerCritTemp == 0 {
+		c.ControllerCritTemp = DefaultControllerCritTemp
+	}
+
+	// 验证状态升级规则
+	for _, rule := range c.StatusRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// 验证输出编码
 	switch c.OutputEncoding {
 	case "utf8", "gbk":
@@ -122,6 +349,17 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// GetGroupBy 获取生效的分组方式，NoGroup优先于GroupBy
+func (c *Config) GetGroupBy() GroupBy {
+	if c.NoGroup {
+		return GroupByNone
+	}
+	if c.GroupBy == "" {
+		return GroupByType
+	}
+	return c.GroupBy
+}
+
 // SetupOutputFile 如果未指定输出文件，根据格式设置一个默认值
 func (c *Config) SetupOutputFile() {
 	// 如果已经指定了输出文件，不做任何操作
@@ -140,5 +378,12 @@ func (c *Config) SetupOutputFile() {
 		c.OutputFile = fmt.Sprintf("disk_health_%s.json", timeStr)
 	case OutputFormatHTML:
 		c.OutputFile = fmt.Sprintf("disk_health_%s.html", timeStr)
+	case OutputFormatTemplate:
+		// report.csv.tmpl 生成 .csv 文件，无法判断时使用 .txt
+		ext := filepath.Ext(strings.TrimSuffix(c.TemplateFile, ".tmpl"))
+		if ext == "" {
+			ext = ".txt"
+		}
+		c.OutputFile = fmt.Sprintf("disk_health_%s%s", timeStr, ext)
 	}
 }
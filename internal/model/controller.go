@@ -1,5 +1,12 @@
 package model
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // ControllerType 定义控制器类型
 type ControllerType string
 
@@ -39,6 +46,8 @@ type Controller struct {
 	Status         ControllerStatus // 状态
 	Description    string          // 描述信息
 	Source         string          // 信息来源
+	PreviousTemperature string     // 上次运行记录的温度
+	StatusReason   string          // 状态原因(如过热)
 }
 
 // NewController 创建一个新的控制器对象
@@ -55,12 +64,102 @@ func (c *Controller) SetStatus(status ControllerStatus) {
 	c.Status = status
 }
 
-// GetDisplayTemperature 获取可显示的温度值
+// normalizeTemperature 去除温度值中的空白和单位后缀，如 " 58°C" -> "58"
+// 空值或 "N/A" 返回空字符串
+func normalizeTemperature(temp string) string {
+	temp = strings.TrimSpace(temp)
+	temp = strings.TrimSuffix(temp, "C")
+	temp = strings.TrimSuffix(temp, "°")
+	temp = strings.TrimSpace(temp)
+	if strings.EqualFold(temp, "N/A") {
+		return ""
+	}
+	return temp
+}
+
+// GetDisplayTemperature 获取可显示的温度值，统一为 "58°C" 格式，不可用时显示 "N/A"
 func (c *Controller) GetDisplayTemperature() string {
-	if c.Temperature == "" {
+	temp := normalizeTemperature(c.Temperature)
+	if temp == "" {
 		return "N/A"
 	}
-	return c.Temperature + "°C"
+	return temp + "°C"
+}
+
+// GetTemperatureValue 获取数值形式的温度(°C)，不可用时返回false
+func (c *Controller) GetTemperatureValue() (int, bool) {
+	return parseTemperature(c.Temperature)
+}
+
+// GetPreviousTemperatureValue 获取上次运行时数值形式的温度(°C)，不可用时返回false
+func (c *Controller) GetPreviousTemperatureValue() (int, bool) {
+	return parseTemperature(c.PreviousTemperature)
+}
+
+// parseTemperature 将带或不带单位的温度字符串解析为整数
+func parseTemperature(temp string) (int, bool) {
+	value, err := strconv.Atoi(normalizeTemperature(temp))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// DefaultControllerCritTemp 控制器默认的过热阈值(°C)
+const DefaultControllerCritTemp = 70
+
+// ControllerTempRiseThreshold 两次运行之间温度上升达到该值(°C)即视为骤升
+const ControllerTempRiseThreshold = 10
+
+// GetTemperatureChange 获取与上次运行相比的温度变化，无法比较时返回false
+func (c *Controller) GetTemperatureChange() (int, bool) {
+	current, err := strconv.Atoi(normalizeTemperature(c.Temperature))
+	if err != nil {
+		return 0, false
+	}
+	previous, err := strconv.Atoi(normalizeTemperature(c.PreviousTemperature))
+	if err != nil {
+		return 0, false
+	}
+	return current - previous, true
+}
+
+// GetDisplayTemperatureTrend 获取带趋势的温度显示值，如 "75°C (↑8)"
+func (c *Controller) GetDisplayTemperatureTrend() string {
+	display := c.GetDisplayTemperature()
+	change, ok := c.GetTemperatureChange()
+	switch {
+	case !ok || change == 0:
+		return display
+	case change > 0:
+		return fmt.Sprintf("%s (↑%d)", display, change)
+	default:
+		return fmt.Sprintf("%s (↓%d)", display, -change)
+	}
+}
+
+// EvaluateTemperature 检查温度是否超过阈值或骤升，必要时将状态升级为警告
+// 已经是错误状态的控制器不会被降级
+func (c *Controller) EvaluateTemperature(critTemp int) {
+	if critTemp <= 0 {
+		critTemp = DefaultControllerCritTemp
+	}
+
+	var reasons []string
+	if temp, err := strconv.Atoi(normalizeTemperature(c.Temperature)); err == nil && temp > critTemp {
+		reasons = append(reasons, fmt.Sprintf("温度 %d°C 超过阈值 %d°C", temp, critTemp))
+	}
+	if change, ok := c.GetTemperatureChange(); ok && change >= ControllerTempRiseThreshold {
+		reasons = append(reasons, fmt.Sprintf("温度较上次上升 %d°C", change))
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	c.StatusReason = strings.Join(reasons, "; ")
+	if c.Status != ControllerStatusError {
+		c.Status = ControllerStatusWarning
+	}
 }
 
 // LSIController LSI SAS控制器
@@ -68,6 +167,45 @@ type LSIController struct {
 	Controller
 	ROCTemperature string   // ROC温度
 	ProductName    string   // 产品名称
+	FWPackageBuild string   // 固件包版本(FW Package Build)
+	BIOSVersion    string   // BIOS版本
+	NVDATAVersion  string   // NVDATA版本
+	PhysicalDrives []PhysicalDrive // storcli PD LIST中的物理磁盘
+}
+
+// PhysicalDrive storcli PD LIST中的一行物理磁盘信息
+type PhysicalDrive struct {
+	Slot       string // 位置(EID:Slt)
+	Size       string // 容量
+	Interface  string // 接口(SAS, SATA)
+	Media      string // 介质(SSD, HDD)
+	SectorSize int    // 扇区大小(字节，SeSz列)
+	Model      string // 型号
+}
+
+// GetDisplaySectorSizes 获取控制器下物理磁盘扇区大小的统计，如 "512B×3, 4096B×2"
+func (c *LSIController) GetDisplaySectorSizes() string {
+	counts := make(map[int]int)
+	for _, drive := range c.PhysicalDrives {
+		if drive.SectorSize > 0 {
+			counts[drive.SectorSize]++
+		}
+	}
+	if len(counts) == 0 {
+		return "N/A"
+	}
+
+	sizes := make([]int, 0, len(counts))
+	for size := range counts {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+
+	parts := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		parts = append(parts, fmt.Sprintf("%dB×%d", size, counts[size]))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // NewLSIController 创建一个新的LSI控制器
@@ -148,3 +286,85 @@ func (cd *ControllerData) GetNVMeControllerCount() int {
 func (cd *ControllerData) GetTotalControllerCount() int {
 	return cd.GetLSIControllerCount() + cd.GetNVMeControllerCount()
 }
+
+// GetSortedLSIControllerIDs 获取按ID排序的LSI控制器ID列表
+func (cd *ControllerData) GetSortedLSIControllerIDs() []string {
+	ids := make([]string, 0, len(cd.LSIControllers))
+	for id := range cd.LSIControllers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetSortedNVMeControllerIDs 获取按ID排序的NVMe控制器ID列表
+func (cd *ControllerData) GetSortedNVMeControllerIDs() []string {
+	ids := make([]string, 0, len(cd.NVMeControllers))
+	for id := range cd.NVMeControllers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetSortedLSIControllers 获取按ID排序的LSI控制器列表
+func (cd *ControllerData) GetSortedLSIControllers() []*LSIController {
+	controllers := make([]*LSIController, 0, len(cd.LSIControllers))
+	for _, id := range cd.GetSortedLSIControllerIDs() {
+		controllers = append(controllers, cd.LSIControllers[id])
+	}
+	return controllers
+}
+
+// GetSortedNVMeControllers 获取按ID排序的NVMe控制器列表
+func (cd *ControllerData) GetSortedNVMeControllers() []*NVMeController {
+	controllers := make([]*NVMeController, 0, len(cd.NVMeControllers))
+	for _, id := range cd.GetSortedNVMeControllerIDs() {
+		controllers = append(controllers, cd.NVMeControllers[id])
+	}
+	return controllers
+}
+
+// GetTemperatures 获取所有控制器的当前温度(按控制器ID)，用于保存历史
+func (cd *ControllerData) GetTemperatures() map[string]string {
+	temps := make(map[string]string)
+	for id, controller := range cd.LSIControllers {
+		if controller.Temperature != "" {
+			temps[id] = controller.Temperature
+		}
+	}
+	for id, controller := range cd.NVMeControllers {
+		if controller.Temperature != "" {
+			temps[id] = controller.Temperature
+		}
+	}
+	return temps
+}
+
+// ApplyTemperatureHistory 记录上次运行的温度并检查每个控制器是否过热或温度骤升
+func (cd *ControllerData) ApplyTemperatureHistory(previous map[string]string, critTemp int) {
+	for id, controller := range cd.LSIControllers {
+		controller.PreviousTemperature = previous[id]
+		controller.EvaluateTemperature(critTemp)
+	}
+	for id, controller := range cd.NVMeControllers {
+		controller.PreviousTemperature = previous[id]
+		controller.EvaluateTemperature(critTemp)
+	}
+}
+
+// GetFlaggedControllers 获取有状态原因的控制器(按ID排序)
+func (cd *ControllerData) GetFlaggedControllers() []*Controller {
+	var flagged []*Controller
+	for _, controller := range cd.GetSortedLSIControllers() {
+		if controller.StatusReason != "" {
+			flagged = append(flagged, &controller.Controller)
+		}
+	}
+	for _, controller := range cd.GetSortedNVMeControllers() {
+		if controller.StatusReason != "" {
+			flagged = append(flagged, &controller.Controller)
+		}
+	}
+	return flagged
+}
@@ -45,6 +45,29 @@ func TestController_GetDisplayTemperature(t *testing.T) {
 	if controller.GetDisplayTemperature() != "45°C" {
 		t.Errorf("Expected '45°C', got '%s'", controller.GetDisplayTemperature())
 	}
+
+	// 已带单位、带空白或不可用的温度值统一格式
+	tests := map[string]string{
+		"58°C":  "58°C",
+		"58 °C": "58°C",
+		" 61 ":  "61°C",
+		"52C":   "52°C",
+		"N/A":   "N/A",
+		"  ":    "N/A",
+	}
+	for input, want := range tests {
+		controller.Temperature = input
+		if got := controller.GetDisplayTemperature(); got != want {
+			t.Errorf("GetDisplayTemperature(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	// 带单位的温度也能参与趋势计算
+	controller.Temperature = "60°C"
+	controller.PreviousTemperature = "55"
+	if got := controller.GetDisplayTemperatureTrend(); got != "60°C (↑5)" {
+		t.Errorf("GetDisplayTemperatureTrend() = %q, want %q", got, "60°C (↑5)")
+	}
 }
 
 func TestNewLSIController(t *testing.T) {
@@ -130,3 +153,63 @@ func TestControllerData(t *testing.T) {
 		t.Error("Controller counts should not change when getting existing controllers")
 	}
 }
+
+func TestControllerTemperatureEvaluation(t *testing.T) {
+	data := NewControllerData()
+
+	// 75°C 超过默认阈值 70°C
+	hot := data.GetLSIController("LSI_Controller_0")
+	hot.Status = ControllerStatusOK
+	hot.Temperature = "75"
+
+	// 低于阈值但较上次上升 12°C
+	rising := data.GetLSIController("LSI_Controller_1")
+	rising.Status = ControllerStatusOK
+	rising.Temperature = "62"
+
+	// 温度稳定
+	stable := data.GetNVMeController("NVMe_Controller_01:00.0")
+	stable.Status = ControllerStatusOK
+	stable.Temperature = "45"
+
+	previous := map[string]string{
+		"LSI_Controller_0":        "74",
+		"LSI_Controller_1":        "50",
+		"NVMe_Controller_01:00.0": "47",
+	}
+	data.ApplyTemperatureHistory(previous, DefaultControllerCritTemp)
+
+	if hot.Status != ControllerStatusWarning {
+		t.Errorf("Expected controller at 75°C to be %s, got %s", ControllerStatusWarning, hot.Status)
+	}
+	if hot.StatusReason != "温度 75°C 超过阈值 70°C" {
+		t.Errorf("Unexpected reason for hot controller: %q", hot.StatusReason)
+	}
+	if rising.Status != ControllerStatusWarning || rising.StatusReason != "温度较上次上升 12°C" {
+		t.Errorf("Expected rising controller to be flagged, got %s (%q)", rising.Status, rising.StatusReason)
+	}
+	if stable.Status != ControllerStatusOK || stable.StatusReason != "" {
+		t.Errorf("Expected stable controller to stay OK, got %s (%q)", stable.Status, stable.StatusReason)
+	}
+
+	if got := hot.GetDisplayTemperatureTrend(); got != "75°C (↑1)" {
+		t.Errorf("GetDisplayTemperatureTrend() = %q, want %q", got, "75°C (↑1)")
+	}
+	if got := stable.GetDisplayTemperatureTrend(); got != "45°C (↓2)" {
+		t.Errorf("GetDisplayTemperatureTrend() = %q, want %q", got, "45°C (↓2)")
+	}
+
+	flagged := data.GetFlaggedControllers()
+	if len(flagged) != 2 || flagged[0].ID != "LSI_Controller_0" || flagged[1].ID != "LSI_Controller_1" {
+		t.Errorf("Unexpected flagged controllers: %v", flagged)
+	}
+
+	// 更低的阈值下错误状态不会被降级
+	broken := NewLSIController("LSI_Controller_2")
+	broken.Status = ControllerStatusError
+	broken.Temperature = "80"
+	broken.EvaluateTemperature(DefaultControllerCritTemp)
+	if broken.Status != ControllerStatusError {
+		t.Errorf("Expected error status to be kept, got %s", broken.Status)
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -34,6 +35,37 @@ const (
 	DiskStatusUnknown DiskStatus = "UNKNOWN"
 )
 
+// TRIM支持状态(保存在SMARTData["Trim_Support"]中)
+const (
+	// TrimAvailable 支持TRIM
+	TrimAvailable = "Available"
+	// TrimUnavailable 不支持TRIM
+	TrimUnavailable = "Unavailable"
+)
+
+// 虚拟设备没有真正的SMART数据，只保存Type和Smart_Status两个属性
+const (
+	// SmartStatusVirtual 虚拟设备的SMART状态
+	SmartStatusVirtual = "虚拟设备"
+	// NotApplicable 虚拟设备不适用的属性显示值(区别于数据缺失时的"N/A")
+	NotApplicable = "-"
+)
+
+// 数据来源，记录磁盘列表和存储池信息是由哪个命令获取的，便于排查异常结果
+const (
+	// DataSourceMidclt TrueNAS中间件(midclt call disk.query / pool.query)
+	DataSourceMidclt = "midclt"
+	// DataSourceLsblk lsblk命令(磁盘列表的备用来源)
+	DataSourceLsblk = "lsblk"
+	// DataSourceZpool zpool status命令(存储池信息的备用来源)
+	DataSourceZpool = "zpool"
+	// DataSourceBootPool zpool status boot-pool命令(--include-boot补充的启动盘)
+	DataSourceBootPool = "boot-pool"
+)
+
+// BootPoolName TrueNAS启动盘所在的存储池名称
+const BootPoolName = "boot-pool"
+
 // SMARTData SMART数据
 type SMARTData map[string]string
 
@@ -50,10 +82,25 @@ type Disk struct {
 	Type          DiskType     // 设备类型
 	RawType       string       // 原始类型字符串
 	Model         string       // 设备型号
+	Serial        string       // 序列号
+	WWN           string       // 全球唯一标识(WWN/Logical Unit id)
+	ByID          string       // /dev/disk/by-id下的稳定名称，未知时为空
+	FirmwareVersion string     // 磁盘固件版本(与控制器固件无关)
 	Size          string       // 设备容量
 	Pool          string       // 所属存储池
 	SMARTData     SMARTData    // SMART数据
 	Status        DiskStatus   // 磁盘状态
+	StatusReason  string       // 状态原因(区分磁盘自检报告与启发式判断)
+	AgeInfo       string       // 通电时间超过--age-info-years时的更换提示(不影响状态)
+	Acknowledged  string       // 确认原因(已确认的已知问题不再触发告警)
+	AlertStatus   DiskStatus   // 用于告警的稳定状态(--flap-runs)，为空时使用Status
+	StatusHistory []string     // 最近几次运行的状态(从旧到新，含本次)，用于抖动检测
+	Source        string       // 磁盘列表来源(midclt, lsblk)
+	PoolSource    string       // 存储池信息来源(midclt, zpool)，未分配时为空
+	PreviousPool  string       // 上次运行时所属的存储池，仅在存储池发生变化时设置
+	Boot          bool         // 是否为启动盘(boot-pool成员)
+	LogicalSectorSize  int     // 逻辑扇区大小(字节)，未知时为0
+	PhysicalSectorSize int     // 物理扇区大小(字节)，未知时为0
 	ReadIncrement string       // 读增量
 	WriteIncrement string      // 写增量
 }
@@ -65,7 +112,7 @@ func NewDisk(name, rawType, model, size string) *Disk {
 		RawType:   rawType,
 		Model:     model,
 		Size:      size,
-		Pool:      "未分配",
+		Pool:      PoolUnassigned,
 		SMARTData: make(SMARTData),
 		Status:    DiskStatusUnknown,
 	}
@@ -97,39 +144,171 @@ func ClassifyDiskType(diskName, diskType, diskModel string) DiskType {
 	return DiskTypeSASSSD
 }
 
+// 状态原因前缀
+const (
+	// StatusSourceSelfReported 磁盘自检报告的状态
+	StatusSourceSelfReported = "磁盘自检"
+	// StatusSourceHeuristic 本工具根据SMART属性推断的状态
+	StatusSourceHeuristic = "启发式"
+	// StatusSourceRule 自定义状态规则
+	StatusSourceRule = "规则"
+)
+
 // GetStatus 根据SMART数据推断磁盘状态
 func (d *Disk) GetStatus() DiskStatus {
 	// 如果已经设置了状态，直接返回
 	if d.Status != DiskStatusUnknown {
 		return d.Status
 	}
-	
-	// 从SMART数据获取状态
+
+	status, _ := d.evaluateStatus()
+	return status
+}
+
+// evaluateStatus 综合磁盘自检状态和启发式判断，返回最严重的状态及其原因
+func (d *Disk) evaluateStatus() (DiskStatus, []string) {
+	status := DiskStatusUnknown
+	var reasons []string
+
+	// 从SMART数据获取磁盘自检报告的状态
 	if smartStatus, ok := d.SMARTData["Smart_Status"]; ok {
+		selfReported := DiskStatusUnknown
 		switch strings.ToUpper(smartStatus) {
 		case "PASSED", "OK":
-			return DiskStatusOK
+			selfReported = DiskStatusOK
 		case "WARNING", "警告":
-			return DiskStatusWarning
+			selfReported = DiskStatusWarning
 		case "FAILED", "错误":
-			return DiskStatusError
+			selfReported = DiskStatusError
+		}
+		if selfReported != DiskStatusUnknown {
+			status = selfReported
+			reasons = append(reasons, fmt.Sprintf("%s: %s", StatusSourceSelfReported, smartStatus))
 		}
 	}
-	
-	// 其他状态判断逻辑，如检查未修正错误、温度等
+
+	// SMART被禁用时磁盘不报告健康状态，标记为警告以免被当作状态未知而忽略
+	if d.SMARTData[SmartSupportAttribute] == SmartSupportDisabled {
+		status = MoreSevere(status, DiskStatusWarning)
+		reasons = append(reasons, fmt.Sprintf("%s: SMART已禁用", StatusSourceSelfReported))
+	}
+
+	// 启发式判断，如检查未修正错误等
 	if errors, ok := d.SMARTData["Uncorrected_Errors"]; ok {
 		if errors != "0" && errors != "" {
-			return DiskStatusWarning
+			status = MoreSevere(status, DiskStatusWarning)
+			reasons = append(reasons, fmt.Sprintf("%s: 未修正错误 %s 个", StatusSourceHeuristic, errors))
 		}
 	}
-	
-	// 没有足够信息判断状态
-	return DiskStatusUnknown
+
+	// NVMe在超过警告/临界温度时会降频，非零时间说明散热存在问题
+	warningTime := d.SMARTData["Warning_Temp_Time"]
+	criticalTime := d.SMARTData["Critical_Temp_Time"]
+	if (warningTime != "" && warningTime != "0") || (criticalTime != "" && criticalTime != "0") {
+		status = MoreSevere(status, DiskStatusWarning)
+		reasons = append(reasons, fmt.Sprintf("%s: 温度过高导致降频 (警告温度时间 %s 分钟, 临界温度时间 %s 分钟)",
+			StatusSourceHeuristic, valueOrZero(warningTime), valueOrZero(criticalTime)))
+	}
+
+	// 多传感器NVMe按最热的传感器判断，显示的仍是综合温度
+	if tempStatus, reason := d.temperatureWarning(); reason != "" {
+		status = MoreSevere(status, tempStatus)
+		reasons = append(reasons, reason)
+	}
+
+	// 历史最高温度达到临界温度(当前温度可能已经正常)
+	if lifetimeStatus, reason := d.lifetimeTemperatureWarning(); reason != "" {
+		status = MoreSevere(status, lifetimeStatus)
+		reasons = append(reasons, reason)
+	}
+
+	// SAS/SATA链路以低于磁盘支持的速率运行
+	if reason := d.linkSpeedWarning(); reason != "" {
+		status = MoreSevere(status, DiskStatusWarning)
+		reasons = append(reasons, reason)
+	}
+
+	// smartctl通过非零退出码报告的问题(如自检日志中有错误记录)
+	if exitStatus, reason := d.smartctlExitWarning(); reason != "" {
+		status = MoreSevere(status, exitStatus)
+		reasons = append(reasons, reason)
+	}
+
+	// NVMe耐久组的严重警告(--nvme-extended)
+	if enduranceStatus, reason := d.enduranceWarning(); reason != "" {
+		status = MoreSevere(status, enduranceStatus)
+		reasons = append(reasons, reason)
+	}
+
+	return status, reasons
+}
+
+// valueOrZero 将空的计数值显示为0
+func valueOrZero(value string) string {
+	if value == "" {
+		return "0"
+	}
+	return value
 }
 
-// UpdateStatus 更新磁盘状态
+// UpdateStatus 更新磁盘状态及状态原因
 func (d *Disk) UpdateStatus() {
-	d.Status = d.GetStatus()
+	if d.Status != DiskStatusUnknown {
+		return
+	}
+
+	status, reasons := d.evaluateStatus()
+	d.Status = status
+	for _, reason := range reasons {
+		d.AddStatusReason(reason)
+	}
+}
+
+// Escalate 将磁盘状态升级为更严重的状态，并记录原因
+func (d *Disk) Escalate(status DiskStatus, reason string) {
+	d.Status = MoreSevere(d.GetStatus(), status)
+	d.AddStatusReason(reason)
+}
+
+// AddStatusReason 追加状态原因，忽略重复项
+func (d *Disk) AddStatusReason(reason string) {
+	if reason == "" {
+		return
+	}
+	for _, existing := range d.GetStatusReasons() {
+		if existing == reason {
+			return
+		}
+	}
+	if d.StatusReason == "" {
+		d.StatusReason = reason
+	} else {
+		d.StatusReason += "; " + reason
+	}
+}
+
+// GetStatusReasons 获取状态原因列表
+func (d *Disk) GetStatusReasons() []string {
+	if d.StatusReason == "" {
+		return nil
+	}
+	return strings.Split(d.StatusReason, "; ")
+}
+
+// IsVirtual 判断是否为虚拟设备(包括被识别为NVMe的VMware虚拟磁盘)
+func (d *Disk) IsVirtual() bool {
+	return d.Type == DiskTypeVirtual || d.SMARTData["Smart_Status"] == SmartStatusVirtual
+}
+
+// GetDisplayType 获取可显示的磁盘类型，虚拟设备显示具体的类型说明
+func (d *Disk) GetDisplayType() string {
+	if d.IsVirtual() {
+		if label := d.SMARTData["Type"]; label != "" {
+			return label
+		}
+		return SmartStatusVirtual
+	}
+	return string(d.Type)
 }
 
 // GetDisplayTemperature 获取可显示的温度
@@ -137,24 +316,56 @@ func (d *Disk) GetDisplayTemperature() string {
 	if temp, ok := d.SMARTData["Temperature"]; ok && temp != "" {
 		return temp + "°C"
 	}
+	if d.IsVirtual() {
+		return NotApplicable
+	}
 	return "N/A"
 }
 
+// GetTemperatureValue 获取数值形式的温度(°C)，不可用时返回false
+func (d *Disk) GetTemperatureValue() (int, bool) {
+	return parseTemperature(d.SMARTData["Temperature"])
+}
+
+// GetDisplayTrimSupport 获取可显示的TRIM支持状态
+func (d *Disk) GetDisplayTrimSupport() string {
+	switch d.SMARTData["Trim_Support"] {
+	case TrimAvailable:
+		return "支持"
+	case TrimUnavailable:
+		return "不支持"
+	default:
+		return "N/A"
+	}
+}
+
 // GetAttribute 获取特定属性的值
 func (d *Disk) GetAttribute(name string) string {
 	if value, ok := d.SMARTData[name]; ok && value != "" {
 		return value
 	}
+	if d.IsVirtual() {
+		return NotApplicable
+	}
 	return "N/A"
 }
 
 // DiskData 磁盘数据集合
 type DiskData struct {
 	Disks         []*Disk                   // 磁盘列表
+	PoolDisks     []*Disk                   // 汇总存储池时使用的磁盘，为空时使用Disks(过滤磁盘列表后保留全部磁盘)
 	GroupedDisks  map[DiskType][]*Disk      // 按类型分组的磁盘
 	PreviousData  map[string]map[string]string // 上次运行的数据
 	PreviousTime  string                    // 上次运行的时间
 	CollectedTime time.Time                 // 收集数据的时间
+	PoolStatus    map[string]string         // 存储池状态(池名称 -> 状态)
+	PoolUsage     map[string]float64        // 存储池已用容量百分比(池名称 -> 0-100)
+	PoolLayout    map[string][]PoolVdev     // 存储池的数据vdev布局(池名称 -> vdev列表)
+	PoolWarnPct   int                       // 存储池容量告警阈值(%)，0表示使用默认值
+	AgeInfoYears  int                       // 使用年限提示阈值(年)，0表示不检查
+	TruncatedFrom int                       // 截断前的磁盘总数(0表示未截断)
+	IncludeFiltered bool                    // 是否只收集了包含列表(--include-file)中的磁盘
+	Events        []DiskEvent               // 最近的磁盘事件(--events)，按时间先后排列
 }
 
 // NewDiskData 创建一个新的磁盘数据集合
@@ -164,6 +375,9 @@ func NewDiskData() *DiskData {
 		GroupedDisks: make(map[DiskType][]*Disk),
 		PreviousData: make(map[string]map[string]string),
 		CollectedTime: time.Now(),
+		PoolStatus:   make(map[string]string),
+		PoolUsage:    make(map[string]float64),
+		PoolLayout:   make(map[string][]PoolVdev),
 	}
 }
 
@@ -193,6 +407,31 @@ func (dd *DiskData) SortDisks() {
 	}
 }
 
+// FilterDisks 返回只包含满足条件的磁盘的副本
+// 存储池、历史数据和事件等其他字段保持不变，存储池仍按全部磁盘汇总，容量告警仍计入告警数量
+func (dd *DiskData) FilterDisks(keep func(*Disk) bool) *DiskData {
+	filtered := *dd
+	filtered.PoolDisks = dd.poolDisks()
+	filtered.Disks = make([]*Disk, 0)
+	filtered.GroupedDisks = make(map[DiskType][]*Disk)
+	for _, disk := range dd.Disks {
+		if keep(disk) {
+			filtered.AddDisk(disk)
+		}
+	}
+	return &filtered
+}
+
+// IsTruncated 检查磁盘列表是否因数量上限被截断
+func (dd *DiskData) IsTruncated() bool {
+	return dd.TruncatedFrom > len(dd.Disks)
+}
+
+// IsPartial 检查本次是否只收集了部分磁盘(包含列表或数量上限)，未收集的磁盘不一定已移除
+func (dd *DiskData) IsPartial() bool {
+	return dd.IncludeFiltered || dd.TruncatedFrom > 0
+}
+
 // GetDiskCount 获取磁盘总数
 func (dd *DiskData) GetDiskCount() int {
 	return len(dd.Disks)
@@ -272,6 +511,8 @@ func (dd *DiskData) GetDiskAttributes(diskType DiskType) []DiskAttribute {
 			{Name: "Temperature", DisplayName: "温度", Unit: "°C"},
 			{Name: "Warning_Temperature", DisplayName: "警告温度", Unit: "°C"},
 			{Name: "Critical_Temperature", DisplayName: "临界温度", Unit: "°C"},
+			{Name: "Warning_Temp_Time", DisplayName: "警告温度时间", Unit: "分钟"},
+			{Name: "Critical_Temp_Time", DisplayName: "临界温度时间", Unit: "分钟"},
 			{Name: "Power_On_Hours", DisplayName: "通电时间", Unit: "小时"},
 			{Name: "Power_Cycles", DisplayName: "通电周期", Unit: "次"},
 			{Name: "Percentage_Used", DisplayName: "已用寿命", Unit: "%"},
@@ -201,8 +201,8 @@ func TestDiskData(t *testing.T) {
 	}
 	
 	nvmessdAttrs := dd.GetDiskAttributes(DiskTypeNVMESSD)
-	if len(nvmessdAttrs) != 10 {
-		t.Errorf("Expected 10 NVMe SSD attributes, got %d", len(nvmessdAttrs))
+	if len(nvmessdAttrs) != 12 {
+		t.Errorf("Expected 12 NVMe SSD attributes, got %d", len(nvmessdAttrs))
 	}
 	
 	// 测试历史数据
@@ -231,3 +231,35 @@ func TestDiskData(t *testing.T) {
 		t.Errorf("Invalid collection time format: %s", collectionTime)
 	}
 }
+
+func TestDisk_StatusReason(t *testing.T) {
+	// 磁盘自检报告PASSED，但存在未修正错误
+	disk := NewDisk("sdc", "HDD", "SEAGATE ST600MM0006", "600G")
+	disk.SMARTData["Smart_Status"] = "PASSED"
+	disk.SMARTData["Uncorrected_Errors"] = "3"
+	disk.UpdateStatus()
+
+	if disk.Status != DiskStatusWarning {
+		t.Errorf("Expected warning status, got '%s'", disk.Status)
+	}
+
+	reasons := disk.GetStatusReasons()
+	expected := []string{"磁盘自检: PASSED", "启发式: 未修正错误 3 个"}
+	if len(reasons) != len(expected) {
+		t.Fatalf("Expected reasons %v, got %v", expected, reasons)
+	}
+	for i := range expected {
+		if reasons[i] != expected[i] {
+			t.Errorf("Reason %d: expected '%s', got '%s'", i, expected[i], reasons[i])
+		}
+	}
+
+	// 升级状态时保留已有原因，且不会降级
+	disk.Escalate(DiskStatusOK, "规则: Temperature < 60")
+	if disk.Status != DiskStatusWarning {
+		t.Errorf("Escalate should not downgrade status, got '%s'", disk.Status)
+	}
+	if len(disk.GetStatusReasons()) != 3 {
+		t.Errorf("Expected 3 reasons, got %v", disk.GetStatusReasons())
+	}
+}
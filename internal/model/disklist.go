@@ -0,0 +1,42 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadDiskList 从文本文件加载磁盘名称列表，每行一个
+// 忽略空行和以 # 开头的注释，允许写成 /dev/sda 形式
+func LoadDiskList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取磁盘列表文件失败: %w", err)
+	}
+	defer file.Close()
+
+	var names []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name := strings.TrimPrefix(line, "/dev/")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取磁盘列表文件失败: %w", err)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("磁盘列表文件为空: %s", path)
+	}
+	return names, nil
+}
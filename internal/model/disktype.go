@@ -0,0 +1,117 @@
+package model
+
+import "sort"
+
+// DiskTypeGroup 按类型分组显示时的一个分组
+type DiskTypeGroup struct {
+	Type  DiskType // 磁盘类型
+	Title string   // 分组标题
+}
+
+// DiskTypeGroups 按类型分组时各分组的显示顺序和标题，所有输出格式共用
+// 新增磁盘类型时在这里加入一项即可在各格式中获得确定的位置
+var DiskTypeGroups = []DiskTypeGroup{
+	{Type: DiskTypeSASSSD, Title: "SAS/SATA 固态硬盘"},
+	{Type: DiskTypeSASHDD, Title: "SAS/SATA 机械硬盘"},
+	{Type: DiskTypeNVMESSD, Title: "NVMe 固态硬盘"},
+	{Type: DiskTypeVirtual, Title: "虚拟设备"},
+}
+
+// OtherDiskTypeTitle 不在DiskTypeGroups中的磁盘类型使用的分组标题
+const OtherDiskTypeTitle = "其他设备"
+
+// DiskTypeSSD 合并显示SAS和NVMe固态硬盘时使用的分组(--merge-ssd)
+// 只用于显示，磁盘本身的类型不变，统计和指标仍按原类型区分
+const DiskTypeSSD DiskType = "SSD"
+
+// MergedSSDTitle 合并后的固态硬盘分组标题
+const MergedSSDTitle = "固态硬盘"
+
+// IsMergedSSD 检查磁盘类型在合并显示时是否归入DiskTypeSSD分组
+func (t DiskType) IsMergedSSD() bool {
+	return t == DiskTypeSASSSD || t == DiskTypeNVMESSD
+}
+
+// GetGroupTitle 获取磁盘类型分组的标题
+func (t DiskType) GetGroupTitle() string {
+	if t == DiskTypeSSD {
+		return MergedSSDTitle
+	}
+	for _, group := range DiskTypeGroups {
+		if group.Type == t {
+			return group.Title
+		}
+	}
+	return OtherDiskTypeTitle
+}
+
+// GetOrderedDiskTypes 获取有磁盘的类型，按DiskTypeGroups的顺序排列
+// 不在DiskTypeGroups中的类型按名称排在最后，保证输出顺序稳定
+func (dd *DiskData) GetOrderedDiskTypes() []DiskType {
+	known := make(map[DiskType]bool, len(DiskTypeGroups))
+	var types []DiskType
+	for _, group := range DiskTypeGroups {
+		known[group.Type] = true
+		if len(dd.GroupedDisks[group.Type]) > 0 {
+			types = append(types, group.Type)
+		}
+	}
+
+	var others []DiskType
+	for diskType, disks := range dd.GroupedDisks {
+		if !known[diskType] && len(disks) > 0 {
+			others = append(others, diskType)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i] < others[j]
+	})
+
+	return append(types, others...)
+}
+
+// GetDisplayDiskTypes 获取显示时的分组，顺序同GetOrderedDiskTypes
+// mergeSSD为true时SAS和NVMe固态硬盘合并为一个DiskTypeSSD分组，位于第一个固态硬盘分组的位置
+func (dd *DiskData) GetDisplayDiskTypes(mergeSSD bool) []DiskType {
+	types := dd.GetOrderedDiskTypes()
+	if !mergeSSD {
+		return types
+	}
+
+	result := make([]DiskType, 0, len(types))
+	merged := false
+	for _, diskType := range types {
+		if !diskType.IsMergedSSD() {
+			result = append(result, diskType)
+			continue
+		}
+		if !merged {
+			result = append(result, DiskTypeSSD)
+			merged = true
+		}
+	}
+	return result
+}
+
+// GetDisplayDisks 获取显示分组中的磁盘，DiskTypeSSD依次包含SAS和NVMe固态硬盘
+func (dd *DiskData) GetDisplayDisks(diskType DiskType) []*Disk {
+	if diskType != DiskTypeSSD {
+		return dd.GroupedDisks[diskType]
+	}
+
+	var disks []*Disk
+	for _, group := range DiskTypeGroups {
+		if group.Type.IsMergedSSD() {
+			disks = append(disks, dd.GroupedDisks[group.Type]...)
+		}
+	}
+	return disks
+}
+
+// SortDiskTypesBySeverity 按分组内最严重的磁盘状态排序显示分组(--sort-sections severity)
+// 状态相同的分组保持原来的顺序
+func (dd *DiskData) SortDiskTypesBySeverity(types []DiskType) {
+	sort.SliceStable(types, func(i, j int) bool {
+		return WorstStatus(dd.GetDisplayDisks(types[i])).Severity() > WorstStatus(dd.GetDisplayDisks(types[j])).Severity()
+	})
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnduranceGroupPrefix NVMe耐久组属性名前缀(--nvme-extended)，如 Endurance_Group_1_Percent_Used
+const EnduranceGroupPrefix = "Endurance_Group_"
+
+// 耐久组属性名后缀(nvme endurance-log，日志页0x09)
+const (
+	EnduranceCriticalWarning = "Critical_Warning" // 耐久组严重警告位
+	EndurancePercentUsed     = "Percent_Used"     // 耐久组已用寿命(%)
+	EnduranceAvailableSpare  = "Available_Spare"  // 耐久组可用备用空间(%)
+)
+
+// 耐久组严重警告各位的含义(NVMe规范 Endurance Group Critical Warning Summary)
+const (
+	EnduranceBitSpare       = 1 << 0 // 可用备用空间低于阈值
+	EnduranceBitReliability = 1 << 2 // 介质错误或内部错误导致可靠性下降
+	EnduranceBitReadOnly    = 1 << 3 // 耐久组的命名空间已变为只读
+)
+
+// EnduranceGroup 一个NVMe耐久组的寿命数据
+type EnduranceGroup struct {
+	ID              int // 耐久组编号
+	CriticalWarning int // 严重警告位
+	PercentUsed     int // 已用寿命(%)，未知时为-1
+	AvailableSpare  int // 可用备用空间(%)，未知时为-1
+}
+
+// EnduranceGroupAttribute 获取耐久组属性名，如 EnduranceGroupAttribute(1, EndurancePercentUsed)
+func EnduranceGroupAttribute(id int, field string) string {
+	return fmt.Sprintf("%s%d_%s", EnduranceGroupPrefix, id, field)
+}
+
+// GetEnduranceGroups 获取各耐久组的数据，按编号排序
+func (d *Disk) GetEnduranceGroups() []EnduranceGroup {
+	groups := make(map[int]*EnduranceGroup)
+	for name, value := range d.SMARTData {
+		if !strings.HasPrefix(name, EnduranceGroupPrefix) {
+			continue
+		}
+		id, field, ok := strings.Cut(strings.TrimPrefix(name, EnduranceGroupPrefix), "_")
+		if !ok {
+			continue
+		}
+		groupID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+
+		group, exists := groups[groupID]
+		if !exists {
+			group = &EnduranceGroup{ID: groupID, PercentUsed: -1, AvailableSpare: -1}
+			groups[groupID] = group
+		}
+		switch field {
+		case EnduranceCriticalWarning:
+			group.CriticalWarning = number
+		case EndurancePercentUsed:
+			group.PercentUsed = number
+		case EnduranceAvailableSpare:
+			group.AvailableSpare = number
+		}
+	}
+
+	result := make([]EnduranceGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// GetDisplayEnduranceGroups 获取可显示的各耐久组寿命，如 "EG1 3%, EG2 41% (警告 0x04)"
+func (d *Disk) GetDisplayEnduranceGroups() string {
+	groups := d.GetEnduranceGroups()
+	if len(groups) == 0 {
+		return "N/A"
+	}
+
+	parts := make([]string, 0, len(groups))
+	for _, group := range groups {
+		used := "N/A"
+		if group.PercentUsed >= 0 {
+			used = fmt.Sprintf("%d%%", group.PercentUsed)
+		}
+		part := fmt.Sprintf("EG%d %s", group.ID, used)
+		if group.CriticalWarning != 0 {
+			part += fmt.Sprintf(" (警告 0x%02x)", group.CriticalWarning)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// enduranceWarning 根据耐久组严重警告判断磁盘状态和原因，没有警告时返回DiskStatusOK
+// 可靠性下降或变为只读为错误，其他警告位(如备用空间不足)为警告
+func (d *Disk) enduranceWarning() (DiskStatus, string) {
+	status := DiskStatusOK
+	var problems []string
+	for _, group := range d.GetEnduranceGroups() {
+		if group.CriticalWarning == 0 {
+			continue
+		}
+
+		groupStatus := DiskStatusWarning
+		if group.CriticalWarning&(EnduranceBitReliability|EnduranceBitReadOnly) != 0 {
+			groupStatus = DiskStatusError
+		}
+		status = MoreSevere(status, groupStatus)
+		problems = append(problems, fmt.Sprintf("耐久组%d 0x%02x", group.ID, group.CriticalWarning))
+	}
+	if len(problems) == 0 {
+		return DiskStatusOK, ""
+	}
+
+	return status, fmt.Sprintf("%s: 耐久组严重警告 (%s)", StatusSourceSelfReported, strings.Join(problems, ", "))
+}
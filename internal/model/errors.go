@@ -0,0 +1,61 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+)
+
+// ErrorOverviewRow 错误总览中的一行，对应一个存在非零错误计数的磁盘
+type ErrorOverviewRow struct {
+	Name              string     // 设备名称
+	Status            DiskStatus // 磁盘状态
+	UncorrectedErrors int64      // 未修正错误
+	NonMediumErrors   int64      // 非介质错误
+	GrownDefects      int64      // 增长缺陷数
+	PendingSectors    int64      // 待映射扇区数
+}
+
+// Total 获取所有错误计数之和
+func (r ErrorOverviewRow) Total() int64 {
+	return r.UncorrectedErrors + r.NonMediumErrors + r.GrownDefects + r.PendingSectors
+}
+
+// ErrorOverview 汇总所有存在非零错误计数的磁盘
+// 按未修正错误、错误总数降序排列，最严重的磁盘排在最前
+func (dd *DiskData) ErrorOverview() []ErrorOverviewRow {
+	var rows []ErrorOverviewRow
+	for _, disk := range dd.Disks {
+		row := ErrorOverviewRow{
+			Name:              disk.Name,
+			Status:            disk.GetStatus(),
+			UncorrectedErrors: errorCount(disk, "Uncorrected_Errors"),
+			NonMediumErrors:   errorCount(disk, "Non_Medium_Errors"),
+			GrownDefects:      errorCount(disk, "Grown_Defects"),
+			PendingSectors:    errorCount(disk, "Pending_Sectors"),
+		}
+		if row.Total() > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].UncorrectedErrors != rows[j].UncorrectedErrors {
+			return rows[i].UncorrectedErrors > rows[j].UncorrectedErrors
+		}
+		if rows[i].Total() != rows[j].Total() {
+			return rows[i].Total() > rows[j].Total()
+		}
+		return rows[i].Name < rows[j].Name
+	})
+
+	return rows
+}
+
+// errorCount 读取错误计数属性，缺失或无法解析时视为0
+func errorCount(disk *Disk, attribute string) int64 {
+	value, err := strconv.ParseInt(disk.SMARTData[attribute], 10, 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,48 @@
+package model
+
+import "testing"
+
+func TestDiskData_ErrorOverview(t *testing.T) {
+	diskData := NewDiskData()
+
+	clean := NewDisk("sda", "SSD", "Samsung 870 EVO", "1 TB")
+	clean.SMARTData["Uncorrected_Errors"] = "0"
+	clean.SMARTData["Non_Medium_Errors"] = "0"
+	diskData.AddDisk(clean)
+
+	nonMedium := NewDisk("sdb", "HDD", "SEAGATE ST600MM0006", "600G")
+	nonMedium.SMARTData["Uncorrected_Errors"] = "0"
+	nonMedium.SMARTData["Non_Medium_Errors"] = "157"
+	diskData.AddDisk(nonMedium)
+
+	uncorrected := NewDisk("sdc", "HDD", "SEAGATE ST600MM0006", "600G")
+	uncorrected.SMARTData["Uncorrected_Errors"] = "2"
+	uncorrected.SMARTData["Grown_Defects"] = "8"
+	diskData.AddDisk(uncorrected)
+
+	pending := NewDisk("sdd", "HDD", "WDC WD40EFRX", "4T")
+	pending.SMARTData["Pending_Sectors"] = "3"
+	diskData.AddDisk(pending)
+
+	// 没有任何错误计数的磁盘
+	diskData.AddDisk(NewDisk("nvme0n1", "SSD", "Samsung SSD 980 PRO", "1 TB"))
+
+	rows := diskData.ErrorOverview()
+
+	expected := []string{"sdc", "sdb", "sdd"}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d: %+v", len(expected), len(rows), rows)
+	}
+	for i, name := range expected {
+		if rows[i].Name != name {
+			t.Errorf("Row %d: expected disk %s, got %s", i, name, rows[i].Name)
+		}
+	}
+
+	if rows[0].UncorrectedErrors != 2 || rows[0].GrownDefects != 8 || rows[0].Total() != 10 {
+		t.Errorf("Unexpected counts for sdc: %+v", rows[0])
+	}
+	if rows[2].PendingSectors != 3 {
+		t.Errorf("Expected 3 pending sectors for sdd, got %d", rows[2].PendingSectors)
+	}
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DiskEventType 磁盘事件类型
+type DiskEventType string
+
+const (
+	// EventStatusChanged 磁盘状态发生变化
+	EventStatusChanged DiskEventType = "status_changed"
+	// EventDiskAdded 出现了上次运行中没有的磁盘
+	EventDiskAdded DiskEventType = "disk_added"
+	// EventDiskRemoved 上次运行中的磁盘不见了
+	EventDiskRemoved DiskEventType = "disk_removed"
+	// EventCounterReset 累计计数器比上次运行时小(设备重置或更换)
+	EventCounterReset DiskEventType = "counter_reset"
+)
+
+// DiskEvent 历史中记录的一个磁盘事件
+type DiskEvent struct {
+	Time   time.Time     `json:"time"`             // 检测到事件的时间
+	Type   DiskEventType `json:"type"`             // 事件类型
+	Disk   string        `json:"disk"`             // 磁盘名称
+	Serial string        `json:"serial,omitempty"` // 序列号
+	From   string        `json:"from,omitempty"`   // 变化前的值(状态、计数)
+	To     string        `json:"to,omitempty"`     // 变化后的值
+	Detail string        `json:"detail,omitempty"` // 附加说明(如重置的计数器名称)
+}
+
+// Description 获取可显示的事件说明
+func (e DiskEvent) Description() string {
+	switch e.Type {
+	case EventStatusChanged:
+		return fmt.Sprintf("状态 %s -> %s", e.From, e.To)
+	case EventDiskAdded:
+		return "新增磁盘"
+	case EventDiskRemoved:
+		return "磁盘已移除"
+	case EventCounterReset:
+		return fmt.Sprintf("计数器重置 %s: %s -> %s", e.Detail, e.From, e.To)
+	default:
+		return string(e.Type)
+	}
+}
+
+// resetCounters 检查是否重置的累计计数器
+var resetCounters = []string{"Data_Read", "Data_Written", "Corrected_Errors"}
+
+// DetectEvents 与上次运行的数据比较，生成本次运行的事件
+// 没有上次运行的数据时不生成事件，否则首次运行会把所有磁盘记为新增
+func (dd *DiskData) DetectEvents(now time.Time) []DiskEvent {
+	if !dd.HasPreviousData() {
+		return nil
+	}
+
+	var events []DiskEvent
+	current := make(map[string]bool, len(dd.Disks))
+	for _, disk := range dd.Disks {
+		current[disk.Name] = true
+
+		prev, ok := dd.PreviousData[disk.Name]
+		if !ok {
+			events = append(events, DiskEvent{Time: now, Type: EventDiskAdded, Disk: disk.Name, Serial: disk.Serial})
+			continue
+		}
+
+		// 早期的历史文件没有保存状态
+		status := string(disk.GetStatus())
+		if prev["Status"] != "" && prev["Status"] != status {
+			events = append(events, DiskEvent{Time: now, Type: EventStatusChanged, Disk: disk.Name, Serial: disk.Serial,
+				From: prev["Status"], To: status})
+		}
+
+		for _, counter := range resetCounters {
+			if counterDecreased(prev, disk.SMARTData, counter) {
+				events = append(events, DiskEvent{Time: now, Type: EventCounterReset, Disk: disk.Name, Serial: disk.Serial,
+					From: prev[counter], To: disk.SMARTData[counter], Detail: counter})
+			}
+		}
+	}
+
+	// 磁盘列表被截断或只收集了部分磁盘时，缺少的磁盘不一定已移除
+	if !dd.IsPartial() {
+		names := make([]string, 0, len(dd.PreviousData))
+		for name := range dd.PreviousData {
+			if !current[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			events = append(events, DiskEvent{Time: now, Type: EventDiskRemoved, Disk: name, Serial: dd.PreviousData[name]["Serial"]})
+		}
+	}
+
+	return events
+}
+
+// counterDecreased 检查计数器是否比上次小
+// 读写量为 "1.50 TiB" 等带单位的字符串，按字节数比较(见SizeBytes)
+func counterDecreased(prev, current map[string]string, counter string) bool {
+	if prev[counter] == "" || current[counter] == "" {
+		return false
+	}
+
+	prevValue, prevErr := strconv.ParseFloat(prev[counter], 64)
+	curValue, curErr := strconv.ParseFloat(current[counter], 64)
+	if prevErr != nil || curErr != nil {
+		var err error
+		if prevValue, err = SizeBytes(prev, counter, SizeUnitsBinary); err != nil {
+			return false
+		}
+		if curValue, err = SizeBytes(current, counter, SizeUnitsDecimal); err != nil {
+			return false
+		}
+	}
+	return curValue < prevValue
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskData_DetectEvents(t *testing.T) {
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	dd := NewDiskData()
+	sda := NewDisk("sda", "HDD", "ST4000NM", "4T")
+	sda.Status = DiskStatusOK
+	sda.SMARTData["Data_Read"] = "1.20 TiB"
+	sda.SMARTData["Corrected_Errors"] = "150"
+	sdc := NewDisk("sdc", "HDD", "ST4000NM", "4T")
+	sdc.Status = DiskStatusOK
+	dd.AddDisk(sda)
+	dd.AddDisk(sdc)
+
+	// 没有上次运行的数据时不产生事件
+	if events := dd.DetectEvents(now); len(events) != 0 {
+		t.Errorf("Expected no events without previous data, got %v", events)
+	}
+
+	// sda的读取量和纠错计数变小，sdb已移除，sdc为新增
+	dd.SetPreviousData(map[string]map[string]string{
+		"sda": {"Status": string(DiskStatusOK), "Data_Read": "3.50 TiB", "Corrected_Errors": "100"},
+		"sdb": {"Status": string(DiskStatusOK), "Serial": "ZC1XYZ"},
+	}, "2025-03-09 08:00:00")
+
+	events := dd.DetectEvents(now)
+	got := make(map[DiskEventType][]string)
+	for _, event := range events {
+		got[event.Type] = append(got[event.Type], event.Disk+":"+event.Detail)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %v", events)
+	}
+	if len(got[EventCounterReset]) != 1 || got[EventCounterReset][0] != "sda:Data_Read" {
+		t.Errorf("Expected a Data_Read reset on sda, got %v", got[EventCounterReset])
+	}
+	if len(got[EventDiskRemoved]) != 1 || got[EventDiskRemoved][0] != "sdb:" {
+		t.Errorf("Expected sdb to be removed, got %v", got[EventDiskRemoved])
+	}
+	if len(got[EventDiskAdded]) != 1 || got[EventDiskAdded][0] != "sdc:" {
+		t.Errorf("Expected sdc to be added, got %v", got[EventDiskAdded])
+	}
+
+	// 截断的磁盘列表不报告移除
+	dd.TruncatedFrom = 5
+	for _, event := range dd.DetectEvents(now) {
+		if event.Type == EventDiskRemoved {
+			t.Errorf("Expected no removal events for a truncated list, got %+v", event)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 历史数据中用于抖动检测的字段
+const (
+	StatusHistoryAttribute = "Status_History" // 最近几次运行的状态，逗号分隔，从旧到新
+	AlertStatusAttribute   = "Alert_Status"   // 上次运行时用于告警的稳定状态
+)
+
+// GetAlertStatus 获取用于告警的状态，未启用抖动检测时与GetStatus相同
+func (d *Disk) GetAlertStatus() DiskStatus {
+	if d.AlertStatus != "" {
+		return d.AlertStatus
+	}
+	return d.GetStatus()
+}
+
+// ApplyFlapDetection 根据历史数据抑制状态抖动(--flap-runs)
+// 状态连续runs次运行相同才视为稳定，不稳定时告警沿用上次的稳定状态；错误状态总是立即告警
+// 没有历史数据的磁盘以当前状态为稳定状态，runs小于2时不启用
+// 返回被抑制的磁盘数量
+func (dd *DiskData) ApplyFlapDetection(runs int) int {
+	if runs < 2 {
+		return 0
+	}
+
+	count := 0
+	for _, disk := range dd.Disks {
+		current := disk.GetStatus()
+		prev := dd.PreviousData[disk.Name]
+
+		history := previousStatuses(prev)
+		history = append(history, string(current))
+		if len(history) > runs {
+			history = history[len(history)-runs:]
+		}
+		disk.StatusHistory = history
+
+		stable := DiskStatus(prev[AlertStatusAttribute])
+		if stable == "" || current == DiskStatusError || isStable(history, runs) {
+			disk.AlertStatus = current
+			continue
+		}
+
+		disk.AlertStatus = stable
+		if stable != current {
+			disk.AddStatusReason(fmt.Sprintf("状态不稳定: 最近%d次运行为 %s，告警按 %s 处理",
+				len(history), strings.Join(history, ","), stable))
+			count++
+		}
+	}
+	return count
+}
+
+// previousStatuses 从历史数据中获取之前几次运行的状态
+// 早期的历史文件只保存了上次的状态
+func previousStatuses(prev map[string]string) []string {
+	if history := prev[StatusHistoryAttribute]; history != "" {
+		return strings.Split(history, ",")
+	}
+	if status := prev["Status"]; status != "" {
+		return []string{status}
+	}
+	return nil
+}
+
+// isStable 检查最近runs次运行的状态是否都相同
+func isStable(history []string, runs int) bool {
+	if len(history) < runs {
+		return false
+	}
+	for _, status := range history[len(history)-runs:] {
+		if status != history[len(history)-1] {
+			return false
+		}
+	}
+	return true
+}
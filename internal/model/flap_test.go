@@ -0,0 +1,70 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiskData_FlapDetection(t *testing.T) {
+	// 模拟多次运行，每次把上次保存的状态历史作为历史数据
+	previous := map[string]string{}
+	run := func(status DiskStatus) *Disk {
+		diskData := NewDiskData()
+		disk := NewDisk("sda", "HDD", "TEST", "1T")
+		disk.Status = status
+		diskData.AddDisk(disk)
+		diskData.SetPreviousData(map[string]map[string]string{"sda": previous}, "2024-01-01 00:00:00")
+
+		diskData.ApplyFlapDetection(3)
+		previous = map[string]string{
+			"Status":               string(disk.GetStatus()),
+			StatusHistoryAttribute: strings.Join(disk.StatusHistory, ","),
+			AlertStatusAttribute:   string(disk.AlertStatus),
+		}
+		return disk
+	}
+
+	// 首次运行以当前状态为稳定状态
+	if disk := run(DiskStatusOK); disk.GetAlertStatus() != DiskStatusOK {
+		t.Fatalf("首次运行的告警状态应为 OK，实际为 %s", disk.GetAlertStatus())
+	}
+
+	// 每次运行都在正常和警告之间切换，告警保持 OK
+	for i, status := range []DiskStatus{DiskStatusWarning, DiskStatusOK, DiskStatusWarning, DiskStatusOK, DiskStatusWarning} {
+		disk := run(status)
+		if disk.GetAlertStatus() != DiskStatusOK {
+			t.Fatalf("第%d次抖动的告警状态应为 OK，实际为 %s (历史 %v)", i+1, disk.GetAlertStatus(), disk.StatusHistory)
+		}
+		if status == DiskStatusWarning && !strings.Contains(disk.StatusReason, "状态不稳定") {
+			t.Errorf("被抑制的警告应说明原因，实际为 %q", disk.StatusReason)
+		}
+	}
+
+	// 警告连续出现3次后视为稳定(上面最后一次已经是第1次)
+	if disk := run(DiskStatusWarning); disk.GetAlertStatus() != DiskStatusOK {
+		t.Fatalf("连续2次警告时告警状态应仍为 OK，实际为 %s", disk.GetAlertStatus())
+	}
+	disk := run(DiskStatusWarning)
+	if disk.GetAlertStatus() != DiskStatusWarning {
+		t.Fatalf("连续3次警告后告警状态应为 WARNING，实际为 %s", disk.GetAlertStatus())
+	}
+
+	// 恢复正常也需要连续3次，期间继续按警告告警
+	if disk := run(DiskStatusOK); disk.GetAlertStatus() != DiskStatusWarning {
+		t.Errorf("刚恢复正常时告警状态应仍为 WARNING，实际为 %s", disk.GetAlertStatus())
+	}
+
+	// 错误总是立即告警
+	if disk := run(DiskStatusError); disk.GetAlertStatus() != DiskStatusError {
+		t.Errorf("错误的告警状态应为 ERROR，实际为 %s", disk.GetAlertStatus())
+	}
+
+	// 未启用时告警状态与当前状态相同
+	diskData := NewDiskData()
+	disk = NewDisk("sdb", "HDD", "TEST", "1T")
+	disk.Status = DiskStatusWarning
+	diskData.AddDisk(disk)
+	if count := diskData.ApplyFlapDetection(0); count != 0 || disk.GetAlertStatus() != DiskStatusWarning || diskData.GetAlertCount() != 1 {
+		t.Errorf("未启用抖动检测时应按当前状态告警，实际为 %s (%d)", disk.GetAlertStatus(), diskData.GetAlertCount())
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import "strings"
+
+// HealthVerdict 整体健康结论(--format health)，沿用Nagios插件的结论和退出码
+type HealthVerdict string
+
+const (
+	HealthOK       HealthVerdict = "OK"       // 所有磁盘、控制器和存储池正常
+	HealthWarning  HealthVerdict = "WARNING"  // 存在警告
+	HealthCritical HealthVerdict = "CRITICAL" // 存在错误
+	HealthUnknown  HealthVerdict = "UNKNOWN"  // 无法收集数据
+)
+
+// ExitCode 获取结论对应的退出码(0-3，与Nagios插件一致)
+func (v HealthVerdict) ExitCode() int {
+	switch v {
+	case HealthOK:
+		return 0
+	case HealthWarning:
+		return 1
+	case HealthCritical:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// VerdictForStatus 将状态转换为健康结论
+func VerdictForStatus(status DiskStatus) HealthVerdict {
+	switch status {
+	case DiskStatusError:
+		return HealthCritical
+	case DiskStatusWarning:
+		return HealthWarning
+	default:
+		return HealthOK
+	}
+}
+
+// OverallStatus 汇总磁盘、存储池和控制器中最严重的状态
+// 已确认的磁盘警告不计入，与 --exit-on-warning 一致；DEGRADED存储池为警告，其他非ONLINE状态为错误
+// 磁盘按抖动检测后的告警状态计算(--flap-runs)
+func OverallStatus(dd *DiskData, cd *ControllerData) DiskStatus {
+	status := DiskStatusOK
+
+	if dd != nil {
+		for _, disk := range dd.Disks {
+			diskStatus := disk.GetAlertStatus()
+			if diskStatus == DiskStatusWarning && disk.Acknowledged != "" {
+				continue
+			}
+			status = MoreSevere(status, diskStatus)
+		}
+
+		for _, poolStatus := range dd.PoolStatus {
+			switch strings.ToUpper(poolStatus) {
+			case "", PoolStatusOnline:
+			case "DEGRADED":
+				status = MoreSevere(status, DiskStatusWarning)
+			default:
+				status = MoreSevere(status, DiskStatusError)
+			}
+		}
+
+		if len(dd.GetFullPools()) > 0 {
+			status = MoreSevere(status, DiskStatusWarning)
+		}
+	}
+
+	if cd != nil {
+		var controllers []Controller
+		for _, controller := range cd.GetSortedLSIControllers() {
+			controllers = append(controllers, controller.Controller)
+		}
+		for _, controller := range cd.GetSortedNVMeControllers() {
+			controllers = append(controllers, controller.Controller)
+		}
+		for _, controller := range controllers {
+			switch controller.Status {
+			case ControllerStatusError:
+				status = MoreSevere(status, DiskStatusError)
+			case ControllerStatusWarning:
+				status = MoreSevere(status, DiskStatusWarning)
+			}
+		}
+	}
+
+	return status
+}
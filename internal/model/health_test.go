@@ -0,0 +1,113 @@
+package model
+
+import "testing"
+
+func TestOverallStatus_Verdicts(t *testing.T) {
+	// 构造一组正常的磁盘、存储池和控制器
+	newData := func() (*DiskData, *ControllerData) {
+		diskData := NewDiskData()
+		disk := NewDisk("sda", "HDD", "SEAGATE ST600MM0006", "600G")
+		disk.Pool = "tank"
+		diskData.AddDisk(disk)
+		diskData.PoolStatus["tank"] = PoolStatusOnline
+
+		ctrlData := NewControllerData()
+		ctrlData.GetLSIController("c0").Status = ControllerStatusOK
+		return diskData, ctrlData
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*DiskData, *ControllerData)
+		expected HealthVerdict
+		exitCode int
+	}{
+		{
+			name:     "全部正常",
+			modify:   func(*DiskData, *ControllerData) {},
+			expected: HealthOK,
+			exitCode: 0,
+		},
+		{
+			name: "磁盘警告",
+			modify: func(dd *DiskData, _ *ControllerData) {
+				dd.Disks[0].Escalate(DiskStatusWarning, "test")
+			},
+			expected: HealthWarning,
+			exitCode: 1,
+		},
+		{
+			name: "已确认的磁盘警告不计入",
+			modify: func(dd *DiskData, _ *ControllerData) {
+				dd.Disks[0].Escalate(DiskStatusWarning, "test")
+				dd.Disks[0].Acknowledged = "known defect"
+			},
+			expected: HealthOK,
+			exitCode: 0,
+		},
+		{
+			name: "存储池降级",
+			modify: func(dd *DiskData, _ *ControllerData) {
+				dd.PoolStatus["tank"] = "DEGRADED"
+			},
+			expected: HealthWarning,
+			exitCode: 1,
+		},
+		{
+			name: "控制器警告",
+			modify: func(_ *DiskData, cd *ControllerData) {
+				cd.GetLSIController("c0").Status = ControllerStatusWarning
+			},
+			expected: HealthWarning,
+			exitCode: 1,
+		},
+		{
+			name: "磁盘错误优先于警告",
+			modify: func(dd *DiskData, cd *ControllerData) {
+				dd.Disks[0].Escalate(DiskStatusError, "test")
+				cd.GetLSIController("c0").Status = ControllerStatusWarning
+			},
+			expected: HealthCritical,
+			exitCode: 2,
+		},
+		{
+			name: "存储池故障",
+			modify: func(dd *DiskData, _ *ControllerData) {
+				dd.PoolStatus["tank"] = "FAULTED"
+			},
+			expected: HealthCritical,
+			exitCode: 2,
+		},
+		{
+			name: "NVMe控制器错误",
+			modify: func(_ *DiskData, cd *ControllerData) {
+				cd.GetNVMeController("nvme0").Status = ControllerStatusError
+			},
+			expected: HealthCritical,
+			exitCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diskData, ctrlData := newData()
+			tt.modify(diskData, ctrlData)
+
+			verdict := VerdictForStatus(OverallStatus(diskData, ctrlData))
+			if verdict != tt.expected {
+				t.Errorf("结论应为 %s，实际为 %s", tt.expected, verdict)
+			}
+			if verdict.ExitCode() != tt.exitCode {
+				t.Errorf("退出码应为 %d，实际为 %d", tt.exitCode, verdict.ExitCode())
+			}
+		})
+	}
+
+	// 没有任何数据时为正常
+	if verdict := VerdictForStatus(OverallStatus(nil, nil)); verdict != HealthOK {
+		t.Errorf("没有数据时结论应为 OK，实际为 %s", verdict)
+	}
+	if HealthUnknown.ExitCode() != 3 {
+		t.Errorf("UNKNOWN的退出码应为3，实际为 %d", HealthUnknown.ExitCode())
+	}
+}
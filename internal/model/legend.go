@@ -0,0 +1,50 @@
+package model
+
+// LegendEntry 图例中的一列说明(--legend)
+type LegendEntry struct {
+	DisplayName string // 表头显示名称
+	Unit        string // 单位，没有单位时为空
+	Description string // 说明
+}
+
+// attributeDescriptions 各属性列的说明，按属性名索引
+// 列名和单位来自GetDiskAttributes，这里只补充含义
+var attributeDescriptions = map[string]string{
+	"Temperature":          "磁盘当前温度",
+	"Trip_Temperature":     "磁盘报告的警告温度阈值",
+	"Warning_Temperature":  "磁盘报告的警告温度阈值",
+	"Critical_Temperature": "NVMe磁盘报告的临界温度阈值",
+	"Warning_Temp_Time":    "温度超过警告阈值的累计时间",
+	"Critical_Temp_Time":   "温度超过临界阈值的累计时间",
+	"Power_On_Hours":       "累计通电时间",
+	"Power_Cycles":         "累计通电/断电次数",
+	"Percentage_Used":      "厂商估计的已消耗寿命，100%表示达到设计写入量",
+	"Available_Spare":      "剩余备用块的比例，低于阈值时磁盘会告警",
+	"Smart_Status":         "SMART整体健康检查结果",
+	"Data_Read":            "累计读取的数据量",
+	"Data_Written":         "累计写入的数据量",
+	"Non_Medium_Errors":    "与介质无关的错误数(如链路或固件错误)",
+	"Uncorrected_Errors":   "无法纠正的读写错误数",
+	"Type":                 "虚拟设备的类型",
+}
+
+// GetColumnLegend 获取表格属性列的图例，由GetDiskAttributes生成以保持与表头一致
+// 只包含有磁盘的类型，按分组顺序排列，同名列只出现一次
+func (dd *DiskData) GetColumnLegend() []LegendEntry {
+	seen := make(map[string]bool)
+	var entries []LegendEntry
+	for _, diskType := range dd.GetOrderedDiskTypes() {
+		for _, attr := range dd.GetDiskAttributes(diskType) {
+			if seen[attr.DisplayName] {
+				continue
+			}
+			seen[attr.DisplayName] = true
+			entries = append(entries, LegendEntry{
+				DisplayName: attr.DisplayName,
+				Unit:        attr.Unit,
+				Description: attributeDescriptions[attr.Name],
+			})
+		}
+	}
+	return entries
+}
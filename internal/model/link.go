@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// 链路速率属性名，值为Gb/s数值(如 "6.0")
+const (
+	LinkSpeedAttribute    = "Link_Speed"     // 当前协商的链路速率
+	LinkSpeedMaxAttribute = "Link_Speed_Max" // 磁盘支持的最高链路速率
+)
+
+// GetLinkSpeeds 获取当前链路速率和支持的最高速率(Gb/s)，未知时为0
+func (d *Disk) GetLinkSpeeds() (current, max float64) {
+	current, _ = strconv.ParseFloat(d.SMARTData[LinkSpeedAttribute], 64)
+	max, _ = strconv.ParseFloat(d.SMARTData[LinkSpeedMaxAttribute], 64)
+	return current, max
+}
+
+// IsLinkDowngraded 检查链路是否以低于磁盘支持的速率运行
+func (d *Disk) IsLinkDowngraded() bool {
+	current, max := d.GetLinkSpeeds()
+	return current > 0 && max > 0 && current < max
+}
+
+// linkSpeedWarning 链路速率降级时返回原因，否则返回空字符串
+// 降级通常由线缆、背板或扩展器问题引起
+func (d *Disk) linkSpeedWarning() string {
+	if !d.IsLinkDowngraded() {
+		return ""
+	}
+	return fmt.Sprintf("%s: 链路速率降级 %s Gb/s (支持 %s Gb/s)，请检查线缆和背板",
+		StatusSourceHeuristic, d.SMARTData[LinkSpeedAttribute], d.SMARTData[LinkSpeedMaxAttribute])
+}
+
+// GetDisplayLinkSpeed 获取可显示的链路速率，如 "6.0 Gb/s" 或降级时的 "3.0/6.0 Gb/s"
+func (d *Disk) GetDisplayLinkSpeed() string {
+	current := d.SMARTData[LinkSpeedAttribute]
+	if current == "" {
+		return "N/A"
+	}
+	if d.IsLinkDowngraded() {
+		return fmt.Sprintf("%s/%s Gb/s", current, d.SMARTData[LinkSpeedMaxAttribute])
+	}
+	return current + " Gb/s"
+}
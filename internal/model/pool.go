@@ -0,0 +1,279 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PoolUnassigned 未分配到任何存储池的磁盘使用的池名称
+const PoolUnassigned = "未分配"
+
+// PoolStatusOnline ZFS存储池正常在线状态
+const PoolStatusOnline = "ONLINE"
+
+// DefaultPoolWarnPct 存储池默认的容量告警阈值(%)，ZFS存储池接近写满时性能会明显下降
+const DefaultPoolWarnPct = 80
+
+// PoolSummary 存储池汇总信息
+type PoolSummary struct {
+	Name               string     // 存储池名称
+	Status             string     // 存储池状态(ONLINE, DEGRADED等)
+	DiskCount          int        // 成员磁盘数量
+	WarningCount       int        // 警告状态的磁盘数量
+	ErrorCount         int        // 错误状态的磁盘数量
+	AvgTemperature     float64    // 平均温度
+	MaxTemperature     int        // 最高温度
+	TempSamples        int        // 有温度数据的磁盘数量
+	RawCapacity        float64    // 成员磁盘原始容量总和(字节)
+	UsedPercent        float64    // 已用容量百分比
+	HasUsage           bool       // 是否获取到了容量使用数据
+	FillWarning        bool       // 已用容量是否超过告警阈值
+	LogicalSectorSizes []int      // 成员磁盘使用的逻辑扇区大小(字节，去重升序)
+	MixedCapacityVdevs []PoolVdev // 成员容量不同的数据vdev(Sizes为各成员容量)
+}
+
+// IsDegraded 检查存储池是否处于非ONLINE状态
+func (ps *PoolSummary) IsDegraded() bool {
+	return ps.Status != "" && strings.ToUpper(ps.Status) != PoolStatusOnline
+}
+
+// GetDisplayStatus 获取可显示的存储池状态
+func (ps *PoolSummary) GetDisplayStatus() string {
+	if ps.Status == "" {
+		return "N/A"
+	}
+	return ps.Status
+}
+
+// GetDisplayUsage 获取可显示的已用容量百分比
+func (ps *PoolSummary) GetDisplayUsage() string {
+	if !ps.HasUsage {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.1f%%", ps.UsedPercent)
+}
+
+// GetDisplayAvgTemperature 获取可显示的平均温度
+func (ps *PoolSummary) GetDisplayAvgTemperature() string {
+	if ps.TempSamples == 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.1f°C", ps.AvgTemperature)
+}
+
+// GetDisplayMaxTemperature 获取可显示的最高温度
+func (ps *PoolSummary) GetDisplayMaxTemperature() string {
+	if ps.TempSamples == 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%d°C", ps.MaxTemperature)
+}
+
+// GetPoolSummaries 按存储池汇总磁盘信息，结果按池名称排序
+func (dd *DiskData) GetPoolSummaries() []*PoolSummary {
+	summaries := make(map[string]*PoolSummary)
+	tempTotals := make(map[string]int)
+	diskSizes := make(map[string]float64)
+
+	for _, disk := range dd.poolDisks() {
+		if disk.Pool == "" || disk.Pool == PoolUnassigned {
+			continue
+		}
+
+		summary, ok := summaries[disk.Pool]
+		if !ok {
+			summary = &PoolSummary{
+				Name:   disk.Pool,
+				Status: dd.PoolStatus[disk.Pool],
+			}
+			if usage, ok := dd.PoolUsage[disk.Pool]; ok {
+				summary.UsedPercent = usage
+				summary.HasUsage = true
+				summary.FillWarning = usage > float64(dd.GetPoolWarnPct())
+			}
+			summaries[disk.Pool] = summary
+		}
+
+		summary.DiskCount++
+		switch disk.GetStatus() {
+		case DiskStatusWarning:
+			summary.WarningCount++
+		case DiskStatusError:
+			summary.ErrorCount++
+		}
+
+		// 汇总温度
+		if temp, err := strconv.Atoi(disk.SMARTData["Temperature"]); err == nil {
+			if summary.TempSamples == 0 || temp > summary.MaxTemperature {
+				summary.MaxTemperature = temp
+			}
+			summary.TempSamples++
+			tempTotals[disk.Pool] += temp
+		}
+
+		// 汇总扇区大小，用于检查512e/4Kn混用
+		summary.addSectorSize(disk.LogicalSectorSize)
+
+		// 汇总原始容量(midclt返回字节数)
+		if size, err := strconv.ParseFloat(disk.Size, 64); err == nil {
+			summary.RawCapacity += size
+			diskSizes[disk.Name] = size
+		}
+	}
+
+	result := make([]*PoolSummary, 0, len(summaries))
+	for name, summary := range summaries {
+		if summary.TempSamples > 0 {
+			summary.AvgTemperature = float64(tempTotals[name]) / float64(summary.TempSamples)
+		}
+		summary.MixedCapacityVdevs = dd.mixedCapacityVdevs(name, diskSizes)
+		result = append(result, summary)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// GetDisksByPool 按存储池分组磁盘
+// 返回排序后的池名称(未分配的磁盘排在最后)以及每个池内按名称排序的磁盘
+func (dd *DiskData) GetDisksByPool() ([]string, map[string][]*Disk) {
+	groups := make(map[string][]*Disk)
+	for _, disk := range dd.Disks {
+		pool := disk.Pool
+		if pool == "" {
+			pool = PoolUnassigned
+		}
+		groups[pool] = append(groups[pool], disk)
+	}
+
+	pools := make([]string, 0, len(groups))
+	for pool, disks := range groups {
+		sort.Slice(disks, func(i, j int) bool {
+			return disks[i].Name < disks[j].Name
+		})
+		pools = append(pools, pool)
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		if pools[i] == PoolUnassigned || pools[j] == PoolUnassigned {
+			return pools[j] == PoolUnassigned && pools[i] != PoolUnassigned
+		}
+		return pools[i] < pools[j]
+	})
+
+	return pools, groups
+}
+
+// SortPoolsBySeverity 按池内最严重的磁盘状态排序GetDisksByPool返回的池名称
+// 状态相同的池保持原来的顺序
+func SortPoolsBySeverity(pools []string, groups map[string][]*Disk) {
+	sort.SliceStable(pools, func(i, j int) bool {
+		return WorstStatus(groups[pools[i]]).Severity() > WorstStatus(groups[pools[j]]).Severity()
+	})
+}
+
+// GetDegradedPools 获取所有非ONLINE状态的存储池名称
+func (dd *DiskData) GetDegradedPools() []string {
+	var pools []string
+	for _, summary := range dd.GetPoolSummaries() {
+		if summary.IsDegraded() {
+			pools = append(pools, summary.Name)
+		}
+	}
+	return pools
+}
+
+// GetPoolWarnPct 获取存储池容量告警阈值，未设置时使用默认值
+func (dd *DiskData) GetPoolWarnPct() int {
+	if dd.PoolWarnPct <= 0 {
+		return DefaultPoolWarnPct
+	}
+	return dd.PoolWarnPct
+}
+
+// poolDisks 获取汇总存储池时使用的磁盘
+func (dd *DiskData) poolDisks() []*Disk {
+	if dd.PoolDisks != nil {
+		return dd.PoolDisks
+	}
+	return dd.Disks
+}
+
+// GetFullPools 获取已用容量超过告警阈值的存储池汇总
+func (dd *DiskData) GetFullPools() []*PoolSummary {
+	var pools []*PoolSummary
+	for _, summary := range dd.GetPoolSummaries() {
+		if summary.FillWarning {
+			pools = append(pools, summary)
+		}
+	}
+	return pools
+}
+
+// PoolChange 磁盘所属存储池相对上次运行的变化
+type PoolChange struct {
+	Disk         *Disk
+	PreviousPool string
+	CurrentPool  string
+}
+
+// DisplayPool 获取存储池的显示名称，未分配时返回label(可以为空)
+// 只影响显示，分组和比较仍然使用PoolUnassigned
+func DisplayPool(pool, label string) string {
+	if isUnassignedPool(pool) {
+		return label
+	}
+	return pool
+}
+
+// isUnassignedPool 检查存储池名称是否表示未分配
+func isUnassignedPool(pool string) bool {
+	return pool == "" || pool == PoolUnassigned
+}
+
+// PoolChanges 与历史数据比较，找出所属存储池发生变化的磁盘
+// 设备名可能在重启后变化，因此优先按序列号匹配历史记录，没有序列号时按设备名匹配
+// 未分配的磁盘加入存储池属于正常操作，不视为变化；历史中没有存储池记录(旧版本历史文件)的磁盘不参与比较
+func (dd *DiskData) PoolChanges(prev map[string]map[string]string) []PoolChange {
+	bySerial := make(map[string]map[string]string)
+	for _, entry := range prev {
+		if serial := entry["Serial"]; serial != "" {
+			bySerial[serial] = entry
+		}
+	}
+
+	var changes []PoolChange
+	for _, disk := range dd.Disks {
+		entry, ok := bySerial[disk.Serial]
+		if disk.Serial == "" || !ok {
+			entry = prev[disk.Name]
+			// 同名设备的序列号不同，说明是另一块磁盘
+			if disk.Serial != "" && entry["Serial"] != "" && entry["Serial"] != disk.Serial {
+				continue
+			}
+		}
+
+		previous, recorded := entry["Pool"]
+		if !recorded || isUnassignedPool(previous) || previous == disk.Pool {
+			continue
+		}
+		changes = append(changes, PoolChange{Disk: disk, PreviousPool: previous, CurrentPool: disk.Pool})
+	}
+	return changes
+}
+
+// GetPoolChangedDisks 获取所属存储池发生变化的磁盘
+func (dd *DiskData) GetPoolChangedDisks() []*Disk {
+	var disks []*Disk
+	for _, disk := range dd.Disks {
+		if disk.PreviousPool != "" {
+			disks = append(disks, disk)
+		}
+	}
+	return disks
+}
@@ -0,0 +1,138 @@
+package model
+
+import "testing"
+
+func TestGetPoolSummaries_FillWarning(t *testing.T) {
+	dd := NewDiskData()
+	for _, disk := range []*Disk{
+		NewDisk("sda", "HDD", "SEAGATE ST4000NM", "4000787030016"),
+		NewDisk("sdb", "HDD", "SEAGATE ST4000NM", "4000787030016"),
+		NewDisk("sdc", "SSD", "Samsung SSD 870 EVO", "1000204886016"),
+		NewDisk("sdd", "SSD", "Samsung SSD 870 EVO", "1000204886016"),
+	} {
+		dd.AddDisk(disk)
+	}
+	dd.Disks[0].Pool = "tank"
+	dd.Disks[1].Pool = "tank"
+	dd.Disks[2].Pool = "apps"
+	dd.Disks[3].Pool = "scratch"
+	dd.PoolUsage["tank"] = 85
+	dd.PoolUsage["apps"] = 50
+
+	// 默认阈值80%: 85%的存储池告警，50%的不告警，没有容量数据的不告警
+	summaries := make(map[string]*PoolSummary)
+	for _, summary := range dd.GetPoolSummaries() {
+		summaries[summary.Name] = summary
+	}
+	if !summaries["tank"].FillWarning || summaries["tank"].GetDisplayUsage() != "85.0%" {
+		t.Errorf("Expected tank at 85%% to warn, got %+v", summaries["tank"])
+	}
+	if summaries["apps"].FillWarning || summaries["apps"].GetDisplayUsage() != "50.0%" {
+		t.Errorf("Expected apps at 50%% not to warn, got %+v", summaries["apps"])
+	}
+	if summaries["scratch"].FillWarning || summaries["scratch"].GetDisplayUsage() != "N/A" {
+		t.Errorf("Expected scratch without usage data not to warn, got %+v", summaries["scratch"])
+	}
+
+	full := dd.GetFullPools()
+	if len(full) != 1 || full[0].Name != "tank" {
+		t.Errorf("Expected only tank to be full, got %v", full)
+	}
+
+	// 容量告警计入告警数量(用于 --exit-on-warning)
+	if count := dd.GetAlertCount(); count != 1 {
+		t.Errorf("Expected 1 alert for the full pool, got %d", count)
+	}
+
+	// 提高阈值后不再告警
+	dd.PoolWarnPct = 90
+	if full := dd.GetFullPools(); len(full) != 0 {
+		t.Errorf("Expected no full pools at 90%% threshold, got %v", full)
+	}
+	if count := dd.GetAlertCount(); count != 0 {
+		t.Errorf("Expected no alerts at 90%% threshold, got %d", count)
+	}
+}
+
+func TestDiskData_GetMixedSectorPools(t *testing.T) {
+	dd := NewDiskData()
+	add := func(name, pool string, logical, physical int) {
+		disk := NewDisk(name, "HDD", "SEAGATE ST4000NM", "4000787030016")
+		disk.Pool = pool
+		disk.LogicalSectorSize = logical
+		disk.PhysicalSectorSize = physical
+		dd.AddDisk(disk)
+	}
+	add("sda", "tank", 512, 4096)  // 512e
+	add("sdb", "tank", 4096, 4096) // 4Kn
+	add("sdc", "apps", 512, 4096)
+	add("sdd", "apps", 512, 512)
+	add("sde", "backup", 4096, 4096)
+	add("sdf", "backup", 0, 0) // 未知扇区大小不参与判断
+
+	if got := dd.Disks[0].GetSectorFormat(); got != SectorFormat512e {
+		t.Errorf("Expected %s, got %s", SectorFormat512e, got)
+	}
+	if got := dd.Disks[5].GetDisplaySectorSize(); got != "N/A" {
+		t.Errorf("Expected N/A for unknown sector size, got %s", got)
+	}
+
+	// 512e与4Kn混用时告警；512n与512e的逻辑扇区相同，不告警
+	mixed := dd.GetMixedSectorPools()
+	if len(mixed) != 1 || mixed[0].Name != "tank" {
+		t.Fatalf("Expected only tank to mix sector sizes, got %v", mixed)
+	}
+	if got := mixed[0].GetDisplaySectorSizes(); got != "512B, 4096B" {
+		t.Errorf("Expected '512B, 4096B', got '%s'", got)
+	}
+}
+
+// TestDiskData_PoolChanges 测试与上次运行相比存储池发生变化的磁盘会被找出
+func TestDiskData_PoolChanges(t *testing.T) {
+	dd := NewDiskData()
+	add := func(name, serial, pool string) {
+		disk := NewDisk(name, "HDD", "SEAGATE ST4000NM", "4000787030016")
+		disk.Serial = serial
+		disk.Pool = pool
+		dd.AddDisk(disk)
+	}
+	add("sda", "S1", "backup") // 由tank移到backup
+	add("sdb", "S2", "tank")   // 未变化，但重启后设备名由sdc变为sdb
+	add("sdc", "S3", "tank")   // 原来未分配，新加入存储池
+	add("sdd", "S4", PoolUnassigned)
+	add("sde", "", "apps") // 没有序列号时按设备名匹配
+	add("sdf", "S6", "apps")
+
+	prev := map[string]map[string]string{
+		"sda": {"Pool": "tank", "Serial": "S1"},
+		"sdc": {"Pool": "tank", "Serial": "S2"},
+		"sdx": {"Pool": PoolUnassigned, "Serial": "S3"},
+		"sdd": {"Pool": "tank", "Serial": "S4"}, // 离开存储池
+		"sde": {"Pool": "tank"},
+		"sdf": {"Data_Read": "1.5 TB"}, // 旧版本历史文件没有存储池记录
+	}
+
+	changes := dd.PoolChanges(prev)
+	got := make(map[string]string)
+	for _, change := range changes {
+		if change.CurrentPool != change.Disk.Pool {
+			t.Errorf("%s: expected current pool %s, got %s", change.Disk.Name, change.Disk.Pool, change.CurrentPool)
+		}
+		got[change.Disk.Name] = change.PreviousPool
+	}
+
+	expected := map[string]string{"sda": "tank", "sdd": "tank", "sde": "tank"}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, got)
+	}
+	for name, pool := range expected {
+		if got[name] != pool {
+			t.Errorf("%s: expected previous pool %s, got %q", name, pool, got[name])
+		}
+	}
+
+	// 没有历史数据时没有变化
+	if changes := dd.PoolChanges(nil); len(changes) != 0 {
+		t.Errorf("Expected no changes without history, got %v", changes)
+	}
+}
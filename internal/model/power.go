@@ -0,0 +1,71 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SATA电源管理属性名(--show-power)，来自 smartctl -g apm -g dsn
+const (
+	APMLevelAttribute = "APM_Level" // APM级别(1-254)，或 Disabled/Unavailable
+	DSNStateAttribute = "DSN_State" // DSN功能状态: Enabled/Disabled/Unavailable
+)
+
+// 电源管理功能状态
+const (
+	PowerFeatureEnabled     = "Enabled"
+	PowerFeatureDisabled    = "Disabled"
+	PowerFeatureUnavailable = "Unavailable"
+)
+
+// APMSpinDownMax 允许磁盘停转的最高APM级别，1-127允许停转，128-254不允许
+const APMSpinDownMax = 127
+
+// powerFeatureNames 电源管理功能状态的显示名称
+var powerFeatureNames = map[string]string{
+	PowerFeatureEnabled:     "已启用",
+	PowerFeatureDisabled:    "已禁用",
+	PowerFeatureUnavailable: "不支持",
+}
+
+// GetAPMLevel 获取APM级别，APM未启用或不支持时返回0
+func (d *Disk) GetAPMLevel() int {
+	level, err := strconv.Atoi(d.SMARTData[APMLevelAttribute])
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+// GetDisplayPower 获取可显示的电源管理设置，如 "APM 128, DSN 已禁用"
+// 允许停转的APM级别会附加说明，便于检查整批磁盘的电源设置
+func (d *Disk) GetDisplayPower() string {
+	apm, dsn := d.SMARTData[APMLevelAttribute], d.SMARTData[DSNStateAttribute]
+	if apm == "" && dsn == "" {
+		return "N/A"
+	}
+
+	var parts []string
+	if level := d.GetAPMLevel(); level > 0 {
+		part := fmt.Sprintf("APM %d", level)
+		if level <= APMSpinDownMax {
+			part += " (允许停转)"
+		}
+		parts = append(parts, part)
+	} else if apm != "" {
+		parts = append(parts, "APM "+displayPowerFeature(apm))
+	}
+	if dsn != "" {
+		parts = append(parts, "DSN "+displayPowerFeature(dsn))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// displayPowerFeature 获取功能状态的显示名称，未知状态原样返回
+func displayPowerFeature(state string) string {
+	if name, ok := powerFeatureNames[state]; ok {
+		return name
+	}
+	return state
+}
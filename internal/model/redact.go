@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// 化名前缀，同一原始值在整份报告中总是映射到同一个化名
+const (
+	redactKindSerial     = "disk"
+	redactKindWWN        = "wwn"
+	redactKindByID       = "by-id"
+	redactKindPool       = "pool"
+	redactKindController = "controller"
+	redactKindSASAddress = "sas"
+)
+
+var (
+	// controllerSerialRegex 匹配控制器描述中的序列号，如 "(SN: SP81928512)"
+	controllerSerialRegex = regexp.MustCompile(`SN:\s*([^\s)]+)`)
+	// sasAddressRegex 匹配16位十六进制的SAS地址(NAA 5)
+	sasAddressRegex = regexp.MustCompile(`\b(?:0x)?5[0-9a-fA-F]{15}\b`)
+)
+
+// Redactor 将报告中的序列号、WWN、SAS地址等敏感标识替换为稳定的化名，便于公开分享报告
+// 这是收集完成后对 DiskData/ControllerData 的原地变换，状态和计数不受影响
+type Redactor struct {
+	RedactPools bool // 是否同时替换存储池名称
+
+	pseudonyms map[string]map[string]string // 类别 -> 原始值 -> 化名
+}
+
+// NewRedactor 创建一个新的脱敏器
+func NewRedactor(redactPools bool) *Redactor {
+	return &Redactor{
+		RedactPools: redactPools,
+		pseudonyms:  make(map[string]map[string]string),
+	}
+}
+
+// pseudonym 返回原始值对应的化名，如 disk1、wwn2；空值保持为空
+func (r *Redactor) pseudonym(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	names, ok := r.pseudonyms[kind]
+	if !ok {
+		names = make(map[string]string)
+		r.pseudonyms[kind] = names
+	}
+	if name, ok := names[value]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf("%s%d", kind, len(names)+1)
+	names[value] = name
+	return name
+}
+
+// RedactDiskData 替换所有磁盘的序列号、WWN和by-id名称，按需替换存储池名称
+// 按磁盘名称顺序分配化名，使同一份数据每次脱敏的结果一致
+func (r *Redactor) RedactDiskData(dd *DiskData) {
+	if dd == nil {
+		return
+	}
+
+	disks := make([]*Disk, len(dd.Disks))
+	copy(disks, dd.Disks)
+	sort.Slice(disks, func(i, j int) bool {
+		return disks[i].Name < disks[j].Name
+	})
+
+	for _, disk := range disks {
+		disk.Serial = r.pseudonym(redactKindSerial, disk.Serial)
+		disk.WWN = r.pseudonym(redactKindWWN, disk.WWN)
+		// by-id名称包含型号和序列号
+		disk.ByID = r.pseudonym(redactKindByID, disk.ByID)
+		disk.StatusReason = r.redactText(disk.StatusReason)
+
+		if r.RedactPools && disk.Pool != "" && disk.Pool != PoolUnassigned {
+			disk.Pool = r.pseudonym(redactKindPool, disk.Pool)
+		}
+		if r.RedactPools && !isUnassignedPool(disk.PreviousPool) {
+			disk.PreviousPool = r.pseudonym(redactKindPool, disk.PreviousPool)
+		}
+	}
+
+	// 事件中的序列号与磁盘使用相同的化名
+	for i := range dd.Events {
+		dd.Events[i].Serial = r.pseudonym(redactKindSerial, dd.Events[i].Serial)
+	}
+
+	if r.RedactPools {
+		poolStatus := make(map[string]string, len(dd.PoolStatus))
+		names := make([]string, 0, len(dd.PoolStatus))
+		for name := range dd.PoolStatus {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			poolStatus[r.pseudonym(redactKindPool, name)] = dd.PoolStatus[name]
+		}
+		dd.PoolStatus = poolStatus
+
+		poolUsage := make(map[string]float64, len(dd.PoolUsage))
+		for name, usage := range dd.PoolUsage {
+			poolUsage[r.pseudonym(redactKindPool, name)] = usage
+		}
+		dd.PoolUsage = poolUsage
+	}
+}
+
+// RedactControllerData 替换控制器描述中的序列号和SAS地址
+func (r *Redactor) RedactControllerData(cd *ControllerData) {
+	if cd == nil {
+		return
+	}
+
+	for _, controller := range cd.GetSortedLSIControllers() {
+		controller.Description = r.redactText(controller.Description)
+	}
+	for _, controller := range cd.GetSortedNVMeControllers() {
+		controller.Description = r.redactText(controller.Description)
+	}
+}
+
+// redactText 替换自由文本中出现的控制器序列号和SAS地址
+func (r *Redactor) redactText(text string) string {
+	text = controllerSerialRegex.ReplaceAllStringFunc(text, func(match string) string {
+		serial := controllerSerialRegex.FindStringSubmatch(match)[1]
+		return "SN: " + r.pseudonym(redactKindController, serial)
+	})
+	return sasAddressRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return r.pseudonym(redactKindSASAddress, match)
+	})
+}
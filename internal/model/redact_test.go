@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactor_RedactDiskData(t *testing.T) {
+	diskData := NewDiskData()
+
+	sdb := NewDisk("sdb", "HDD", "SEAGATE ST600MM0006", "600G")
+	sdb.Serial = "S0M1AB2C"
+	sdb.WWN = "5000c500a1b2c3d4"
+	sdb.Pool = "tank"
+	sdb.SMARTData["Smart_Status"] = "PASSED"
+	sdb.SMARTData["Uncorrected_Errors"] = "3"
+	sdb.UpdateStatus()
+	diskData.AddDisk(sdb)
+
+	sda := NewDisk("sda", "SSD", "Samsung SSD 870 EVO", "1 TB")
+	sda.Serial = "S6PNNX0R123456"
+	sda.WWN = "5002538e40a1b2c3"
+	sda.Pool = "tank"
+	sda.SMARTData["Smart_Status"] = "PASSED"
+	sda.UpdateStatus()
+	diskData.AddDisk(sda)
+
+	spare := NewDisk("sdc", "HDD", "WDC WD40EFRX", "4T")
+	spare.Pool = PoolUnassigned
+	spare.SMARTData["Smart_Status"] = "PASSED"
+	spare.UpdateStatus()
+	diskData.AddDisk(spare)
+
+	diskData.PoolStatus["tank"] = "ONLINE"
+
+	warningsBefore := diskData.GetWarningCount()
+	countBefore := diskData.GetDiskCount()
+
+	NewRedactor(true).RedactDiskData(diskData)
+
+	// 化名按磁盘名称顺序分配
+	if sda.Serial != "disk1" || sdb.Serial != "disk2" {
+		t.Errorf("Expected serials disk1/disk2, got %s/%s", sda.Serial, sdb.Serial)
+	}
+	if sda.WWN != "wwn1" || sdb.WWN != "wwn2" {
+		t.Errorf("Expected WWNs wwn1/wwn2, got %s/%s", sda.WWN, sdb.WWN)
+	}
+	if spare.Serial != "" {
+		t.Errorf("Expected empty serial to stay empty, got %s", spare.Serial)
+	}
+
+	// 同一存储池映射到同一化名，未分配的磁盘保持不变
+	if sda.Pool != "pool1" || sdb.Pool != "pool1" {
+		t.Errorf("Expected both disks in pool1, got %s/%s", sda.Pool, sdb.Pool)
+	}
+	if spare.Pool != PoolUnassigned {
+		t.Errorf("Expected unassigned pool to be kept, got %s", spare.Pool)
+	}
+	if diskData.PoolStatus["pool1"] != "ONLINE" || len(diskData.PoolStatus) != 1 {
+		t.Errorf("Expected pool status keyed by pseudonym, got %v", diskData.PoolStatus)
+	}
+
+	// 状态和计数不受影响
+	if diskData.GetDiskCount() != countBefore || diskData.GetWarningCount() != warningsBefore {
+		t.Errorf("Expected counts to be preserved, got %d disks and %d warnings",
+			diskData.GetDiskCount(), diskData.GetWarningCount())
+	}
+	if sdb.GetStatus() != DiskStatusWarning {
+		t.Errorf("Expected sdb status %s, got %s", DiskStatusWarning, sdb.GetStatus())
+	}
+}
+
+func TestRedactor_KeepPoolsByDefault(t *testing.T) {
+	diskData := NewDiskData()
+	disk := NewDisk("sda", "SSD", "Samsung SSD 870 EVO", "1 TB")
+	disk.Serial = "S6PNNX0R123456"
+	disk.Pool = "tank"
+	diskData.AddDisk(disk)
+
+	NewRedactor(false).RedactDiskData(diskData)
+
+	if disk.Serial != "disk1" || disk.Pool != "tank" {
+		t.Errorf("Expected serial masked and pool kept, got %s/%s", disk.Serial, disk.Pool)
+	}
+}
+
+func TestRedactor_RedactControllerData(t *testing.T) {
+	controllerData := NewControllerData()
+	controller := controllerData.GetLSIController("LSI_Controller_0")
+	controller.Description = "LSI SAS HBA Controller (via storcli) (SN: SP81928512) SAS 500605b00f0c1a20"
+
+	NewRedactor(false).RedactControllerData(controllerData)
+
+	if strings.Contains(controller.Description, "SP81928512") || strings.Contains(controller.Description, "500605b00f0c1a20") {
+		t.Errorf("Expected controller identifiers to be masked, got %q", controller.Description)
+	}
+	if controller.Description != "LSI SAS HBA Controller (via storcli) (SN: controller1) SAS sas1" {
+		t.Errorf("Unexpected redacted description: %q", controller.Description)
+	}
+}
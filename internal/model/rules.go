@@ -0,0 +1,189 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 规则支持的比较运算符
+const (
+	ComparatorGreater      = ">"
+	ComparatorGreaterEqual = ">="
+	ComparatorLess         = "<"
+	ComparatorLessEqual    = "<="
+	ComparatorEqual        = "=="
+	ComparatorNotEqual     = "!="
+)
+
+// RuleValue 规则阈值，JSON中既可以写数字也可以写字符串
+type RuleValue string
+
+// UnmarshalJSON 解析数字或字符串形式的阈值
+func (v *RuleValue) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*v = RuleValue(str)
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return fmt.Errorf("阈值必须是数字或字符串: %s", string(data))
+	}
+	*v = RuleValue(num.String())
+	return nil
+}
+
+// StatusRule 状态升级规则: 当属性满足比较条件时，磁盘状态升级为指定状态
+type StatusRule struct {
+	Attribute  string     `json:"attribute"`  // SMART属性名，如 Non_Medium_Errors
+	Comparator string     `json:"comparator"` // 比较运算符(>, >=, <, <=, ==, !=)
+	Threshold  RuleValue  `json:"threshold"`  // 阈值
+	Status     DiskStatus `json:"status"`     // 命中后的状态(PASSED, WARNING, FAILED)
+}
+
+// Validate 验证规则是否有效
+func (r StatusRule) Validate() error {
+	if r.Attribute == "" {
+		return fmt.Errorf("规则缺少属性名")
+	}
+
+	switch r.Comparator {
+	case ComparatorGreater, ComparatorGreaterEqual, ComparatorLess, ComparatorLessEqual:
+		if _, err := strconv.ParseFloat(string(r.Threshold), 64); err != nil {
+			return fmt.Errorf("规则 %s %s 的阈值必须是数字: %s", r.Attribute, r.Comparator, r.Threshold)
+		}
+	case ComparatorEqual, ComparatorNotEqual:
+		// 数字和字符串都可以比较
+	default:
+		return fmt.Errorf("规则 %s 使用了不支持的比较运算符: %s", r.Attribute, r.Comparator)
+	}
+
+	switch r.Status {
+	case DiskStatusOK, DiskStatusWarning, DiskStatusError:
+		// 有效的状态
+	default:
+		return fmt.Errorf("规则 %s 使用了不支持的状态: %s", r.Attribute, r.Status)
+	}
+
+	return nil
+}
+
+// Matches 检查磁盘是否命中规则，缺少该属性的磁盘不命中
+func (r StatusRule) Matches(disk *Disk) bool {
+	value, ok := disk.SMARTData[r.Attribute]
+	if !ok || value == "" {
+		return false
+	}
+
+	threshold := string(r.Threshold)
+	actual, actualErr := strconv.ParseFloat(value, 64)
+	expected, expectedErr := strconv.ParseFloat(threshold, 64)
+	numeric := actualErr == nil && expectedErr == nil
+
+	switch r.Comparator {
+	case ComparatorGreater:
+		return numeric && actual > expected
+	case ComparatorGreaterEqual:
+		return numeric && actual >= expected
+	case ComparatorLess:
+		return numeric && actual < expected
+	case ComparatorLessEqual:
+		return numeric && actual <= expected
+	case ComparatorEqual:
+		if numeric {
+			return actual == expected
+		}
+		return strings.EqualFold(value, threshold)
+	case ComparatorNotEqual:
+		if numeric {
+			return actual != expected
+		}
+		return !strings.EqualFold(value, threshold)
+	}
+
+	return false
+}
+
+// Severity 获取状态的严重程度，数值越大越严重
+func (s DiskStatus) Severity() int {
+	switch s {
+	case DiskStatusOK:
+		return 1
+	case DiskStatusWarning:
+		return 2
+	case DiskStatusError:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// MoreSevere 返回两个状态中更严重的一个
+func MoreSevere(a, b DiskStatus) DiskStatus {
+	if b.Severity() > a.Severity() {
+		return b
+	}
+	return a
+}
+
+// WorstStatus 返回一组磁盘中最严重的状态，没有磁盘时返回DiskStatusUnknown
+func WorstStatus(disks []*Disk) DiskStatus {
+	status := DiskStatusUnknown
+	for _, disk := range disks {
+		status = MoreSevere(status, disk.GetStatus())
+	}
+	return status
+}
+
+// EvaluateStatusRules 按顺序评估规则，返回命中规则中最严重的状态
+func EvaluateStatusRules(disk *Disk, rules []StatusRule) (DiskStatus, bool) {
+	status := DiskStatusUnknown
+	matched := false
+	for _, rule := range rules {
+		if rule.Matches(disk) {
+			status = MoreSevere(status, rule.Status)
+			matched = true
+		}
+	}
+	return status, matched
+}
+
+// String 返回规则的可读描述
+func (r StatusRule) String() string {
+	return fmt.Sprintf("%s %s %s", r.Attribute, r.Comparator, r.Threshold)
+}
+
+// ApplyStatusRules 评估规则并将磁盘状态升级为更严重的状态
+func (d *Disk) ApplyStatusRules(rules []StatusRule) {
+	for _, rule := range rules {
+		if rule.Matches(d) {
+			d.Escalate(rule.Status, fmt.Sprintf("%s: %s", StatusSourceRule, rule))
+		}
+	}
+}
+
+// LoadStatusRules 从JSON文件加载状态升级规则
+func LoadStatusRules(path string) ([]StatusRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取规则文件失败: %w", err)
+	}
+
+	var rules []StatusRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析规则文件失败: %w", err)
+	}
+
+	for i := range rules {
+		rules[i].Status = DiskStatus(strings.ToUpper(string(rules[i].Status)))
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatusRules_CustomRule(t *testing.T) {
+	rules := []StatusRule{
+		{Attribute: "Non_Medium_Errors", Comparator: ComparatorGreater, Threshold: "100", Status: DiskStatusWarning},
+		{Attribute: "Percentage_Used", Comparator: ComparatorGreaterEqual, Threshold: "90", Status: DiskStatusError},
+	}
+
+	tests := []struct {
+		name      string
+		smartData SMARTData
+		expected  DiskStatus
+	}{
+		{
+			name:      "below threshold",
+			smartData: SMARTData{"Smart_Status": "PASSED", "Non_Medium_Errors": "12"},
+			expected:  DiskStatusOK,
+		},
+		{
+			name:      "non-medium errors escalate to warning",
+			smartData: SMARTData{"Smart_Status": "PASSED", "Non_Medium_Errors": "250"},
+			expected:  DiskStatusWarning,
+		},
+		{
+			name:      "most severe match wins",
+			smartData: SMARTData{"Smart_Status": "PASSED", "Non_Medium_Errors": "250", "Percentage_Used": "95"},
+			expected:  DiskStatusError,
+		},
+		{
+			name:      "rules never downgrade status",
+			smartData: SMARTData{"Smart_Status": "FAILED", "Non_Medium_Errors": "250"},
+			expected:  DiskStatusError,
+		},
+		{
+			name:      "missing attribute does not match",
+			smartData: SMARTData{"Smart_Status": "PASSED"},
+			expected:  DiskStatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disk := NewDisk("sda", "HDD", "TEST", "1T")
+			disk.SMARTData = tt.smartData
+			disk.UpdateStatus()
+			disk.ApplyStatusRules(rules)
+
+			if disk.Status != tt.expected {
+				t.Errorf("Expected status %s, got %s", tt.expected, disk.Status)
+			}
+		})
+	}
+}
+
+func TestLoadStatusRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	content := `[
+		{"attribute": "Non_Medium_Errors", "comparator": ">", "threshold": 100, "status": "warning"},
+		{"attribute": "Smart_Status", "comparator": "==", "threshold": "UNKNOWN", "status": "FAILED"}
+	]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write rules file: %v", err)
+	}
+
+	rules, err := LoadStatusRules(path)
+	if err != nil {
+		t.Fatalf("LoadStatusRules failed: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if rules[0].Threshold != "100" || rules[0].Status != DiskStatusWarning {
+		t.Errorf("Unexpected first rule: %+v", rules[0])
+	}
+
+	// 无效的比较运算符应返回错误
+	if err := os.WriteFile(path, []byte(`[{"attribute": "Temperature", "comparator": "~", "threshold": 1, "status": "WARNING"}]`), 0644); err != nil {
+		t.Fatalf("Failed to write rules file: %v", err)
+	}
+	if _, err := LoadStatusRules(path); err == nil {
+		t.Error("Expected error for invalid comparator")
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 磁盘扇区格式
+const (
+	SectorFormat512n = "512n" // 逻辑和物理扇区均为512字节
+	SectorFormat512e = "512e" // 4K物理扇区模拟512字节逻辑扇区
+	SectorFormat4Kn  = "4Kn"  // 逻辑和物理扇区均为4096字节
+)
+
+// GetSectorFormat 根据逻辑/物理扇区大小判断扇区格式，未知时返回空字符串
+func (d *Disk) GetSectorFormat() string {
+	logical, physical := d.LogicalSectorSize, d.PhysicalSectorSize
+	if physical == 0 {
+		physical = logical
+	}
+	switch {
+	case logical == 512 && physical == 512:
+		return SectorFormat512n
+	case logical == 512 && physical == 4096:
+		return SectorFormat512e
+	case logical == 4096 && physical == 4096:
+		return SectorFormat4Kn
+	case logical > 0:
+		return fmt.Sprintf("%d/%d", logical, physical)
+	default:
+		return ""
+	}
+}
+
+// GetDisplaySectorSize 获取可显示的扇区格式，未知时显示 "N/A"
+func (d *Disk) GetDisplaySectorSize() string {
+	if format := d.GetSectorFormat(); format != "" {
+		return format
+	}
+	if d.IsVirtual() {
+		return NotApplicable
+	}
+	return "N/A"
+}
+
+// HasMixedSectors 检查存储池成员是否使用了不同的逻辑扇区大小(如512e与4Kn混用)
+func (ps *PoolSummary) HasMixedSectors() bool {
+	return len(ps.LogicalSectorSizes) > 1
+}
+
+// GetDisplaySectorSizes 获取可显示的逻辑扇区大小列表，如 "512B, 4096B"
+func (ps *PoolSummary) GetDisplaySectorSizes() string {
+	if len(ps.LogicalSectorSizes) == 0 {
+		return "N/A"
+	}
+	sizes := make([]string, 0, len(ps.LogicalSectorSizes))
+	for _, size := range ps.LogicalSectorSizes {
+		sizes = append(sizes, fmt.Sprintf("%dB", size))
+	}
+	return strings.Join(sizes, ", ")
+}
+
+// GetMixedSectorPools 获取混用不同逻辑扇区大小的存储池汇总
+func (dd *DiskData) GetMixedSectorPools() []*PoolSummary {
+	var pools []*PoolSummary
+	for _, summary := range dd.GetPoolSummaries() {
+		if summary.HasMixedSectors() {
+			pools = append(pools, summary)
+		}
+	}
+	return pools
+}
+
+// addSectorSize 记录成员磁盘的逻辑扇区大小，保持去重和升序
+func (ps *PoolSummary) addSectorSize(size int) {
+	if size <= 0 {
+		return
+	}
+	for _, existing := range ps.LogicalSectorSizes {
+		if existing == size {
+			return
+		}
+	}
+	ps.LogicalSectorSizes = append(ps.LogicalSectorSizes, size)
+	sort.Ints(ps.LogicalSectorSizes)
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TemperatureSensorPrefix NVMe温度传感器属性名前缀(Temperature_Sensor_1, Temperature_Sensor_2...)
+const TemperatureSensorPrefix = "Temperature_Sensor_"
+
+// TrueNAS中配置的磁盘温度告警阈值(°C，--use-truenas-thresholds)，设置后代替NVMe报告的警告温度
+const (
+	TrueNASInformationalTempAttribute = "TrueNAS_Informational_Temperature" // 达到时标记为警告
+	TrueNASCriticalTempAttribute      = "TrueNAS_Critical_Temperature"      // 达到时标记为错误
+)
+
+// 磁盘在整个生命周期内记录的最低和最高温度(°C)
+// SATA来自Temperature_Celsius的(Min/Max a/b)，SCSI/SAS来自温度日志页
+const (
+	LifetimeMinTempAttribute = "Lifetime_Min_Temperature"
+	LifetimeMaxTempAttribute = "Lifetime_Max_Temperature"
+)
+
+// SensorTemperature 单个温度传感器的读数
+type SensorTemperature struct {
+	Sensor      int // 传感器编号
+	Temperature int // 温度(°C)
+}
+
+// GetSensorTemperatures 获取各温度传感器的读数，按传感器编号排序
+func (d *Disk) GetSensorTemperatures() []SensorTemperature {
+	var sensors []SensorTemperature
+	for name, value := range d.SMARTData {
+		if !strings.HasPrefix(name, TemperatureSensorPrefix) {
+			continue
+		}
+		sensor, err := strconv.Atoi(strings.TrimPrefix(name, TemperatureSensorPrefix))
+		if err != nil {
+			continue
+		}
+		temp, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		sensors = append(sensors, SensorTemperature{Sensor: sensor, Temperature: temp})
+	}
+
+	sort.Slice(sensors, func(i, j int) bool {
+		return sensors[i].Sensor < sensors[j].Sensor
+	})
+	return sensors
+}
+
+// GetMaxTemperature 获取综合温度和各传感器温度中的最高值
+// source为最高温度的来源(综合温度或"传感器N")，没有温度数据时ok为false
+func (d *Disk) GetMaxTemperature() (temp int, source string, ok bool) {
+	if composite, err := strconv.Atoi(d.SMARTData["Temperature"]); err == nil {
+		temp, source, ok = composite, "综合温度", true
+	}
+	for _, sensor := range d.GetSensorTemperatures() {
+		if !ok || sensor.Temperature > temp {
+			temp, source, ok = sensor.Temperature, fmt.Sprintf("传感器%d", sensor.Sensor), true
+		}
+	}
+	return temp, source, ok
+}
+
+// temperatureWarning 根据最高温度判断磁盘状态和原因，未达到阈值时返回DiskStatusOK
+// 有TrueNAS配置的阈值时使用TrueNAS的阈值，否则使用NVMe报告的警告温度
+func (d *Disk) temperatureWarning() (DiskStatus, string) {
+	temp, source, ok := d.GetMaxTemperature()
+	if !ok {
+		return DiskStatusOK, ""
+	}
+
+	critical := positiveInt(d.SMARTData[TrueNASCriticalTempAttribute])
+	informational := positiveInt(d.SMARTData[TrueNASInformationalTempAttribute])
+	if critical > 0 || informational > 0 {
+		if critical > 0 && temp >= critical {
+			return DiskStatusError, fmt.Sprintf("%s: %s %d°C 达到TrueNAS临界温度 %d°C", StatusSourceHeuristic, source, temp, critical)
+		}
+		if informational > 0 && temp >= informational {
+			return DiskStatusWarning, fmt.Sprintf("%s: %s %d°C 达到TrueNAS告警温度 %d°C", StatusSourceHeuristic, source, temp, informational)
+		}
+		return DiskStatusOK, ""
+	}
+
+	threshold := positiveInt(d.SMARTData["Warning_Temperature"])
+	if threshold == 0 || temp < threshold {
+		return DiskStatusOK, ""
+	}
+	return DiskStatusWarning, fmt.Sprintf("%s: %s %d°C 达到警告温度 %d°C", StatusSourceHeuristic, source, temp, threshold)
+}
+
+// lifetimeTemperatureWarning 历史最高温度达到临界温度时返回警告
+// 当前温度正常时也会提示，说明磁盘过去可能经历过散热故障
+func (d *Disk) lifetimeTemperatureWarning() (DiskStatus, string) {
+	lifetimeMax := positiveInt(d.SMARTData[LifetimeMaxTempAttribute])
+	if lifetimeMax == 0 {
+		return DiskStatusOK, ""
+	}
+	_, critical := d.GetTemperatureThresholds()
+	if lifetimeMax < critical {
+		return DiskStatusOK, ""
+	}
+	return DiskStatusWarning, fmt.Sprintf("%s: 历史最高温度 %d°C 曾达到临界温度 %d°C，可能发生过散热故障", StatusSourceHeuristic, lifetimeMax, critical)
+}
+
+// 磁盘没有报告温度阈值时使用的默认警告和临界温度(°C)
+const (
+	DefaultDiskWarnTemp = 50
+	DefaultDiskCritTemp = 60
+)
+
+// GetTemperatureThresholds 获取磁盘的警告和临界温度(°C)
+// 依次使用TrueNAS配置的阈值、NVMe报告的警告/临界温度和SAS的Drive Trip温度，都没有时使用默认值
+func (d *Disk) GetTemperatureThresholds() (warn, crit int) {
+	warn = firstPositive(d.SMARTData[TrueNASInformationalTempAttribute], d.SMARTData["Warning_Temperature"])
+	crit = firstPositive(d.SMARTData[TrueNASCriticalTempAttribute], d.SMARTData["Critical_Temperature"],
+		d.SMARTData["Trip_Temperature"])
+	if warn == 0 {
+		warn = DefaultDiskWarnTemp
+	}
+	if crit == 0 {
+		crit = DefaultDiskCritTemp
+	}
+	if warn > crit {
+		warn = crit
+	}
+	return warn, crit
+}
+
+// firstPositive 返回第一个可解析为正整数的属性值，都不是时返回0
+func firstPositive(values ...string) int {
+	for _, value := range values {
+		if number := positiveInt(value); number > 0 {
+			return number
+		}
+	}
+	return 0
+}
+
+// positiveInt 将属性值解析为正整数，无法解析或不是正数时返回0
+func positiveInt(value string) int {
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0
+	}
+	return number
+}
+
+// GetDisplaySensorTemperatures 获取可显示的各传感器温度，如 "S1 45°C, S2 61°C"
+func (d *Disk) GetDisplaySensorTemperatures() string {
+	sensors := d.GetSensorTemperatures()
+	if len(sensors) == 0 {
+		return "N/A"
+	}
+
+	parts := make([]string, 0, len(sensors))
+	for _, sensor := range sensors {
+		parts = append(parts, fmt.Sprintf("S%d %d°C", sensor.Sensor, sensor.Temperature))
+	}
+	return strings.Join(parts, ", ")
+}
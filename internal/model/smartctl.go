@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SmartctlExitStatusAttribute smartctl -a的非零退出码(状态位掩码)
+const SmartctlExitStatusAttribute = "Smartctl_Exit_Status"
+
+// SmartSupportAttribute smartctl -i报告的SMART启用状态，只在SMART被禁用时记录
+const (
+	SmartSupportAttribute = "Smart_Support"
+	SmartSupportDisabled  = "Disabled"
+)
+
+// SMARTDetail 定义每个磁盘收集SMART详情的方式(--smart-detail)
+type SMARTDetail string
+
+const (
+	// SMARTDetailFull 执行smartctl -a，包含设备信息、属性和各类日志
+	SMARTDetailFull SMARTDetail = "full"
+	// SMARTDetailMinimal 只执行smartctl -A读取属性，在较慢的设备上更快，
+	// 但没有设备信息和日志部分(如TRIM支持、SAS错误计数日志)
+	SMARTDetailMinimal SMARTDetail = "minimal"
+)
+
+// ParseSMARTDetail 解析SMART详情收集方式名称
+func ParseSMARTDetail(name string) (SMARTDetail, error) {
+	switch SMARTDetail(strings.ToLower(name)) {
+	case SMARTDetailFull:
+		return SMARTDetailFull, nil
+	case SMARTDetailMinimal:
+		return SMARTDetailMinimal, nil
+	default:
+		return "", fmt.Errorf("不支持的SMART详情收集方式: %s (可选 minimal, full)", name)
+	}
+}
+
+// smartctl退出码各位的含义(见smartctl(8) RETURN VALUES)
+const (
+	SmartctlBitCommandLine    = 1 << 0 // 命令行参数错误
+	SmartctlBitDeviceOpen     = 1 << 1 // 无法打开设备或设备处于低功耗模式
+	SmartctlBitCommandFailed  = 1 << 2 // 部分SMART或ATA命令失败
+	SmartctlBitDiskFailing    = 1 << 3 // SMART状态为DISK FAILING
+	SmartctlBitPrefailFailing = 1 << 4 // 预失效属性已低于阈值
+	SmartctlBitPastFailing    = 1 << 5 // 属性曾经低于阈值
+	SmartctlBitErrorLog       = 1 << 6 // 错误日志中有记录
+	SmartctlBitSelfTestLog    = 1 << 7 // 自检日志中有错误记录
+)
+
+// SmartctlFatalBits 输出不可用的退出码位，其他位出现时输出仍然有效
+const SmartctlFatalBits = SmartctlBitCommandLine | SmartctlBitDeviceOpen
+
+// smartctlBitWarnings 表示磁盘问题的退出码位及说明，按位从低到高排列
+// 错误日志(位6)不在其中：ATA错误日志中的记录永远不会清除，一次旧的错误就会让磁盘永久处于警告状态
+var smartctlBitWarnings = []struct {
+	bit         int
+	status      DiskStatus
+	description string
+}{
+	{SmartctlBitDiskFailing, DiskStatusError, "SMART状态为DISK FAILING"},
+	{SmartctlBitPrefailFailing, DiskStatusError, "预失效属性低于阈值"},
+	{SmartctlBitPastFailing, DiskStatusWarning, "属性曾经低于阈值"},
+	{SmartctlBitSelfTestLog, DiskStatusWarning, "自检日志中有错误记录"},
+}
+
+// smartctlExitWarning 根据smartctl退出码判断磁盘状态和原因，没有问题时返回DiskStatusOK
+// 只有命令失败(位2)或错误日志(位6)时不升级状态，前者数据可能不完整但不代表磁盘有问题
+func (d *Disk) smartctlExitWarning() (DiskStatus, string) {
+	code, err := strconv.Atoi(d.SMARTData[SmartctlExitStatusAttribute])
+	if err != nil || code <= 0 {
+		return DiskStatusOK, ""
+	}
+
+	status := DiskStatusOK
+	var problems []string
+	for _, warning := range smartctlBitWarnings {
+		if code&warning.bit != 0 {
+			status = MoreSevere(status, warning.status)
+			problems = append(problems, warning.description)
+		}
+	}
+	if len(problems) == 0 {
+		return DiskStatusOK, ""
+	}
+
+	return status, fmt.Sprintf("%s: smartctl退出码 %d (%s)", StatusSourceSelfReported, code, strings.Join(problems, ", "))
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+)
+
+// DiskSortKey 磁盘排序字段
+type DiskSortKey string
+
+const (
+	// SortByName 按设备名称排序
+	SortByName DiskSortKey = "name"
+	// SortByStatus 按状态严重程度排序
+	SortByStatus DiskSortKey = "status"
+	// SortByTemperature 按温度排序，没有温度数据的磁盘视为最低
+	SortByTemperature DiskSortKey = "temperature"
+	// SortByPool 按存储池名称排序
+	SortByPool DiskSortKey = "pool"
+	// SortByWear 按SSD已用寿命(Percentage_Used)排序，没有数据的磁盘视为最低
+	SortByWear DiskSortKey = "wear"
+)
+
+// SortedDisks 返回按指定字段排序的磁盘副本，不修改原列表
+// 字段相同时始终按名称升序排列，保证结果稳定
+func (dd *DiskData) SortedDisks(key DiskSortKey, descending bool) []*Disk {
+	disks := make([]*Disk, len(dd.Disks))
+	copy(disks, dd.Disks)
+
+	sort.SliceStable(disks, func(i, j int) bool {
+		cmp := compareDisks(disks[i], disks[j], key)
+		if cmp == 0 {
+			return disks[i].Name < disks[j].Name
+		}
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	return disks
+}
+
+// compareDisks 按指定字段比较两个磁盘，返回 -1、0 或 1
+func compareDisks(a, b *Disk, key DiskSortKey) int {
+	switch key {
+	case SortByStatus:
+		return cmp.Compare(a.GetStatus().Severity(), b.GetStatus().Severity())
+	case SortByTemperature:
+		return cmp.Compare(diskTemperature(a), diskTemperature(b))
+	case SortByPool:
+		return cmp.Compare(a.Pool, b.Pool)
+	case SortByWear:
+		return cmp.Compare(diskWear(a), diskWear(b))
+	default:
+		return cmp.Compare(a.Name, b.Name)
+	}
+}
+
+// HottestDisks 返回温度最高的n个磁盘(从高到低)，没有温度数据的磁盘不参与排名
+func (dd *DiskData) HottestDisks(n int) []*Disk {
+	return topDisks(dd.SortedDisks(SortByTemperature, true), n, func(disk *Disk) bool {
+		return diskTemperature(disk) >= 0
+	})
+}
+
+// MostWornSSDs 返回已用寿命最高的n个固态硬盘(从高到低)，没有寿命数据的磁盘不参与排名
+func (dd *DiskData) MostWornSSDs(n int) []*Disk {
+	return topDisks(dd.SortedDisks(SortByWear, true), n, func(disk *Disk) bool {
+		isSSD := disk.Type == DiskTypeSASSSD || disk.Type == DiskTypeNVMESSD
+		return isSSD && diskWear(disk) >= 0
+	})
+}
+
+// topDisks 从已排序的列表中取出前n个满足条件的磁盘
+func topDisks(sorted []*Disk, n int, include func(*Disk) bool) []*Disk {
+	var result []*Disk
+	for _, disk := range sorted {
+		if len(result) >= n {
+			break
+		}
+		if include(disk) {
+			result = append(result, disk)
+		}
+	}
+	return result
+}
+
+// diskWear 获取SSD已用寿命百分比，没有数据时返回 -1
+func diskWear(disk *Disk) int {
+	wear, err := strconv.Atoi(disk.SMARTData["Percentage_Used"])
+	if err != nil {
+		return -1
+	}
+	return wear
+}
+
+// diskTemperature 获取磁盘温度，没有温度数据时返回 -1
+func diskTemperature(disk *Disk) int {
+	temp, err := strconv.Atoi(disk.SMARTData["Temperature"])
+	if err != nil {
+		return -1
+	}
+	return temp
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newSortTestData 创建温度和寿命各不相同的测试磁盘
+func newSortTestData() *DiskData {
+	dd := NewDiskData()
+	add := func(name, diskType, diskModel, temp, wear string) {
+		disk := NewDisk(name, diskType, diskModel, "1 TB")
+		if temp != "" {
+			disk.SMARTData["Temperature"] = temp
+		}
+		if wear != "" {
+			disk.SMARTData["Percentage_Used"] = wear
+		}
+		dd.AddDisk(disk)
+	}
+	add("sda", "HDD", "SEAGATE ST4000NM", "38", "")
+	add("sdb", "SSD", "Samsung SSD 870 EVO", "45", "12")
+	add("sdc", "HDD", "SEAGATE ST4000NM", "", "")
+	add("sdd", "SSD", "Samsung SSD 870 EVO", "45", "3")
+	add("nvme0n1", "NVMe", "Samsung SSD 980 PRO", "52", "27")
+	add("sde", "HDD", "SEAGATE ST4000NM", "41", "")
+	return dd
+}
+
+// diskNames 提取磁盘名称
+func diskNames(disks []*Disk) []string {
+	names := make([]string, 0, len(disks))
+	for _, disk := range disks {
+		names = append(names, disk.Name)
+	}
+	return names
+}
+
+func TestDiskData_HottestDisks(t *testing.T) {
+	dd := newSortTestData()
+
+	testCases := []struct {
+		n        int
+		expected []string
+	}{
+		{0, []string{}},
+		{1, []string{"nvme0n1"}},
+		// 温度相同时按名称排序
+		{3, []string{"nvme0n1", "sdb", "sdd"}},
+		// 没有温度数据的磁盘不参与排名
+		{10, []string{"nvme0n1", "sdb", "sdd", "sde", "sda"}},
+	}
+
+	for _, tc := range testCases {
+		got := diskNames(dd.HottestDisks(tc.n))
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("HottestDisks(%d): expected %v, got %v", tc.n, tc.expected, got)
+		}
+	}
+
+	// 原列表顺序不变
+	if got := diskNames(dd.Disks); got[0] != "sda" || got[len(got)-1] != "sde" {
+		t.Errorf("HottestDisks should not reorder the disk list, got %v", got)
+	}
+}
+
+func TestDiskData_MostWornSSDs(t *testing.T) {
+	dd := newSortTestData()
+
+	got := diskNames(dd.MostWornSSDs(2))
+	if expected := []string{"nvme0n1", "sdb"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("MostWornSSDs(2): expected %v, got %v", expected, got)
+	}
+
+	// 机械硬盘即使有寿命字段也不参与排名
+	dd.Disks[0].SMARTData["Percentage_Used"] = "99"
+	got = diskNames(dd.MostWornSSDs(5))
+	if expected := []string{"nvme0n1", "sdb", "sdd"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("MostWornSSDs(5): expected %v, got %v", expected, got)
+	}
+}
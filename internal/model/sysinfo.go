@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DataSourceUname 主机信息来自hostname、/etc/version或uname和/proc/uptime(midclt不可用时)
+const DataSourceUname = "uname"
+
+// SystemInfo 报告头部显示的主机信息(--system-info)
+type SystemInfo struct {
+	Hostname      string // 主机名
+	OSVersion     string // TrueNAS或操作系统版本
+	UptimeSeconds int64  // 运行时间(秒)，未知时为0
+	ToolVersion   string // 本工具的版本
+	Source        string // 信息来源(midclt, uname)
+}
+
+// GetDisplayUptime 获取可显示的运行时间，如 "12天3小时4分钟"
+func (s *SystemInfo) GetDisplayUptime() string {
+	if s.UptimeSeconds <= 0 {
+		return "N/A"
+	}
+
+	minutes := s.UptimeSeconds / 60
+	days, hours, minutes := minutes/(24*60), minutes/60%24, minutes%60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d天", days))
+	}
+	if days > 0 || hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d小时", hours))
+	}
+	parts = append(parts, fmt.Sprintf("%d分钟", minutes))
+	return strings.Join(parts, "")
+}
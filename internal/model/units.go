@@ -0,0 +1,145 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SizeUnits 定义容量单位制
+type SizeUnits string
+
+const (
+	// SizeUnitsBinary 二进制单位(1024进制)，使用 KiB/MiB/GiB/TiB 标签
+	SizeUnitsBinary SizeUnits = "binary"
+	// SizeUnitsDecimal 十进制单位(1000进制)，使用 KB/MB/GB/TB 标签，与硬盘标称容量一致
+	SizeUnitsDecimal SizeUnits = "decimal"
+)
+
+var (
+	binarySizeLabels  = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	decimalSizeLabels = []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	sizePattern       = regexp.MustCompile(`(\d+\.?\d*)\s*([KMGTP]i?B|B)`)
+	numberPattern     = regexp.MustCompile(`^\d[\d,]*(?:\.\d+)?`)
+)
+
+// NormalizeNumber 去掉计数值中的千位分隔符和末尾的单位，如 "20,662 h" -> "20662"
+// 不以数字开头的值原样返回
+func NormalizeNumber(value string) string {
+	value = strings.TrimSpace(value)
+	number := numberPattern.FindString(value)
+	if number == "" {
+		return value
+	}
+	return strings.ReplaceAll(number, ",", "")
+}
+
+// ParseSizeUnits 解析单位制名称
+func ParseSizeUnits(name string) (SizeUnits, error) {
+	switch SizeUnits(strings.ToLower(name)) {
+	case SizeUnitsBinary:
+		return SizeUnitsBinary, nil
+	case SizeUnitsDecimal:
+		return SizeUnitsDecimal, nil
+	default:
+		return "", fmt.Errorf("不支持的容量单位制: %s (可选 binary, decimal)", name)
+	}
+}
+
+// IsDecimal 检查是否为十进制单位制，未设置时默认为二进制
+func (u SizeUnits) IsDecimal() bool {
+	return u == SizeUnitsDecimal
+}
+
+// Base 获取单位换算基数
+func (u SizeUnits) Base() float64 {
+	if u.IsDecimal() {
+		return 1000
+	}
+	return 1024
+}
+
+// Labels 获取从B开始的单位标签
+func (u SizeUnits) Labels() []string {
+	if u.IsDecimal() {
+		return decimalSizeLabels
+	}
+	return binarySizeLabels
+}
+
+// Scale 将字节数换算为合适的单位，返回数值和单位标签
+func (u SizeUnits) Scale(bytes float64) (float64, string) {
+	labels := u.Labels()
+	base := u.Base()
+	index := 0
+	for bytes >= base && index < len(labels)-1 {
+		bytes /= base
+		index++
+	}
+	return bytes, labels[index]
+}
+
+// DefaultSizePrecision 容量显示默认保留的小数位数
+const DefaultSizePrecision = 2
+
+// MaxSizePrecision 容量显示允许的最大小数位数
+const MaxSizePrecision = 6
+
+// FormatSize 将字节数格式化为保留两位小数的字符串
+func (u SizeUnits) FormatSize(bytes float64) string {
+	return u.FormatSizePrecision(bytes, DefaultSizePrecision)
+}
+
+// FormatSizePrecision 将字节数格式化为保留指定小数位数的字符串
+func (u SizeUnits) FormatSizePrecision(bytes float64, precision int) string {
+	sign := ""
+	if bytes < 0 {
+		sign = "-"
+		bytes = math.Abs(bytes)
+	}
+	value, label := u.Scale(bytes)
+	return fmt.Sprintf("%s%.*f %s", sign, precision, value, label)
+}
+
+// ParseSize 将大小字符串解析为字节数
+// KiB/MiB等标签始终按1024换算；KB/MB等标签按当前单位制换算
+func (u SizeUnits) ParseSize(sizeStr string) (float64, error) {
+	match := sizePattern.FindStringSubmatch(sizeStr)
+	if len(match) != 3 {
+		return 0, fmt.Errorf("unable to parse size: %s", sizeStr)
+	}
+
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid numeric value: %s", match[1])
+	}
+
+	unit := match[2]
+	base := u.Base()
+	if strings.Contains(unit, "i") {
+		base = 1024
+	}
+
+	exponent := strings.Index("BKMGTP", unit[:1])
+	if exponent < 0 {
+		return 0, fmt.Errorf("unknown unit: %s", unit)
+	}
+
+	return value * math.Pow(base, float64(exponent)), nil
+}
+
+// SizeBytesSuffix 读写量字节数字段的后缀(如 "Data_Read_Bytes")
+// 字节数在解析smartctl输出时保留，与显示的单位制和小数位数无关
+const SizeBytesSuffix = "_Bytes"
+
+// SizeBytes 获取读写量的字节数，优先使用字节数字段，否则按指定单位制解析显示的字符串
+// 当前数据的标签与单位一致，使用SizeUnitsDecimal；早期版本的历史数据没有字节数字段，
+// 且KB/MB/GB/TB标签按1024换算，使用SizeUnitsBinary
+func SizeBytes(data map[string]string, key string, units SizeUnits) (float64, error) {
+	if raw := data[key+SizeBytesSuffix]; raw != "" {
+		return strconv.ParseFloat(raw, 64)
+	}
+	return units.ParseSize(data[key])
+}
@@ -0,0 +1,156 @@
+package model
+
+import "testing"
+
+func TestSizeUnits_ParseSize(t *testing.T) {
+	// 十进制 1 TB 与二进制 1 TiB 的字节数不同
+	decimalBytes, err := SizeUnitsDecimal.ParseSize("1 TB")
+	if err != nil {
+		t.Fatalf("ParseSize(\"1 TB\") decimal failed: %v", err)
+	}
+	if decimalBytes != 1e12 {
+		t.Errorf("1 TB decimal: expected %v bytes, got %v", 1e12, decimalBytes)
+	}
+
+	binaryBytes, err := SizeUnitsBinary.ParseSize("1 TiB")
+	if err != nil {
+		t.Fatalf("ParseSize(\"1 TiB\") binary failed: %v", err)
+	}
+	if binaryBytes != 1<<40 {
+		t.Errorf("1 TiB binary: expected %v bytes, got %v", 1<<40, binaryBytes)
+	}
+
+	if decimalBytes == binaryBytes {
+		t.Error("1 TB decimal and 1 TiB binary should differ")
+	}
+
+	testCases := []struct {
+		units    SizeUnits
+		input    string
+		expected float64
+		hasError bool
+	}{
+		{SizeUnitsBinary, "1 KB", 1024, false}, // 二进制模式下KB按1024换算
+		{SizeUnitsDecimal, "1 KB", 1000, false},
+		{SizeUnitsDecimal, "1 GiB", 1 << 30, false}, // 显式的GiB始终按1024换算
+		{SizeUnitsBinary, "500 GB", 500 << 30, false},
+		{SizeUnitsBinary, "1.5", 0, true},
+		{SizeUnitsBinary, "1.5 XB", 0, true},
+	}
+
+	for _, tc := range testCases {
+		result, err := tc.units.ParseSize(tc.input)
+		if tc.hasError {
+			if err == nil {
+				t.Errorf("ParseSize(%q) %s: expected error", tc.input, tc.units)
+			}
+			continue
+		}
+		if err != nil || result != tc.expected {
+			t.Errorf("ParseSize(%q) %s: expected %v, got %v (err: %v)", tc.input, tc.units, tc.expected, result, err)
+		}
+	}
+}
+
+func TestSizeUnits_FormatSize(t *testing.T) {
+	if result := SizeUnitsBinary.FormatSize(1 << 40); result != "1.00 TiB" {
+		t.Errorf("Binary FormatSize(1 TiB): expected '1.00 TiB', got '%s'", result)
+	}
+	if result := SizeUnitsDecimal.FormatSize(1e12); result != "1.00 TB" {
+		t.Errorf("Decimal FormatSize(1 TB): expected '1.00 TB', got '%s'", result)
+	}
+
+	// 同一字节数在两种单位制下显示不同
+	if result := SizeUnitsDecimal.FormatSize(1 << 40); result != "1.10 TB" {
+		t.Errorf("Decimal FormatSize(1 TiB): expected '1.10 TB', got '%s'", result)
+	}
+	if result := SizeUnitsBinary.FormatSize(-512 << 30); result != "-512.00 GiB" {
+		t.Errorf("Binary FormatSize(-512 GiB): expected '-512.00 GiB', got '%s'", result)
+	}
+
+	// 未设置时按二进制处理
+	var unset SizeUnits
+	if result := unset.FormatSize(1 << 30); result != "1.00 GiB" {
+		t.Errorf("Unset FormatSize(1 GiB): expected '1.00 GiB', got '%s'", result)
+	}
+}
+
+func TestSizeUnits_FormatSizePrecision(t *testing.T) {
+	tests := []struct {
+		units     SizeUnits
+		bytes     float64
+		precision int
+		expected  string
+	}{
+		{SizeUnitsBinary, 1.5 * (1 << 40), 0, "2 TiB"},
+		{SizeUnitsBinary, 1.5 * (1 << 40), 3, "1.500 TiB"},
+		{SizeUnitsDecimal, 1 << 40, 0, "1 TB"},
+		{SizeUnitsDecimal, 1 << 40, 3, "1.100 TB"},
+		{SizeUnitsBinary, -512 << 30, 0, "-512 GiB"},
+	}
+
+	for _, tt := range tests {
+		result := tt.units.FormatSizePrecision(tt.bytes, tt.precision)
+		if result != tt.expected {
+			t.Errorf("FormatSizePrecision(%v, %d) with %s: expected '%s', got '%s'",
+				tt.bytes, tt.precision, tt.units, tt.expected, result)
+		}
+
+		// 不同精度的输出都能被重新解析
+		if _, err := tt.units.ParseSize(result); err != nil {
+			t.Errorf("ParseSize(%q) failed: %v", result, err)
+		}
+	}
+}
+
+func TestParseSizeUnits(t *testing.T) {
+	if units, err := ParseSizeUnits("Decimal"); err != nil || units != SizeUnitsDecimal {
+		t.Errorf("ParseSizeUnits(\"Decimal\"): expected decimal, got %s (err: %v)", units, err)
+	}
+	if _, err := ParseSizeUnits("metric"); err == nil {
+		t.Error("ParseSizeUnits(\"metric\"): expected error")
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"20662", "20662"},
+		{"20662 h", "20662"},
+		{"20,662", "20662"},
+		{" 20,662 hours", "20662"},
+		{"36491.5h", "36491.5"},
+		{"N/A", "N/A"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if result := NormalizeNumber(tc.input); result != tc.expected {
+			t.Errorf("NormalizeNumber(%q): expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+}
+
+func TestSizeBytes(t *testing.T) {
+	testCases := []struct {
+		data     map[string]string
+		units    SizeUnits
+		expected float64
+	}{
+		// 字节数字段优先，与显示的单位无关
+		{map[string]string{"Data_Read": "280.21 TB", "Data_Read_Bytes": "280210005000000"}, SizeUnitsBinary, 280210005000000},
+		// 早期版本的历史数据: TB按1024换算
+		{map[string]string{"Data_Read": "1.00 TB"}, SizeUnitsBinary, 1 << 40},
+		{map[string]string{"Data_Read": "1.00 TiB"}, SizeUnitsDecimal, 1 << 40},
+		{map[string]string{"Data_Read": "1.00 TB"}, SizeUnitsDecimal, 1e12},
+	}
+
+	for _, tc := range testCases {
+		result, err := SizeBytes(tc.data, "Data_Read", tc.units)
+		if err != nil || result != tc.expected {
+			t.Errorf("SizeBytes(%v, %s): expected %.0f, got %.0f (err: %v)", tc.data, tc.units, tc.expected, result, err)
+		}
+	}
+}
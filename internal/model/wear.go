@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WearBaselineAttribute 历史数据中固态硬盘已用寿命的基线，格式为 "已用寿命@通电小时"，如 "3@12000"
+// 首次读到已用寿命时记录，之后每次运行沿用，用于计算寿命消耗速率
+const WearBaselineAttribute = "Wear_Baseline"
+
+// DefaultWearHorizonDays 默认的寿命耗尽预警期限(天)
+const DefaultWearHorizonDays = 365
+
+// WearMinHours 计算消耗速率所需的最短观察时间(通电小时)
+// 已用寿命是整数百分比，观察时间太短时一个百分点的变化就会得出过高的速率
+const WearMinHours = 7 * 24
+
+// WearProjection 按寿命消耗速率估算的剩余寿命
+type WearProjection struct {
+	RatePerDay float64 // 每天消耗的寿命百分比
+	DaysLeft   float64 // 按当前速率用完剩余寿命(到100%)的天数
+}
+
+// FormatWearBaseline 生成已用寿命基线
+func FormatWearBaseline(used, hours float64) string {
+	return fmt.Sprintf("%s@%s", strconv.FormatFloat(used, 'f', -1, 64), strconv.FormatFloat(hours, 'f', -1, 64))
+}
+
+// ParseWearBaseline 解析已用寿命基线，返回已用寿命和通电小时
+func ParseWearBaseline(baseline string) (float64, float64, bool) {
+	usedText, hoursText, ok := strings.Cut(baseline, "@")
+	if !ok {
+		return 0, 0, false
+	}
+	used, errUsed := strconv.ParseFloat(usedText, 64)
+	hours, errHours := strconv.ParseFloat(hoursText, 64)
+	if errUsed != nil || errHours != nil {
+		return 0, 0, false
+	}
+	return used, hours, true
+}
+
+// ProjectWear 根据基线以来的已用寿命增长估算消耗速率和剩余天数
+// 观察时间不足WearMinHours或已用寿命没有增长时返回false
+func ProjectWear(used, hours, baseUsed, baseHours float64) (WearProjection, bool) {
+	elapsed := hours - baseHours
+	if elapsed < WearMinHours || used <= baseUsed {
+		return WearProjection{}, false
+	}
+
+	rate := (used - baseUsed) / (elapsed / 24)
+	daysLeft := 0.0
+	if used < 100 {
+		daysLeft = (100 - used) / rate
+	}
+	return WearProjection{RatePerDay: rate, DaysLeft: daysLeft}, true
+}
@@ -0,0 +1,383 @@
+// output/dashboard.go
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HostReport is one host's JSON report (--format json) shown in the
+// multi-host dashboard
+type HostReport struct {
+	Host   string
+	report jsonReport
+}
+
+// LoadHostReport reads a JSON report from a file. The host name comes from
+// the report's system object (--system-info), or the file name without
+// extension when the report has none
+func LoadHostReport(filename string) (*HostReport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	base := filepath.Base(filename)
+	return ParseHostReport(data, strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// ParseHostReport parses a JSON report, using fallbackHost when the report
+// does not name its host
+func ParseHostReport(data []byte, fallbackHost string) (*HostReport, error) {
+	var report jsonReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	host := fallbackHost
+	if report.System != nil && report.System.Hostname != "" {
+		host = report.System.Hostname
+	}
+	return &HostReport{Host: host, report: report}, nil
+}
+
+// dashboardHost is one host tab of the dashboard
+type dashboardHost struct {
+	Name         string
+	TabID        string
+	GeneratedAt  string
+	Status       string
+	DiskCount    int
+	WarningCount int
+	ErrorCount   int
+	Disks        []jsonDisk
+	Controllers  []jsonController
+}
+
+// newDashboardHost summarizes a host report; the host status is the worst
+// status of its disks and controllers
+func newDashboardHost(index int, hr *HostReport) dashboardHost {
+	host := dashboardHost{
+		Name:        hr.Host,
+		TabID:       fmt.Sprintf("host-%d-tab", index),
+		GeneratedAt: hr.report.GeneratedAt,
+		Status:      "status-ok",
+		DiskCount:   len(hr.report.Disks),
+		Disks:       hr.report.Disks,
+	}
+	if controllers := hr.report.Controllers; controllers != nil {
+		host.Controllers = append(append(host.Controllers, controllers.LSI...), controllers.NVMe...)
+	}
+
+	for _, disk := range host.Disks {
+		switch host.raise(disk.Status) {
+		case "status-error":
+			host.ErrorCount++
+		case "status-warning":
+			host.WarningCount++
+		}
+	}
+	for _, controller := range host.Controllers {
+		host.raise(controller.Status)
+	}
+	return host
+}
+
+// raise makes the host status at least as severe as status and returns the
+// status's CSS class
+func (h *dashboardHost) raise(status string) string {
+	class := GetStatusClass(status)
+	switch {
+	case class == "status-error":
+		h.Status = class
+	case class == "status-warning" && h.Status != "status-error":
+		h.Status = class
+	}
+	return class
+}
+
+// WriteDashboard renders the reports of several hosts as one HTML page: an
+// overview tab with a row per host, followed by a tab per host with its
+// disks and controllers
+func WriteDashboard(w io.Writer, reports []*HostReport, generatedAt string) error {
+	if len(reports) == 0 {
+		return fmt.Errorf("no host reports to render")
+	}
+
+	hosts := make([]dashboardHost, 0, len(reports))
+	for i, report := range reports {
+		hosts = append(hosts, newDashboardHost(i, report))
+	}
+
+	funcMap := template.FuncMap{
+		"getStatusClass": GetStatusClass,
+		"temperature": func(value *int) string {
+			if value == nil {
+				return "N/A"
+			}
+			return fmt.Sprintf("%d°C", *value)
+		},
+	}
+	tmpl, err := template.New("dashboard").Funcs(funcMap).Parse(dashboardTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse dashboard template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"Title":     "磁盘健康状态汇总",
+		"Timestamp": generatedAt,
+		"Hosts":     hosts,
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render dashboard: %w", err)
+	}
+	return nil
+}
+
+// dashboardTemplate uses the tab classes and openTab script of the single
+// host report, so both pages look and behave the same
+const dashboardTemplate = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, Helvetica, Arial, sans-serif;
+            margin: 0;
+            padding: 20px;
+            color: #333;
+            background-color: #f5f5f5;
+        }
+        .container {
+            max-width: 1200px;
+            margin: 0 auto;
+        }
+        .panel {
+            background-color: white;
+            border-radius: 5px;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.12), 0 1px 2px rgba(0,0,0,0.24);
+            margin-bottom: 20px;
+            overflow: hidden;
+        }
+        .panel-header {
+            padding: 15px 20px;
+            background-color: #0747a6;
+            color: white;
+            font-weight: 500;
+        }
+        .panel-body {
+            padding: 0;
+            overflow: auto;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th {
+            background-color: #f4f5f7;
+            text-align: left;
+            padding: 10px;
+            border-bottom: 1px solid #ddd;
+        }
+        td {
+            padding: 10px;
+            border-bottom: 1px solid #eee;
+            white-space: nowrap;
+        }
+        .status-ok {
+            color: #00875a;
+            font-weight: bold;
+        }
+        .status-warning {
+            color: #ff8b00;
+            font-weight: bold;
+        }
+        .status-error {
+            color: #de350b;
+            font-weight: bold;
+        }
+        .tab-container {
+            margin-bottom: 20px;
+        }
+        .tabs {
+            display: flex;
+            flex-wrap: wrap;
+            list-style: none;
+            padding: 0;
+            margin: 0;
+            background-color: white;
+            border-radius: 5px 5px 0 0;
+            overflow: hidden;
+        }
+        .tab {
+            padding: 12px 24px;
+            cursor: pointer;
+            transition: background-color 0.3s;
+        }
+        .tab.active {
+            background-color: #0747a6;
+            color: white;
+            font-weight: 500;
+        }
+        .tab:hover:not(.active) {
+            background-color: #f4f5f7;
+        }
+        .tab-content {
+            display: none;
+        }
+        .tab-content.active {
+            display: block;
+        }
+        .last-update {
+            font-size: 12px;
+            color: #5e6c84;
+            text-align: right;
+            margin-bottom: 10px;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{.Title}}</h1>
+
+        <div class="last-update">最后更新时间: {{.Timestamp}}</div>
+
+        <div class="tab-container">
+            <ul class="tabs">
+                <li class="tab active" onclick="openTab(event, 'overview-tab')">概览</li>
+                {{range .Hosts}}
+                <li class="tab {{.Status}}" onclick="openTab(event, '{{.TabID}}')">{{.Name}}</li>
+                {{end}}
+            </ul>
+
+            <div id="overview-tab" class="tab-content active">
+                <div class="panel">
+                    <div class="panel-header">主机</div>
+                    <div class="panel-body">
+                        <table id="host-table">
+                            <thead>
+                                <tr>
+                                    <th>主机</th>
+                                    <th>磁盘数</th>
+                                    <th>警告数</th>
+                                    <th>错误数</th>
+                                    <th>控制器数</th>
+                                    <th>报告时间</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .Hosts}}
+                                <tr>
+                                    <td class="{{.Status}}">{{.Name}}</td>
+                                    <td>{{.DiskCount}}</td>
+                                    <td class="{{if .WarningCount}}status-warning{{end}}">{{.WarningCount}}</td>
+                                    <td class="{{if .ErrorCount}}status-error{{end}}">{{.ErrorCount}}</td>
+                                    <td>{{len .Controllers}}</td>
+                                    <td>{{.GeneratedAt}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+            </div>
+            {{range .Hosts}}
+
+            <div id="{{.TabID}}" class="tab-content">
+                <div class="panel">
+                    <div class="panel-header">{{.Name}} 磁盘</div>
+                    <div class="panel-body">
+                        <table>
+                            <thead>
+                                <tr>
+                                    <th>磁盘名称</th>
+                                    <th>类型</th>
+                                    <th>型号</th>
+                                    <th>容量</th>
+                                    <th>存储池</th>
+                                    <th>温度</th>
+                                    <th>状态</th>
+                                    <th>原因</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .Disks}}
+                                <tr>
+                                    <td>{{.Name}}</td>
+                                    <td>{{.Type}}</td>
+                                    <td>{{.Model}}</td>
+                                    <td>{{.Size}}</td>
+                                    <td>{{.Pool}}</td>
+                                    <td>{{temperature .Temperature}}</td>
+                                    <td class="{{getStatusClass .Status}}">{{.Status}}</td>
+                                    <td>{{.StatusReason}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+                {{if .Controllers}}
+                <div class="panel">
+                    <div class="panel-header">{{.Name}} 控制器</div>
+                    <div class="panel-body">
+                        <table>
+                            <thead>
+                                <tr>
+                                    <th>控制器ID</th>
+                                    <th>型号</th>
+                                    <th>固件版本</th>
+                                    <th>温度</th>
+                                    <th>状态</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .Controllers}}
+                                <tr>
+                                    <td>{{.ID}}</td>
+                                    <td>{{.Model}}</td>
+                                    <td>{{.FirmwareVersion}}</td>
+                                    <td>{{temperature .Temperature}}</td>
+                                    <td class="{{getStatusClass .Status}}">{{.Status}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+                {{end}}
+            </div>
+            {{end}}
+        </div>
+    </div>
+
+    <script>
+        function openTab(evt, tabName) {
+            var i, tabcontent, tablinks;
+
+            // Hide all tab content
+            tabcontent = document.getElementsByClassName("tab-content");
+            for (i = 0; i < tabcontent.length; i++) {
+                tabcontent[i].className = tabcontent[i].className.replace(" active", "");
+            }
+
+            // Remove active class from all tabs
+            tablinks = document.getElementsByClassName("tab");
+            for (i = 0; i < tablinks.length; i++) {
+                tablinks[i].className = tablinks[i].className.replace(" active", "");
+            }
+
+            // Show the current tab and add active class
+            document.getElementById(tabName).className += " active";
+            evt.currentTarget.className += " active";
+        }
+    </script>
+</body>
+</html>`
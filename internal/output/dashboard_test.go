@@ -0,0 +1,76 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+func TestWriteDashboard_TwoHosts(t *testing.T) {
+	// The first host names itself through --system-info; the second one
+	// has no system object and is named after its file
+	named := createJSONFormatter(map[string]interface{}{
+		OptionSystemInfo: &model.SystemInfo{Hostname: "nas-a"},
+	})
+	if err := named.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Failed to format disk info: %v", err)
+	}
+	if err := named.FormatControllerInfo(createTestControllerData()); err != nil {
+		t.Fatalf("Failed to format controller info: %v", err)
+	}
+	first, err := ParseHostReport([]byte(named.String()), "report-1")
+	if err != nil {
+		t.Fatalf("Failed to parse first report: %v", err)
+	}
+
+	healthy := model.NewDiskData()
+	healthy.AddDisk(&model.Disk{Name: "sdz", Type: model.DiskTypeSASHDD, Model: "HUH721212AL", SMARTData: map[string]string{"Smart_Status": "OK"}})
+	unnamed := createJSONFormatter(nil)
+	if err := unnamed.FormatDiskInfo(healthy); err != nil {
+		t.Fatalf("Failed to format disk info: %v", err)
+	}
+	second, err := ParseHostReport([]byte(unnamed.String()), "nas-b")
+	if err != nil {
+		t.Fatalf("Failed to parse second report: %v", err)
+	}
+
+	if first.Host != "nas-a" || second.Host != "nas-b" {
+		t.Fatalf("Expected hosts nas-a and nas-b, got %q and %q", first.Host, second.Host)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDashboard(&buf, []*HostReport{first, second}, "2025-03-10 12:34:56"); err != nil {
+		t.Fatalf("Failed to write dashboard: %v", err)
+	}
+	html := buf.String()
+
+	for _, expected := range []string{
+		`onclick="openTab(event, 'host-0-tab')">nas-a</li>`,
+		`onclick="openTab(event, 'host-1-tab')">nas-b</li>`,
+		`<div id="host-0-tab" class="tab-content">`,
+		`<div id="host-1-tab" class="tab-content">`,
+		`<td>sdz</td>`,
+		`nas-a 控制器`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected dashboard to contain %q", expected)
+		}
+	}
+	if strings.Contains(html, "nas-b 控制器") {
+		t.Error("Expected no controller panel for a host without controllers")
+	}
+}
+
+func TestWriteDashboard_NoReports(t *testing.T) {
+	if err := WriteDashboard(&bytes.Buffer{}, nil, ""); err == nil {
+		t.Error("Expected an error without host reports")
+	}
+}
+
+func TestParseHostReport_Invalid(t *testing.T) {
+	if _, err := ParseHostReport([]byte("not json"), "nas"); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
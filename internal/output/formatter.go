@@ -3,6 +3,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
 // OutputFormatter 定义输出格式化接口
@@ -23,6 +25,9 @@ type OutputFormatter interface {
 	// SaveToFile 保存到文件
 	SaveToFile(filename string) error
 
+	// WriteToWriter 将格式化结果写入 writer (如标准输出)
+	WriteToWriter(w io.Writer) error
+
 	// SetOption 设置格式化选项
 	SetOption(name string, value interface{}) error
 
@@ -48,7 +53,23 @@ const (
 	OptionIncludeSummary   = "include_summary"   // 是否包含摘要信息
 	OptionIncludeTimestamp = "include_timestamp" // 是否包含时间戳
 	OptionColorOutput      = "color_output"      // 是否使用彩色输出
+	OptionColorTheme       = "color_theme"       // 状态配色方案(default, deuteranopia)
 	OptionGroupByType      = "group_by_type"     // 是否按类型分组
+	OptionGroupBy          = "group_by"          // 分组方式(type, pool, none)，优先于group_by_type
+	OptionShowSerial       = "show_serial"       // 是否显示序列号和WWN
+	OptionShowFeatures     = "show_features"     // 是否显示SSD特性(TRIM支持)
+	OptionShowSensors      = "show_sensors"      // 是否显示NVMe各温度传感器的读数
+	OptionShowEndurance    = "show_endurance"    // 是否显示NVMe耐久组寿命
+	OptionShowSectors      = "show_sectors"      // 是否显示磁盘扇区格式(512n/512e/4Kn)
+	OptionShowFirmware     = "show_firmware"     // 是否显示磁盘固件版本
+	OptionShowLinkSpeed    = "show_link_speed"   // 是否显示SAS/SATA链路速率
+	OptionShowPower        = "show_power"        // 是否显示SATA磁盘的APM级别和DSN状态
+	OptionColumns          = "columns"           // 按磁盘类型自定义的表格列(map[model.DiskType][]model.DiskColumn)
+	OptionVerbose          = "verbose"           // 是否显示详细信息(如控制器固件版本)
+	OptionSystemInfo       = "system_info"       // 报告头部的主机信息(*model.SystemInfo)，未设置时不显示
+	OptionLegend           = "legend"            // 是否显示列、单位和状态颜色的图例
+	OptionMergeSSD         = "merge_ssd"         // 是否将SAS和NVMe固态硬盘合并为一个分组显示
+	OptionSortSections     = "sort_sections"     // 分组的显示顺序(default, severity)
 
 	// 文本格式特定选项
 	OptionBorderStyle = "border_style" // 边框样式
@@ -65,6 +86,13 @@ const (
 	OptionTemperatureBar      = "temperature_bar"      // 显示视觉温度指示器
 	OptionEnableInteractivity = "enable_interactivity" // 启用交互式功能（排序、过滤）
 	OptionHtmlTitle           = "html_title"           // HTML页面标题
+	OptionIncludeRawSmart     = "include_raw_smart"    // 附带每个磁盘完整的SMART数据(可折叠)
+	OptionTemperatureHeatmap  = "temperature_heatmap"  // 显示按温度着色的磁盘热力图
+
+	// 通用选项
+	OptionSizeUnits       = "size_units"       // 容量单位制 (binary, decimal)
+	OptionIDFormat        = "id_format"        // 磁盘标识方式 (name, by-id)
+	OptionUnassignedLabel = "unassigned_label" // 未分配存储池的显示名称，可以为空
 )
 
 // 边框样式常量
@@ -92,16 +120,25 @@ type BaseFormatter struct {
 	diskData       *model.DiskData        // 磁盘数据
 	controllerData *model.ControllerData  // 控制器数据
 	generationTime time.Time              // 生成时间
+	clock          system.Clock           // 时间来源
 }
 
 // NewBaseFormatter 创建基本格式化器
 func NewBaseFormatter() BaseFormatter {
+	clock := system.RealClock{}
 	return BaseFormatter{
 		options:        make(map[string]interface{}),
-		generationTime: time.Now(),
+		generationTime: clock.Now(),
+		clock:          clock,
 	}
 }
 
+// SetClock 设置时间来源并据此重置生成时间，测试中可注入固定时钟
+func (b *BaseFormatter) SetClock(clock system.Clock) {
+	b.clock = clock
+	b.generationTime = clock.Now()
+}
+
 // SetOption 设置格式化选项
 func (b *BaseFormatter) SetOption(name string, value interface{}) error {
 	b.options[name] = value
@@ -119,8 +156,13 @@ func (b *BaseFormatter) GetOption(name string, defaultValue interface{}) interfa
 // GetBoolOption 获取布尔选项值
 func (b *BaseFormatter) GetBoolOption(name string, defaultValue bool) bool {
 	value := b.GetOption(name, defaultValue)
-	if boolValue, ok := value.(bool); ok {
-		return boolValue
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		if boolValue, err := strconv.ParseBool(v); err == nil {
+			return boolValue
+		}
 	}
 	return defaultValue
 }
@@ -150,6 +192,158 @@ func (b *BaseFormatter) GetIntOption(name string, defaultValue int) int {
 	return defaultValue
 }
 
+// GetDiskColumns 获取某类磁盘自定义的表格列，未自定义时返回nil
+func (b *BaseFormatter) GetDiskColumns(diskType model.DiskType) []model.DiskColumn {
+	columns, _ := b.GetOption(OptionColumns, nil).(map[model.DiskType][]model.DiskColumn)
+	return columns[diskType]
+}
+
+// compactAttributes 紧凑模式下保留的属性列
+var compactAttributes = map[string]bool{
+	"Temperature":    true,
+	"Smart_Status":   true,
+	"Power_On_Hours": true,
+}
+
+// GetMergedSSDColumns 获取合并显示的固态硬盘表格的列(merge_ssd)
+// 与各类型的默认表格一致: 紧凑模式只保留温度、通电时间和SMART状态，show_serial在名称后加入序列号和WWN
+func (b *BaseFormatter) GetMergedSSDColumns() []model.DiskColumn {
+	compact := b.GetBoolOption(OptionCompactMode, false)
+	showSerial := b.GetBoolOption(OptionShowSerial, false)
+
+	var columns []model.DiskColumn
+	for _, column := range model.GetMergedSSDColumns() {
+		if compact && (column.Name == model.ColumnModel || (column.Attribute != "" && !compactAttributes[column.Attribute])) {
+			continue
+		}
+		columns = append(columns, column)
+		if showSerial && column.Name == model.ColumnName {
+			serial, _ := model.LookupDiskColumn(model.ColumnSerial)
+			wwn, _ := model.LookupDiskColumn(model.ColumnWWN)
+			columns = append(columns, serial, wwn)
+		}
+	}
+	return columns
+}
+
+// GetDiskColumnValue 获取磁盘在自定义列中的显示值
+func (b *BaseFormatter) GetDiskColumnValue(disk *model.Disk, column model.DiskColumn) string {
+	switch column.Name {
+	case model.ColumnName:
+		return b.GetDiskName(disk)
+	case model.ColumnByID:
+		return displayOrNA(disk.ByID)
+	case model.ColumnModel:
+		return disk.Model
+	case model.ColumnSize:
+		return FormatSciNotation(disk.Size, b.GetSizeUnits())
+	case model.ColumnPool:
+		return b.GetPoolName(disk.Pool)
+	case model.ColumnSerial:
+		return displayOrNA(disk.Serial)
+	case model.ColumnWWN:
+		return displayOrNA(disk.WWN)
+	case model.ColumnStatus:
+		return FormatSMARTStatus(string(disk.GetStatus()))
+	case model.ColumnFirmware:
+		return displayOrNA(disk.FirmwareVersion)
+	case model.ColumnLink:
+		return disk.GetDisplayLinkSpeed()
+	case model.ColumnPower:
+		return disk.GetDisplayPower()
+	}
+
+	attribute := column.ResolveAttribute(disk.Type)
+	value := disk.GetAttribute(attribute)
+	switch attribute {
+	case "Temperature":
+		value = disk.GetDisplayTemperature()
+	case "Power_On_Hours":
+		value = FormatPowerOnHours(value)
+	case "Smart_Status":
+		value = FormatSMARTStatus(value)
+	}
+	return value
+}
+
+// GetGroupBy 获取磁盘分组方式，未设置group_by时按group_by_type选项决定
+func (b *BaseFormatter) GetGroupBy() model.GroupBy {
+	if name := b.GetStringOption(OptionGroupBy, ""); name != "" {
+		if groupBy, err := model.ParseGroupBy(name); err == nil {
+			return groupBy
+		}
+	}
+
+	if b.GetBoolOption(OptionGroupByType, true) {
+		return model.GroupByType
+	}
+	return model.GroupByNone
+}
+
+// GetSectionOrder 获取分组的显示顺序，未设置或无法识别时使用固定顺序
+func (b *BaseFormatter) GetSectionOrder() model.SectionOrder {
+	if order, err := model.ParseSectionOrder(b.GetStringOption(OptionSortSections, "")); err == nil {
+		return order
+	}
+	return model.SectionOrderDefault
+}
+
+// GetDisplayDiskTypes 获取按类型分组时显示的分组，应用merge_ssd和sort_sections选项
+func (b *BaseFormatter) GetDisplayDiskTypes() []model.DiskType {
+	types := b.diskData.GetDisplayDiskTypes(b.GetBoolOption(OptionMergeSSD, false))
+	if b.GetSectionOrder() == model.SectionOrderSeverity {
+		b.diskData.SortDiskTypesBySeverity(types)
+	}
+	return types
+}
+
+// GetSizeUnits 获取容量单位制选项，默认为二进制
+func (b *BaseFormatter) GetSizeUnits() model.SizeUnits {
+	units, err := model.ParseSizeUnits(b.GetStringOption(OptionSizeUnits, string(model.SizeUnitsBinary)))
+	if err != nil {
+		return model.SizeUnitsBinary
+	}
+	return units
+}
+
+// GetDiskName 按磁盘标识方式选项获取显示的磁盘名称，默认为内核设备名
+func (b *BaseFormatter) GetDiskName(disk *model.Disk) string {
+	format, err := model.ParseIDFormat(b.GetStringOption(OptionIDFormat, string(model.IDFormatName)))
+	if err != nil {
+		format = model.IDFormatName
+	}
+	return disk.GetDisplayName(format)
+}
+
+// GetSystemInfo 获取报告头部显示的主机信息，未设置system_info选项时返回nil
+func (b *BaseFormatter) GetSystemInfo() *model.SystemInfo {
+	info, _ := b.GetOption(OptionSystemInfo, nil).(*model.SystemInfo)
+	return info
+}
+
+// GetPoolName 获取存储池的显示名称，未分配时使用unassigned_label选项(默认为"未分配")
+func (b *BaseFormatter) GetPoolName(pool string) string {
+	return model.DisplayPool(pool, b.GetStringOption(OptionUnassignedLabel, model.PoolUnassigned))
+}
+
+// FormatMixedCapacityVdevs 格式化存储池中成员容量不同的vdev，如 "MIRROR: sda 3.64 TiB, sdb 7.28 TiB"
+func (b *BaseFormatter) FormatMixedCapacityVdevs(summary *model.PoolSummary) string {
+	units := b.GetSizeUnits()
+	vdevs := make([]string, 0, len(summary.MixedCapacityVdevs))
+	for _, vdev := range summary.MixedCapacityVdevs {
+		members := make([]string, 0, len(vdev.Disks))
+		for i, name := range vdev.Disks {
+			size := "N/A"
+			if vdev.Sizes[i] > 0 {
+				size = FormatBytes(vdev.Sizes[i], units)
+			}
+			members = append(members, fmt.Sprintf("%s %s", name, size))
+		}
+		vdevs = append(vdevs, fmt.Sprintf("%s: %s", strings.ToUpper(vdev.Type), strings.Join(members, ", ")))
+	}
+	return strings.Join(vdevs, "; ")
+}
+
 // FormatTimestamp 格式化时间戳
 func (b *BaseFormatter) FormatTimestamp() string {
 	return b.generationTime.Format("2006-01-02 15:04:05")
@@ -170,6 +364,22 @@ func (b *BaseFormatter) SetData(diskData *model.DiskData, controllerData *model.
 	b.controllerData = controllerData
 }
 
+// GetFlaggedDisks 获取处于警告或错误状态的磁盘
+func (b *BaseFormatter) GetFlaggedDisks() []*model.Disk {
+	if b.diskData == nil {
+		return nil
+	}
+
+	var flagged []*model.Disk
+	for _, disk := range b.diskData.Disks {
+		switch disk.GetStatus() {
+		case model.DiskStatusWarning, model.DiskStatusError:
+			flagged = append(flagged, disk)
+		}
+	}
+	return flagged
+}
+
 // GetSummaryInfo 获取系统摘要信息
 func (b *BaseFormatter) GetSummaryInfo() map[string]string {
 	summary := make(map[string]string)
@@ -202,6 +412,69 @@ func (b *BaseFormatter) GetSummaryInfo() map[string]string {
 	// 收集时间
 	summary["CollectionTime"] = b.diskData.GetCollectionTime()
 
+	// 原始容量，以及知道存储池布局时估算的可用容量
+	units := b.GetSizeUnits()
+	if raw := b.diskData.GetRawCapacity(units); raw > 0 {
+		summary["RawCapacity"] = FormatBytes(raw, units)
+	}
+	if usable, ok := b.diskData.GetUsableCapacity(units); ok {
+		summary["UsableCapacity"] = FormatBytes(usable, units)
+	}
+
+	// 已用容量超过告警阈值的存储池，格式为 "tank (85.0%), backup (91.2%)"
+	if fullPools := b.diskData.GetFullPools(); len(fullPools) > 0 {
+		entries := make([]string, 0, len(fullPools))
+		for _, pool := range fullPools {
+			entries = append(entries, fmt.Sprintf("%s (%s)", pool.Name, pool.GetDisplayUsage()))
+		}
+		summary["FullPools"] = strings.Join(entries, ", ")
+	}
+
+	// 混用不同逻辑扇区大小的存储池，格式为 "tank (512B, 4096B)"
+	if mixedPools := b.diskData.GetMixedSectorPools(); len(mixedPools) > 0 {
+		entries := make([]string, 0, len(mixedPools))
+		for _, pool := range mixedPools {
+			entries = append(entries, fmt.Sprintf("%s (%s)", pool.Name, pool.GetDisplaySectorSizes()))
+		}
+		summary["MixedSectorPools"] = strings.Join(entries, ", ")
+	}
+
+	// 数据vdev混用不同容量磁盘的存储池，格式为 "tank (MIRROR: sda 3.64 TiB, sdb 7.28 TiB)"
+	if mixedPools := b.diskData.GetMixedCapacityPools(); len(mixedPools) > 0 {
+		entries := make([]string, 0, len(mixedPools))
+		for _, pool := range mixedPools {
+			entries = append(entries, fmt.Sprintf("%s (%s)", pool.Name, b.FormatMixedCapacityVdevs(pool)))
+		}
+		summary["MixedCapacityPools"] = strings.Join(entries, ", ")
+	}
+
+	// 启动盘(--include-boot)
+	var bootDisks []string
+	for _, disk := range b.diskData.Disks {
+		if disk.Boot {
+			bootDisks = append(bootDisks, disk.Name)
+		}
+	}
+	if len(bootDisks) > 0 {
+		summary["BootDisks"] = strings.Join(bootDisks, ", ")
+	}
+
+	// 通电时间超过提示阈值的磁盘(--age-info-years)，格式为 "sda (6.0 年)"
+	if agedDisks := b.diskData.GetAgedDisks(); len(agedDisks) > 0 {
+		entries := make([]string, 0, len(agedDisks))
+		for _, disk := range agedDisks {
+			age, _ := disk.GetAgeYears()
+			entries = append(entries, fmt.Sprintf("%s (%.1f 年)", disk.Name, age))
+		}
+		summary["AgedDisks"] = strings.Join(entries, ", ")
+		summary["AgeInfoYears"] = fmt.Sprintf("%d", b.diskData.AgeInfoYears)
+	}
+
+	// 磁盘列表被截断时记录原始数量
+	if b.diskData.IsTruncated() {
+		summary["TruncatedFrom"] = fmt.Sprintf("%d", b.diskData.TruncatedFrom)
+	}
+
 	return summary
 }
 
@@ -219,6 +492,20 @@ func FormatDiskStatus(status model.DiskStatus) string {
 	}
 }
 
+// StatusLegendEntry 状态图例中的一项
+type StatusLegendEntry struct {
+	Status      model.DiskStatus // 磁盘状态
+	Description string           // 状态的含义
+}
+
+// StatusLegend 各磁盘状态的含义，文本和HTML图例共用
+var StatusLegend = []StatusLegendEntry{
+	{Status: model.DiskStatusOK, Description: "未发现问题"},
+	{Status: model.DiskStatusWarning, Description: "需要关注，如温度偏高、寿命接近耗尽或出现错误记录"},
+	{Status: model.DiskStatusError, Description: "磁盘报告故障或存在严重问题，应尽快处理"},
+	{Status: model.DiskStatusUnknown, Description: "无法获取SMART数据，状态未知"},
+}
+
 // FormatSMARTStatus 格式化 SMART 状态
 func FormatSMARTStatus(status string) string {
 	switch strings.ToUpper(status) {
@@ -255,8 +542,10 @@ func FormatPowerOnHours(hours string) string {
 		return "N/A"
 	}
 
+	// 部分固件带单位或千位分隔符，如 "20662 h"、"20,662"
+	value := model.NormalizeNumber(hours)
 	var h float64
-	if _, err := fmt.Sscanf(hours, "%f", &h); err != nil {
+	if _, err := fmt.Sscanf(value, "%f", &h); err != nil {
 		return hours
 	}
 
@@ -280,7 +569,7 @@ func FormatPowerOnHours(hours string) string {
 	h -= float64(days * hoursPerDay)
 
 	// 特殊处理硬编码的特定场景
-	switch hours {
+	switch value {
 	case "9000":
 		years, months, days = 1, 1, 0
 		h = 0
@@ -310,6 +599,22 @@ func FormatPowerOnHours(hours string) string {
 	return strings.Join(parts, " ")
 }
 
+// SupportedFormats 已实现的输出格式(不含暂未实现的PDF)
+var SupportedFormats = []string{"text", "html", "json", "template"}
+
+// SupportedOptions 汇总每种输出格式支持的选项(格式 -> 选项名 -> 描述)
+func SupportedOptions() map[string]map[string]string {
+	options := make(map[string]map[string]string, len(SupportedFormats))
+	for _, format := range SupportedFormats {
+		formatter, err := NewFormatter(format, nil)
+		if err != nil {
+			continue
+		}
+		options[format] = formatter.GetSupportedOptions()
+	}
+	return options
+}
+
 // NewFormatter 创建指定类型的格式化器
 func NewFormatter(format string, options map[string]interface{}) (OutputFormatter, error) {
 	switch strings.ToLower(format) {
@@ -319,42 +624,47 @@ func NewFormatter(format string, options map[string]interface{}) (OutputFormatte
 		return NewTextFormatter(options), nil
 	case "html", "h":
 		return NewHTMLFormatter(options), nil
+	case "json", "j":
+		return NewJSONFormatter(options), nil
+	case "template":
+		return NewTemplateFormatter(options), nil
 	default:
 		return nil, fmt.Errorf("不支持的输出格式: %s", format)
 	}
 }
 
-// FormatSciNotation 将科学计数法转换为人类可读格式
-func FormatSciNotation(size string) string {
-	// 尝试解析为科学计数法
-	var value float64
-	if _, err := fmt.Sscanf(size, "%e", &value); err == nil {
-		// 转换为适当的单位
-		units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
-		unitIndex := 0
+// FormatSciNotation 将字节数字符串(可为科学计数法)按指定单位制转换为人类可读格式
+func FormatSciNotation(size string, units model.SizeUnits) string {
+	value, err := strconv.ParseFloat(strings.TrimSpace(size), 64)
+	if err != nil {
+		// 如果不是数值，返回原始值
+		return size
+	}
 
-		for value >= 1024 && unitIndex < len(units)-1 {
-			value /= 1024
-			unitIndex++
-		}
+	value, label := units.Scale(value)
 
-		// 根据数值大小选择合适的精度
-		if value < 10 {
-			return fmt.Sprintf("%.2f %s", value, units[unitIndex])
-		} else if value < 100 {
-			return fmt.Sprintf("%.1f %s", value, units[unitIndex])
-		} else {
-			return fmt.Sprintf("%.0f %s", value, units[unitIndex])
-		}
+	// 根据数值大小选择合适的精度
+	if value < 10 {
+		return fmt.Sprintf("%.2f %s", value, label)
+	} else if value < 100 {
+		return fmt.Sprintf("%.1f %s", value, label)
 	}
+	return fmt.Sprintf("%.0f %s", value, label)
+}
 
-	// 如果不是科学计数法，返回原始值
-	return size
+// FormatBytes 将字节数格式化为人类可读格式，0表示未知
+func FormatBytes(bytes float64, units model.SizeUnits) string {
+	if bytes <= 0 {
+		return "N/A"
+	}
+	return FormatSciNotation(strconv.FormatFloat(bytes, 'e', -1, 64), units)
 }
 
 // 这些是将在各个具体格式化器中实现的函数声明
 var (
-	NewPDFFormatter  func(options map[string]interface{}) OutputFormatter
-	NewTextFormatter func(options map[string]interface{}) OutputFormatter
-	NewHTMLFormatter func(options map[string]interface{}) OutputFormatter
+	NewPDFFormatter      func(options map[string]interface{}) OutputFormatter
+	NewTextFormatter     func(options map[string]interface{}) OutputFormatter
+	NewHTMLFormatter     func(options map[string]interface{}) OutputFormatter
+	NewJSONFormatter     func(options map[string]interface{}) OutputFormatter
+	NewTemplateFormatter func(options map[string]interface{}) OutputFormatter
 )
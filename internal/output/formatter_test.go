@@ -1,11 +1,15 @@
 package output
 
 import (
+	"bytes"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
 func TestBaseFormatter_Options(t *testing.T) {
@@ -68,6 +72,16 @@ func TestBaseFormatter_Options(t *testing.T) {
 	if bf.GetIntOption("float_int", 0) != 78 {
 		t.Error("GetIntOption float conversion failed")
 	}
+
+	bf.SetOption("string_bool", "1")
+	if !bf.GetBoolOption("string_bool", false) {
+		t.Error("GetBoolOption string conversion failed")
+	}
+
+	bf.SetOption("invalid_bool", "maybe")
+	if !bf.GetBoolOption("invalid_bool", true) {
+		t.Error("GetBoolOption should fall back to the default for invalid strings")
+	}
 }
 
 func TestFormatDiskStatus(t *testing.T) {
@@ -154,6 +168,9 @@ func TestFormatPowerOnHours(t *testing.T) {
 		{"8784", "1y 1d"},
 		{"9024", "1y 1m 1d"},
 		{"9025", "1y 1m 1d 1h"},
+		{"20662", "2y 4m 10d 22h"},
+		{"20662 h", "2y 4m 10d 22h"}, // 带单位
+		{"20,662", "2y 4m 10d 22h"},  // 带千位分隔符
 		{"invalid", "invalid"},
 	}
 
@@ -215,6 +232,7 @@ func TestBaseFormatter_GetSummaryInfo(t *testing.T) {
 		"ErrorCount":      "1",
 		"ControllerCount": "2",
 		"CollectionTime":  "2025-03-10 12:34:56",
+		"RawCapacity":     "7.00 TiB", // 1 TB * 3 + 4 TB，默认按二进制换算
 	}
 
 	if !reflect.DeepEqual(summary, expectedSummary) {
@@ -228,6 +246,25 @@ func TestBaseFormatter_GetSummaryInfo(t *testing.T) {
 	}
 }
 
+func TestBaseFormatter_FixedClock(t *testing.T) {
+	fixed := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	tf := NewTextFormatter(nil).(*TextFormatter)
+	tf.SetClock(system.FixedClock{Time: fixed})
+
+	if ts := tf.FormatTimestamp(); ts != "2025-03-10 08:00:00" {
+		t.Errorf("FormatTimestamp: expected 2025-03-10 08:00:00, got %s", ts)
+	}
+
+	// 渲染结果中的生成时间也应使用固定时钟
+	if err := tf.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if !strings.Contains(tf.String(), "生成时间: 2025-03-10 08:00:00") {
+		t.Error("Rendered output should contain the fixed timestamp")
+	}
+}
+
 func TestNewFormatter(t *testing.T) {
 	// 存储原始工厂函数
 	originalPDFFormatter := NewPDFFormatter
@@ -318,6 +355,10 @@ func (m *MockFormatter) SaveToFile(filename string) error {
 	return nil
 }
 
+func (m *MockFormatter) WriteToWriter(w io.Writer) error {
+	return nil
+}
+
 func (m *MockFormatter) SetOption(name string, value interface{}) error {
 	if m.options == nil {
 		m.options = make(map[string]interface{})
@@ -336,3 +377,244 @@ func (m *MockFormatter) SetData(diskData *model.DiskData, controllerData *model.
 	m.diskData = diskData
 	m.controllerData = controllerData
 }
+
+func TestControllerOrdering(t *testing.T) {
+	// 以非字母顺序插入三个控制器
+	controllerData := model.NewControllerData()
+	for _, id := range []string{"LSI_Controller_2", "LSI_Controller_0", "LSI_Controller_1"} {
+		controller := model.NewLSIController(id)
+		controller.Model = "LSI SAS 9300-8i"
+		controller.Status = model.ControllerStatusOK
+		controllerData.LSIControllers[id] = controller
+	}
+	expectedOrder := []string{"LSI_Controller_0", "LSI_Controller_1", "LSI_Controller_2"}
+
+	assertOrder := func(name, content string) {
+		t.Helper()
+		last := -1
+		for _, id := range expectedOrder {
+			index := strings.Index(content, id)
+			if index < 0 {
+				t.Fatalf("%s: controller %s not found in output", name, id)
+			}
+			if index < last {
+				t.Errorf("%s: controller %s is out of order", name, id)
+			}
+			last = index
+		}
+	}
+
+	// map遍历顺序随机，多次渲染以确认顺序稳定
+	for i := 0; i < 10; i++ {
+		tf := NewTextFormatter(map[string]interface{}{OptionColorOutput: false}).(*TextFormatter)
+		if err := tf.FormatControllerInfo(controllerData); err != nil {
+			t.Fatalf("Text FormatControllerInfo failed: %v", err)
+		}
+		assertOrder("text", tf.String())
+
+		hf := NewHTMLFormatter(nil).(*HTMLFormatter)
+		if err := hf.FormatControllerInfo(controllerData); err != nil {
+			t.Fatalf("HTML FormatControllerInfo failed: %v", err)
+		}
+		assertOrder("html", hf.htmlBuffer.String())
+	}
+}
+
+// TestFormatters_WriteToWriter 测试每种格式化器都能写入 writer
+func TestFormatters_WriteToWriter(t *testing.T) {
+	formatters := map[string]OutputFormatter{
+		"text": createTextFormatter(nil),
+		"html": createHTMLFormatter(nil),
+		"json": createJSONFormatter(nil),
+	}
+	expected := map[string]string{
+		"text": "TrueNAS磁盘健康监控",
+		"html": "<html",
+		"json": `"disks"`,
+	}
+
+	for name, formatter := range formatters {
+		if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+			t.Fatalf("%s: FormatDiskInfo failed: %v", name, err)
+		}
+		if err := formatter.FormatControllerInfo(createTestControllerData()); err != nil {
+			t.Fatalf("%s: FormatControllerInfo failed: %v", name, err)
+		}
+
+		var buf bytes.Buffer
+		if err := formatter.WriteToWriter(&buf); err != nil {
+			t.Fatalf("%s: WriteToWriter failed: %v", name, err)
+		}
+		if !strings.Contains(buf.String(), expected[name]) {
+			t.Errorf("%s: expected output to contain %q", name, expected[name])
+		}
+	}
+}
+
+func TestFormatters_IDFormat(t *testing.T) {
+	newData := func() *model.DiskData {
+		diskData := createTestDiskData()
+		for _, disk := range diskData.Disks {
+			if disk.Name == "sda" {
+				disk.ByID = "ata-Samsung_SSD_870_EVO_1TB_S5Y1NX0R123456"
+			}
+		}
+		return diskData
+	}
+
+	// 默认显示内核设备名
+	text := createTextFormatter(map[string]interface{}{OptionColorOutput: false})
+	if err := text.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if strings.Contains(text.String(), "ata-Samsung_SSD_870_EVO_1TB_S5Y1NX0R123456") {
+		t.Error("Expected kernel names unless id_format is by-id")
+	}
+
+	// by-id时显示稳定名称，没有链接的磁盘仍显示内核设备名
+	options := map[string]interface{}{OptionColorOutput: false, OptionIDFormat: "by-id"}
+	text = createTextFormatter(options)
+	if err := text.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	html := createHTMLFormatter(options)
+	if err := html.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	for name, output := range map[string]string{"text": text.String(), "html": html.htmlBuffer.String()} {
+		if !strings.Contains(output, "ata-Samsung_SSD_870_EVO_1TB_S5Y1NX0R123456") {
+			t.Errorf("%s: expected the by-id name for sda", name)
+		}
+		if !strings.Contains(output, "sdb") {
+			t.Errorf("%s: expected the kernel name for sdb, which has no by-id link", name)
+		}
+	}
+
+	// by_id列，没有链接时为N/A
+	column, _ := model.LookupDiskColumn(model.ColumnByID)
+	base := NewBaseFormatter()
+	for _, disk := range newData().Disks {
+		want := "N/A"
+		if disk.Name == "sda" {
+			want = "ata-Samsung_SSD_870_EVO_1TB_S5Y1NX0R123456"
+		}
+		if got := base.GetDiskColumnValue(disk, column); got != want {
+			t.Errorf("%s: expected by_id column %s, got %s", disk.Name, want, got)
+		}
+	}
+}
+
+func TestFormatters_UnassignedLabel(t *testing.T) {
+	newData := func() *model.DiskData {
+		diskData := createTestDiskData()
+		spare := model.NewDisk("sdz", "HDD", "WDC WD40EFRX-68N", "4 TB")
+		spare.Type = model.DiskTypeSASHDD
+		diskData.AddDisk(spare)
+		return diskData
+	}
+	column, _ := model.LookupDiskColumn(model.ColumnPool)
+
+	// 默认显示"未分配"
+	base := NewBaseFormatter()
+	base.SetData(newData(), nil)
+	for _, disk := range base.diskData.Disks {
+		want := disk.Pool
+		if got := base.GetDiskColumnValue(disk, column); got != want {
+			t.Errorf("%s: expected pool column %q, got %q", disk.Name, want, got)
+		}
+	}
+
+	// 空标签时未分配磁盘的存储池为空，其他磁盘不受影响
+	options := map[string]interface{}{OptionColorOutput: false, OptionUnassignedLabel: ""}
+	base = NewBaseFormatter()
+	for name, value := range options {
+		base.SetOption(name, value)
+	}
+	for _, disk := range newData().Disks {
+		want := disk.Pool
+		if disk.Name == "sdz" {
+			want = ""
+		}
+		if got := base.GetDiskColumnValue(disk, column); got != want {
+			t.Errorf("%s: expected pool column %q, got %q", disk.Name, want, got)
+		}
+	}
+
+	jf := createJSONFormatter(options)
+	if err := jf.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	for _, disk := range jf.buildReport().Disks {
+		if disk.Name == "sdz" && disk.Pool != "" {
+			t.Errorf("Expected a blank JSON pool for sdz, got %q", disk.Pool)
+		}
+		if disk.Name == "sda" && disk.Pool != "tank" {
+			t.Errorf("Expected pool tank for sda, got %q", disk.Pool)
+		}
+	}
+
+	// 按存储池分组时未分配的磁盘仍然单独成组
+	options[OptionGroupBy] = string(model.GroupByPool)
+	text := createTextFormatter(options)
+	if err := text.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	output := text.String()
+	if !strings.Contains(output, "存储池: "+model.PoolUnassigned) || !strings.Contains(output, "sdz") {
+		t.Error("Expected unassigned disks to keep their own group")
+	}
+}
+
+func TestFormatters_DiskGroupOrder(t *testing.T) {
+	// 在虚拟设备之前加入热备盘分组，模拟新增的磁盘类型
+	spareType := model.DiskType("SPARE")
+	original := model.DiskTypeGroups
+	t.Cleanup(func() { model.DiskTypeGroups = original })
+	groups := append([]model.DiskTypeGroup{}, original[:3]...)
+	groups = append(groups, model.DiskTypeGroup{Type: spareType, Title: "热备盘"})
+	model.DiskTypeGroups = append(groups, original[3:]...)
+
+	newData := func() *model.DiskData {
+		diskData := createTestDiskData()
+		spare := model.NewDisk("sdx", "HDD", "WDC WD40EFRX-68N", "4 TB")
+		spare.Type = spareType
+		virtual := model.NewDisk("sdv", "Virtual", "VMware Virtual disk", "100 GB")
+		virtual.Type = model.DiskTypeVirtual
+		unknown := model.NewDisk("sdu", "HDD", "Unknown", "1 TB")
+		unknown.Type = model.DiskType("TAPE")
+		for _, disk := range []*model.Disk{unknown, virtual, spare} {
+			diskData.AddDisk(disk)
+		}
+		return diskData
+	}
+
+	// 未知类型排在所有已知分组之后
+	expected := []string{"SAS/SATA 固态硬盘", "SAS/SATA 机械硬盘", "NVMe 固态硬盘", "热备盘", "虚拟设备", model.OtherDiskTypeTitle}
+
+	text := createTextFormatter(map[string]interface{}{OptionColorOutput: false})
+	if err := text.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	html := createHTMLFormatter(nil)
+	if err := html.FormatDiskInfo(newData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+
+	for name, output := range map[string]string{"text": text.String(), "html": html.htmlBuffer.String()} {
+		last := -1
+		for _, title := range expected {
+			index := strings.Index(output, title)
+			if index < 0 {
+				t.Errorf("%s: missing section %q", name, title)
+				continue
+			}
+			if index < last {
+				t.Errorf("%s: section %q is out of order", name, title)
+			}
+			last = index
+		}
+		if !strings.Contains(output, "sdx") || !strings.Contains(output, "sdu") {
+			t.Errorf("%s: expected disks of the new types to be listed", name)
+		}
+	}
+}
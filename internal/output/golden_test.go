@@ -0,0 +1,92 @@
+package output
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
+)
+
+// 使用 go test ./internal/output -run Golden -update 重新生成golden文件
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// goldenClock 固定的生成时间，保证输出可重复
+var goldenClock = system.FixedClock{Time: time.Date(2025, 3, 10, 12, 34, 56, 0, time.UTC)}
+
+// createGoldenControllerData 创建包含多个控制器的测试数据，用于验证输出顺序稳定
+func createGoldenControllerData() *model.ControllerData {
+	controllerData := createTestControllerData()
+
+	lsiController := model.NewLSIController("LSI_Controller_1")
+	lsiController.Model = "LSI SAS 9305-16i"
+	lsiController.FirmwareVersion = "16.00.10.00"
+	lsiController.DriverVersion = "7.705.18.00-rh8.1"
+	lsiController.Temperature = "52"
+	lsiController.DeviceCount = "4"
+	lsiController.Status = model.ControllerStatusOK
+	controllerData.LSIControllers["LSI_Controller_1"] = lsiController
+
+	nvmeController := model.NewNVMeController("NVMe_Controller_1")
+	nvmeController.Bus = "0000:02:00.0"
+	nvmeController.Description = "Non-Volatile memory controller: Intel Corporation NVMe Datacenter SSD"
+	nvmeController.Temperature = "40"
+	nvmeController.Status = model.ControllerStatusOK
+	controllerData.NVMeControllers["NVMe_Controller_1"] = nvmeController
+
+	return controllerData
+}
+
+// assertGolden 比较输出与golden文件，-update 时重写golden文件
+func assertGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatalf("Failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file %s: %v (run with -update to create it)", path, err)
+	}
+	if string(expected) != actual {
+		t.Errorf("Output does not match golden file %s (run with -update to refresh it)", path)
+	}
+}
+
+func TestTextFormatter_Golden(t *testing.T) {
+	tf := NewTextFormatter(map[string]interface{}{
+		OptionColorOutput: false,
+	}).(*TextFormatter)
+	tf.SetClock(goldenClock)
+
+	if err := tf.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if err := tf.FormatControllerInfo(createGoldenControllerData()); err != nil {
+		t.Fatalf("FormatControllerInfo failed: %v", err)
+	}
+
+	assertGolden(t, "text.golden", tf.String())
+}
+
+func TestHTMLFormatter_Golden(t *testing.T) {
+	hf := NewHTMLFormatter(nil).(*HTMLFormatter)
+	hf.SetClock(goldenClock)
+
+	if err := hf.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if err := hf.FormatControllerInfo(createGoldenControllerData()); err != nil {
+		t.Fatalf("FormatControllerInfo failed: %v", err)
+	}
+
+	assertGolden(t, "report.html.golden", hf.htmlBuffer.String())
+}
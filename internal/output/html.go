@@ -3,6 +3,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/template"
@@ -13,6 +14,7 @@ import (
 // Default option values for HTML formatter
 const (
 	DefaultShowTemperatureBar  = true
+	DefaultTemperatureHeatmap  = false
 	DefaultEnableInteractivity = true
 	DefaultHtmlTitle           = "TrueNAS磁盘健康监控"
 )
@@ -54,6 +56,18 @@ func (hf *HTMLFormatter) GetSupportedOptions() map[string]string {
 		OptionGroupByType:         "Group disks by type",
 		OptionIncludeSummary:      "Include summary information",
 		OptionIncludeTimestamp:    "Include timestamp",
+		OptionIDFormat:            "Disk identifier shown in tables (name, by-id)",
+		OptionUnassignedLabel:     "Pool name shown for disks not in any pool (may be empty)",
+		OptionSizeUnits:           "Size units (binary, decimal)",
+		OptionShowSerial:          "Show serial number and WWN columns",
+		OptionShowFeatures:        "Show SSD feature columns (TRIM support)",
+		OptionVerbose:             "Show controller firmware package, BIOS and NVDATA versions",
+		OptionSystemInfo:          "Host information shown in the report header (set with --system-info)",
+		OptionIncludeRawSmart:     "Include each disk's full SMART data in collapsible panels",
+		OptionTemperatureHeatmap:  "Show a heatmap of all disks colored by temperature",
+		OptionLegend:              "Explain table columns, units and status colors at the end of the disk tab",
+		OptionMergeSSD:            "Show SAS and NVMe SSDs in a single SSD section",
+		OptionSortSections:        "Order of disk type sections (default, severity)",
 	}
 }
 
@@ -112,25 +126,46 @@ func (hf *HTMLFormatter) SaveToFile(filename string) error {
 	return nil
 }
 
+// String returns the formatted output as a string
+func (hf *HTMLFormatter) String() string {
+	return hf.htmlBuffer.String()
+}
+
+// WriteToWriter writes the formatted output to a writer
+func (hf *HTMLFormatter) WriteToWriter(w io.Writer) error {
+	_, err := io.WriteString(w, hf.htmlBuffer.String())
+	return err
+}
+
 // generateHTML generates the complete HTML document
 func (hf *HTMLFormatter) generateHTML() error {
 	hf.htmlBuffer.Reset()
 
-	// 转换 GroupedDisks 的键为字符串
-	groupedDisksStr := make(map[string][]*model.Disk)
+	// Summarize disks per pool for the pools tab
+	var errorOverview []model.ErrorOverviewRow
 	if hf.diskData != nil {
-		for diskType, disks := range hf.diskData.GroupedDisks {
-			groupedDisksStr[string(diskType)] = disks
-		}
+		errorOverview = hf.diskData.ErrorOverview()
 	}
 
+	var poolSummary []*model.PoolSummary
+	if hf.diskData != nil {
+		poolSummary = hf.diskData.GetPoolSummaries()
+	}
+
+	// Pre-sort controllers so rows render in a stable order
+	lsiControllers, nvmeControllers := hf.sortedControllers()
+
 	// Define the data to pass to the template
 	data := map[string]interface{}{
 		"Title":               hf.GetStringOption(OptionHtmlTitle, DefaultHtmlTitle),
 		"Timestamp":           hf.FormatTimestamp(),
+		"SystemInfo":          hf.GetSystemInfo(),
 		"DiskData":            hf.diskData,
 		"ControllerData":      hf.controllerData,
 		"ShowTemperatureBar":  hf.GetBoolOption(OptionTemperatureBar, DefaultShowTemperatureBar),
+		"ShowSerial":          hf.GetBoolOption(OptionShowSerial, false),
+		"ShowFeatures":        hf.GetBoolOption(OptionShowFeatures, false),
+		"Verbose":             hf.GetBoolOption(OptionVerbose, false),
 		"EnableInteractivity": hf.GetBoolOption(OptionEnableInteractivity, DefaultEnableInteractivity),
 		"HasIncrement":        hf.diskData != nil && hf.diskData.HasPreviousData(),
 		"PreviousTime": func() string {
@@ -141,7 +176,15 @@ func (hf *HTMLFormatter) generateHTML() error {
 			}
 		}(),
 		"SummaryInfo":     hf.GetSummaryInfo(),
-		"GroupedDisksStr": groupedDisksStr, // 新增传入转换后的 groupedDisks
+		"DiskGroups":      hf.diskGroups(),
+		"PoolSummary":     poolSummary,
+		"FlaggedDisks":    hf.GetFlaggedDisks(),
+		"ErrorOverview":   errorOverview,
+		"LSIControllers":  lsiControllers,
+		"NVMeControllers": nvmeControllers,
+		"IncludeRawSmart": hf.GetBoolOption(OptionIncludeRawSmart, false),
+		"Heatmap":         hf.temperatureHeatmap(),
+		"Legend":          hf.legend(),
 	}
 
 	// Create a new template and parse the HTML template string
@@ -149,11 +192,19 @@ func (hf *HTMLFormatter) generateHTML() error {
 		"getStatusClass":       GetStatusClass,
 		"formatTemperatureBar": hf.formatTemperatureBar,
 		"formatPowerOnHours":   FormatPowerOnHours,
-		"formatSize":           FormatSciNotation,
+		"diskName":             hf.GetDiskName,
+		"poolName":             hf.GetPoolName,
+		"mixedCapacityVdevs":   hf.FormatMixedCapacityVdevs,
+		"formatSize": func(size string) string {
+			return FormatSciNotation(size, hf.GetSizeUnits())
+		},
+		"formatBytes": func(bytes float64) string {
+			return FormatBytes(bytes, hf.GetSizeUnits())
+		},
 		"string": func(v interface{}) string {
 			return fmt.Sprintf("%v", v)
 		},
-	}).Parse(htmlTemplate)
+	}).Parse(htmlTemplate + systemInfoTemplate + heatmapTemplate + legendTemplate)
 
 	if err != nil {
 		return fmt.Errorf("failed to parse HTML template: %w", err)
@@ -167,17 +218,177 @@ func (hf *HTMLFormatter) generateHTML() error {
 	return nil
 }
 
+// htmlLegend holds the column and status explanations of the legend panel
+type htmlLegend struct {
+	Columns  []model.LegendEntry
+	Statuses []htmlLegendStatus
+}
+
+// htmlLegendStatus is one status in the legend, styled like the tables
+type htmlLegendStatus struct {
+	Class       string
+	Name        string
+	Description string
+}
+
+// legend builds the legend panel, or returns nil when the option is off
+func (hf *HTMLFormatter) legend() *htmlLegend {
+	if !hf.GetBoolOption(OptionLegend, false) || hf.diskData == nil {
+		return nil
+	}
+
+	legend := &htmlLegend{Columns: hf.diskData.GetColumnLegend()}
+	for _, entry := range StatusLegend {
+		legend.Statuses = append(legend.Statuses, htmlLegendStatus{
+			Class:       GetStatusClass(string(entry.Status)),
+			Name:        FormatDiskStatus(entry.Status),
+			Description: entry.Description,
+		})
+	}
+	return legend
+}
+
+// customDiskTable holds a disk table with columns chosen through --columns
+type customDiskTable struct {
+	ID      string
+	Headers []customDiskHeader
+	Rows    []customDiskRow
+}
+
+// customDiskHeader is a column header with the sort type used by sortTable
+type customDiskHeader struct {
+	Title    string
+	SortType string
+}
+
+// customDiskRow is one disk in a custom table; Status drives the
+// problem-disk toggle
+type customDiskRow struct {
+	Status string
+	Cells  []string
+}
+
+// htmlDiskGroup is one disk type section of the disk tab
+type htmlDiskGroup struct {
+	Type    string
+	Title   string
+	TableID string
+	Disks   []*model.Disk
+	Custom  *customDiskTable // set when the type has custom columns
+}
+
+// diskTableIDs maps disk types to the element IDs of their tables
+var diskTableIDs = map[model.DiskType]string{
+	model.DiskTypeSASSSD:  "ssd-table",
+	model.DiskTypeSASHDD:  "hdd-table",
+	model.DiskTypeNVMESSD: "nvme-table",
+	model.DiskTypeVirtual: "virtual-table",
+}
+
+// diskTableID returns the element ID of a disk type's table; types without
+// a fixed ID derive one from the type name
+func diskTableID(diskType model.DiskType) string {
+	if id, ok := diskTableIDs[diskType]; ok {
+		return id
+	}
+	return strings.ToLower(string(diskType)) + "-table"
+}
+
+// diskGroups builds the disk type sections in the group order shared with
+// the text formatter
+func (hf *HTMLFormatter) diskGroups() []htmlDiskGroup {
+	if hf.diskData == nil {
+		return nil
+	}
+
+	var groups []htmlDiskGroup
+	for _, diskType := range hf.GetDisplayDiskTypes() {
+		groups = append(groups, htmlDiskGroup{
+			Type:    string(diskType),
+			Title:   diskType.GetGroupTitle(),
+			TableID: diskTableID(diskType),
+			Disks:   hf.diskData.GetDisplayDisks(diskType),
+			Custom:  hf.customDiskTable(diskType),
+		})
+	}
+	return groups
+}
+
+// customDiskTable builds the table for a disk type with custom columns, or
+// returns nil so the type keeps its default table. Merged SSDs always use
+// a custom table with the columns of both SSD types
+func (hf *HTMLFormatter) customDiskTable(diskType model.DiskType) *customDiskTable {
+	columns := hf.GetDiskColumns(diskType)
+	if len(columns) == 0 && diskType == model.DiskTypeSSD {
+		columns = hf.GetMergedSSDColumns()
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	table := &customDiskTable{ID: diskTableID(diskType)}
+	for _, column := range columns {
+		table.Headers = append(table.Headers, customDiskHeader{Title: column.DisplayName, SortType: diskColumnSortType(column)})
+	}
+	for _, disk := range hf.diskData.GetDisplayDisks(diskType) {
+		row := customDiskRow{Status: string(disk.GetStatus()), Cells: make([]string, 0, len(columns))}
+		for _, column := range columns {
+			row.Cells = append(row.Cells, hf.GetDiskColumnValue(disk, column))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// diskColumnSortType returns how sortTable should compare a column's values:
+// numeric, size (e.g. "1.50 TB"), duration (e.g. "1y 2m 3d") or text
+func diskColumnSortType(column model.DiskColumn) string {
+	switch column.Name {
+	case model.ColumnSize:
+		return "size"
+	case model.ColumnLink:
+		return "numeric"
+	}
+
+	switch column.Attribute {
+	case "Data_Read", "Data_Written":
+		return "size"
+	case "Power_On_Hours":
+		return "duration"
+	case "Temperature", "Percentage_Used", "Available_Spare":
+		return "numeric"
+	}
+	return "text"
+}
+
+// sortedControllers returns the LSI and NVMe controllers sorted by ID
+func (hf *HTMLFormatter) sortedControllers() ([]*model.LSIController, []*model.NVMeController) {
+	if hf.controllerData == nil {
+		return nil, nil
+	}
+	return hf.controllerData.GetSortedLSIControllers(), hf.controllerData.GetSortedNVMeControllers()
+}
+
 // generateControllerOnlyHTML generates an HTML document with only controller information
 func (hf *HTMLFormatter) generateControllerOnlyHTML(controllerOnly bool) error {
 	hf.htmlBuffer.Reset()
 
+	// Pre-sort controllers so rows render in a stable order
+	lsiControllers, nvmeControllers := hf.sortedControllers()
+
 	// Define the data to pass to the template
 	data := map[string]interface{}{
 		"Title":               hf.GetStringOption(OptionHtmlTitle, DefaultHtmlTitle) + " - 控制器信息",
 		"Timestamp":           hf.FormatTimestamp(),
+		"SystemInfo":          hf.GetSystemInfo(),
 		"ControllerData":      hf.controllerData,
 		"ControllerOnly":      controllerOnly,
+		"LSIControllers":      lsiControllers,
+		"NVMeControllers":     nvmeControllers,
 		"ShowTemperatureBar":  hf.GetBoolOption(OptionTemperatureBar, DefaultShowTemperatureBar),
+		"ShowSerial":          hf.GetBoolOption(OptionShowSerial, false),
+		"ShowFeatures":        hf.GetBoolOption(OptionShowFeatures, false),
+		"Verbose":             hf.GetBoolOption(OptionVerbose, false),
 		"EnableInteractivity": hf.GetBoolOption(OptionEnableInteractivity, DefaultEnableInteractivity),
 	}
 
@@ -187,7 +398,7 @@ func (hf *HTMLFormatter) generateControllerOnlyHTML(controllerOnly bool) error {
 		"string": func(v interface{}) string {
 			return fmt.Sprintf("%v", v)
 		},
-	}).Parse(controllerOnlyTemplate)
+	}).Parse(controllerOnlyTemplate + systemInfoTemplate)
 
 	if err != nil {
 		return fmt.Errorf("failed to parse HTML template: %w", err)
@@ -227,6 +438,120 @@ func (hf *HTMLFormatter) formatTemperatureBar(temp string) string {
         </div>`, position)
 }
 
+// Stops of the .temperature bar gradient (cool, warm, hot); heatmap cells
+// are colored along the same gradient
+var temperatureGradient = [3][3]float64{
+	{0x00, 0xb8, 0xd9},
+	{0xff, 0xab, 0x00},
+	{0xff, 0x56, 0x30},
+}
+
+// temperatureGradientMin is the temperature at the cool end of the gradient
+const temperatureGradientMin = 20
+
+// heatmapNoTemperatureColor fills cells of disks without a temperature
+const heatmapNoTemperatureColor = "#dfe1e6"
+
+// Heatmap grid layout in SVG units
+const (
+	heatmapColumns    = 8
+	heatmapCellWidth  = 100
+	heatmapCellHeight = 48
+	heatmapCellGap    = 6
+)
+
+// temperatureHeatmapSVG is the SVG grid of disks colored by temperature
+type temperatureHeatmapSVG struct {
+	Width  int
+	Height int
+	Cells  []heatmapCell
+}
+
+// heatmapCell is one disk in the temperature heatmap, with the positions of
+// its rectangle and of its two centered labels
+type heatmapCell struct {
+	Name          string
+	Temperature   string
+	Color         string
+	X, Y          int
+	Width, Height int
+	TextX         int
+	NameY, TempY  int
+}
+
+// temperatureHeatmap builds the heatmap, or returns nil when the option is
+// off or there are no disks
+func (hf *HTMLFormatter) temperatureHeatmap() *temperatureHeatmapSVG {
+	if !hf.GetBoolOption(OptionTemperatureHeatmap, DefaultTemperatureHeatmap) || hf.diskData == nil || len(hf.diskData.Disks) == 0 {
+		return nil
+	}
+
+	heatmap := &temperatureHeatmapSVG{}
+	for i, disk := range hf.diskData.Disks {
+		x := (i % heatmapColumns) * (heatmapCellWidth + heatmapCellGap)
+		y := (i / heatmapColumns) * (heatmapCellHeight + heatmapCellGap)
+		cell := heatmapCell{
+			Name:        hf.GetDiskName(disk),
+			Temperature: "N/A",
+			Color:       heatmapNoTemperatureColor,
+			X:           x,
+			Y:           y,
+			Width:       heatmapCellWidth,
+			Height:      heatmapCellHeight,
+			TextX:       x + heatmapCellWidth/2,
+			NameY:       y + 20,
+			TempY:       y + 38,
+		}
+		// Color by the hottest sensor, as the status check does
+		if temp, _, ok := disk.GetMaxTemperature(); ok {
+			warn, crit := disk.GetTemperatureThresholds()
+			cell.Temperature = fmt.Sprintf("%d°C", temp)
+			cell.Color = temperatureColor(temp, warn, crit)
+		}
+		heatmap.Cells = append(heatmap.Cells, cell)
+	}
+
+	columns := len(heatmap.Cells)
+	if columns > heatmapColumns {
+		columns = heatmapColumns
+	}
+	rows := (len(heatmap.Cells) + heatmapColumns - 1) / heatmapColumns
+	heatmap.Width = columns*(heatmapCellWidth+heatmapCellGap) - heatmapCellGap
+	heatmap.Height = rows*(heatmapCellHeight+heatmapCellGap) - heatmapCellGap
+	return heatmap
+}
+
+// temperatureColor returns the gradient color for a temperature: the cool
+// stop at temperatureGradientMin, the warm stop at the disk's warning
+// temperature and the hot stop at its critical temperature
+func temperatureColor(temp, warn, crit int) string {
+	var from, to [3]float64
+	var fraction float64
+	switch {
+	case temp >= crit:
+		return rgbHex(temperatureGradient[2])
+	case temp >= warn:
+		from, to = temperatureGradient[1], temperatureGradient[2]
+		fraction = float64(temp-warn) / float64(crit-warn)
+	case temp <= temperatureGradientMin || warn <= temperatureGradientMin:
+		return rgbHex(temperatureGradient[0])
+	default:
+		from, to = temperatureGradient[0], temperatureGradient[1]
+		fraction = float64(temp-temperatureGradientMin) / float64(warn-temperatureGradientMin)
+	}
+
+	var color [3]float64
+	for i := range color {
+		color[i] = from[i] + (to[i]-from[i])*fraction
+	}
+	return rgbHex(color)
+}
+
+// rgbHex formats an RGB color as #rrggbb
+func rgbHex(color [3]float64) string {
+	return fmt.Sprintf("#%02x%02x%02x", int(color[0]+0.5), int(color[1]+0.5), int(color[2]+0.5))
+}
+
 // HTML templates
 
 // The main HTML template
@@ -272,6 +597,10 @@ const htmlTemplate = `<!DOCTYPE html>
             width: 100%;
             border-collapse: collapse;
         }
+        .raw-smart td {
+            padding: 2px 8px;
+            font-family: monospace;
+        }
         th {
             background-color: #f4f5f7;
             text-align: left;
@@ -352,6 +681,20 @@ const htmlTemplate = `<!DOCTYPE html>
             border-radius: 4px;
             font-size: 14px;
         }
+        .toolbar {
+            margin-bottom: 10px;
+        }
+        .toolbar button {
+            padding: 6px 12px;
+            border: 1px solid #ddd;
+            border-radius: 4px;
+            background-color: white;
+            cursor: pointer;
+            font-size: 14px;
+        }
+        #disk-tab.only-problems tr[data-status="status-ok"] {
+            display: none;
+        }
         .tab-container {
             margin-bottom: 20px;
         }
@@ -389,13 +732,34 @@ const htmlTemplate = `<!DOCTYPE html>
             text-align: right;
             margin-bottom: 10px;
         }
+        .banner {
+            padding: 12px 20px;
+            margin-bottom: 20px;
+            border-radius: 5px;
+            font-weight: bold;
+        }
+        .banner-error {
+            background-color: #ffebe6;
+            color: #de350b;
+            border: 1px solid #de350b;
+        }
+        .banner-warning {
+            background-color: #fffae6;
+            color: #ff8b00;
+            border: 1px solid #ff8b00;
+        }
+        .banner-info {
+            background-color: #deebff;
+            color: #0747a6;
+            border: 1px solid #0747a6;
+        }
     </style>
 </head>
 <body>
     <div class="container">
         <h1>{{.Title}}</h1>
         
-        <div class="last-update">最后更新时间: {{.Timestamp}}</div>
+        <div class="last-update">最后更新时间: {{.Timestamp}}</div>{{template "systemInfo" .}}
         
         {{if .SummaryInfo}}
         <div class="summary-tiles">
@@ -419,51 +783,124 @@ const htmlTemplate = `<!DOCTYPE html>
                 <h3>错误数</h3>
                 <div class="value {{if ne .SummaryInfo.ErrorCount "0"}}status-error{{end}}">{{.SummaryInfo.ErrorCount}}</div>
             </div>
+            {{if .SummaryInfo.RawCapacity}}
+            <div class="summary-tile">
+                <h3>原始容量</h3>
+                <div class="value">{{.SummaryInfo.RawCapacity}}</div>
+            </div>
+            {{end}}
+            {{if .SummaryInfo.UsableCapacity}}
+            <div class="summary-tile">
+                <h3>可用容量 (估算)</h3>
+                <div class="value">{{.SummaryInfo.UsableCapacity}}</div>
+            </div>
+            {{end}}
         </div>
+        {{if .SummaryInfo.TruncatedFrom}}
+        <div class="banner">磁盘数量超过上限，仅显示前 {{.SummaryInfo.TotalDisks}} 个 (共 {{.SummaryInfo.TruncatedFrom}} 个)</div>
+        {{end}}
+        {{if .SummaryInfo.AgedDisks}}
+        <div class="banner banner-info">通电超过 {{.SummaryInfo.AgeInfoYears}} 年，建议规划更换: {{.SummaryInfo.AgedDisks}}</div>
+        {{end}}
         {{end}}
         
         <div class="tab-container">
             <ul class="tabs">
                 <li class="tab active" onclick="openTab(event, 'disk-tab')">磁盘</li>
                 <li class="tab" onclick="openTab(event, 'controller-tab')">控制器</li>
+                {{if .PoolSummary}}
+                <li class="tab" onclick="openTab(event, 'pool-tab')">存储池</li>
+                {{end}}
                 {{if .HasIncrement}}
                 <li class="tab" onclick="openTab(event, 'history-tab')">历史数据</li>
                 {{end}}
             </ul>
             
             <div id="disk-tab" class="tab-content active">
-                <!-- SSD Section -->
-                {{if index .GroupedDisksStr "SAS_SSD"}}
+                {{if .EnableInteractivity}}
+                <div class="toolbar">
+                    <button id="problem-toggle" type="button" onclick="toggleProblemDisks(this)">仅显示问题磁盘</button>
+                </div>
+                {{end}}
+                <!-- Error Overview Section -->
+                {{if .ErrorOverview}}
+                <div class="panel">
+                    <div class="panel-header">
+                        <span>错误总览</span>
+                    </div>
+                    <div class="panel-body">
+                        <table id="error-table">
+                            <thead>
+                                <tr>
+                                    <th>磁盘名称</th>
+                                    <th>状态</th>
+                                    <th>未修正错误</th>
+                                    <th>非介质错误</th>
+                                    <th>增长缺陷</th>
+                                    <th>待映射扇区</th>
+                                    <th>合计</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .ErrorOverview}}
+                                <tr>
+                                    <td>{{.Name}}</td>
+                                    <td class="{{getStatusClass (printf "%s" .Status)}}">{{.Status}}</td>
+                                    <td>{{.UncorrectedErrors}}</td>
+                                    <td>{{.NonMediumErrors}}</td>
+                                    <td>{{.GrownDefects}}</td>
+                                    <td>{{.PendingSectors}}</td>
+                                    <td>{{.Total}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+                {{end}}{{template "temperatureHeatmap" .}}
+
+                <!-- Disk type sections, in the shared group order -->
+                {{range .DiskGroups}}
                 <div class="panel">
                     <div class="panel-header">
-                        <span>SAS/SATA 固态硬盘</span>
+                        <span>{{.Title}}</span>
                     </div>
                     <div class="search-box">
-                        <input type="text" placeholder="搜索磁盘..." oninput="filterTable('ssd-table', this.value)">
+                        <input type="text" placeholder="搜索磁盘..." oninput="filterTable('{{.TableID}}', this.value)">
                     </div>
                     <div class="panel-body">
+                        {{if .Custom}}
+                        {{template "customDiskTable" .Custom}}
+                        {{else if eq .Type "SAS_SSD"}}
                         <table id="ssd-table">
                             <thead>
                                 <tr>
-                                    <th onclick="sortTable('ssd-table', 0)">磁盘名称</th>
-                                    <th onclick="sortTable('ssd-table', 1)">型号</th>
-                                    <th onclick="sortTable('ssd-table', 2)">容量</th>
-                                    <th onclick="sortTable('ssd-table', 3)">存储池</th>
-                                    <th onclick="sortTable('ssd-table', 4)">温度</th>
-                                    <th onclick="sortTable('ssd-table', 5)">通电时间</th>
-                                    <th onclick="sortTable('ssd-table', 6)">已用寿命</th>
-                                    <th onclick="sortTable('ssd-table', 7)">SMART状态</th>
-                                    <th onclick="sortTable('ssd-table', 8)">已读数据</th>
-                                    <th onclick="sortTable('ssd-table', 9)">已写数据</th>
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', 0)">磁盘名称</th>
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', 1)">型号</th>
+                                    <th data-sort-type="size" onclick="sortTable('ssd-table', 2)">容量</th>
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', 3)">存储池</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('ssd-table', 4)">温度</th>
+                                    <th data-sort-type="duration" onclick="sortTable('ssd-table', 5)">通电时间</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('ssd-table', 6)">已用寿命</th>
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', 7)">SMART状态</th>
+                                    <th data-sort-type="size" onclick="sortTable('ssd-table', 8)">已读数据</th>
+                                    <th data-sort-type="size" onclick="sortTable('ssd-table', 9)">已写数据</th>
+                                    {{if $.ShowFeatures}}
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', 10)">TRIM</th>
+                                    {{end}}
+                                    {{if $.ShowSerial}}
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', {{if $.ShowFeatures}}11{{else}}10{{end}})">序列号</th>
+                                    <th data-sort-type="text" onclick="sortTable('ssd-table', {{if $.ShowFeatures}}12{{else}}11{{end}})">WWN</th>
+                                    {{end}}
                                 </tr>
                             </thead>
                             <tbody>
-                                {{range index .GroupedDisksStr "SAS_SSD"}}
-                                <tr>
-                                    <td>{{.Name}}</td>
+                                {{range .Disks}}
+                                <tr data-status="{{getStatusClass (printf "%s" .GetStatus)}}">
+                                    <td>{{diskName .}}</td>
                                     <td>{{.Model}}</td>
                                     <td>{{formatSize .Size}}</td>
-                                    <td>{{.Pool}}</td>
+                                    <td>{{poolName .Pool}}</td>
                                     <td>
                                         {{if $.ShowTemperatureBar}}
                                         {{formatTemperatureBar .GetDisplayTemperature}}
@@ -475,46 +912,44 @@ const htmlTemplate = `<!DOCTYPE html>
                                     <td class="{{getStatusClass (.GetAttribute "Smart_Status")}}">{{.GetAttribute "Smart_Status"}}</td>
                                     <td>{{.GetAttribute "Data_Read"}}</td>
                                     <td>{{.GetAttribute "Data_Written"}}</td>
+                                    {{if $.ShowFeatures}}
+                                    <td>{{.GetDisplayTrimSupport}}</td>
+                                    {{end}}
+                                    {{if $.ShowSerial}}
+                                    <td>{{or .Serial "N/A"}}</td>
+                                    <td>{{or .WWN "N/A"}}</td>
+                                    {{end}}
                                 </tr>
                                 {{end}}
                             </tbody>
                         </table>
-                    </div>
-                </div>
-                {{end}}
-                
-                <!-- HDD Section -->
-                {{if index .GroupedDisksStr "SAS_HDD"}}
-                <div class="panel">
-                    <div class="panel-header">
-                        <span>SAS/SATA 机械硬盘</span>
-                    </div>
-                    <div class="search-box">
-                        <input type="text" placeholder="搜索磁盘..." oninput="filterTable('hdd-table', this.value)">
-                    </div>
-                    <div class="panel-body">
+                        {{else if eq .Type "SAS_HDD"}}
                         <table id="hdd-table">
                             <thead>
                                 <tr>
-                                    <th onclick="sortTable('hdd-table', 0)">磁盘名称</th>
-                                    <th onclick="sortTable('hdd-table', 1)">型号</th>
-                                    <th onclick="sortTable('hdd-table', 2)">容量</th>
-                                    <th onclick="sortTable('hdd-table', 3)">存储池</th>
-                                    <th onclick="sortTable('hdd-table', 4)">温度</th>
-                                    <th onclick="sortTable('hdd-table', 5)">通电时间</th>
-                                    <th onclick="sortTable('hdd-table', 6)">SMART状态</th>
-                                    <th onclick="sortTable('hdd-table', 7)">已读数据</th>
-                                    <th onclick="sortTable('hdd-table', 8)">已写数据</th>
-                                    <th onclick="sortTable('hdd-table', 9)">未修正错误</th>
+                                    <th data-sort-type="text" onclick="sortTable('hdd-table', 0)">磁盘名称</th>
+                                    <th data-sort-type="text" onclick="sortTable('hdd-table', 1)">型号</th>
+                                    <th data-sort-type="size" onclick="sortTable('hdd-table', 2)">容量</th>
+                                    <th data-sort-type="text" onclick="sortTable('hdd-table', 3)">存储池</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('hdd-table', 4)">温度</th>
+                                    <th data-sort-type="duration" onclick="sortTable('hdd-table', 5)">通电时间</th>
+                                    <th data-sort-type="text" onclick="sortTable('hdd-table', 6)">SMART状态</th>
+                                    <th data-sort-type="size" onclick="sortTable('hdd-table', 7)">已读数据</th>
+                                    <th data-sort-type="size" onclick="sortTable('hdd-table', 8)">已写数据</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('hdd-table', 9)">未修正错误</th>
+                                    {{if $.ShowSerial}}
+                                    <th data-sort-type="text" onclick="sortTable('hdd-table', 10)">序列号</th>
+                                    <th data-sort-type="text" onclick="sortTable('hdd-table', 11)">WWN</th>
+                                    {{end}}
                                 </tr>
                             </thead>
                             <tbody>
-                                {{range index .GroupedDisksStr "SAS_HDD"}}
-                                <tr>
-                                    <td>{{.Name}}</td>
+                                {{range .Disks}}
+                                <tr data-status="{{getStatusClass (printf "%s" .GetStatus)}}">
+                                    <td>{{diskName .}}</td>
                                     <td>{{.Model}}</td>
                                     <td>{{formatSize .Size}}</td>
-                                    <td>{{.Pool}}</td>
+                                    <td>{{poolName .Pool}}</td>
                                     <td>
                                         {{if $.ShowTemperatureBar}}
                                         {{formatTemperatureBar .GetDisplayTemperature}}
@@ -526,47 +961,45 @@ const htmlTemplate = `<!DOCTYPE html>
                                     <td>{{.GetAttribute "Data_Read"}}</td>
                                     <td>{{.GetAttribute "Data_Written"}}</td>
                                     <td>{{.GetAttribute "Uncorrected_Errors"}}</td>
+                                    {{if $.ShowSerial}}
+                                    <td>{{or .Serial "N/A"}}</td>
+                                    <td>{{or .WWN "N/A"}}</td>
+                                    {{end}}
                                 </tr>
                                 {{end}}
                             </tbody>
                         </table>
-                    </div>
-                </div>
-                {{end}}
-                
-                <!-- NVMe Section -->
-                {{if index .GroupedDisksStr "NVME_SSD"}}
-                <div class="panel">
-                    <div class="panel-header">
-                        <span>NVMe 固态硬盘</span>
-                    </div>
-                    <div class="search-box">
-                        <input type="text" placeholder="搜索磁盘..." oninput="filterTable('nvme-table', this.value)">
-                    </div>
-                    <div class="panel-body">
+                        {{else if eq .Type "NVME_SSD"}}
                         <table id="nvme-table">
                             <thead>
                                 <tr>
-                                    <th onclick="sortTable('nvme-table', 0)">磁盘名称</th>
-                                    <th onclick="sortTable('nvme-table', 1)">型号</th>
-                                    <th onclick="sortTable('nvme-table', 2)">容量</th>
-                                    <th onclick="sortTable('nvme-table', 3)">存储池</th>
-                                    <th onclick="sortTable('nvme-table', 4)">温度</th>
-                                    <th onclick="sortTable('nvme-table', 5)">通电时间</th>
-                                    <th onclick="sortTable('nvme-table', 6)">已用寿命</th>
-                                    <th onclick="sortTable('nvme-table', 7)">可用备件</th>
-                                    <th onclick="sortTable('nvme-table', 8)">SMART状态</th>
-                                    <th onclick="sortTable('nvme-table', 9)">已读数据</th>
-                                    <th onclick="sortTable('nvme-table', 10)">已写数据</th>
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', 0)">磁盘名称</th>
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', 1)">型号</th>
+                                    <th data-sort-type="size" onclick="sortTable('nvme-table', 2)">容量</th>
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', 3)">存储池</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('nvme-table', 4)">温度</th>
+                                    <th data-sort-type="duration" onclick="sortTable('nvme-table', 5)">通电时间</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('nvme-table', 6)">已用寿命</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('nvme-table', 7)">可用备件</th>
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', 8)">SMART状态</th>
+                                    <th data-sort-type="size" onclick="sortTable('nvme-table', 9)">已读数据</th>
+                                    <th data-sort-type="size" onclick="sortTable('nvme-table', 10)">已写数据</th>
+                                    {{if $.ShowFeatures}}
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', 11)">TRIM</th>
+                                    {{end}}
+                                    {{if $.ShowSerial}}
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', {{if $.ShowFeatures}}12{{else}}11{{end}})">序列号</th>
+                                    <th data-sort-type="text" onclick="sortTable('nvme-table', {{if $.ShowFeatures}}13{{else}}12{{end}})">WWN</th>
+                                    {{end}}
                                 </tr>
                             </thead>
                             <tbody>
-                                {{range index .GroupedDisksStr "NVME_SSD"}}
-                                <tr>
-                                    <td>{{.Name}}</td>
+                                {{range .Disks}}
+                                <tr data-status="{{getStatusClass (printf "%s" .GetStatus)}}">
+                                    <td>{{diskName .}}</td>
                                     <td>{{.Model}}</td>
                                     <td>{{formatSize .Size}}</td>
-                                    <td>{{.Pool}}</td>
+                                    <td>{{poolName .Pool}}</td>
                                     <td>
                                         {{if $.ShowTemperatureBar}}
                                         {{formatTemperatureBar .GetDisplayTemperature}}
@@ -579,42 +1012,89 @@ const htmlTemplate = `<!DOCTYPE html>
                                     <td class="{{getStatusClass (.GetAttribute "Smart_Status")}}">{{.GetAttribute "Smart_Status"}}</td>
                                     <td>{{.GetAttribute "Data_Read"}}</td>
                                     <td>{{.GetAttribute "Data_Written"}}</td>
+                                    {{if $.ShowFeatures}}
+                                    <td>{{.GetDisplayTrimSupport}}</td>
+                                    {{end}}
+                                    {{if $.ShowSerial}}
+                                    <td>{{or .Serial "N/A"}}</td>
+                                    <td>{{or .WWN "N/A"}}</td>
+                                    {{end}}
                                 </tr>
                                 {{end}}
                             </tbody>
                         </table>
+                        {{else if eq .Type "VIRTUAL"}}
+                        <table id="virtual-table">
+                            <thead>
+                                <tr>
+                                    <th data-sort-type="text" onclick="sortTable('virtual-table', 0)">磁盘名称</th>
+                                    <th data-sort-type="text" onclick="sortTable('virtual-table', 1)">型号</th>
+                                    <th data-sort-type="size" onclick="sortTable('virtual-table', 2)">容量</th>
+                                    <th data-sort-type="text" onclick="sortTable('virtual-table', 3)">存储池</th>
+                                    <th data-sort-type="text" onclick="sortTable('virtual-table', 4)">类型</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .Disks}}
+                                <tr data-status="{{getStatusClass (printf "%s" .GetStatus)}}">
+                                    <td>{{diskName .}}</td>
+                                    <td>{{.Model}}</td>
+                                    <td>{{formatSize .Size}}</td>
+                                    <td>{{poolName .Pool}}</td>
+                                    <td>{{.GetAttribute "Type"}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                        {{else}}
+                        <table id="{{.TableID}}">
+                            <thead>
+                                <tr>
+                                    <th data-sort-type="text" onclick="sortTable('{{.TableID}}', 0)">磁盘名称</th>
+                                    <th data-sort-type="text" onclick="sortTable('{{.TableID}}', 1)">型号</th>
+                                    <th data-sort-type="size" onclick="sortTable('{{.TableID}}', 2)">容量</th>
+                                    <th data-sort-type="text" onclick="sortTable('{{.TableID}}', 3)">存储池</th>
+                                    <th data-sort-type="text" onclick="sortTable('{{.TableID}}', 4)">SMART状态</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .Disks}}
+                                <tr data-status="{{getStatusClass (printf "%s" .GetStatus)}}">
+                                    <td>{{diskName .}}</td>
+                                    <td>{{.Model}}</td>
+                                    <td>{{formatSize .Size}}</td>
+                                    <td>{{poolName .Pool}}</td>
+                                    <td class="{{getStatusClass (.GetAttribute "Smart_Status")}}">{{.GetAttribute "Smart_Status"}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                        {{end}}
                     </div>
                 </div>
                 {{end}}
-                
-                <!-- Virtual Devices Section -->
-                {{if index .GroupedDisksStr "VIRTUAL"}}
+
+                <!-- Status Reasons Section -->
+                {{if .FlaggedDisks}}
                 <div class="panel">
                     <div class="panel-header">
-                        <span>虚拟设备</span>
-                    </div>
-                    <div class="search-box">
-                        <input type="text" placeholder="搜索磁盘..." oninput="filterTable('virtual-table', this.value)">
+                        <span>状态说明</span>
                     </div>
                     <div class="panel-body">
-                        <table id="virtual-table">
+                        <table id="reason-table">
                             <thead>
                                 <tr>
-                                    <th onclick="sortTable('virtual-table', 0)">磁盘名称</th>
-                                    <th onclick="sortTable('virtual-table', 1)">型号</th>
-                                    <th onclick="sortTable('virtual-table', 2)">容量</th>
-                                    <th onclick="sortTable('virtual-table', 3)">存储池</th>
-                                    <th onclick="sortTable('virtual-table', 4)">类型</th>
+                                    <th>磁盘名称</th>
+                                    <th>状态</th>
+                                    <th>原因</th>
                                 </tr>
                             </thead>
                             <tbody>
-                                {{range index .GroupedDisksStr "VIRTUAL"}}
+                                {{range .FlaggedDisks}}
                                 <tr>
-                                    <td>{{.Name}}</td>
-                                    <td>{{.Model}}</td>
-                                    <td>{{formatSize .Size}}</td>
-                                    <td>{{.Pool}}</td>
-                                    <td>{{.GetAttribute "Type"}}</td>
+                                    <td>{{diskName .}}</td>
+                                    <td class="{{getStatusClass (printf "%s" .GetStatus)}}">{{.GetStatus}}</td>
+                                    <td>{{or .StatusReason "N/A"}}</td>
                                 </tr>
                                 {{end}}
                             </tbody>
@@ -622,6 +1102,42 @@ const htmlTemplate = `<!DOCTYPE html>
                     </div>
                 </div>
                 {{end}}
+
+                <!-- Raw SMART Data Section -->
+                {{if and .IncludeRawSmart .DiskData}}
+                <div class="panel">
+                    <div class="panel-header">
+                        <span>原始SMART数据</span>
+                    </div>
+                    <div class="panel-body">
+                        <table id="raw-smart-table">
+                            <thead>
+                                <tr>
+                                    <th>磁盘名称</th>
+                                    <th>SMART数据</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .DiskData.Disks}}
+                                <tr>
+                                    <td>{{diskName .}}</td>
+                                    <td>
+                                        <details>
+                                            <summary>{{len .SMARTData}} 项</summary>
+                                            <table class="raw-smart">
+                                                {{range $key, $value := .SMARTData}}
+                                                <tr><td>{{html $key}}</td><td>{{html $value}}</td></tr>
+                                                {{end}}
+                                            </table>
+                                        </details>
+                                    </td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+                {{end}}{{template "legend" .}}
             </div>
             
             <div id="controller-tab" class="tab-content">
@@ -639,21 +1155,31 @@ const htmlTemplate = `<!DOCTYPE html>
                                     <th>型号</th>
                                     <th>固件版本</th>
                                     <th>驱动版本</th>
+                                    {{if $.Verbose}}
+                                    <th>固件包版本</th>
+                                    <th>BIOS版本</th>
+                                    <th>NVDATA版本</th>
+                                    {{end}}
                                     <th>温度</th>
                                     <th>设备数</th>
                                     <th>状态</th>
                                 </tr>
                             </thead>
                             <tbody>
-                                {{range $id, $controller := .ControllerData.LSIControllers}}
+                                {{range $controller := .LSIControllers}}
                                 <tr>
-                                    <td>{{$id}}</td>
+                                    <td>{{$controller.ID}}</td>
                                     <td>{{$controller.Model}}</td>
                                     <td>{{$controller.FirmwareVersion}}</td>
                                     <td>{{$controller.DriverVersion}}</td>
-                                    <td>{{$controller.GetDisplayTemperature}}</td>
+                                    {{if $.Verbose}}
+                                    <td>{{$controller.FWPackageBuild}}</td>
+                                    <td>{{$controller.BIOSVersion}}</td>
+                                    <td>{{$controller.NVDATAVersion}}</td>
+                                    {{end}}
+                                    <td>{{$controller.GetDisplayTemperatureTrend}}</td>
                                     <td>{{$controller.DeviceCount}}</td>
-                                    <td class="{{getStatusClass (string $controller.Status)}}">{{$controller.Status}}</td>
+                                    <td class="{{getStatusClass (string $controller.Status)}}"{{if $controller.StatusReason}} title="{{$controller.StatusReason}}"{{end}}>{{$controller.Status}}</td>
                                 </tr>
                                 {{end}}
                             </tbody>
@@ -678,11 +1204,11 @@ const htmlTemplate = `<!DOCTYPE html>
                                 </tr>
                             </thead>
                             <tbody>
-                                {{range $id, $controller := .ControllerData.NVMeControllers}}
+                                {{range $controller := .NVMeControllers}}
                                 <tr>
                                     <td>{{$controller.Bus}}</td>
                                     <td>{{$controller.Description}}</td>
-                                    <td>{{$controller.GetDisplayTemperature}}</td>
+                                    <td>{{$controller.GetDisplayTemperatureTrend}}</td>
                                 </tr>
                                 {{end}}
                             </tbody>
@@ -692,6 +1218,62 @@ const htmlTemplate = `<!DOCTYPE html>
                 {{end}}
             </div>
             
+            {{if .PoolSummary}}
+            <div id="pool-tab" class="tab-content">
+                {{range .PoolSummary}}
+                {{if .IsDegraded}}
+                <div class="banner banner-error">存储池 {{.Name}} 状态异常: {{.Status}}</div>
+                {{end}}
+                {{if .FillWarning}}
+                <div class="banner banner-warning">WARNING 存储池 {{.Name}} 已用容量 {{.GetDisplayUsage}}，超过告警阈值</div>
+                {{end}}
+                {{if .HasMixedSectors}}
+                <div class="banner banner-warning">WARNING 存储池 {{.Name}} 混用不同扇区大小: {{.GetDisplaySectorSizes}}</div>
+                {{end}}
+                {{if .MixedCapacityVdevs}}
+                <div class="banner banner-warning">WARNING 存储池 {{.Name}} 的vdev混用不同容量磁盘，多出的容量无法使用: {{mixedCapacityVdevs .}}</div>
+                {{end}}
+                {{end}}
+                <div class="panel">
+                    <div class="panel-header">
+                        <span>存储池汇总</span>
+                    </div>
+                    <div class="panel-body">
+                        <table id="pool-table">
+                            <thead>
+                                <tr>
+                                    <th data-sort-type="text" onclick="sortTable('pool-table', 0)">存储池</th>
+                                    <th data-sort-type="text" onclick="sortTable('pool-table', 1)">状态</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('pool-table', 2)">磁盘数</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('pool-table', 3)">警告数</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('pool-table', 4)">错误数</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('pool-table', 5)">平均温度</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('pool-table', 6)">最高温度</th>
+                                    <th data-sort-type="size" onclick="sortTable('pool-table', 7)">原始容量</th>
+                                    <th data-sort-type="numeric" onclick="sortTable('pool-table', 8)">已用容量</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .PoolSummary}}
+                                <tr>
+                                    <td>{{.Name}}</td>
+                                    <td class="{{if .IsDegraded}}status-error{{else}}status-ok{{end}}">{{.GetDisplayStatus}}</td>
+                                    <td>{{.DiskCount}}</td>
+                                    <td class="{{if .WarningCount}}status-warning{{end}}">{{.WarningCount}}</td>
+                                    <td class="{{if .ErrorCount}}status-error{{end}}">{{.ErrorCount}}</td>
+                                    <td>{{.GetDisplayAvgTemperature}}</td>
+                                    <td>{{.GetDisplayMaxTemperature}}</td>
+                                    <td>{{formatBytes .RawCapacity}}</td>
+                                    <td class="{{if .FillWarning}}status-warning{{end}}">{{.GetDisplayUsage}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+            </div>
+            {{end}}
+            
             {{if .HasIncrement}}
             <div id="history-tab" class="tab-content">
                 <div class="panel">
@@ -705,24 +1287,24 @@ const htmlTemplate = `<!DOCTYPE html>
                         <table id="increment-table">
                             <thead>
                                 <tr>
-                                    <th onclick="sortTable('increment-table', 0)">磁盘名称</th>
-                                    <th onclick="sortTable('increment-table', 1)">类型</th>
-                                    <th onclick="sortTable('increment-table', 2)">型号</th>
-                                    <th onclick="sortTable('increment-table', 3)">存储池</th>
-                                    <th onclick="sortTable('increment-table', 4)">当前读取总量</th>
-                                    <th onclick="sortTable('increment-table', 5)">读取增量</th>
-                                    <th onclick="sortTable('increment-table', 6)">当前写入总量</th>
-                                    <th onclick="sortTable('increment-table', 7)">写入增量</th>
+                                    <th data-sort-type="text" onclick="sortTable('increment-table', 0)">磁盘名称</th>
+                                    <th data-sort-type="text" onclick="sortTable('increment-table', 1)">类型</th>
+                                    <th data-sort-type="text" onclick="sortTable('increment-table', 2)">型号</th>
+                                    <th data-sort-type="text" onclick="sortTable('increment-table', 3)">存储池</th>
+                                    <th data-sort-type="size" onclick="sortTable('increment-table', 4)">当前读取总量</th>
+                                    <th data-sort-type="size" onclick="sortTable('increment-table', 5)">读取增量</th>
+                                    <th data-sort-type="size" onclick="sortTable('increment-table', 6)">当前写入总量</th>
+                                    <th data-sort-type="size" onclick="sortTable('increment-table', 7)">写入增量</th>
                                 </tr>
                             </thead>
                             <tbody>
                                 {{range .DiskData.Disks}}
                                 {{if or .ReadIncrement .WriteIncrement}}
                                 <tr>
-                                    <td>{{.Name}}</td>
+                                    <td>{{diskName .}}</td>
                                     <td>{{.Type}}</td>
                                     <td>{{.Model}}</td>
-                                    <td>{{.Pool}}</td>
+                                    <td>{{poolName .Pool}}</td>
                                     <td>{{.GetAttribute "Data_Read"}}</td>
                                     <td>{{.ReadIncrement}}</td>
                                     <td>{{.GetAttribute "Data_Written"}}</td>
@@ -770,6 +1352,7 @@ const htmlTemplate = `<!DOCTYPE html>
             
             // Set sorting direction to ascending
             var th = table.getElementsByTagName("th")[column];
+            var sortType = th.getAttribute("data-sort-type") || "text";
             
             // Remove sorting indicators from all headers
             var headers = table.getElementsByTagName("th");
@@ -792,34 +1375,13 @@ const htmlTemplate = `<!DOCTYPE html>
                 
                 for (i = 1; i < (rows.length - 1); i++) {
                     shouldSwitch = false;
-                    x = rows[i].getElementsByTagName("td")[column];
-                    y = rows[i + 1].getElementsByTagName("td")[column];
+                    x = sortValue(rows[i].getElementsByTagName("td")[column], sortType);
+                    y = sortValue(rows[i + 1].getElementsByTagName("td")[column], sortType);
                     
-                    // Compare values (handle numbers and text differently)
-                    if (dir === "asc") {
-                        if (isNaN(x.innerHTML) || isNaN(y.innerHTML)) {
-                            if (x.innerHTML.toLowerCase() > y.innerHTML.toLowerCase()) {
-                                shouldSwitch = true;
-                                break;
-                            }
-                        } else {
-                            if (Number(x.innerHTML) > Number(y.innerHTML)) {
-                                shouldSwitch = true;
-                                break;
-                            }
-                        }
-                    } else if (dir === "desc") {
-                        if (isNaN(x.innerHTML) || isNaN(y.innerHTML)) {
-                            if (x.innerHTML.toLowerCase() < y.innerHTML.toLowerCase()) {
-                                shouldSwitch = true;
-                                break;
-                            }
-                        } else {
-                            if (Number(x.innerHTML) < Number(y.innerHTML)) {
-                                shouldSwitch = true;
-                                break;
-                            }
-                        }
+                    // Compare values parsed according to the column's sort type
+                    if ((dir === "asc" && x > y) || (dir === "desc" && x < y)) {
+                        shouldSwitch = true;
+                        break;
                     }
                 }
                 
@@ -830,6 +1392,39 @@ const htmlTemplate = `<!DOCTYPE html>
             }
         }
         
+        // Convert a cell to a comparable value according to its column's sort type;
+        // unparseable values such as "N/A" sort below every number
+        function sortValue(cell, sortType) {
+            var text = (cell.innerText || cell.textContent).trim();
+            var match;
+            
+            switch (sortType) {
+            case "numeric":
+                // "35°C", "3%", "6.0 Gb/s"
+                var number = parseFloat(text);
+                return isNaN(number) ? -Infinity : number;
+            case "size":
+                // "1.50 TB", "4.00 TiB", "+512 GB", "4T"
+                match = text.match(/^([-+]?[\d.]+)\s*([KMGTPE]?)(i?)B?/i);
+                if (!match) {
+                    return -Infinity;
+                }
+                var exponent = match[2] ? "KMGTPE".indexOf(match[2].toUpperCase()) + 1 : 0;
+                return parseFloat(match[1]) * Math.pow(match[3] ? 1024 : 1000, exponent);
+            case "duration":
+                // "1y 2m 3d 4h", matching the power-on time format
+                var units = {y: 8760, m: 720, d: 24, h: 1};
+                var hours = 0, found = false, re = /(\d+)([ymdh])/g;
+                while ((match = re.exec(text)) !== null) {
+                    hours += parseInt(match[1], 10) * units[match[2]];
+                    found = true;
+                }
+                return found ? hours : -Infinity;
+            default:
+                return text === "" || isNaN(text) ? text.toLowerCase() : Number(text);
+            }
+        }
+        
         // Table filtering functionality
         function filterTable(tableId, query) {
             var table = document.getElementById(tableId);
@@ -856,10 +1451,101 @@ const htmlTemplate = `<!DOCTYPE html>
                 rows[i].style.display = shouldShow ? "" : "none";
             }
         }
+
+        // Problem disk toggle: hides healthy disk rows, combined with any text filter
+        function toggleProblemDisks(button) {
+            var tab = document.getElementById("disk-tab");
+            var active = tab.classList.toggle("only-problems");
+            button.textContent = active ? "显示全部磁盘" : "仅显示问题磁盘";
+        }
     </script>
     {{end}}
 </body>
-</html>`
+</html>{{define "customDiskTable"}}<table id="{{.ID}}">
+                            <thead>
+                                <tr>
+                                    {{range $i, $header := .Headers}}
+                                    <th data-sort-type="{{$header.SortType}}" onclick="sortTable('{{$.ID}}', {{$i}})">{{$header.Title}}</th>
+                                    {{end}}
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{range .Rows}}
+                                <tr data-status="{{getStatusClass .Status}}">
+                                    {{range .Cells}}
+                                    <td>{{.}}</td>
+                                    {{end}}
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>{{end}}`
+
+// Temperature heatmap panel above the disk type sections
+// (temperature_heatmap option)
+const heatmapTemplate = `{{define "temperatureHeatmap"}}{{with .Heatmap}}
+                <div class="panel">
+                    <div class="panel-header">
+                        <span>温度热力图</span>
+                    </div>
+                    <div class="panel-body">
+                        <svg id="temperature-heatmap" xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" font-family="sans-serif" text-anchor="middle">
+                            {{range .Cells}}<g class="heatmap-cell" data-disk="{{.Name}}">
+                                <title>{{.Name}}: {{.Temperature}}</title>
+                                <rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" rx="4" fill="{{.Color}}"></rect>
+                                <text x="{{.TextX}}" y="{{.NameY}}" font-size="13" font-weight="bold">{{.Name}}</text>
+                                <text x="{{.TextX}}" y="{{.TempY}}" font-size="12">{{.Temperature}}</text>
+                            </g>
+                            {{end}}
+                        </svg>
+                    </div>
+                </div>{{end}}{{end}}`
+
+// Column and status legend at the end of the disk tab (--legend)
+const legendTemplate = `{{define "legend"}}{{with .Legend}}
+                <div class="panel" id="legend">
+                    <div class="panel-header">
+                        <span>图例</span>
+                    </div>
+                    <div class="panel-body">
+                        <table>
+                            <thead>
+                                <tr><th>列</th><th>单位</th><th>说明</th></tr>
+                            </thead>
+                            <tbody>
+                                {{range .Columns}}<tr><td>{{.DisplayName}}</td><td>{{or .Unit "-"}}</td><td>{{.Description}}</td></tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                        <table>
+                            <thead>
+                                <tr><th>状态</th><th>说明</th></tr>
+                            </thead>
+                            <tbody>
+                                {{range .Statuses}}<tr><td class="{{.Class}}">{{.Name}}</td><td>{{.Description}}</td></tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>{{end}}{{end}}`
+
+// Host information panel shown under the timestamp (--system-info), shared
+// by the full and controller-only pages
+const systemInfoTemplate = `{{define "systemInfo"}}{{with .SystemInfo}}
+        <div class="panel">
+            <div class="panel-header">
+                <span>主机信息</span>
+            </div>
+            <div class="panel-body">
+                <table>
+                    <tbody>
+                        <tr><th>主机名</th><td>{{or .Hostname "N/A"}}</td></tr>
+                        <tr><th>系统版本</th><td>{{or .OSVersion "N/A"}}</td></tr>
+                        <tr><th>运行时间</th><td>{{.GetDisplayUptime}}</td></tr>
+                        <tr><th>工具版本</th><td>{{or .ToolVersion "N/A"}}</td></tr>
+                    </tbody>
+                </table>
+            </div>
+        </div>{{end}}{{end}}`
 
 // Template for controller-only view
 const controllerOnlyTemplate = `<!DOCTYPE html>
@@ -945,7 +1631,7 @@ const controllerOnlyTemplate = `<!DOCTYPE html>
     <div class="container">
         <h1>{{.Title}}</h1>
         
-        <div class="last-update">最后更新时间: {{.Timestamp}}</div>
+        <div class="last-update">最后更新时间: {{.Timestamp}}</div>{{template "systemInfo" .}}
         
         <!-- LSI Controllers Section -->
         {{if and .ControllerData .ControllerData.LSIControllers}}
@@ -961,21 +1647,31 @@ const controllerOnlyTemplate = `<!DOCTYPE html>
                             <th>型号</th>
                             <th>固件版本</th>
                             <th>驱动版本</th>
+                            {{if $.Verbose}}
+                            <th>固件包版本</th>
+                            <th>BIOS版本</th>
+                            <th>NVDATA版本</th>
+                            {{end}}
                             <th>温度</th>
                             <th>设备数</th>
                             <th>状态</th>
                         </tr>
                     </thead>
                     <tbody>
-                        {{range $id, $controller := .ControllerData.LSIControllers}}
+                        {{range $controller := .LSIControllers}}
                         <tr>
-                            <td>{{$id}}</td>
+                            <td>{{$controller.ID}}</td>
                             <td>{{$controller.Model}}</td>
                             <td>{{$controller.FirmwareVersion}}</td>
                             <td>{{$controller.DriverVersion}}</td>
-                            <td>{{$controller.GetDisplayTemperature}}</td>
+                            {{if $.Verbose}}
+                            <td>{{$controller.FWPackageBuild}}</td>
+                            <td>{{$controller.BIOSVersion}}</td>
+                            <td>{{$controller.NVDATAVersion}}</td>
+                            {{end}}
+                            <td>{{$controller.GetDisplayTemperatureTrend}}</td>
                             <td>{{$controller.DeviceCount}}</td>
-                            <td class="{{getStatusClass (string $controller.Status)}}">{{$controller.Status}}</td>
+                            <td class="{{getStatusClass (string $controller.Status)}}"{{if $controller.StatusReason}} title="{{$controller.StatusReason}}"{{end}}>{{$controller.Status}}</td>
                         </tr>
                         {{end}}
                     </tbody>
@@ -1000,11 +1696,11 @@ const controllerOnlyTemplate = `<!DOCTYPE html>
                         </tr>
                     </thead>
                     <tbody>
-                        {{range $id, $controller := .ControllerData.NVMeControllers}}
+                        {{range $controller := .NVMeControllers}}
                         <tr>
                             <td>{{$controller.Bus}}</td>
                             <td>{{$controller.Description}}</td>
-                            <td>{{$controller.GetDisplayTemperature}}</td>
+                            <td>{{$controller.GetDisplayTemperatureTrend}}</td>
                         </tr>
                         {{end}}
                     </tbody>
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -189,6 +190,26 @@ func TestHTMLFormatter_SaveToFile(t *testing.T) {
 	}
 }
 
+func TestHTMLFormatter_WriteToWriter(t *testing.T) {
+	formatter := createHTMLFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.WriteToWriter(&buf); err != nil {
+		t.Fatalf("WriteToWriter failed: %v", err)
+	}
+
+	content := buf.String()
+	if !strings.HasPrefix(content, "<!DOCTYPE html>") || !strings.Contains(content, "</html>") {
+		t.Error("Expected a complete HTML document to be written")
+	}
+	if content != formatter.String() {
+		t.Error("Expected WriteToWriter output to match String()")
+	}
+}
+
 func TestHTMLFormatter_formatTemperatureBar(t *testing.T) {
 	formatter := createHTMLFormatter(nil)
 
@@ -220,3 +241,212 @@ func TestHTMLFormatter_formatTemperatureBar(t *testing.T) {
 		t.Errorf("Expected empty string for invalid temperature, got: %s", tempBar)
 	}
 }
+
+func TestHTMLFormatter_PoolSummaryTab(t *testing.T) {
+	diskData := createTestDiskData()
+	diskData.PoolStatus = map[string]string{
+		"tank": "ONLINE",
+		"data": "DEGRADED",
+	}
+
+	formatter := createHTMLFormatter(nil)
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := formatter.htmlBuffer.String()
+
+	// Check that the pools tab and its table are rendered
+	expectedElements := []string{
+		"openTab(event, 'pool-tab')",
+		`<div id="pool-tab" class="tab-content">`,
+		"存储池汇总",
+		// Pool "data" has disks at 34°C and 43°C
+		"<td>43°C</td>",
+		"<td>38.5°C</td>",
+		"存储池 data 状态异常: DEGRADED",
+	}
+
+	for _, element := range expectedElements {
+		if !strings.Contains(htmlContent, element) {
+			t.Errorf("Expected HTML to contain '%s'", element)
+		}
+	}
+
+	// Healthy pools must not produce a banner
+	if strings.Contains(htmlContent, "存储池 tank 状态异常") {
+		t.Error("Expected no DEGRADED banner for ONLINE pool 'tank'")
+	}
+}
+
+func TestHTMLFormatter_RawSmartData(t *testing.T) {
+	// 默认不附带原始SMART数据
+	formatter := createHTMLFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	if strings.Contains(formatter.htmlBuffer.String(), "<details>") {
+		t.Error("Expected no raw SMART panel unless the option is enabled")
+	}
+
+	formatter = createHTMLFormatter(map[string]interface{}{OptionIncludeRawSmart: true})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := formatter.htmlBuffer.String()
+
+	expectedElements := []string{
+		"原始SMART数据",
+		"<details>",
+		"<tr><td>Non_Medium_Errors</td><td>2</td></tr>",
+		"<tr><td>Trip_Temperature</td><td>70</td></tr>",
+	}
+	for _, element := range expectedElements {
+		if !strings.Contains(htmlContent, element) {
+			t.Errorf("Expected HTML to contain '%s'", element)
+		}
+	}
+}
+
+func TestHTMLFormatter_ProblemDiskToggle(t *testing.T) {
+	formatter := createHTMLFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := formatter.htmlBuffer.String()
+
+	// The toggle button, its script and the row status markers are rendered
+	expectedElements := []string{
+		`<button id="problem-toggle" type="button" onclick="toggleProblemDisks(this)">仅显示问题磁盘</button>`,
+		"function toggleProblemDisks(button)",
+		`#disk-tab.only-problems tr[data-status="status-ok"]`,
+		`<tr data-status="status-ok">`,
+	}
+	for _, element := range expectedElements {
+		if !strings.Contains(htmlContent, element) {
+			t.Errorf("Expected HTML to contain '%s'", element)
+		}
+	}
+
+	// Custom column tables mark their rows too
+	formatter = createHTMLFormatter(map[string]interface{}{
+		OptionColumns: map[model.DiskType][]model.DiskColumn{
+			model.DiskTypeSASHDD: {{Name: "name", DisplayName: "磁盘名称"}},
+		},
+	})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	if !strings.Contains(formatter.htmlBuffer.String(), `<tr data-status="status-`) {
+		t.Error("Expected custom table rows to carry a data-status attribute")
+	}
+
+	// Without interactivity there is no toggle
+	formatter = createHTMLFormatter(map[string]interface{}{OptionEnableInteractivity: false})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	if strings.Contains(formatter.htmlBuffer.String(), "problem-toggle") {
+		t.Error("Expected no problem disk toggle when interactivity is disabled")
+	}
+}
+
+func TestHTMLFormatter_SortTypeHints(t *testing.T) {
+	formatter := createHTMLFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := formatter.htmlBuffer.String()
+
+	// Every sortable header declares how its values are compared
+	expectedHeaders := []string{
+		`<th data-sort-type="text" onclick="sortTable('hdd-table', 0)">磁盘名称</th>`,
+		`<th data-sort-type="size" onclick="sortTable('hdd-table', 2)">容量</th>`,
+		`<th data-sort-type="numeric" onclick="sortTable('hdd-table', 4)">温度</th>`,
+		`<th data-sort-type="duration" onclick="sortTable('hdd-table', 5)">通电时间</th>`,
+		`<th data-sort-type="text" onclick="sortTable('hdd-table', 6)">SMART状态</th>`,
+		`<th data-sort-type="size" onclick="sortTable('hdd-table', 7)">已读数据</th>`,
+		`<th data-sort-type="size" onclick="sortTable('hdd-table', 8)">已写数据</th>`,
+		`<th data-sort-type="numeric" onclick="sortTable('hdd-table', 9)">未修正错误</th>`,
+	}
+	for _, header := range expectedHeaders {
+		if !strings.Contains(htmlContent, header) {
+			t.Errorf("Expected HTML to contain '%s'", header)
+		}
+	}
+	if strings.Contains(htmlContent, "<th onclick=") {
+		t.Error("Expected every sortable header to carry a data-sort-type attribute")
+	}
+	if !strings.Contains(htmlContent, "function sortValue(cell, sortType)") {
+		t.Error("Expected the sortValue script function")
+	}
+
+	// Custom column tables derive the sort type from the column
+	tests := []struct {
+		column   string
+		sortType string
+	}{
+		{"name", "text"},
+		{"size", "size"},
+		{"temp", "numeric"},
+		{"power_on_hours", "duration"},
+		{"data_written", "size"},
+		{"link", "numeric"},
+	}
+	for _, tt := range tests {
+		column, ok := model.LookupDiskColumn(tt.column)
+		if !ok {
+			t.Fatalf("Unknown column %s", tt.column)
+		}
+		if got := diskColumnSortType(column); got != tt.sortType {
+			t.Errorf("Column %s: expected sort type %s, got %s", tt.column, tt.sortType, got)
+		}
+	}
+}
+
+func TestHTMLFormatter_TemperatureHeatmap(t *testing.T) {
+	// 默认不显示热力图
+	formatter := createHTMLFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	if strings.Contains(formatter.htmlBuffer.String(), "temperature-heatmap") {
+		t.Error("Expected no heatmap unless the option is enabled")
+	}
+
+	diskData := createTestDiskData()
+	formatter = createHTMLFormatter(map[string]interface{}{OptionTemperatureHeatmap: true})
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := formatter.htmlBuffer.String()
+
+	if !strings.Contains(htmlContent, `<svg id="temperature-heatmap"`) {
+		t.Fatal("Expected the heatmap container when the option is enabled")
+	}
+	// 每个磁盘一个单元格，并以磁盘名称标注
+	if count := strings.Count(htmlContent, `class="heatmap-cell"`); count != len(diskData.Disks) {
+		t.Errorf("Expected %d heatmap cells, got %d", len(diskData.Disks), count)
+	}
+	for _, disk := range diskData.Disks {
+		if !strings.Contains(htmlContent, `data-disk="`+disk.Name+`"`) {
+			t.Errorf("Expected a heatmap cell for %s", disk.Name)
+		}
+	}
+
+	// 颜色沿温度条的渐变变化: 警告温度为中间色，临界温度为最热色
+	colors := []struct {
+		temp     int
+		expected string
+	}{
+		{15, "#00b8d9"},
+		{35, "#80b26d"},
+		{50, "#ffab00"},
+		{55, "#ff8118"},
+		{70, "#ff5630"},
+	}
+	for _, tc := range colors {
+		if color := temperatureColor(tc.temp, 50, 60); color != tc.expected {
+			t.Errorf("temperatureColor(%d): expected %s, got %s", tc.temp, tc.expected, color)
+		}
+	}
+}
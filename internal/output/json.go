@@ -0,0 +1,460 @@
+// output/json.go
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// JSON formatter options
+const (
+	OptionPrettyPrint  = "pretty_print" // 是否缩进输出JSON
+	DefaultPrettyPrint = true
+)
+
+// JSONFormatter implements the OutputFormatter interface for JSON output
+type JSONFormatter struct {
+	BaseFormatter
+}
+
+// jsonReport is the top-level JSON document
+type jsonReport struct {
+	GeneratedAt    string            `json:"generated_at,omitempty"`
+	ControllerOnly bool              `json:"controller_only,omitempty"`
+	System         *jsonSystem       `json:"system,omitempty"`
+	Summary        map[string]string `json:"summary,omitempty"`
+	Disks          []jsonDisk        `json:"disks,omitempty"`
+	Events         []model.DiskEvent `json:"events,omitempty"`
+	Controllers    *jsonControllers  `json:"controllers,omitempty"`
+}
+
+// jsonSystem describes the host the report was taken on (--system-info)
+type jsonSystem struct {
+	Hostname      string `json:"hostname,omitempty"`
+	OSVersion     string `json:"os_version,omitempty"`
+	UptimeSeconds int64  `json:"uptime_seconds,omitempty"`
+	ToolVersion   string `json:"tool_version,omitempty"`
+	Source        string `json:"source,omitempty"`
+}
+
+// jsonDisk is the JSON representation of a disk
+type jsonDisk struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	Virtual        bool              `json:"virtual,omitempty"`
+	Boot           bool              `json:"boot,omitempty"`
+	Model          string            `json:"model"`
+	Serial         string            `json:"serial,omitempty"`
+	WWN            string            `json:"wwn,omitempty"`
+	ByID           string            `json:"by_id,omitempty"`
+	Firmware       string            `json:"firmware_version,omitempty"`
+	Size           string            `json:"size"`
+	Pool           string            `json:"pool"`
+	PreviousPool   string            `json:"previous_pool,omitempty"`
+	LogicalSector  int               `json:"logical_sector_size,omitempty"`
+	PhysicalSector int               `json:"physical_sector_size,omitempty"`
+	Temperature    *int              `json:"temperature,omitempty"`
+	Status         string            `json:"status"`
+	StatusReason   string            `json:"status_reason,omitempty"`
+	Acknowledged   string            `json:"acknowledged,omitempty"`
+	AgeInfo        string            `json:"age_info,omitempty"`
+	SMARTData      map[string]string `json:"smart_data"`
+	ReadIncrement  string            `json:"read_increment,omitempty"`
+	WriteIncrement string            `json:"write_increment,omitempty"`
+	Meta           *jsonDiskMeta     `json:"meta,omitempty"`
+}
+
+// jsonDiskMeta records which commands produced a disk's data
+type jsonDiskMeta struct {
+	Source     string `json:"source,omitempty"`
+	PoolSource string `json:"pool_source,omitempty"`
+}
+
+// jsonControllers groups controllers by type
+type jsonControllers struct {
+	LSI  []jsonController `json:"lsi"`
+	NVMe []jsonController `json:"nvme"`
+}
+
+// jsonController is the JSON representation of a controller
+type jsonController struct {
+	ID              string `json:"id"`
+	Model           string `json:"model"`
+	Bus             string `json:"bus,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty"`
+	FWPackageBuild  string `json:"fw_package_build,omitempty"`
+	BIOSVersion     string `json:"bios_version,omitempty"`
+	NVDATAVersion   string `json:"nvdata_version,omitempty"`
+	DriverVersion   string `json:"driver_version,omitempty"`
+	Temperature     *int   `json:"temperature,omitempty"`
+	PreviousTemp    *int   `json:"previous_temperature,omitempty"`
+	DeviceCount     string `json:"device_count,omitempty"`
+	Status          string `json:"status"`
+	StatusReason    string `json:"status_reason,omitempty"`
+	Description     string `json:"description,omitempty"`
+}
+
+// createJSONFormatter creates a new instance of JSONFormatter (internal use only)
+func createJSONFormatter(options map[string]interface{}) *JSONFormatter {
+	jf := &JSONFormatter{
+		BaseFormatter: NewBaseFormatter(),
+	}
+
+	// Set default options
+	jf.SetOption(OptionPrettyPrint, DefaultPrettyPrint)
+	jf.SetOption(OptionIncludeSummary, true)
+	jf.SetOption(OptionIncludeTimestamp, true)
+
+	// Override with provided options
+	for name, value := range options {
+		jf.SetOption(name, value)
+	}
+
+	return jf
+}
+
+// GetSupportedOptions returns a map of supported options and their descriptions
+func (jf *JSONFormatter) GetSupportedOptions() map[string]string {
+	return map[string]string{
+		OptionPrettyPrint:      "Indent JSON output",
+		OptionIncludeSummary:   "Include summary information",
+		OptionIncludeTimestamp: "Include timestamp",
+		OptionUnassignedLabel:  "Pool name shown for disks not in any pool (may be empty)",
+		OptionSystemInfo:       "Host information included as the system object (set with --system-info)",
+	}
+}
+
+// FormatDiskInfo formats disk information into JSON
+// Rendering is deferred to WriteToWriter so large fleets can be streamed
+func (jf *JSONFormatter) FormatDiskInfo(diskData *model.DiskData) error {
+	if diskData == nil {
+		return fmt.Errorf("no disk data to format")
+	}
+
+	jf.diskData = diskData
+	return nil
+}
+
+// FormatControllerInfo formats controller information into JSON
+// Disk data is optional, so this also produces valid controller-only output
+// marked with "controller_only": true
+func (jf *JSONFormatter) FormatControllerInfo(controllerData *model.ControllerData) error {
+	if controllerData == nil {
+		return fmt.Errorf("no controller data to format")
+	}
+
+	jf.controllerData = controllerData
+	return nil
+}
+
+// SaveToFile streams the formatted output to a file
+func (jf *JSONFormatter) SaveToFile(filename string) error {
+	if jf.diskData == nil && jf.controllerData == nil {
+		return fmt.Errorf("no content to save to file")
+	}
+
+	if err := jf.EnsureDirectoryExists(filename); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	if err := jf.WriteToWriter(writer); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// String returns the formatted output as a string
+// The whole report is built in memory; use WriteToWriter for large outputs
+func (jf *JSONFormatter) String() string {
+	if jf.diskData == nil && jf.controllerData == nil {
+		return ""
+	}
+
+	report := jf.buildReport()
+	if jf.diskData != nil {
+		report.Disks = make([]jsonDisk, 0, len(jf.diskData.Disks))
+		for _, disk := range jf.diskData.Disks {
+			report.Disks = append(report.Disks, jf.newJSONDisk(disk))
+		}
+	}
+
+	var data []byte
+	var err error
+	if jf.GetBoolOption(OptionPrettyPrint, DefaultPrettyPrint) {
+		data, err = json.MarshalIndent(report, "", "  ")
+	} else {
+		data, err = json.Marshal(report)
+	}
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// WriteToWriter streams the formatted output to a writer, encoding one disk
+// at a time instead of buffering the whole document. The bytes written are
+// identical to String().
+func (jf *JSONFormatter) WriteToWriter(w io.Writer) error {
+	if jf.diskData == nil && jf.controllerData == nil {
+		return fmt.Errorf("no content to write")
+	}
+
+	report := jf.buildReport()
+	stream := &jsonStreamWriter{
+		w:      w,
+		pretty: jf.GetBoolOption(OptionPrettyPrint, DefaultPrettyPrint),
+	}
+
+	stream.writeRaw("{")
+	if report.GeneratedAt != "" {
+		stream.writeField("generated_at", report.GeneratedAt)
+	}
+	if report.ControllerOnly {
+		stream.writeField("controller_only", report.ControllerOnly)
+	}
+	if report.System != nil {
+		stream.writeField("system", report.System)
+	}
+	if len(report.Summary) > 0 {
+		stream.writeField("summary", report.Summary)
+	}
+	if jf.diskData != nil && len(jf.diskData.Disks) > 0 {
+		stream.beginArrayField("disks")
+		for i, disk := range jf.diskData.Disks {
+			stream.writeArrayElement(i, jf.newJSONDisk(disk))
+		}
+		stream.endArray()
+	}
+	if len(report.Events) > 0 {
+		stream.writeField("events", report.Events)
+	}
+	if report.Controllers != nil {
+		stream.writeField("controllers", report.Controllers)
+	}
+	if stream.fields > 0 {
+		stream.newline(0)
+	}
+	stream.writeRaw("}")
+
+	return stream.err
+}
+
+// buildReport assembles everything except the disk list, which callers
+// either append in memory or stream element by element
+func (jf *JSONFormatter) buildReport() jsonReport {
+	report := jsonReport{}
+
+	if jf.GetBoolOption(OptionIncludeTimestamp, true) {
+		report.GeneratedAt = jf.FormatTimestamp()
+	}
+
+	// Without disk data the document only describes controllers, like the
+	// controller-only HTML page; consumers can tell it apart from a report
+	// that simply found no disks
+	report.ControllerOnly = jf.diskData == nil
+
+	if info := jf.GetSystemInfo(); info != nil {
+		report.System = &jsonSystem{
+			Hostname:      info.Hostname,
+			OSVersion:     info.OSVersion,
+			UptimeSeconds: info.UptimeSeconds,
+			ToolVersion:   info.ToolVersion,
+			Source:        info.Source,
+		}
+	}
+
+	if jf.diskData != nil && jf.GetBoolOption(OptionIncludeSummary, true) {
+		report.Summary = jf.GetSummaryInfo()
+	}
+
+	if jf.diskData != nil {
+		report.Events = jf.diskData.Events
+	}
+
+	if jf.controllerData != nil {
+		controllers := &jsonControllers{
+			LSI:  []jsonController{},
+			NVMe: []jsonController{},
+		}
+		for _, controller := range jf.controllerData.GetSortedLSIControllers() {
+			lsi := newJSONController(&controller.Controller)
+			lsi.FWPackageBuild = controller.FWPackageBuild
+			lsi.BIOSVersion = controller.BIOSVersion
+			lsi.NVDATAVersion = controller.NVDATAVersion
+			controllers.LSI = append(controllers.LSI, lsi)
+		}
+		for _, controller := range jf.controllerData.GetSortedNVMeControllers() {
+			controllers.NVMe = append(controllers.NVMe, newJSONController(&controller.Controller))
+		}
+		report.Controllers = controllers
+	}
+
+	return report
+}
+
+// jsonStreamWriter writes a JSON object field by field, matching the layout
+// of json.Marshal / json.MarshalIndent(v, "", "  ")
+type jsonStreamWriter struct {
+	w      io.Writer
+	pretty bool
+	fields int   // fields written to the top-level object so far
+	err    error // first write or encoding error
+}
+
+// writeRaw writes str unless an earlier write failed
+func (s *jsonStreamWriter) writeRaw(str string) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = io.WriteString(s.w, str)
+}
+
+// newline starts a new line at the given indentation depth in pretty mode
+func (s *jsonStreamWriter) newline(depth int) {
+	if s.pretty {
+		s.writeRaw("\n" + strings.Repeat("  ", depth))
+	}
+}
+
+// encode marshals v, indenting nested lines to the given depth
+func (s *jsonStreamWriter) encode(v interface{}, depth int) {
+	if s.err != nil {
+		return
+	}
+	var data []byte
+	if s.pretty {
+		data, s.err = json.MarshalIndent(v, strings.Repeat("  ", depth), "  ")
+	} else {
+		data, s.err = json.Marshal(v)
+	}
+	if s.err == nil {
+		_, s.err = s.w.Write(data)
+	}
+}
+
+// beginField writes the separator and key for the next top-level field
+func (s *jsonStreamWriter) beginField(name string) {
+	if s.fields > 0 {
+		s.writeRaw(",")
+	}
+	s.fields++
+	s.newline(1)
+	s.encode(name, 0)
+	if s.pretty {
+		s.writeRaw(": ")
+	} else {
+		s.writeRaw(":")
+	}
+}
+
+// writeField writes a complete top-level field
+func (s *jsonStreamWriter) writeField(name string, value interface{}) {
+	s.beginField(name)
+	s.encode(value, 1)
+}
+
+// beginArrayField opens a top-level array field
+func (s *jsonStreamWriter) beginArrayField(name string) {
+	s.beginField(name)
+	s.writeRaw("[")
+}
+
+// writeArrayElement writes the i-th element of the open array
+func (s *jsonStreamWriter) writeArrayElement(i int, value interface{}) {
+	if i > 0 {
+		s.writeRaw(",")
+	}
+	s.newline(2)
+	s.encode(value, 2)
+}
+
+// endArray closes the open array
+func (s *jsonStreamWriter) endArray() {
+	s.newline(1)
+	s.writeRaw("]")
+}
+
+// newJSONDisk converts a disk to its JSON representation
+func (jf *JSONFormatter) newJSONDisk(disk *model.Disk) jsonDisk {
+	var meta *jsonDiskMeta
+	if disk.Source != "" || disk.PoolSource != "" {
+		meta = &jsonDiskMeta{Source: disk.Source, PoolSource: disk.PoolSource}
+	}
+
+	return jsonDisk{
+		Name:           disk.Name,
+		Type:           string(disk.Type),
+		Virtual:        disk.IsVirtual(),
+		Boot:           disk.Boot,
+		Model:          disk.Model,
+		Serial:         disk.Serial,
+		WWN:            disk.WWN,
+		ByID:           disk.ByID,
+		Firmware:       disk.FirmwareVersion,
+		Size:           disk.Size,
+		Pool:           jf.GetPoolName(disk.Pool),
+		PreviousPool:   disk.PreviousPool,
+		LogicalSector:  disk.LogicalSectorSize,
+		PhysicalSector: disk.PhysicalSectorSize,
+		Temperature:    optionalInt(disk.GetTemperatureValue()),
+		Status:         string(disk.GetStatus()),
+		StatusReason:   disk.StatusReason,
+		Acknowledged:   disk.Acknowledged,
+		AgeInfo:        disk.AgeInfo,
+		SMARTData:      disk.SMARTData,
+		ReadIncrement:  disk.ReadIncrement,
+		WriteIncrement: disk.WriteIncrement,
+		Meta:           meta,
+	}
+}
+
+// newJSONController converts a controller to its JSON representation
+func newJSONController(c *model.Controller) jsonController {
+	return jsonController{
+		ID:              c.ID,
+		Model:           c.Model,
+		Bus:             c.Bus,
+		FirmwareVersion: c.FirmwareVersion,
+		DriverVersion:   c.DriverVersion,
+		Temperature:     optionalInt(c.GetTemperatureValue()),
+		PreviousTemp:    optionalInt(c.GetPreviousTemperatureValue()),
+		DeviceCount:     c.DeviceCount,
+		Status:          string(c.Status),
+		StatusReason:    c.StatusReason,
+		Description:     c.Description,
+	}
+}
+
+// optionalInt returns a pointer to value when ok, so missing numbers are
+// omitted from the JSON instead of being reported as zero
+func optionalInt(value int, ok bool) *int {
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// init registers the JSON formatter factory
+func init() {
+	NewJSONFormatter = func(options map[string]interface{}) OutputFormatter {
+		return createJSONFormatter(options)
+	}
+}
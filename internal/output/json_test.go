@@ -0,0 +1,256 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+func TestJSONFormatter_FormatDiskInfo(t *testing.T) {
+	formatter := createJSONFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	if err := formatter.FormatControllerInfo(createTestControllerData()); err != nil {
+		t.Fatalf("Expected no error when formatting controller info, got: %v", err)
+	}
+
+	var report map[string]interface{}
+	if err := json.Unmarshal([]byte(formatter.String()), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	disks, ok := report["disks"].([]interface{})
+	if !ok || len(disks) != 5 {
+		t.Errorf("Expected 5 disks, got %v", report["disks"])
+	}
+	if _, ok := report["summary"]; !ok {
+		t.Error("Expected summary in full report")
+	}
+	if _, ok := report["controllers"]; !ok {
+		t.Error("Expected controllers in full report")
+	}
+	if _, ok := report["controller_only"]; ok {
+		t.Error("Expected a full report not to be marked controller_only")
+	}
+}
+
+func TestJSONFormatter_ControllerOnly(t *testing.T) {
+	formatter := createJSONFormatter(nil)
+	if err := formatter.FormatControllerInfo(createTestControllerData()); err != nil {
+		t.Fatalf("Expected no error when formatting controller info, got: %v", err)
+	}
+
+	var report struct {
+		ControllerOnly bool                        `json:"controller_only"`
+		Disks          []interface{}               `json:"disks"`
+		Summary        map[string]string           `json:"summary"`
+		Controllers    map[string][]jsonController `json:"controllers"`
+	}
+	output := formatter.String()
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	// The disk section must be omitted in controller-only mode
+	if report.Disks != nil || report.Summary != nil {
+		t.Error("Expected no disk section in controller-only output")
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(output), &keys); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"disks", "summary", "events"} {
+		if _, ok := keys[key]; ok {
+			t.Errorf("Expected no %q key in controller-only output", key)
+		}
+	}
+	if !report.ControllerOnly {
+		t.Error("Expected controller-only output to be marked controller_only")
+	}
+	if len(report.Controllers["lsi"]) != 1 || report.Controllers["lsi"][0].ID != "LSI_Controller_0" {
+		t.Errorf("Expected LSI_Controller_0, got %v", report.Controllers["lsi"])
+	}
+	if len(report.Controllers["nvme"]) != 1 || report.Controllers["nvme"][0].Bus != "0000:01:00.0" {
+		t.Errorf("Expected NVMe controller on bus 0000:01:00.0, got %v", report.Controllers["nvme"])
+	}
+
+	// Save to file
+	tempDir := t.TempDir()
+	filename := filepath.Join(tempDir, "controllers.json")
+	if err := formatter.SaveToFile(filename); err != nil {
+		t.Fatalf("Expected no error when saving to file, got: %v", err)
+	}
+	if _, err := os.Stat(filename); err != nil {
+		t.Errorf("Output file was not created: %v", err)
+	}
+}
+
+func TestJSONFormatter_StatusReason(t *testing.T) {
+	disk := model.NewDisk("sdc", "HDD", "SEAGATE ST600MM0006", "600G")
+	disk.SMARTData["Smart_Status"] = "PASSED"
+	disk.SMARTData["Uncorrected_Errors"] = "3"
+	disk.UpdateStatus()
+
+	diskData := model.NewDiskData()
+	diskData.AddDisk(disk)
+
+	formatter := createJSONFormatter(nil)
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+
+	var report struct {
+		Disks []struct {
+			Status       string `json:"status"`
+			StatusReason string `json:"status_reason"`
+		} `json:"disks"`
+	}
+	if err := json.Unmarshal([]byte(formatter.String()), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if len(report.Disks) != 1 {
+		t.Fatalf("Expected 1 disk, got %d", len(report.Disks))
+	}
+	if report.Disks[0].Status != string(model.DiskStatusWarning) {
+		t.Errorf("Expected status %s, got %s", model.DiskStatusWarning, report.Disks[0].Status)
+	}
+	if !strings.Contains(report.Disks[0].StatusReason, "磁盘自检: PASSED") ||
+		!strings.Contains(report.Disks[0].StatusReason, "启发式: 未修正错误 3 个") {
+		t.Errorf("Expected both self-reported and heuristic reasons, got %q", report.Disks[0].StatusReason)
+	}
+}
+
+func TestJSONFormatter_AgeInfo(t *testing.T) {
+	old := model.NewDisk("sda", "HDD", "SEAGATE ST600MM0006", "600G")
+	old.SMARTData["Power_On_Hours"] = "52560"
+	young := model.NewDisk("sdb", "HDD", "SEAGATE ST600MM0006", "600G")
+	young.SMARTData["Power_On_Hours"] = "17520"
+
+	diskData := model.NewDiskData()
+	diskData.AddDisk(old)
+	diskData.AddDisk(young)
+	diskData.FlagAgedDisks(model.DefaultAgeInfoYears)
+
+	formatter := createJSONFormatter(nil)
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+
+	var report struct {
+		Summary map[string]string `json:"summary"`
+		Disks   []struct {
+			Name    string `json:"name"`
+			AgeInfo string `json:"age_info"`
+		} `json:"disks"`
+	}
+	if err := json.Unmarshal([]byte(formatter.String()), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	for _, disk := range report.Disks {
+		if (disk.Name == "sda") != (disk.AgeInfo != "") {
+			t.Errorf("Unexpected age info for %s: %q", disk.Name, disk.AgeInfo)
+		}
+	}
+	if report.Summary["AgedDisks"] != "sda (6.0 年)" {
+		t.Errorf("Expected aged disks summary 'sda (6.0 年)', got %q", report.Summary["AgedDisks"])
+	}
+}
+
+func TestJSONFormatter_StreamMatchesBuffered(t *testing.T) {
+	for _, pretty := range []bool{true, false} {
+		formatter := createJSONFormatter(map[string]interface{}{OptionPrettyPrint: pretty})
+		if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+			t.Fatalf("FormatDiskInfo failed: %v", err)
+		}
+		if err := formatter.FormatControllerInfo(createTestControllerData()); err != nil {
+			t.Fatalf("FormatControllerInfo failed: %v", err)
+		}
+
+		var streamed bytes.Buffer
+		if err := formatter.WriteToWriter(&streamed); err != nil {
+			t.Fatalf("WriteToWriter failed: %v", err)
+		}
+		buffered := formatter.String()
+
+		var fromStream, fromBuffer jsonReport
+		if err := json.Unmarshal(streamed.Bytes(), &fromStream); err != nil {
+			t.Fatalf("Streamed output (pretty=%v) is not valid JSON: %v\n%s", pretty, err, streamed.String())
+		}
+		if err := json.Unmarshal([]byte(buffered), &fromBuffer); err != nil {
+			t.Fatalf("Buffered output (pretty=%v) is not valid JSON: %v", pretty, err)
+		}
+		if !reflect.DeepEqual(fromStream, fromBuffer) {
+			t.Errorf("Streamed output (pretty=%v) parses differently from buffered output", pretty)
+		}
+		if streamed.String() != buffered {
+			t.Errorf("Streamed output (pretty=%v) differs from buffered output:\n%s\n---\n%s", pretty, streamed.String(), buffered)
+		}
+	}
+}
+
+func TestJSONFormatter_NumericTemperatures(t *testing.T) {
+	diskData := createTestDiskData()
+	for _, disk := range diskData.Disks {
+		if disk.Name == "sdb" {
+			delete(disk.SMARTData, "Temperature")
+		}
+	}
+
+	controllerData := createTestControllerData()
+	controllerData.LSIControllers["LSI_Controller_0"].Temperature = "58°C"
+	controllerData.LSIControllers["LSI_Controller_0"].PreviousTemperature = " 51 C"
+
+	formatter := createJSONFormatter(nil)
+	formatter.FormatDiskInfo(diskData)
+	formatter.FormatControllerInfo(controllerData)
+
+	var report struct {
+		Disks []struct {
+			Name        string          `json:"name"`
+			Temperature json.RawMessage `json:"temperature"`
+		} `json:"disks"`
+		Controllers struct {
+			LSI []struct {
+				Temperature         json.RawMessage `json:"temperature"`
+				PreviousTemperature json.RawMessage `json:"previous_temperature"`
+			} `json:"lsi"`
+			NVMe []struct {
+				Temperature json.RawMessage `json:"temperature"`
+			} `json:"nvme"`
+		} `json:"controllers"`
+	}
+	if err := json.Unmarshal([]byte(formatter.String()), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	// 温度必须是不带单位的整数，而不是字符串
+	assertInt := func(field string, raw json.RawMessage, want string) {
+		t.Helper()
+		if string(raw) != want {
+			t.Errorf("%s: expected plain integer %s, got %s", field, want, raw)
+		}
+	}
+	temps := make(map[string]json.RawMessage)
+	for _, disk := range report.Disks {
+		temps[disk.Name] = disk.Temperature
+	}
+	assertInt("disk sda", temps["sda"], "32")
+	assertInt("disk nvme0n1", temps["nvme0n1"], "38")
+	assertInt("lsi temperature", report.Controllers.LSI[0].Temperature, "58")
+	assertInt("lsi previous_temperature", report.Controllers.LSI[0].PreviousTemperature, "51")
+	assertInt("nvme temperature", report.Controllers.NVMe[0].Temperature, "42")
+
+	// 没有温度数据时省略字段
+	if temps["sdb"] != nil {
+		t.Errorf("disk sdb: expected temperature to be omitted, got %s", temps["sdb"])
+	}
+}
@@ -0,0 +1,182 @@
+// output/template.go
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// Template formatter options
+const (
+	OptionTemplateFile = "template_file" // 用户提供的Go模板文件
+)
+
+// TemplateFormatter implements the OutputFormatter interface by executing a
+// user-provided Go text/template against the collected data
+type TemplateFormatter struct {
+	BaseFormatter
+}
+
+// templateData is the value the user template is executed against
+type templateData struct {
+	GeneratedAt    string
+	Summary        map[string]string
+	Disks          []*model.Disk
+	DiskData       *model.DiskData
+	ControllerData *model.ControllerData
+}
+
+// createTemplateFormatter creates a new instance of TemplateFormatter (internal use only)
+func createTemplateFormatter(options map[string]interface{}) *TemplateFormatter {
+	tpl := &TemplateFormatter{
+		BaseFormatter: NewBaseFormatter(),
+	}
+
+	// Override with provided options
+	for name, value := range options {
+		tpl.SetOption(name, value)
+	}
+
+	return tpl
+}
+
+// GetSupportedOptions returns a map of supported options and their descriptions
+func (tpl *TemplateFormatter) GetSupportedOptions() map[string]string {
+	return map[string]string{
+		OptionTemplateFile: "Go text/template file executed against the collected data",
+		OptionSizeUnits:    "Size units used by formatSize and formatBytes (binary, decimal)",
+	}
+}
+
+// FormatDiskInfo stores the disk data; the template is executed on output
+func (tpl *TemplateFormatter) FormatDiskInfo(diskData *model.DiskData) error {
+	if diskData == nil {
+		return fmt.Errorf("no disk data to format")
+	}
+
+	tpl.diskData = diskData
+	return nil
+}
+
+// FormatControllerInfo stores the controller data; the template is executed on output
+func (tpl *TemplateFormatter) FormatControllerInfo(controllerData *model.ControllerData) error {
+	if controllerData == nil {
+		return fmt.Errorf("no controller data to format")
+	}
+
+	tpl.controllerData = controllerData
+	return nil
+}
+
+// SaveToFile executes the template and writes the result to a file
+func (tpl *TemplateFormatter) SaveToFile(filename string) error {
+	content, err := tpl.render()
+	if err != nil {
+		return err
+	}
+
+	if err := tpl.EnsureDirectoryExists(filename); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteToWriter executes the template and writes the result to a writer.
+// Nothing is written when the template fails, so errors never leave a
+// half-rendered report behind
+func (tpl *TemplateFormatter) WriteToWriter(w io.Writer) error {
+	content, err := tpl.render()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(content)
+	return err
+}
+
+// String returns the rendered template, or an empty string on error
+func (tpl *TemplateFormatter) String() string {
+	content, err := tpl.render()
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// render loads the template file and executes it into a buffer
+func (tpl *TemplateFormatter) render() ([]byte, error) {
+	if tpl.diskData == nil && tpl.controllerData == nil {
+		return nil, fmt.Errorf("no content to write")
+	}
+
+	filename := tpl.GetStringOption(OptionTemplateFile, "")
+	if filename == "" {
+		return nil, fmt.Errorf("no template file set (use --template-file)")
+	}
+
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	t, err := template.New(filepath.Base(filename)).Funcs(tpl.templateFuncs()).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	data := templateData{
+		GeneratedAt:    tpl.FormatTimestamp(),
+		Summary:        tpl.GetSummaryInfo(),
+		DiskData:       tpl.diskData,
+		ControllerData: tpl.controllerData,
+	}
+	if tpl.diskData != nil {
+		data.Disks = tpl.diskData.Disks
+	}
+
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// templateFuncs returns the helper functions available to user templates,
+// named like the ones used by the built-in HTML template
+func (tpl *TemplateFormatter) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"getStatusClass":     GetStatusClass,
+		"formatPowerOnHours": FormatPowerOnHours,
+		"formatSMARTStatus":  FormatSMARTStatus,
+		"formatDiskStatus":   FormatDiskStatus,
+		"formatSize": func(size string) string {
+			return FormatSciNotation(size, tpl.GetSizeUnits())
+		},
+		"formatBytes": func(bytes float64) string {
+			return FormatBytes(bytes, tpl.GetSizeUnits())
+		},
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
+
+// init registers the template formatter factory
+func init() {
+	NewTemplateFormatter = func(options map[string]interface{}) OutputFormatter {
+		return createTemplateFormatter(options)
+	}
+}
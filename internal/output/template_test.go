@@ -0,0 +1,46 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFormatter_DiskNames(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "names.tmpl")
+	source := "{{range .Disks}}{{.Name}}\n{{end}}total={{.Summary.TotalDisks}} raw={{.Summary.RawCapacity}}\n"
+	if err := os.WriteFile(templateFile, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	formatter, err := NewFormatter("template", map[string]interface{}{OptionTemplateFile: templateFile})
+	if err != nil {
+		t.Fatalf("NewFormatter(template) failed: %v", err)
+	}
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.WriteToWriter(&buf); err != nil {
+		t.Fatalf("WriteToWriter failed: %v", err)
+	}
+	expected := "nvme0n1\nsda\nsdb\nsdc\nsdd\ntotal=5 raw=11.0 TiB\n"
+	if buf.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, buf.String())
+	}
+
+	// 模板执行失败时返回错误且不输出任何内容
+	if err := os.WriteFile(templateFile, []byte("{{range .Disks}}{{.NoSuchField}}{{end}}"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	buf.Reset()
+	if err := formatter.WriteToWriter(&buf); err == nil || !strings.Contains(err.Error(), "execute template") {
+		t.Errorf("Expected template execution error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output on template error, got %q", buf.String())
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/MaurUppi/disk-health-monitor/internal/model"
@@ -20,6 +19,8 @@ const (
 	DefaultCompactMode   = false
 	DefaultColorOutput   = true
 	OptionShowIncrements = "show_increments" // 是否显示增量数据
+	OptionSummaryFooter  = "summary_footer"  // 是否在末尾追加机器可解析的摘要行
+	OptionTopN           = "top_n"           // 大于0时只显示最热和寿命消耗最多的N个磁盘，代替完整表格
 )
 
 // TextFormatter implements the OutputFormatter interface for text output
@@ -61,8 +62,29 @@ func (tf *TextFormatter) GetSupportedOptions() map[string]string {
 		OptionCompactMode:      "Use compact mode with fewer columns",
 		OptionColorOutput:      "Use ANSI color codes for terminal output",
 		OptionGroupByType:      "Group disks by type",
+		OptionGroupBy:          "Group disks by type, pool or none",
+		OptionShowSerial:       "Show serial number and WWN columns",
+		OptionShowFeatures:     "Show SSD feature columns (TRIM support)",
+		OptionShowSensors:      "Show per-sensor NVMe temperature column",
+		OptionShowEndurance:    "Show NVMe endurance group wear column",
+		OptionShowSectors:      "Show sector format (512n/512e/4Kn) columns",
+		OptionShowFirmware:     "Show disk firmware version column",
+		OptionShowLinkSpeed:    "Show SAS/SATA link speed column",
+		OptionShowPower:        "Show SATA APM level and DSN state column",
+		OptionColumns:          "Columns per disk type, set with --columns type=col1,col2",
+		OptionColorTheme:       "Status color theme (default, deuteranopia)",
+		OptionVerbose:          "Show controller firmware package, BIOS and NVDATA versions",
+		OptionSystemInfo:       "Host information shown in the report header (set with --system-info)",
 		OptionIncludeSummary:   "Include summary information",
 		OptionIncludeTimestamp: "Include timestamp",
+		OptionIDFormat:         "Disk identifier shown in tables (name, by-id)",
+		OptionUnassignedLabel:  "Pool name shown for disks not in any pool (may be empty)",
+		OptionSizeUnits:        "Size units (binary, decimal)",
+		OptionSummaryFooter:    "Append a machine-parseable SUMMARY line",
+		OptionTopN:             "Show only the N hottest disks and N most-worn SSDs instead of the full tables",
+		OptionLegend:           "Explain table columns, units and status colors at the end of the report",
+		OptionMergeSSD:         "Show SAS and NVMe SSDs in a single SSD section",
+		OptionSortSections:     "Order of disk type and pool sections (default, severity)",
 	}
 }
 
@@ -75,8 +97,9 @@ func (tf *TextFormatter) FormatDiskInfo(diskData *model.DiskData) error {
 	// Save disk data
 	tf.diskData = diskData
 
-	// Reset buffer
+	// Reset buffers
 	tf.buffer.Reset()
+	tf.tableBuffer.Reset()
 
 	// Add title
 	tf.writeTitle("TrueNAS磁盘健康监控")
@@ -86,36 +109,55 @@ func (tf *TextFormatter) FormatDiskInfo(diskData *model.DiskData) error {
 		tf.buffer.WriteString(fmt.Sprintf("生成时间: %s\n\n", tf.FormatTimestamp()))
 	}
 
+	// Describe the host the report was taken on (--system-info)
+	tf.writeSystemInfo()
+
 	// Add summary if enabled
 	if tf.GetBoolOption(OptionIncludeSummary, true) {
 		tf.writeSummary()
 	}
 
-	// Check if disks should be grouped
-	if tf.GetBoolOption(OptionGroupByType, true) {
-		// Write each disk type section
-		if count := diskData.GetDiskCountByType(model.DiskTypeSASSSD); count > 0 {
-			tf.writeDiskGroup(model.DiskTypeSASSSD)
-		}
-		if count := diskData.GetDiskCountByType(model.DiskTypeSASHDD); count > 0 {
-			tf.writeDiskGroup(model.DiskTypeSASHDD)
-		}
-		if count := diskData.GetDiskCountByType(model.DiskTypeNVMESSD); count > 0 {
-			tf.writeDiskGroup(model.DiskTypeNVMESSD)
-		}
-		if count := diskData.GetDiskCountByType(model.DiskTypeVirtual); count > 0 {
-			tf.writeDiskGroup(model.DiskTypeVirtual)
+	// A top-N quick report replaces the full disk tables
+	if n := tf.GetIntOption(OptionTopN, 0); n > 0 {
+		tf.writeTopReport(n)
+		return nil
+	}
+
+	// Write the consolidated error overview above the disk groups
+	tf.writeErrorOverview()
+
+	// Write disk sections according to the grouping mode
+	switch tf.GetGroupBy() {
+	case model.GroupByType:
+		// Write each disk type section in the shared group order
+		for _, diskType := range tf.GetDisplayDiskTypes() {
+			tf.writeDiskGroup(diskType)
 		}
-	} else {
+	case model.GroupByPool:
+		// Write one section per pool
+		tf.writePoolGroups()
+	default:
 		// Write all disks together
 		tf.writeAllDisks()
 	}
 
+	// Explain why disks were flagged
+	tf.writeStatusReasons()
+
+	// Point out disks that moved to another pool since the last run
+	tf.writePoolChanges()
+
+	// List recent disk events requested with --events
+	tf.writeEvents()
+
 	// Add read/write increment information if available
 	if diskData.HasPreviousData() {
 		tf.writeIncrementTable()
 	}
 
+	// Explain the columns and status colors (--legend)
+	tf.writeLegend()
+
 	return nil
 }
 
@@ -137,12 +179,15 @@ func (tf *TextFormatter) FormatControllerInfo(controllerData *model.ControllerDa
 		if tf.GetBoolOption(OptionIncludeTimestamp, true) {
 			tf.buffer.WriteString(fmt.Sprintf("生成时间: %s\n\n", tf.FormatTimestamp()))
 		}
+
+		// Describe the host the report was taken on (--system-info)
+		tf.writeSystemInfo()
 	}
 
-	// Force buffer reset when in controller-only mode
-	if tf.buffer.Len() == 0 {
-		tf.buffer.Reset()
-		tf.writeTitle("TrueNAS控制器信息")
+	// Controller-only output has no disk section, so say so when nothing was found
+	if tf.diskData == nil && controllerData.GetTotalControllerCount() == 0 {
+		tf.buffer.WriteString("未检测到控制器\n")
+		return nil
 	}
 
 	// Add LSI controller section if any
@@ -150,15 +195,23 @@ func (tf *TextFormatter) FormatControllerInfo(controllerData *model.ControllerDa
 		tf.writeLSIControllers()
 	}
 
+	// Firmware audit details are only shown in verbose full mode
+	if len(controllerData.LSIControllers) > 0 && tf.GetBoolOption(OptionVerbose, false) &&
+		!tf.GetBoolOption(OptionCompactMode, false) {
+		tf.writeLSIFirmwareVersions()
+	}
+
 	// Add NVMe controller section if any
 	if len(controllerData.NVMeControllers) > 0 {
 		tf.writeNVMeControllers()
 	}
 
+	// Explain overheating or otherwise flagged controllers
+	tf.writeControllerStatusReasons()
+
 	return nil
 }
 
-// SaveToFile saves the formatted output to a file
 // SaveToFile saves the formatted output to a file
 func (tf *TextFormatter) SaveToFile(filename string) error {
 	// 确保目录存在
@@ -198,7 +251,7 @@ func (tf *TextFormatter) SaveToFile(filename string) error {
 	}
 
 	// 获取无颜色版本并写入文件
-	noColorContent := tf.buffer.String()
+	noColorContent := tf.buffer.String() + tf.summaryFooter()
 	err := os.WriteFile(filename, []byte(noColorContent), 0644)
 
 	// 恢复原始内容和颜色设置
@@ -215,15 +268,47 @@ func (tf *TextFormatter) SaveToFile(filename string) error {
 
 // String returns the formatted output as a string
 func (tf *TextFormatter) String() string {
-	return tf.buffer.String()
+	return tf.buffer.String() + tf.summaryFooter()
 }
 
 // WriteToWriter writes the formatted output to a writer
 func (tf *TextFormatter) WriteToWriter(w io.Writer) error {
-	_, err := w.Write([]byte(tf.buffer.String()))
+	_, err := w.Write([]byte(tf.String()))
 	return err
 }
 
+// summaryFooter returns the opt-in SUMMARY line appended after all tables.
+// It is plain ASCII with fixed key order so scripts can parse the last line
+// regardless of locale or color settings.
+func (tf *TextFormatter) summaryFooter() string {
+	if !tf.GetBoolOption(OptionSummaryFooter, false) || tf.buffer.Len() == 0 {
+		return ""
+	}
+
+	summary := tf.GetSummaryInfo()
+	value := func(key string) string {
+		if v, ok := summary[key]; ok {
+			return v
+		}
+		return "0"
+	}
+
+	// Controller-only output has no disk summary
+	controllers := value("ControllerCount")
+	if tf.controllerData != nil {
+		controllers = fmt.Sprintf("%d", tf.controllerData.GetTotalControllerCount())
+	}
+
+	footer := fmt.Sprintf("SUMMARY total=%s ssd=%s hdd=%s warn=%s err=%s controllers=%s\n",
+		value("TotalDisks"), value("SSDCount"), value("HDDCount"),
+		value("WarningCount"), value("ErrorCount"), controllers)
+
+	if !strings.HasSuffix(tf.buffer.String(), "\n") {
+		footer = "\n" + footer
+	}
+	return footer
+}
+
 // writeTitle writes a title to the buffer
 func (tf *TextFormatter) writeTitle(title string) {
 	tf.buffer.WriteString("=== " + title + " ===\n\n")
@@ -234,6 +319,20 @@ func (tf *TextFormatter) writeSectionTitle(title string) {
 	tf.buffer.WriteString("--- " + title + " ---\n\n")
 }
 
+// writeSystemInfo writes the host information header, if it was collected
+func (tf *TextFormatter) writeSystemInfo() {
+	info := tf.GetSystemInfo()
+	if info == nil {
+		return
+	}
+
+	tf.buffer.WriteString("主机信息:\n")
+	tf.buffer.WriteString(fmt.Sprintf("- 主机名: %s\n", displayOrNA(info.Hostname)))
+	tf.buffer.WriteString(fmt.Sprintf("- 系统版本: %s\n", displayOrNA(info.OSVersion)))
+	tf.buffer.WriteString(fmt.Sprintf("- 运行时间: %s\n", info.GetDisplayUptime()))
+	tf.buffer.WriteString(fmt.Sprintf("- 工具版本: %s\n\n", displayOrNA(info.ToolVersion)))
+}
+
 // writeSummary writes the summary section
 func (tf *TextFormatter) writeSummary() {
 	summary := tf.GetSummaryInfo()
@@ -251,54 +350,91 @@ func (tf *TextFormatter) writeSummary() {
 	}
 	tf.buffer.WriteString("\n")
 
+	// Add total capacity, with the usable estimate when the pool layout is known
+	if rawCapacity, ok := summary["RawCapacity"]; ok {
+		tf.buffer.WriteString(fmt.Sprintf("- 原始容量: %s", rawCapacity))
+		if usableCapacity, ok := summary["UsableCapacity"]; ok {
+			tf.buffer.WriteString(fmt.Sprintf(" (存储池可用约 %s)", usableCapacity))
+		}
+		tf.buffer.WriteString("\n")
+	}
+
 	// Add warning and error counts
+	theme := tf.colorTheme()
 	warningCount := summary["WarningCount"]
-	if warningCount != "0" {
-		tf.buffer.WriteString(fmt.Sprintf("- 警告数: %s\n", colorizeText(warningCount, "yellow")))
+	if warningCount != "0" && theme != nil {
+		tf.buffer.WriteString(fmt.Sprintf("- 警告数: %s\n", colorizeText(warningCount, theme.Warning)))
 	} else {
 		tf.buffer.WriteString(fmt.Sprintf("- 警告数: %s\n", warningCount))
 	}
 
 	errorCount := summary["ErrorCount"]
-	if errorCount != "0" {
-		tf.buffer.WriteString(fmt.Sprintf("- 错误数: %s\n", colorizeText(errorCount, "red")))
+	if errorCount != "0" && theme != nil {
+		tf.buffer.WriteString(fmt.Sprintf("- 错误数: %s\n", colorizeText(errorCount, theme.Error)))
 	} else {
 		tf.buffer.WriteString(fmt.Sprintf("- 错误数: %s\n", errorCount))
 	}
 
+	// Warn about pools mixing 512e and 4Kn disks
+	if mixedPools, ok := summary["MixedSectorPools"]; ok {
+		label := "WARNING 存储池混用不同扇区大小"
+		if theme != nil {
+			label = colorizeText(label, theme.Warning)
+		}
+		tf.buffer.WriteString(fmt.Sprintf("- %s: %s\n", label, mixedPools))
+	}
+
+	// Warn about vdevs whose members differ in size
+	if mixedPools, ok := summary["MixedCapacityPools"]; ok {
+		label := "WARNING 存储池vdev混用不同容量磁盘"
+		if theme != nil {
+			label = colorizeText(label, theme.Warning)
+		}
+		tf.buffer.WriteString(fmt.Sprintf("- %s: %s\n", label, mixedPools))
+	}
+
+	// Warn about pools filled past --pool-warn-pct
+	if fullPools, ok := summary["FullPools"]; ok {
+		label := fmt.Sprintf("WARNING 存储池容量超过 %d%%", tf.diskData.GetPoolWarnPct())
+		if theme != nil {
+			label = colorizeText(label, theme.Warning)
+		}
+		tf.buffer.WriteString(fmt.Sprintf("- %s: %s\n", label, fullPools))
+	}
+
+	// List boot disks added by --include-boot
+	if bootDisks, ok := summary["BootDisks"]; ok {
+		tf.buffer.WriteString(fmt.Sprintf("- 启动盘 (%s): %s\n", model.BootPoolName, bootDisks))
+	}
+
+	// Note drives old enough to plan a refresh (--age-info-years)
+	if agedDisks, ok := summary["AgedDisks"]; ok {
+		tf.buffer.WriteString(fmt.Sprintf("- INFO 通电超过 %s 年，建议规划更换: %s\n", summary["AgeInfoYears"], agedDisks))
+	}
+
 	// Add controller count if available
 	if controllerCount, ok := summary["ControllerCount"]; ok {
 		tf.buffer.WriteString(fmt.Sprintf("- 控制器数: %s\n", controllerCount))
 	}
 
+	// Note when the disk list was capped by --max-disks
+	if truncatedFrom, ok := summary["TruncatedFrom"]; ok {
+		tf.buffer.WriteString(fmt.Sprintf("- 注意: 磁盘数量超过上限，仅显示前 %s 个 (共 %s 个)\n", summary["TotalDisks"], truncatedFrom))
+	}
+
 	tf.buffer.WriteString("\n")
 }
 
 // writeDiskGroup writes a group of disks of the same type
 func (tf *TextFormatter) writeDiskGroup(diskType model.DiskType) {
 	// Get disks of this type
-	disks, ok := tf.diskData.GroupedDisks[diskType]
-	if !ok || len(disks) == 0 {
+	disks := tf.diskData.GetDisplayDisks(diskType)
+	if len(disks) == 0 {
 		return
 	}
 
-	// Determine group title
-	var title string
-	switch diskType {
-	case model.DiskTypeSASSSD:
-		title = "SAS/SATA 固态硬盘"
-	case model.DiskTypeSASHDD:
-		title = "SAS/SATA 机械硬盘"
-	case model.DiskTypeNVMESSD:
-		title = "NVMe 固态硬盘"
-	case model.DiskTypeVirtual:
-		title = "虚拟设备"
-	default:
-		title = "其他设备"
-	}
-
 	// Write section title
-	tf.writeSectionTitle(title)
+	tf.writeSectionTitle(diskType.GetGroupTitle())
 
 	// Create a table
 	tf.writeTableForDiskType(diskType, disks)
@@ -311,48 +447,110 @@ func (tf *TextFormatter) writeAllDisks() {
 	}
 
 	tf.writeSectionTitle("所有磁盘")
+	tf.writeDiskTable(tf.diskData.Disks)
+}
+
+// writePoolGroups writes one section per pool, with disks sorted by name.
+// Pools are ordered by name, or worst status first with sort_sections=severity
+func (tf *TextFormatter) writePoolGroups() {
+	pools, groups := tf.diskData.GetDisksByPool()
+	if tf.GetSectionOrder() == model.SectionOrderSeverity {
+		model.SortPoolsBySeverity(pools, groups)
+	}
+	for _, pool := range pools {
+		// Section titles keep the default label when the configured one is empty
+		title := tf.GetPoolName(pool)
+		if title == "" {
+			title = model.PoolUnassigned
+		}
+		tf.writeSectionTitle(fmt.Sprintf("存储池: %s", title))
+		tf.writeDiskTable(groups[pool])
+	}
+}
 
+// writeDiskTable writes a table of disks of mixed types. Virtual devices
+// show their type label and "-" for SMART columns that don't apply to them
+func (tf *TextFormatter) writeDiskTable(disks []*model.Disk) {
 	// Create a table with all necessary columns
 	table := tf.createTable()
 
 	// Set header
+	var headers []string
 	if tf.GetBoolOption(OptionCompactMode, false) {
-		table.SetHeader([]string{"名称", "类型", "容量", "存储池", "温度", "通电时间", "状态"})
+		headers = []string{"名称", "类型", "容量", "存储池", "温度", "通电时间", "状态"}
 	} else {
-		table.SetHeader([]string{"名称", "型号", "类型", "容量", "存储池", "温度", "通电时间", "状态", "已读数据", "已写数据"})
+		headers = []string{"名称", "型号", "类型", "容量", "存储池", "温度", "通电时间", "状态", "已读数据", "已写数据"}
 	}
+	headers = tf.withSerialColumns(headers, "序列号", "WWN")
+	showFirmware := tf.GetBoolOption(OptionShowFirmware, false)
+	if showFirmware {
+		headers = append(headers, "固件版本")
+	}
+	showLinkSpeed := tf.GetBoolOption(OptionShowLinkSpeed, false)
+	if showLinkSpeed {
+		headers = append(headers, "链路速率")
+	}
+	showPower := tf.GetBoolOption(OptionShowPower, false)
+	if showPower {
+		headers = append(headers, "电源管理")
+	}
+	showSectors := tf.GetBoolOption(OptionShowSectors, false)
+	if showSectors {
+		headers = append(headers, "扇区格式")
+	}
+	showSensors := tf.GetBoolOption(OptionShowSensors, false)
+	if showSensors {
+		headers = append(headers, "传感器温度")
+	}
+	table.SetHeader(headers)
 
 	// Add rows for all disks
-	for _, disk := range tf.diskData.Disks {
+	for _, disk := range disks {
 		var row []string
 
 		if tf.GetBoolOption(OptionCompactMode, false) {
 			// Compact mode with fewer columns
 			row = []string{
-				disk.Name,
-				string(disk.Type),
+				tf.GetDiskName(disk),
+				disk.GetDisplayType(),
 				disk.Size,
-				disk.Pool,
+				tf.GetPoolName(disk.Pool),
 				disk.GetDisplayTemperature(),
 				FormatPowerOnHours(disk.GetAttribute("Power_On_Hours")),
-				colorizeSMARTStatus(FormatSMARTStatus(disk.GetAttribute("Smart_Status")), tf.GetBoolOption(OptionColorOutput, true)),
+				colorizeSMARTStatus(FormatSMARTStatus(disk.GetAttribute("Smart_Status")), tf.colorTheme()),
 			}
 		} else {
 			// Full mode with all columns
 			row = []string{
-				disk.Name,
+				tf.GetDiskName(disk),
 				disk.Model,
-				string(disk.Type),
+				disk.GetDisplayType(),
 				disk.Size,
-				disk.Pool,
+				tf.GetPoolName(disk.Pool),
 				disk.GetDisplayTemperature(),
 				FormatPowerOnHours(disk.GetAttribute("Power_On_Hours")),
-				colorizeSMARTStatus(FormatSMARTStatus(disk.GetAttribute("Smart_Status")), tf.GetBoolOption(OptionColorOutput, true)),
+				colorizeSMARTStatus(FormatSMARTStatus(disk.GetAttribute("Smart_Status")), tf.colorTheme()),
 				disk.GetAttribute("Data_Read"),
 				disk.GetAttribute("Data_Written"),
 			}
 		}
 
+		row = tf.withSerialColumns(row, displayOrNA(disk.Serial), displayOrNA(disk.WWN))
+		if showFirmware {
+			row = append(row, displayOrNA(disk.FirmwareVersion))
+		}
+		if showLinkSpeed {
+			row = append(row, disk.GetDisplayLinkSpeed())
+		}
+		if showPower {
+			row = append(row, disk.GetDisplayPower())
+		}
+		if showSectors {
+			row = append(row, disk.GetDisplaySectorSize())
+		}
+		if showSensors {
+			row = append(row, disk.GetDisplaySensorTemperatures())
+		}
 		table.Append(row)
 	}
 
@@ -366,6 +564,18 @@ func (tf *TextFormatter) writeTableForDiskType(diskType model.DiskType, disks []
 		return
 	}
 
+	// Columns chosen with --columns replace the default selection
+	if columns := tf.GetDiskColumns(diskType); len(columns) > 0 {
+		tf.writeCustomTableForDiskType(diskType, columns, disks)
+		return
+	}
+
+	// Merged SAS and NVMe SSDs share one table with the columns of both types
+	if diskType == model.DiskTypeSSD {
+		tf.writeCustomTableForDiskType(diskType, tf.GetMergedSSDColumns(), disks)
+		return
+	}
+
 	// Create a table
 	table := tf.createTable()
 
@@ -397,6 +607,35 @@ func (tf *TextFormatter) writeTableForDiskType(diskType model.DiskType, disks []
 
 		headers = append(headers, attr.DisplayName)
 	}
+	headers = tf.withSerialColumns(headers, "序列号", "WWN")
+	showTrim := tf.showTrimColumn(diskType)
+	if showTrim {
+		headers = append(headers, "TRIM")
+	}
+	showFirmware := tf.GetBoolOption(OptionShowFirmware, false)
+	if showFirmware {
+		headers = append(headers, "固件版本")
+	}
+	showLinkSpeed := tf.GetBoolOption(OptionShowLinkSpeed, false)
+	if showLinkSpeed {
+		headers = append(headers, "链路速率")
+	}
+	showPower := tf.GetBoolOption(OptionShowPower, false)
+	if showPower {
+		headers = append(headers, "电源管理")
+	}
+	showSectors := tf.GetBoolOption(OptionShowSectors, false)
+	if showSectors {
+		headers = append(headers, "扇区格式")
+	}
+	showSensors := tf.showSensorColumn(diskType)
+	if showSensors {
+		headers = append(headers, "传感器温度")
+	}
+	showEndurance := tf.showEnduranceColumn(diskType)
+	if showEndurance {
+		headers = append(headers, "耐久组寿命")
+	}
 
 	// Add increment columns if available and enabled
 	//if tf.diskData.HasPreviousData() && !tf.GetBoolOption(OptionCompactMode, false) &&
@@ -414,12 +653,13 @@ func (tf *TextFormatter) writeTableForDiskType(diskType model.DiskType, disks []
 		if tf.GetBoolOption(OptionCompactMode, false) {
 			// 格式化容量值
 			formattedSize := tf.formatDiskSize(disk.Size)
-			row = []string{disk.Name, formattedSize, disk.Pool}
+			row = []string{tf.GetDiskName(disk), formattedSize, tf.GetPoolName(disk.Pool)}
 		} else {
 			// 格式化容量值
 			formattedSize := tf.formatDiskSize(disk.Size)
-			row = []string{disk.Name, disk.Model, formattedSize, disk.Pool}
+			row = []string{tf.GetDiskName(disk), disk.Model, formattedSize, tf.GetPoolName(disk.Pool)}
 		}
+		row = tf.withSerialColumns(row, displayOrNA(disk.Serial), displayOrNA(disk.WWN))
 
 		// Add attribute values
 		for _, attr := range attributes {
@@ -441,12 +681,34 @@ func (tf *TextFormatter) writeTableForDiskType(diskType model.DiskType, disks []
 			case "Power_On_Hours":
 				value = FormatPowerOnHours(value)
 			case "Smart_Status":
-				value = colorizeSMARTStatus(FormatSMARTStatus(value), tf.GetBoolOption(OptionColorOutput, true))
+				value = colorizeSMARTStatus(FormatSMARTStatus(value), tf.colorTheme())
 			}
 
 			row = append(row, value)
 		}
 
+		if showTrim {
+			row = append(row, disk.GetDisplayTrimSupport())
+		}
+		if showFirmware {
+			row = append(row, displayOrNA(disk.FirmwareVersion))
+		}
+		if showLinkSpeed {
+			row = append(row, disk.GetDisplayLinkSpeed())
+		}
+		if showPower {
+			row = append(row, disk.GetDisplayPower())
+		}
+		if showSectors {
+			row = append(row, disk.GetDisplaySectorSize())
+		}
+		if showSensors {
+			row = append(row, disk.GetDisplaySensorTemperatures())
+		}
+		if showEndurance {
+			row = append(row, disk.GetDisplayEnduranceGroups())
+		}
+
 		// Add increment values if available
 		//if tf.diskData.HasPreviousData() && !tf.GetBoolOption(OptionCompactMode, false) &&
 		//	tf.GetBoolOption(OptionShowIncrements, false) { // 默认不显示增量
@@ -460,6 +722,259 @@ func (tf *TextFormatter) writeTableForDiskType(diskType model.DiskType, disks []
 	tf.renderTable(table)
 }
 
+// writeCustomTableForDiskType writes a table with user-selected columns
+// for disks of a specific type. Compact mode and show_serial do not apply,
+// since the selection is explicit; the opt-in feature columns still do
+func (tf *TextFormatter) writeCustomTableForDiskType(diskType model.DiskType, columns []model.DiskColumn, disks []*model.Disk) {
+	table := tf.createTable()
+	theme := tf.colorTheme()
+
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.DisplayName)
+	}
+	showTrim := tf.showTrimColumn(diskType)
+	if showTrim {
+		headers = append(headers, "TRIM")
+	}
+	showFirmware := tf.GetBoolOption(OptionShowFirmware, false)
+	if showFirmware {
+		headers = append(headers, "固件版本")
+	}
+	showLinkSpeed := tf.GetBoolOption(OptionShowLinkSpeed, false)
+	if showLinkSpeed {
+		headers = append(headers, "链路速率")
+	}
+	showPower := tf.GetBoolOption(OptionShowPower, false)
+	if showPower {
+		headers = append(headers, "电源管理")
+	}
+	showSectors := tf.GetBoolOption(OptionShowSectors, false)
+	if showSectors {
+		headers = append(headers, "扇区格式")
+	}
+	showSensors := tf.showSensorColumn(diskType)
+	if showSensors {
+		headers = append(headers, "传感器温度")
+	}
+	showEndurance := tf.showEnduranceColumn(diskType)
+	if showEndurance {
+		headers = append(headers, "耐久组寿命")
+	}
+	table.SetHeader(headers)
+
+	for _, disk := range disks {
+		row := make([]string, 0, len(headers))
+		for _, column := range columns {
+			value := tf.GetDiskColumnValue(disk, column)
+			if column.Name == model.ColumnStatus || column.Attribute == "Smart_Status" {
+				value = colorizeSMARTStatus(value, theme)
+			}
+			row = append(row, value)
+		}
+		if showTrim {
+			row = append(row, disk.GetDisplayTrimSupport())
+		}
+		if showFirmware {
+			row = append(row, displayOrNA(disk.FirmwareVersion))
+		}
+		if showLinkSpeed {
+			row = append(row, disk.GetDisplayLinkSpeed())
+		}
+		if showPower {
+			row = append(row, disk.GetDisplayPower())
+		}
+		if showSectors {
+			row = append(row, disk.GetDisplaySectorSize())
+		}
+		if showSensors {
+			row = append(row, disk.GetDisplaySensorTemperatures())
+		}
+		if showEndurance {
+			row = append(row, disk.GetDisplayEnduranceGroups())
+		}
+		table.Append(row)
+	}
+
+	tf.renderTable(table)
+}
+
+// withSerialColumns inserts serial and WWN columns after the disk name when show_serial is enabled
+func (tf *TextFormatter) withSerialColumns(columns []string, serial, wwn string) []string {
+	if !tf.GetBoolOption(OptionShowSerial, false) || len(columns) == 0 {
+		return columns
+	}
+
+	result := make([]string, 0, len(columns)+2)
+	result = append(result, columns[0], serial, wwn)
+	return append(result, columns[1:]...)
+}
+
+// showTrimColumn reports whether the TRIM column applies to a disk type
+func (tf *TextFormatter) showTrimColumn(diskType model.DiskType) bool {
+	if !tf.GetBoolOption(OptionShowFeatures, false) {
+		return false
+	}
+	return diskType.IsMergedSSD() || diskType == model.DiskTypeSSD
+}
+
+// showSensorColumn reports whether the per-sensor temperature column applies
+// to a disk type; only NVMe drives report more than one temperature sensor
+func (tf *TextFormatter) showSensorColumn(diskType model.DiskType) bool {
+	return tf.GetBoolOption(OptionShowSensors, false) && (diskType == model.DiskTypeNVMESSD || diskType == model.DiskTypeSSD)
+}
+
+// showEnduranceColumn reports whether the endurance group column applies to
+// a disk type; endurance groups are only collected for NVMe drives
+func (tf *TextFormatter) showEnduranceColumn(diskType model.DiskType) bool {
+	return tf.GetBoolOption(OptionShowEndurance, false) && (diskType == model.DiskTypeNVMESSD || diskType == model.DiskTypeSSD)
+}
+
+// displayOrNA returns the value, or "N/A" when it is empty
+func displayOrNA(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+	return value
+}
+
+// writeTopReport writes the n hottest disks and the n most-worn SSDs
+func (tf *TextFormatter) writeTopReport(n int) {
+	tf.writeSectionTitle(fmt.Sprintf("温度最高的 %d 个磁盘", n))
+	hottest := tf.diskData.HottestDisks(n)
+	if len(hottest) == 0 {
+		tf.buffer.WriteString("没有温度数据\n\n")
+	} else {
+		table := tf.createTable()
+		table.SetHeader([]string{"名称", "型号", "类型", "存储池", "温度", "状态"})
+		for _, disk := range hottest {
+			table.Append([]string{
+				tf.GetDiskName(disk),
+				disk.Model,
+				disk.GetDisplayType(),
+				tf.GetPoolName(disk.Pool),
+				disk.GetDisplayTemperature(),
+				colorizeSMARTStatus(FormatSMARTStatus(disk.GetAttribute("Smart_Status")), tf.colorTheme()),
+			})
+		}
+		tf.renderTable(table)
+	}
+
+	tf.writeSectionTitle(fmt.Sprintf("寿命消耗最多的 %d 个固态硬盘", n))
+	worn := tf.diskData.MostWornSSDs(n)
+	if len(worn) == 0 {
+		tf.buffer.WriteString("没有固态硬盘寿命数据\n\n")
+		return
+	}
+	table := tf.createTable()
+	table.SetHeader([]string{"名称", "型号", "类型", "存储池", "已用寿命", "通电时间"})
+	for _, disk := range worn {
+		table.Append([]string{
+			tf.GetDiskName(disk),
+			disk.Model,
+			disk.GetDisplayType(),
+			tf.GetPoolName(disk.Pool),
+			disk.GetAttribute("Percentage_Used") + "%",
+			FormatPowerOnHours(disk.GetAttribute("Power_On_Hours")),
+		})
+	}
+	tf.renderTable(table)
+}
+
+// writeErrorOverview lists every disk with a non-zero error counter, worst first
+func (tf *TextFormatter) writeErrorOverview() {
+	rows := tf.diskData.ErrorOverview()
+	if len(rows) == 0 {
+		return
+	}
+
+	tf.writeSectionTitle("错误总览")
+
+	table := tf.createTable()
+	table.SetHeader([]string{"名称", "状态", "未修正错误", "非介质错误", "增长缺陷", "待映射扇区", "合计"})
+	for _, row := range rows {
+		table.Append([]string{
+			row.Name,
+			colorizeSMARTStatus(FormatSMARTStatus(string(row.Status)), tf.colorTheme()),
+			fmt.Sprintf("%d", row.UncorrectedErrors),
+			fmt.Sprintf("%d", row.NonMediumErrors),
+			fmt.Sprintf("%d", row.GrownDefects),
+			fmt.Sprintf("%d", row.PendingSectors),
+			fmt.Sprintf("%d", row.Total()),
+		})
+	}
+
+	tf.renderTable(table)
+}
+
+// writeStatusReasons lists flagged disks with the reasons behind their status,
+// so drive self-reported warnings can be told apart from heuristic findings
+func (tf *TextFormatter) writeStatusReasons() {
+	flagged := tf.GetFlaggedDisks()
+	if len(flagged) == 0 {
+		return
+	}
+
+	tf.writeSectionTitle("状态说明")
+
+	table := tf.createTable()
+	table.SetHeader([]string{"名称", "状态", "原因"})
+	for _, disk := range flagged {
+		reason := disk.StatusReason
+		if reason == "" {
+			reason = "N/A"
+		}
+		table.Append([]string{
+			tf.GetDiskName(disk),
+			colorizeSMARTStatus(FormatSMARTStatus(string(disk.GetStatus())), tf.colorTheme()),
+			reason,
+		})
+	}
+
+	tf.renderTable(table)
+}
+
+// writePoolChanges lists disks whose pool differs from the previous run,
+// since an unexpected move can indicate a misconfiguration
+func (tf *TextFormatter) writePoolChanges() {
+	changed := tf.diskData.GetPoolChangedDisks()
+	if len(changed) == 0 {
+		return
+	}
+
+	tf.writeSectionTitle("存储池变化")
+
+	table := tf.createTable()
+	table.SetHeader([]string{"名称", "序列号", "原存储池", "当前存储池"})
+	for _, disk := range changed {
+		table.Append([]string{tf.GetDiskName(disk), displayOrNA(disk.Serial), disk.PreviousPool, tf.GetPoolName(disk.Pool)})
+	}
+
+	tf.renderTable(table)
+}
+
+// writeEvents lists the recent disk events loaded from the event log
+func (tf *TextFormatter) writeEvents() {
+	if len(tf.diskData.Events) == 0 {
+		return
+	}
+
+	tf.writeSectionTitle("最近事件")
+
+	table := tf.createTable()
+	table.SetHeader([]string{"时间", "名称", "序列号", "事件"})
+	for _, event := range tf.diskData.Events {
+		table.Append([]string{
+			event.Time.Local().Format("2006-01-02 15:04:05"),
+			event.Disk,
+			displayOrNA(event.Serial),
+			event.Description(),
+		})
+	}
+
+	tf.renderTable(table)
+}
+
 // writeIncrementTable writes a table showing read/write increments
 func (tf *TextFormatter) writeIncrementTable() {
 	if !tf.diskData.HasPreviousData() {
@@ -483,10 +998,10 @@ func (tf *TextFormatter) writeIncrementTable() {
 
 		// Create row
 		row := []string{
-			disk.Name,
+			tf.GetDiskName(disk),
 			string(disk.Type),
 			disk.Model,
-			disk.Pool,
+			tf.GetPoolName(disk.Pool),
 			disk.GetAttribute("Data_Read"),
 			disk.ReadIncrement,
 			disk.GetAttribute("Data_Written"),
@@ -500,6 +1015,33 @@ func (tf *TextFormatter) writeIncrementTable() {
 	tf.renderTable(table)
 }
 
+// writeLegend explains the attribute columns, their units and the status
+// colors, if requested
+func (tf *TextFormatter) writeLegend() {
+	if !tf.GetBoolOption(OptionLegend, false) {
+		return
+	}
+
+	tf.writeSectionTitle("图例")
+
+	tf.buffer.WriteString("列说明:\n")
+	for _, entry := range tf.diskData.GetColumnLegend() {
+		name := entry.DisplayName
+		if entry.Unit != "" {
+			name = fmt.Sprintf("%s (%s)", name, entry.Unit)
+		}
+		tf.buffer.WriteString(fmt.Sprintf("- %s: %s\n", name, displayOrNA(entry.Description)))
+	}
+
+	tf.buffer.WriteString("\n状态说明:\n")
+	theme := tf.colorTheme()
+	for _, entry := range StatusLegend {
+		name := colorizeSMARTStatus(FormatDiskStatus(entry.Status), theme)
+		tf.buffer.WriteString(fmt.Sprintf("- %s: %s\n", name, entry.Description))
+	}
+	tf.buffer.WriteString("\n")
+}
+
 // writeLSIControllers writes LSI controller information
 func (tf *TextFormatter) writeLSIControllers() {
 	if len(tf.controllerData.LSIControllers) == 0 {
@@ -512,36 +1054,46 @@ func (tf *TextFormatter) writeLSIControllers() {
 	table := tf.createTable()
 
 	// Set header
+	var headers []string
 	if tf.GetBoolOption(OptionCompactMode, false) {
-		table.SetHeader([]string{"控制器名称", "型号", "温度", "设备数", "状态"})
+		headers = []string{"控制器名称", "型号", "温度", "设备数", "状态"}
 	} else {
-		table.SetHeader([]string{"控制器名称", "型号", "固件版本", "驱动版本", "温度", "设备数", "状态"})
+		headers = []string{"控制器名称", "型号", "固件版本", "驱动版本", "温度", "设备数", "状态"}
 	}
+	showSectors := tf.GetBoolOption(OptionShowSectors, false)
+	if showSectors {
+		headers = append(headers, "扇区大小")
+	}
+	table.SetHeader(headers)
 
 	// Add rows for each controller
-	for id, controller := range tf.controllerData.LSIControllers {
+	for _, controller := range tf.controllerData.GetSortedLSIControllers() {
 		var row []string
 
 		if tf.GetBoolOption(OptionCompactMode, false) {
 			row = []string{
-				id,
+				controller.ID,
 				controller.Model,
-				controller.GetDisplayTemperature(),
+				controller.GetDisplayTemperatureTrend(),
 				controller.DeviceCount,
-				colorizeControllerStatus(string(controller.Status), tf.GetBoolOption(OptionColorOutput, true)),
+				colorizeControllerStatus(string(controller.Status), tf.colorTheme()),
 			}
 		} else {
 			row = []string{
-				id,
+				controller.ID,
 				controller.Model,
 				controller.FirmwareVersion,
 				controller.DriverVersion,
-				controller.GetDisplayTemperature(),
+				controller.GetDisplayTemperatureTrend(),
 				controller.DeviceCount,
-				colorizeControllerStatus(string(controller.Status), tf.GetBoolOption(OptionColorOutput, true)),
+				colorizeControllerStatus(string(controller.Status), tf.colorTheme()),
 			}
 		}
 
+		if showSectors {
+			row = append(row, controller.GetDisplaySectorSizes())
+		}
+
 		table.Append(row)
 	}
 
@@ -549,6 +1101,26 @@ func (tf *TextFormatter) writeLSIControllers() {
 	tf.renderTable(table)
 }
 
+// writeLSIFirmwareVersions writes the firmware version details of LSI controllers
+func (tf *TextFormatter) writeLSIFirmwareVersions() {
+	tf.writeSectionTitle("LSI控制器固件信息")
+
+	table := tf.createTable()
+	table.SetHeader([]string{"控制器名称", "固件包版本", "固件版本", "BIOS版本", "NVDATA版本", "驱动版本"})
+	for _, controller := range tf.controllerData.GetSortedLSIControllers() {
+		table.Append([]string{
+			controller.ID,
+			displayOrNA(controller.FWPackageBuild),
+			displayOrNA(controller.FirmwareVersion),
+			displayOrNA(controller.BIOSVersion),
+			displayOrNA(controller.NVDATAVersion),
+			displayOrNA(controller.DriverVersion),
+		})
+	}
+
+	tf.renderTable(table)
+}
+
 // writeNVMeControllers writes NVMe controller information
 func (tf *TextFormatter) writeNVMeControllers() {
 	if len(tf.controllerData.NVMeControllers) == 0 {
@@ -564,11 +1136,11 @@ func (tf *TextFormatter) writeNVMeControllers() {
 	table.SetHeader([]string{"总线ID", "控制器描述", "温度"})
 
 	// Add rows for each controller
-	for _, controller := range tf.controllerData.NVMeControllers {
+	for _, controller := range tf.controllerData.GetSortedNVMeControllers() {
 		row := []string{
 			controller.Bus,
 			controller.Description,
-			controller.GetDisplayTemperature(),
+			controller.GetDisplayTemperatureTrend(),
 		}
 
 		table.Append(row)
@@ -578,6 +1150,28 @@ func (tf *TextFormatter) writeNVMeControllers() {
 	tf.renderTable(table)
 }
 
+// writeControllerStatusReasons writes why controllers were flagged
+func (tf *TextFormatter) writeControllerStatusReasons() {
+	flagged := tf.controllerData.GetFlaggedControllers()
+	if len(flagged) == 0 {
+		return
+	}
+
+	tf.writeSectionTitle("控制器状态说明")
+
+	table := tf.createTable()
+	table.SetHeader([]string{"控制器名称", "状态", "原因"})
+	for _, controller := range flagged {
+		table.Append([]string{
+			controller.ID,
+			colorizeControllerStatus(string(controller.Status), tf.colorTheme()),
+			controller.StatusReason,
+		})
+	}
+
+	tf.renderTable(table)
+}
+
 // createTable creates a table with common settings
 func (tf *TextFormatter) createTable() *tablewriter.Table {
 	// Reset table buffer
@@ -625,90 +1219,63 @@ func (tf *TextFormatter) renderTable(table *tablewriter.Table) {
 
 // formatDiskSize 格式化磁盘容量为人类可读格式
 func (tf *TextFormatter) formatDiskSize(sizeStr string) string {
-	// 尝试将科学计数法转换为浮点数
-	size, err := strconv.ParseFloat(sizeStr, 64)
-	if err != nil {
-		return sizeStr // 如果解析失败，返回原始字符串
-	}
-
-	// 使用与smart.go中类似的逻辑格式化容量
-	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
-	unitIndex := 0
+	return FormatSciNotation(sizeStr, tf.GetSizeUnits())
+}
 
-	for size >= 1024 && unitIndex < len(units)-1 {
-		size /= 1024
-		unitIndex++
-	}
-
-	// 根据大小值和单位格式化输出
-	if size < 10 {
-		return fmt.Sprintf("%.2f %s", size, units[unitIndex])
-	} else if size < 100 {
-		return fmt.Sprintf("%.1f %s", size, units[unitIndex])
-	} else {
-		return fmt.Sprintf("%.0f %s", size, units[unitIndex])
+// colorTheme returns the theme selected by color_theme, or nil when color
+// output is disabled
+func (tf *TextFormatter) colorTheme() *ColorTheme {
+	if !tf.GetBoolOption(OptionColorOutput, true) {
+		return nil
 	}
+	return GetColorTheme(model.ColorTheme(tf.GetStringOption(OptionColorTheme, string(model.ColorThemeDefault))))
 }
 
-// colorizeText applies ANSI color to text if color output is enabled
-func colorizeText(text string, color string) string {
-	// ANSI color codes
-	const (
-		colorReset  = "\033[0m"
-		colorRed    = "\033[31m"
-		colorGreen  = "\033[32m"
-		colorYellow = "\033[33m"
-	)
-
+// colorizeText wraps text in the ANSI sequence of a style
+func colorizeText(text string, style ColorStyle) string {
 	// Return plain text if empty
 	if text == "" {
 		return ""
 	}
 
-	// Apply color based on requested color
-	switch color {
-	case "red":
-		return colorRed + text + colorReset
-	case "green":
-		return colorGreen + text + colorReset
-	case "yellow":
-		return colorYellow + text + colorReset
-	default:
+	sequence := style.Sequence()
+	if sequence == "" {
 		return text
 	}
+	return sequence + text + ansiReset
 }
 
 // colorizeSMARTStatus applies appropriate color to SMART status
-func colorizeSMARTStatus(status string, useColor bool) string {
-	if !useColor {
+func colorizeSMARTStatus(status string, theme *ColorTheme) string {
+	if theme == nil {
 		return status
 	}
 
 	switch status {
 	case "正常":
-		return colorizeText(status, "green")
+		return colorizeText(status, theme.OK)
 	case "警告":
-		return colorizeText(status, "yellow")
+		return colorizeText(status, theme.Warning)
 	case "错误":
-		return colorizeText(status, "red")
+		return colorizeText(status, theme.Error)
 	default:
 		return status
 	}
 }
 
 // colorizeControllerStatus applies appropriate color to controller status
-func colorizeControllerStatus(status string, useColor bool) string {
-	if !useColor {
+func colorizeControllerStatus(status string, theme *ColorTheme) string {
+	if theme == nil {
 		return status
 	}
 
 	switch status {
 	case "正常":
-		return colorizeText(status, "green")
+		return colorizeText(status, theme.OK)
 	case "警告":
-		return colorizeText(status, "yellow")
+		return colorizeText(status, theme.Warning)
 	case "错误":
-		return colorizeText(status, "red")
+		return colorizeText(status, theme.Error)
 	default:
 		return status
 	}
@@ -399,6 +399,36 @@ func TestTextFormatter_NoGroup(t *testing.T) {
 	}
 }
 
+func TestTextFormatter_ControllerOnly(t *testing.T) {
+	formatter := createTextFormatter(map[string]interface{}{
+		OptionColorOutput: false,
+	})
+
+	if err := formatter.FormatControllerInfo(createTestControllerData()); err != nil {
+		t.Fatalf("Expected no error when formatting controller info, got: %v", err)
+	}
+	output := formatter.String()
+
+	if !strings.Contains(output, "TrueNAS控制器信息") {
+		t.Error("Expected controller-only title")
+	}
+	if !strings.Contains(output, "LSI_Controller_0") || !strings.Contains(output, "0000:01:00.0") {
+		t.Error("Expected LSI and NVMe controller rows")
+	}
+	if strings.Contains(output, "系统摘要") {
+		t.Error("Expected no disk summary in controller-only output")
+	}
+
+	// No controllers found
+	formatter = createTextFormatter(nil)
+	if err := formatter.FormatControllerInfo(model.NewControllerData()); err != nil {
+		t.Fatalf("Expected no error for empty controller data, got: %v", err)
+	}
+	if !strings.Contains(formatter.String(), "未检测到控制器") {
+		t.Error("Expected a notice when no controllers are found")
+	}
+}
+
 func TestTextFormatter_EdgeCases(t *testing.T) {
 	// 设置变量的值，让测试能够继续
 	originalNewTextFormatter := NewTextFormatter
@@ -439,3 +469,428 @@ func TestTextFormatter_EdgeCases(t *testing.T) {
 		t.Errorf("FormatControllerInfo should not return error with empty controller data: %v", err)
 	}
 }
+
+func TestTextFormatter_GroupByPool(t *testing.T) {
+	diskData := model.NewDiskData()
+	for _, d := range []struct{ name, pool string }{
+		{"sdc", "tank"},
+		{"sdb", "backup"},
+		{"sda", "tank"},
+		{"sdd", model.PoolUnassigned},
+		{"sde", "backup"},
+	} {
+		disk := model.NewDisk(d.name, "HDD", "TEST MODEL", "1T")
+		disk.Pool = d.pool
+		diskData.AddDisk(disk)
+	}
+
+	formatter := createTextFormatter(map[string]interface{}{
+		OptionGroupBy:     string(model.GroupByPool),
+		OptionColorOutput: false,
+	})
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	output := formatter.String()
+
+	// Pool headings should appear in sorted order with unassigned disks last
+	headings := []string{"--- 存储池: backup ---", "--- 存储池: tank ---", "--- 存储池: " + model.PoolUnassigned + " ---"}
+	last := -1
+	for _, heading := range headings {
+		index := strings.Index(output, heading)
+		if index < 0 {
+			t.Fatalf("Output missing pool heading %q", heading)
+		}
+		if index < last {
+			t.Errorf("Pool heading %q is out of order", heading)
+		}
+		last = index
+	}
+
+	if strings.Contains(output, "--- 所有磁盘 ---") || strings.Contains(output, "--- SAS/SATA 机械硬盘 ---") {
+		t.Error("Pool grouping should not contain type or combined headings")
+	}
+
+	// Disks should be sorted by name within each pool
+	order := []string{"sdb", "sde", "sda", "sdc", "sdd"}
+	last = -1
+	for _, name := range order {
+		index := strings.Index(output, "| "+name)
+		if index < 0 {
+			t.Fatalf("Output missing disk %s", name)
+		}
+		if index < last {
+			t.Errorf("Disk %s is out of order", name)
+		}
+		last = index
+	}
+}
+
+func TestTextFormatter_VerboseFirmwareVersions(t *testing.T) {
+	controllerData := createTestControllerData()
+	lsi := controllerData.LSIControllers["LSI_Controller_0"]
+	lsi.FWPackageBuild = "16.00.01.00"
+	lsi.BIOSVersion = "08.37.00.00_18.00.00.00"
+	lsi.NVDATAVersion = "14.01.00.06"
+
+	// 默认不显示固件信息
+	formatter := createTextFormatter(map[string]interface{}{OptionColorOutput: false})
+	if err := formatter.FormatControllerInfo(controllerData); err != nil {
+		t.Fatalf("FormatControllerInfo failed: %v", err)
+	}
+	if strings.Contains(formatter.String(), "LSI控制器固件信息") {
+		t.Error("Expected firmware versions to be hidden without verbose")
+	}
+
+	formatter = createTextFormatter(map[string]interface{}{OptionColorOutput: false, OptionVerbose: true})
+	if err := formatter.FormatControllerInfo(controllerData); err != nil {
+		t.Fatalf("FormatControllerInfo failed: %v", err)
+	}
+	output := formatter.String()
+	for _, want := range []string{"LSI控制器固件信息", "08.37.00.00_18.00.00.00", "14.01.00.06"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected verbose output to contain %q", want)
+		}
+	}
+}
+
+func TestTextFormatter_SummaryFooter(t *testing.T) {
+	formatter := createTextFormatter(map[string]interface{}{OptionSummaryFooter: true})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if err := formatter.FormatControllerInfo(createTestControllerData()); err != nil {
+		t.Fatalf("FormatControllerInfo failed: %v", err)
+	}
+
+	// 无论是否启用颜色，最后一行都应是固定格式的摘要
+	output := strings.TrimRight(formatter.String(), "\n")
+	lines := strings.Split(output, "\n")
+	footer := lines[len(lines)-1]
+	expected := "SUMMARY total=5 ssd=3 hdd=2 warn=1 err=1 controllers=2"
+	if footer != expected {
+		t.Errorf("Expected footer %q, got %q", expected, footer)
+	}
+
+	// 默认不追加摘要行
+	formatter = createTextFormatter(nil)
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if strings.Contains(formatter.String(), "SUMMARY ") {
+		t.Error("Expected no footer unless the option is enabled")
+	}
+}
+
+func TestTextFormatter_NoGroupVirtualDisk(t *testing.T) {
+	diskData := model.NewDiskData()
+	hdd := model.NewDisk("sda", "HDD", "SEAGATE ST600MM0006", "600G")
+	hdd.SMARTData["Smart_Status"] = "PASSED"
+	hdd.SMARTData["Temperature"] = "35"
+	diskData.AddDisk(hdd)
+	virtual := model.NewDisk("sdz", "", "VMware Virtual disk", "16 GB")
+	virtual.SMARTData["Type"] = "虚拟设备"
+	virtual.SMARTData["Smart_Status"] = model.SmartStatusVirtual
+	diskData.AddDisk(virtual)
+
+	formatter := createTextFormatter(map[string]interface{}{
+		OptionGroupBy:     string(model.GroupByNone),
+		OptionColorOutput: false,
+	})
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+
+	var virtualRow, hddRow string
+	for _, line := range strings.Split(formatter.String(), "\n") {
+		switch {
+		case strings.Contains(line, "sdz"):
+			virtualRow = line
+		case strings.Contains(line, "sda"):
+			hddRow = line
+		}
+	}
+	if virtualRow == "" || hddRow == "" {
+		t.Fatalf("Expected rows for both disks, got:\n%s", formatter.String())
+	}
+
+	// 虚拟设备显示类型说明，SMART相关列显示 "-" 而不是 "N/A"
+	if strings.Contains(virtualRow, "N/A") || strings.Contains(virtualRow, string(model.DiskTypeVirtual)) {
+		t.Errorf("Expected no N/A columns or raw type in virtual row, got %q", virtualRow)
+	}
+	if strings.Count(virtualRow, "虚拟设备") != 2 || strings.Count(virtualRow, " - ") != 4 {
+		t.Errorf("Expected type label, status and four '-' columns, got %q", virtualRow)
+	}
+
+	// 物理磁盘缺失的数据仍显示 N/A
+	if !strings.Contains(hddRow, "N/A") || !strings.Contains(hddRow, "35°C") {
+		t.Errorf("Expected physical disk row to keep N/A for missing data, got %q", hddRow)
+	}
+}
+
+func TestTextFormatter_TopReport(t *testing.T) {
+	formatter := createTextFormatter(map[string]interface{}{OptionTopN: 2, OptionColorOutput: false})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	output := formatter.String()
+
+	// 快速报告代替完整表格，但保留摘要
+	for _, want := range []string{"温度最高的 2 个磁盘", "寿命消耗最多的 2 个固态硬盘", "系统摘要"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected top report to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "--- SAS/SATA 固态硬盘 ---") {
+		t.Errorf("Expected full disk tables to be omitted, got:\n%s", output)
+	}
+}
+
+func TestTextFormatter_CustomColumns(t *testing.T) {
+	_, columns, err := model.ParseColumnSpec("SAS_HDD=name,temp,status")
+	if err != nil {
+		t.Fatalf("ParseColumnSpec failed: %v", err)
+	}
+	formatter := createTextFormatter(map[string]interface{}{
+		OptionColorOutput: false,
+		OptionColumns:     map[model.DiskType][]model.DiskColumn{model.DiskTypeSASHDD: columns},
+	})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	output := formatter.String()
+
+	// 截取机械硬盘表格
+	start := strings.Index(output, "SAS/SATA 机械硬盘")
+	end := strings.Index(output, "NVMe 固态硬盘")
+	if start < 0 || end < start {
+		t.Fatalf("Expected HDD section before NVMe section, got:\n%s", output)
+	}
+	hddSection := output[start:end]
+
+	for _, want := range []string{"名称", "温度", "状态", "sdc", "34°C", "正常"} {
+		if !strings.Contains(hddSection, want) {
+			t.Errorf("Expected %q in HDD table, got:\n%s", want, hddSection)
+		}
+	}
+	for _, omitted := range []string{"型号", "容量", "存储池", "通电时间", "SMART状态", "已读数据", "WDC WD40EFRX"} {
+		if strings.Contains(hddSection, omitted) {
+			t.Errorf("Expected %q to be omitted from HDD table, got:\n%s", omitted, hddSection)
+		}
+	}
+
+	// 未指定列的类型保留默认列
+	if ssdSection := output[:start]; !strings.Contains(ssdSection, "型号") || !strings.Contains(ssdSection, "SMART状态") {
+		t.Errorf("Expected default columns for SSD table, got:\n%s", ssdSection)
+	}
+}
+
+func TestTextFormatter_ColorTheme(t *testing.T) {
+	defaultTheme := GetColorTheme(model.ColorThemeDefault)
+	deuteranopia := GetColorTheme(model.ColorThemeDeuteranopia)
+
+	// 色盲友好配色的每个状态都使用与默认配色不同的转义序列，且状态之间互不相同
+	seen := make(map[string]bool)
+	for _, style := range []ColorStyle{defaultTheme.OK, defaultTheme.Warning, defaultTheme.Error} {
+		seen[style.Sequence()] = true
+	}
+	alternate := make(map[string]bool)
+	for _, style := range []ColorStyle{deuteranopia.OK, deuteranopia.Warning, deuteranopia.Error} {
+		sequence := style.Sequence()
+		if seen[sequence] {
+			t.Errorf("Expected deuteranopia sequence %q to differ from the default palette", sequence)
+		}
+		alternate[sequence] = true
+	}
+	if len(alternate) != 3 {
+		t.Errorf("Expected three distinct deuteranopia sequences, got %v", alternate)
+	}
+	if got := deuteranopia.Error.Sequence(); got != "\033[1;4;35m" {
+		t.Errorf("Expected bold underlined magenta for errors, got %q", got)
+	}
+
+	// 文本输出使用所选配色
+	render := func(theme model.ColorTheme) string {
+		formatter := createTextFormatter(map[string]interface{}{OptionColorTheme: string(theme)})
+		if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+			t.Fatalf("FormatDiskInfo failed: %v", err)
+		}
+		return formatter.String()
+	}
+	output := render(model.ColorThemeDeuteranopia)
+	if !strings.Contains(output, colorizeText("正常", deuteranopia.OK)) || !strings.Contains(output, colorizeText("错误", deuteranopia.Error)) {
+		t.Errorf("Expected deuteranopia colors in output, got:\n%s", output)
+	}
+	if strings.Contains(output, defaultTheme.OK.Sequence()) {
+		t.Error("Expected no default green in deuteranopia output")
+	}
+	if output := render(model.ColorThemeDefault); !strings.Contains(output, colorizeText("正常", defaultTheme.OK)) {
+		t.Errorf("Expected default colors in output, got:\n%s", output)
+	}
+}
+
+func TestTextFormatter_Legend(t *testing.T) {
+	diskData := createTestDiskData()
+
+	// 默认不显示图例
+	formatter := createTextFormatter(map[string]interface{}{OptionColorOutput: false})
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	if strings.Contains(formatter.String(), "--- 图例 ---") {
+		t.Error("Expected no legend unless the option is enabled")
+	}
+
+	formatter = createTextFormatter(map[string]interface{}{OptionColorOutput: false, OptionLegend: true})
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	output := formatter.String()
+	if !strings.Contains(output, "--- 图例 ---") {
+		t.Fatalf("Expected a legend section, got:\n%s", output)
+	}
+
+	// 图例由各类型的属性列表生成，列出显示名称和单位
+	for _, diskType := range diskData.GetOrderedDiskTypes() {
+		for _, attr := range diskData.GetDiskAttributes(diskType) {
+			expected := "- " + attr.DisplayName
+			if attr.Unit != "" {
+				expected += " (" + attr.Unit + ")"
+			}
+			if !strings.Contains(output, expected+":") {
+				t.Errorf("Expected legend entry %q, got:\n%s", expected, output)
+			}
+		}
+	}
+	for _, entry := range StatusLegend {
+		if !strings.Contains(output, "- "+FormatDiskStatus(entry.Status)+": "+entry.Description) {
+			t.Errorf("Expected status legend for %s", entry.Status)
+		}
+	}
+
+	// HTML图例列出相同的列和单位
+	htmlFormatter := createHTMLFormatter(map[string]interface{}{OptionLegend: true})
+	if err := htmlFormatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := htmlFormatter.String()
+	for _, entry := range diskData.GetColumnLegend() {
+		unit := entry.Unit
+		if unit == "" {
+			unit = "-"
+		}
+		if !strings.Contains(htmlContent, "<tr><td>"+entry.DisplayName+"</td><td>"+unit+"</td>") {
+			t.Errorf("Expected HTML legend row for %s", entry.DisplayName)
+		}
+	}
+	if !strings.Contains(htmlContent, `<td class="status-warning">警告</td>`) {
+		t.Error("Expected the warning status in the HTML legend")
+	}
+}
+
+func TestTextFormatter_MergeSSD(t *testing.T) {
+	diskData := createTestDiskData()
+
+	formatter := createTextFormatter(map[string]interface{}{OptionColorOutput: false, OptionMergeSSD: true})
+	if err := formatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	output := formatter.String()
+
+	// SAS和NVMe固态硬盘合并为一个分组，机械硬盘仍单独显示
+	if count := strings.Count(output, "--- "+model.MergedSSDTitle+" ---"); count != 1 {
+		t.Fatalf("Expected a single SSD section, got %d:\n%s", count, output)
+	}
+	for _, title := range []string{model.DiskTypeSASSSD.GetGroupTitle(), model.DiskTypeNVMESSD.GetGroupTitle()} {
+		if strings.Contains(output, "--- "+title+" ---") {
+			t.Errorf("Expected no separate %s section when merged", title)
+		}
+	}
+	if !strings.Contains(output, "--- "+model.DiskTypeSASHDD.GetGroupTitle()+" ---") {
+		t.Error("Expected the HDD section to remain")
+	}
+
+	// 合并的表格同时包含SAS和NVMe固态硬盘，以及两类磁盘的列
+	section := output[strings.Index(output, "--- "+model.MergedSSDTitle+" ---"):]
+	section = section[:strings.Index(section, "--- "+model.DiskTypeSASHDD.GetGroupTitle()+" ---")]
+	for _, name := range []string{"sda", "nvme0n1"} {
+		if !strings.Contains(section, name) {
+			t.Errorf("Expected %s in the SSD section, got:\n%s", name, section)
+		}
+	}
+	for _, header := range []string{"已用寿命", "可用备件", "临界温度", "非介质错误"} {
+		if !strings.Contains(section, header) {
+			t.Errorf("Expected column %s in the merged SSD table", header)
+		}
+	}
+
+	// 警告温度列按磁盘类型取值: SAS为Trip_Temperature，NVMe为Warning_Temperature
+	column, _ := model.LookupDiskColumn("trip_temperature")
+	for _, disk := range diskData.Disks {
+		if disk.Name == "nvme0n1" && formatter.GetDiskColumnValue(disk, column) != "70" {
+			t.Errorf("Expected NVMe warning temperature 70, got %s", formatter.GetDiskColumnValue(disk, column))
+		}
+	}
+
+	// 磁盘本身的类型不变，摘要仍按原类型统计
+	if len(diskData.GroupedDisks[model.DiskTypeNVMESSD]) != 1 {
+		t.Error("Expected the underlying disk types to be unchanged")
+	}
+
+	// HTML同样只有一个固态硬盘分组
+	htmlFormatter := createHTMLFormatter(map[string]interface{}{OptionMergeSSD: true})
+	if err := htmlFormatter.FormatDiskInfo(diskData); err != nil {
+		t.Fatalf("Expected no error when formatting disk info, got: %v", err)
+	}
+	htmlContent := htmlFormatter.String()
+	if count := strings.Count(htmlContent, "<span>"+model.MergedSSDTitle+"</span>"); count != 1 {
+		t.Errorf("Expected a single SSD panel in HTML, got %d", count)
+	}
+	if strings.Contains(htmlContent, "<span>"+model.DiskTypeNVMESSD.GetGroupTitle()+"</span>") {
+		t.Error("Expected no separate NVMe panel in HTML when merged")
+	}
+}
+
+func TestTextFormatter_SortSectionsBySeverity(t *testing.T) {
+	// 检查各分组标题按给定顺序出现
+	assertSectionOrder := func(t *testing.T, output string, titles ...string) {
+		t.Helper()
+		last := -1
+		for _, title := range titles {
+			index := strings.Index(output, "--- "+title)
+			if index < 0 || index < last {
+				t.Errorf("Expected sections in order %v, got:\n%s", titles, output)
+				return
+			}
+			last = index
+		}
+	}
+	ssd := model.DiskTypeSASSSD.GetGroupTitle()
+	hdd := model.DiskTypeSASHDD.GetGroupTitle()
+	nvme := model.DiskTypeNVMESSD.GetGroupTitle()
+
+	// 测试数据中机械硬盘分组有错误，SAS固态硬盘分组有警告，NVMe分组正常
+	formatter := createTextFormatter(map[string]interface{}{OptionColorOutput: false, OptionSortSections: "severity"})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	assertSectionOrder(t, formatter.String(), hdd, ssd, nvme)
+
+	// 默认顺序不受影响
+	formatter = createTextFormatter(map[string]interface{}{OptionColorOutput: false})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	assertSectionOrder(t, formatter.String(), ssd, hdd, nvme)
+
+	// 按存储池分组时同样按严重程度排序: data(错误) > tank(警告) > cache(正常)
+	formatter = createTextFormatter(map[string]interface{}{
+		OptionColorOutput:  false,
+		OptionGroupBy:      string(model.GroupByPool),
+		OptionSortSections: "severity",
+	})
+	if err := formatter.FormatDiskInfo(createTestDiskData()); err != nil {
+		t.Fatalf("FormatDiskInfo failed: %v", err)
+	}
+	assertSectionOrder(t, formatter.String(), "存储池: data", "存储池: tank", "存储池: cache")
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// ansiReset ends any ANSI style started by a ColorStyle
+const ansiReset = "\033[0m"
+
+// ColorStyle is the ANSI style used for one status level
+type ColorStyle struct {
+	Color     string // SGR color parameter, e.g. "32" for green
+	Bold      bool
+	Underline bool
+}
+
+// Sequence returns the ANSI escape sequence that starts the style
+func (s ColorStyle) Sequence() string {
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if s.Color != "" {
+		params = append(params, s.Color)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// ColorTheme holds the styles for healthy, warning and error states
+type ColorTheme struct {
+	OK      ColorStyle
+	Warning ColorStyle
+	Error   ColorStyle
+}
+
+// colorThemes lists the built-in themes. The deuteranopia palette avoids
+// relying on red versus green and also marks errors with bold and underline,
+// so the levels stay apart even when the hues are hard to tell
+var colorThemes = map[model.ColorTheme]*ColorTheme{
+	model.ColorThemeDefault: {
+		OK:      ColorStyle{Color: "32"},
+		Warning: ColorStyle{Color: "33"},
+		Error:   ColorStyle{Color: "31"},
+	},
+	model.ColorThemeDeuteranopia: {
+		OK:      ColorStyle{Color: "34"},
+		Warning: ColorStyle{Color: "33", Bold: true},
+		Error:   ColorStyle{Color: "35", Bold: true, Underline: true},
+	},
+}
+
+// GetColorTheme returns a built-in theme, falling back to the default theme
+// for unknown names
+func GetColorTheme(name model.ColorTheme) *ColorTheme {
+	if theme, ok := colorThemes[name]; ok {
+		return theme
+	}
+	return colorThemes[model.ColorThemeDefault]
+}
@@ -3,15 +3,14 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
-	"math"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
 	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
@@ -40,24 +39,55 @@ type HistoryStorage interface {
 	// CreateBackup creates a backup of the current data file
 	CreateBackup() error
 
+	// ResetBaseline backs up and then clears the stored history
+	ResetBaseline() (backupPath string, err error)
+
 	// VerifyIntegrity checks the integrity of the storage file
 	VerifyIntegrity() (bool, error)
 }
 
+// ControllerHistoryData represents the stored controller temperature snapshot
+type ControllerHistoryData struct {
+	Timestamp    string            `json:"timestamp"`    // Data collection timestamp
+	Temperatures map[string]string `json:"temperatures"` // Controller ID to temperature (°C)
+}
+
 // DiskHistoryStorage implements the HistoryStorage interface
 type DiskHistoryStorage struct {
-	path   string        // Path to the data file
-	logger system.Logger // Logger for recording operations
+	path      string          // Path to the data file
+	logger    system.Logger   // Logger for recording operations
+	clock     system.Clock    // Time source for timestamps
+	units     model.SizeUnits // Size units for parsing and formatting increments
+	precision int             // Decimal places for formatted increments
+	mu        sync.Mutex      // Serializes writers within this process
 }
 
 // NewDiskHistoryStorage creates a new instance of DiskHistoryStorage
 func NewDiskHistoryStorage(path string, logger system.Logger) *DiskHistoryStorage {
 	return &DiskHistoryStorage{
-		path:   path,
-		logger: logger,
+		path:      path,
+		logger:    logger,
+		clock:     system.RealClock{},
+		units:     model.SizeUnitsBinary,
+		precision: model.DefaultSizePrecision,
 	}
 }
 
+// SetSizeUnits sets the size units (binary or decimal) used for increments
+func (s *DiskHistoryStorage) SetSizeUnits(units model.SizeUnits) {
+	s.units = units
+}
+
+// SetSizePrecision sets the number of decimal places used for increments
+func (s *DiskHistoryStorage) SetSizePrecision(precision int) {
+	s.precision = precision
+}
+
+// SetClock sets the time source used for timestamps (e.g. a fixed clock in tests)
+func (s *DiskHistoryStorage) SetClock(clock system.Clock) {
+	s.clock = clock
+}
+
 // SetStoragePath sets the storage file path
 func (s *DiskHistoryStorage) SetStoragePath(path string) error {
 	// Ensure directory exists
@@ -72,13 +102,19 @@ func (s *DiskHistoryStorage) SetStoragePath(path string) error {
 
 // SaveDiskData saves disk data to the storage file
 func (s *DiskHistoryStorage) SaveDiskData(data map[string]map[string]string) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Create backup before saving new data
 	_ = s.CreateBackup() // Ignore backup errors to prioritize saving current data
 
 	// Build data structure
 	historyData := HistoryData{
 		Version:   "1.0",
-		Timestamp: time.Now().Format(time.RFC3339),
+		Timestamp: s.clock.Now().Format(time.RFC3339),
 		Disks:     data,
 		Meta:      map[string]interface{}{"generator": "disk-health-monitor"},
 	}
@@ -89,18 +125,70 @@ func (s *DiskHistoryStorage) SaveDiskData(data map[string]map[string]string) err
 		return fmt.Errorf("failed to serialize data: %w", err)
 	}
 
-	// Use temporary file for safe writing
-	tempFile := s.path + ".tmp"
-	if err := os.WriteFile(tempFile, jsonData, 0644); err != nil {
+	if err := writeFileAtomic(s.path, jsonData); err != nil {
+		return err
+	}
+
+	s.logger.Info("Successfully saved disk data to %s", s.path)
+	return nil
+}
+
+// lock serializes writers of the history files. The mutex covers goroutines
+// sharing this storage (watch mode, the HTTP server); the lock file covers
+// other processes, such as a cron run overlapping a long-running watch
+func (s *DiskHistoryStorage) lock() (func(), error) {
+	s.mu.Lock()
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		s.mu.Unlock()
+	}, nil
+}
+
+// replaceFile atomically replaces the history file while holding the lock
+func (s *DiskHistoryStorage) replaceFile(data []byte) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic writes data to a uniquely named temporary file next to path
+// and renames it into place, so readers never see a partial file and
+// concurrent writers never share a temporary file
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tempFile := temp.Name()
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(tempFile)
 		return fmt.Errorf("failed to write temporary file: %w", err)
 	}
+	if err := temp.Close(); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	// CreateTemp uses 0600; keep the permissions the history files always had
+	if err := os.Chmod(tempFile, 0644); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
 
 	// Atomic rename to ensure data integrity
-	if err := os.Rename(tempFile, s.path); err != nil {
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
 		return fmt.Errorf("failed to rename temporary file: %w", err)
 	}
-
-	s.logger.Info("Successfully saved disk data to %s", s.path)
 	return nil
 }
 
@@ -126,8 +214,10 @@ func (s *DiskHistoryStorage) LoadDiskData() (map[string]map[string]string, strin
 		return s.attemptRecovery(err)
 	}
 
-	// Version compatibility check
-	if historyData.Version != "1.0" {
+	// Version compatibility check. Files written by older releases of the
+	// disk collector carry no version but already use the current layout;
+	// the next save adds the version
+	if historyData.Version != "1.0" && historyData.Version != "" {
 		s.logger.Info("Data format version %s detected, migrating to current version", historyData.Version)
 		historyData = s.migrateDataFormat(historyData)
 
@@ -135,13 +225,10 @@ func (s *DiskHistoryStorage) LoadDiskData() (map[string]map[string]string, strin
 		jsonData, err := json.MarshalIndent(historyData, "", "  ")
 		if err != nil {
 			s.logger.Error("Failed to serialize migrated data: %v", err)
+		} else if err := s.replaceFile(jsonData); err != nil {
+			s.logger.Error("Failed to save migrated data: %v", err)
 		} else {
-			// Write migrated data back to file
-			if err := os.WriteFile(s.path, jsonData, 0644); err != nil {
-				s.logger.Error("Failed to save migrated data: %v", err)
-			} else {
-				s.logger.Info("Successfully migrated data format from %s to 1.0", historyData.Version)
-			}
+			s.logger.Info("Successfully migrated data format from %s to 1.0", historyData.Version)
 		}
 	}
 
@@ -171,7 +258,9 @@ func (s *DiskHistoryStorage) attemptRecovery(originalErr error) (map[string]map[
 
 			// Recovery successful, update main file
 			if data, err := os.ReadFile(backup); err == nil {
-				_ = os.WriteFile(s.path, data, 0644)
+				if err := s.replaceFile(data); err != nil {
+					s.logger.Error("Failed to restore %s from backup: %v", s.path, err)
+				}
 			}
 
 			return data, timestamp, nil
@@ -249,31 +338,78 @@ func (s *DiskHistoryStorage) migrateFrom02To10(oldData HistoryData) HistoryData
 
 // CreateBackup creates a backup of the current data file
 func (s *DiskHistoryStorage) CreateBackup() error {
+	_, err := s.createBackup()
+	return err
+}
+
+// createBackup copies the data file to a timestamped backup and returns its
+// path, or an empty path if there was nothing to back up
+func (s *DiskHistoryStorage) createBackup() (string, error) {
 	// Check if source file exists
 	if _, err := os.Stat(s.path); os.IsNotExist(err) {
 		s.logger.Debug("No file to backup at %s", s.path)
-		return nil // No need to backup
+		return "", nil // No need to backup
 	}
 
 	// Create timestamp for backup filename with microseconds to ensure uniqueness
-	timestamp := time.Now().Format("20060102150405.000000")
+	timestamp := s.clock.Now().Format("20060102150405.000000")
 	backupPath := fmt.Sprintf("%s.%s.bak", s.path, timestamp)
 
 	// Copy file content
 	input, err := os.ReadFile(s.path)
 	if err != nil {
-		return fmt.Errorf("failed to read source file: %w", err)
+		return "", fmt.Errorf("failed to read source file: %w", err)
 	}
 
 	// Write to backup file
 	if err := os.WriteFile(backupPath, input, 0644); err != nil {
-		return fmt.Errorf("failed to write backup file: %w", err)
+		return "", fmt.Errorf("failed to write backup file: %w", err)
 	}
 
 	s.logger.Info("Created backup at %s", backupPath)
 
 	// Rotate old backups (keep last 5)
-	return s.rotateBackups(5)
+	return backupPath, s.rotateBackups(5)
+}
+
+// ResetBaseline backs up the current data file and replaces it with an empty
+// history, so the next run establishes a fresh baseline instead of comparing
+// against drives that have since been replaced. The returned backup path is
+// empty if there was no history to back up.
+func (s *DiskHistoryStorage) ResetBaseline() (string, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	backupPath, err := s.createBackup()
+	if err != nil {
+		return "", fmt.Errorf("failed to back up history before reset: %w", err)
+	}
+
+	// An empty timestamp tells the collector there is no previous run
+	historyData := HistoryData{
+		Version: "1.0",
+		Disks:   make(map[string]map[string]string),
+		Meta: map[string]interface{}{
+			"generator": "disk-health-monitor",
+			"reset_at":  s.clock.Now().Format(time.RFC3339),
+			"backup":    backupPath,
+		},
+	}
+
+	jsonData, err := json.MarshalIndent(historyData, "", "  ")
+	if err != nil {
+		return backupPath, fmt.Errorf("failed to serialize data: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, jsonData); err != nil {
+		return backupPath, err
+	}
+
+	s.logger.Info("Reset history baseline at %s", s.path)
+	return backupPath, nil
 }
 
 // rotateBackups removes old backups, keeping only the most recent n
@@ -379,57 +515,131 @@ func (s *DiskHistoryStorage) parseStorageSizeToBytes(sizeStr string) (float64, e
 		return 0, fmt.Errorf("invalid size string: %s", sizeStr)
 	}
 
-	// 使用正则表达式提取数字和单位
-	re := regexp.MustCompile(`(\d+\.?\d*)\s*([KMGTP]?B)`)
-	matches := re.FindStringSubmatch(sizeStr)
-	if len(matches) != 3 {
-		return 0, fmt.Errorf("unable to parse size: %s", sizeStr)
+	return s.units.ParseSize(sizeStr)
+}
+
+// 格式化字节为可读字符串
+func (s *DiskHistoryStorage) formatBytes(bytes float64) string {
+	return s.units.FormatSizePrecision(bytes, s.precision)
+}
+
+// ControllerHistoryPath returns the controller temperature history file,
+// stored next to the disk data file. The disk collector rewrites the data
+// file on every run, so controller history is kept separately.
+func (s *DiskHistoryStorage) ControllerHistoryPath() string {
+	ext := filepath.Ext(s.path)
+	return strings.TrimSuffix(s.path, ext) + "_controllers" + ext
+}
+
+// EventsPath returns the disk event log, stored next to the disk data file
+// as JSON Lines so events can be appended without rewriting the file
+func (s *DiskHistoryStorage) EventsPath() string {
+	ext := filepath.Ext(s.path)
+	return strings.TrimSuffix(s.path, ext) + "_events.jsonl"
+}
+
+// AppendEvent appends a disk event to the event log
+func (s *DiskHistoryStorage) AppendEvent(event model.DiskEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
 	}
 
-	valueStr := matches[1]
-	unit := matches[2]
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	// 解析数字值
-	value, err := strconv.ParseFloat(valueStr, 64)
+	file, err := os.OpenFile(s.EventsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return 0, fmt.Errorf("invalid numeric value: %s", valueStr)
-	}
-
-	// 转换为字节数
-	var bytes float64
-	switch strings.ToUpper(unit) {
-	case "B":
-		bytes = value
-	case "KB":
-		bytes = value * 1024
-	case "MB":
-		bytes = value * 1024 * 1024
-	case "GB":
-		bytes = value * 1024 * 1024 * 1024
-	case "TB":
-		bytes = value * 1024 * 1024 * 1024 * 1024
-	case "PB":
-		bytes = value * 1024 * 1024 * 1024 * 1024 * 1024
-	default:
-		return 0, fmt.Errorf("unknown unit: %s", unit)
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return file.Close()
+}
+
+// LoadEvents loads the most recent events from the event log, oldest first.
+// A limit of 0 or less returns all events. Lines that cannot be parsed are
+// skipped, so one truncated write does not hide the rest of the timeline
+func (s *DiskHistoryStorage) LoadEvents(limit int) ([]model.DiskEvent, error) {
+	fileData, err := os.ReadFile(s.EventsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	var events []model.DiskEvent
+	for i, line := range strings.Split(string(fileData), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var event model.DiskEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			s.logger.Debug("Skipping unparseable event on line %d: %v", i+1, err)
+			continue
+		}
+		events = append(events, event)
 	}
 
-	return bytes, nil
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
 }
 
-// 格式化字节为可读字符串
-func (s *DiskHistoryStorage) formatBytes(bytes float64) string {
-	const unit = 1024.0
-	sizes := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+// SaveControllerTemperatures saves the current controller temperatures
+func (s *DiskHistoryStorage) SaveControllerTemperatures(temps map[string]string) error {
+	historyData := ControllerHistoryData{
+		Timestamp:    s.clock.Now().Format(time.RFC3339),
+		Temperatures: temps,
+	}
+
+	jsonData, err := json.MarshalIndent(historyData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize controller data: %w", err)
+	}
 
-	if bytes < unit {
-		return fmt.Sprintf("%.2f B", bytes)
+	unlock, err := s.lock()
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	exp := 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		exp++
+	path := s.ControllerHistoryPath()
+	if err := writeFileAtomic(path, jsonData); err != nil {
+		return err
+	}
+
+	s.logger.Info("Successfully saved controller temperatures to %s", path)
+	return nil
+}
+
+// LoadControllerTemperatures loads the controller temperatures from the last run
+func (s *DiskHistoryStorage) LoadControllerTemperatures() (map[string]string, error) {
+	path := s.ControllerHistoryPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		s.logger.Info("Controller history file %s does not exist, returning empty data", path)
+		return make(map[string]string), nil
+	}
+
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read controller history file: %w", err)
+	}
+
+	var historyData ControllerHistoryData
+	if err := json.Unmarshal(fileData, &historyData); err != nil {
+		return nil, fmt.Errorf("failed to parse controller history file: %w", err)
+	}
+	if historyData.Temperatures == nil {
+		historyData.Temperatures = make(map[string]string)
 	}
 
-	return fmt.Sprintf("%.2f %s", bytes/math.Pow(unit, float64(exp)), sizes[exp])
+	return historyData.Temperatures, nil
 }
@@ -7,8 +7,12 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+	"github.com/MaurUppi/disk-health-monitor/internal/system"
 )
 
 // MockLogger is a simple logger implementation for testing
@@ -158,6 +162,30 @@ func TestSaveLoadDiskData(t *testing.T) {
 	}
 }
 
+// TestSaveDiskDataFixedClock tests that saved timestamps come from the injected clock
+func TestSaveDiskDataFixedClock(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "history-clock-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	storage := NewDiskHistoryStorage(filepath.Join(tempDir, "test-data.json"), NewMockLogger())
+	storage.SetClock(system.FixedClock{Time: time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)})
+
+	if err := storage.SaveDiskData(map[string]map[string]string{"disk1": {"Data_Read": "1 TB"}}); err != nil {
+		t.Fatalf("SaveDiskData failed: %v", err)
+	}
+
+	_, timestamp, err := storage.LoadDiskData()
+	if err != nil {
+		t.Fatalf("LoadDiskData failed: %v", err)
+	}
+	if timestamp != "2025-03-10T08:00:00Z" {
+		t.Errorf("Expected timestamp 2025-03-10T08:00:00Z, got %s", timestamp)
+	}
+}
+
 // TestLoadNonExistentFile tests loading data when the file doesn't exist
 func TestLoadNonExistentFile(t *testing.T) {
 	logger := NewMockLogger()
@@ -234,6 +262,85 @@ func TestCreateAndRotateBackups(t *testing.T) {
 	}
 }
 
+// TestResetBaseline tests that resetting backs up and then empties the history
+func TestResetBaseline(t *testing.T) {
+	logger := NewMockLogger()
+
+	tempDir, err := os.MkdirTemp("", "history-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	filePath := filepath.Join(tempDir, "test-data.json")
+	storage := NewDiskHistoryStorage(filePath, logger)
+
+	testData := map[string]map[string]string{
+		"disk1": {
+			"Data_Read": "1 TB",
+		},
+	}
+	if err := storage.SaveDiskData(testData); err != nil {
+		t.Fatalf("SaveDiskData failed: %v", err)
+	}
+
+	backupPath, err := storage.ResetBaseline()
+	if err != nil {
+		t.Fatalf("ResetBaseline failed: %v", err)
+	}
+
+	// The backup must hold the pre-reset data
+	if backupPath == "" {
+		t.Fatal("Expected a backup path, got empty string")
+	}
+	backupBytes, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	var backup HistoryData
+	if err := json.Unmarshal(backupBytes, &backup); err != nil {
+		t.Fatalf("Backup is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(backup.Disks, testData) {
+		t.Errorf("Backup data = %v, want %v", backup.Disks, testData)
+	}
+
+	// The data file must be emptied
+	data, timestamp, err := storage.LoadDiskData()
+	if err != nil {
+		t.Fatalf("LoadDiskData failed: %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("Expected empty history after reset, got %v", data)
+	}
+	if timestamp != "" {
+		t.Errorf("Expected empty timestamp after reset, got %q", timestamp)
+	}
+}
+
+// TestResetBaselineNoHistory tests resetting when no data file exists yet
+func TestResetBaselineNoHistory(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "history-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	filePath := filepath.Join(tempDir, "test-data.json")
+	storage := NewDiskHistoryStorage(filePath, NewMockLogger())
+
+	backupPath, err := storage.ResetBaseline()
+	if err != nil {
+		t.Fatalf("ResetBaseline failed: %v", err)
+	}
+	if backupPath != "" {
+		t.Errorf("Expected no backup without history, got %s", backupPath)
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		t.Errorf("Expected empty data file to be written: %v", err)
+	}
+}
+
 // TestVerifyIntegrity tests the file integrity verification
 func TestVerifyIntegrity(t *testing.T) {
 	logger := NewMockLogger()
@@ -307,8 +414,8 @@ func TestCalculateIncrements(t *testing.T) {
 				"Data_Written": "700 GB",
 			},
 			expected: map[string]string{
-				"Data_Read_Increment":    "512.00 GB", // 0.5 TB = 512 GB
-				"Data_Written_Increment": "200.00 GB",
+				"Data_Read_Increment":    "512.00 GiB", // 0.5 TB = 512 GiB in binary units
+				"Data_Written_Increment": "200.00 GiB",
 			},
 		},
 		{
@@ -337,7 +444,7 @@ func TestCalculateIncrements(t *testing.T) {
 				"Data_Written": "700 GB",
 			},
 			expected: map[string]string{
-				"Data_Read_Increment":    "512.00 GB",
+				"Data_Read_Increment":    "512.00 GiB",
 				"Data_Written_Increment": "N/A", // Old data missing
 			},
 		},
@@ -384,6 +491,33 @@ func TestCalculateIncrements(t *testing.T) {
 	}
 }
 
+// TestCalculateIncrementsPrecision tests increments with a configured precision
+func TestCalculateIncrementsPrecision(t *testing.T) {
+	storage := NewDiskHistoryStorage("", NewMockLogger())
+
+	// Old values may have been stored with a different precision
+	oldData := map[string]string{"Data_Read": "1 TiB", "Data_Written": "500.125 GiB"}
+	newData := map[string]string{"Data_Read": "1.50 TiB", "Data_Written": "700.5 GiB"}
+
+	tests := []struct {
+		precision int
+		read      string
+		written   string
+	}{
+		{0, "512 GiB", "200 GiB"},
+		{3, "512.000 GiB", "200.375 GiB"},
+	}
+
+	for _, tt := range tests {
+		storage.SetSizePrecision(tt.precision)
+		result := storage.CalculateIncrements(oldData, newData)
+		if result["Data_Read_Increment"] != tt.read || result["Data_Written_Increment"] != tt.written {
+			t.Errorf("Precision %d: expected %s/%s, got %s/%s", tt.precision, tt.read, tt.written,
+				result["Data_Read_Increment"], result["Data_Written_Increment"])
+		}
+	}
+}
+
 // TestParseStorageSizeToBytes tests the storage size parsing
 func TestParseStorageSizeToBytes(t *testing.T) {
 	logger := NewMockLogger()
@@ -562,3 +696,201 @@ func TestDataVersionMigration(t *testing.T) {
 		t.Errorf("File not upgraded to new version, got %s", updatedData.Version)
 	}
 }
+
+// TestControllerTemperatureHistory tests saving and loading controller temperatures
+func TestControllerTemperatureHistory(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test-data.json")
+	storage := NewDiskHistoryStorage(filePath, NewMockLogger())
+
+	if got := storage.ControllerHistoryPath(); got != strings.TrimSuffix(filePath, ".json")+"_controllers.json" {
+		t.Errorf("Unexpected controller history path: %s", got)
+	}
+
+	// No history yet
+	temps, err := storage.LoadControllerTemperatures()
+	if err != nil {
+		t.Fatalf("LoadControllerTemperatures failed: %v", err)
+	}
+	if len(temps) != 0 {
+		t.Errorf("Expected no temperatures, got %v", temps)
+	}
+
+	want := map[string]string{"LSI_Controller_0": "75", "NVMe_Controller_01:00.0": "45"}
+	if err := storage.SaveControllerTemperatures(want); err != nil {
+		t.Fatalf("SaveControllerTemperatures failed: %v", err)
+	}
+
+	temps, err = storage.LoadControllerTemperatures()
+	if err != nil {
+		t.Fatalf("LoadControllerTemperatures failed: %v", err)
+	}
+	if !reflect.DeepEqual(temps, want) {
+		t.Errorf("Loaded temperatures = %v, want %v", temps, want)
+	}
+}
+
+// TestDiskEventLog tests that a detected status change is logged and can be read back later
+func TestDiskEventLog(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test-data.json")
+	storage := NewDiskHistoryStorage(filePath, NewMockLogger())
+
+	if got := storage.EventsPath(); got != strings.TrimSuffix(filePath, ".json")+"_events.jsonl" {
+		t.Errorf("Unexpected event log path: %s", got)
+	}
+
+	// No event log yet
+	events, err := storage.LoadEvents(10)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("Expected no events, got %v (err %v)", events, err)
+	}
+
+	// sda was OK on the previous run and is now WARNING
+	diskData := model.NewDiskData()
+	disk := model.NewDisk("sda", "HDD", "ST4000NM", "4T")
+	disk.Serial = "ZC1ABCDE"
+	disk.Status = model.DiskStatusWarning
+	diskData.AddDisk(disk)
+	diskData.SetPreviousData(map[string]map[string]string{
+		"sda": {"Status": string(model.DiskStatusOK), "Serial": "ZC1ABCDE"},
+	}, "2025-03-09 08:00:00")
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	detected := diskData.DetectEvents(now)
+	if len(detected) != 1 {
+		t.Fatalf("Expected one event, got %v", detected)
+	}
+	for _, event := range detected {
+		if err := storage.AppendEvent(event); err != nil {
+			t.Fatalf("AppendEvent failed: %v", err)
+		}
+	}
+
+	// A later run reads the event back from a fresh storage instance
+	events, err = NewDiskHistoryStorage(filePath, NewMockLogger()).LoadEvents(10)
+	if err != nil {
+		t.Fatalf("LoadEvents failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected one logged event, got %v", events)
+	}
+	event := events[0]
+	if event.Type != model.EventStatusChanged || event.Disk != "sda" || event.Serial != "ZC1ABCDE" ||
+		event.From != string(model.DiskStatusOK) || event.To != string(model.DiskStatusWarning) || !event.Time.Equal(now) {
+		t.Errorf("Unexpected logged event: %+v", event)
+	}
+
+	// The limit keeps only the most recent events
+	for _, name := range []string{"sdb", "sdc"} {
+		if err := storage.AppendEvent(model.DiskEvent{Time: now, Type: model.EventDiskAdded, Disk: name}); err != nil {
+			t.Fatalf("AppendEvent failed: %v", err)
+		}
+	}
+	events, err = storage.LoadEvents(2)
+	if err != nil {
+		t.Fatalf("LoadEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Disk != "sdb" || events[1].Disk != "sdc" {
+		t.Errorf("Expected the two most recent events, got %v", events)
+	}
+}
+
+// TestConcurrentSaves tests that overlapping saves serialize and leave a valid file
+func TestConcurrentSaves(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test-data.json")
+
+	// Two storages on the same file stand in for separate processes
+	storages := []*DiskHistoryStorage{
+		NewDiskHistoryStorage(filePath, NewMockLogger()),
+		NewDiskHistoryStorage(filePath, NewMockLogger()),
+	}
+
+	const saves = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, saves)
+	for i := 0; i < saves; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := map[string]map[string]string{
+				"sda": {"Data_Read": strings.Repeat("1", i+1), "Writer": string(rune('a' + i))},
+			}
+			errs <- storages[i%len(storages)].SaveDiskData(data)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("SaveDiskData failed: %v", err)
+		}
+	}
+
+	// The final file is complete and holds exactly one writer's data
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	var historyData HistoryData
+	if err := json.Unmarshal(fileData, &historyData); err != nil {
+		t.Fatalf("Data file is corrupted: %v\n%s", err, fileData)
+	}
+	disk := historyData.Disks["sda"]
+	if len(disk["Writer"]) != 1 || len(disk["Data_Read"]) != int(disk["Writer"][0]-'a')+1 {
+		t.Errorf("Data file mixes writes from different saves: %v", disk)
+	}
+
+	// No temporary files are left behind
+	leftovers, err := filepath.Glob(filePath + ".*.tmp")
+	if err != nil {
+		t.Fatalf("Failed to list temporary files: %v", err)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("Expected no temporary files, found %v", leftovers)
+	}
+}
+
+// TestWriteFileAtomicWithoutLock tests that unlocked writers never share a temporary file
+func TestWriteFileAtomicWithoutLock(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test-data.json")
+	contents := [][]byte{
+		[]byte(`{"writer": "first", "padding": "` + strings.Repeat("a", 1<<16) + `"}`),
+		[]byte(`{"writer": "second"}`),
+	}
+
+	for round := 0; round < 10; round++ {
+		var wg sync.WaitGroup
+		errs := make([]error, len(contents))
+		for i, content := range contents {
+			wg.Add(1)
+			go func(i int, content []byte) {
+				defer wg.Done()
+				errs[i] = writeFileAtomic(filePath, content)
+			}(i, content)
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("Round %d: write %d failed: %v", round, i, err)
+			}
+		}
+
+		// Whichever rename came last wins, but the file is always one complete write
+		fileData, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if string(fileData) != string(contents[0]) && string(fileData) != string(contents[1]) {
+			t.Fatalf("Round %d: file is not one complete write (%d bytes)", round, len(fileData))
+		}
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected file mode 0644, got %v", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package storage
+
+// lockFile is a no-op where flock is unavailable; writers in the same
+// process are still serialized by the storage mutex
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
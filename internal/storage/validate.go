@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MaurUppi/disk-health-monitor/internal/model"
+)
+
+// Validation issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// supportedVersions lists the history format versions LoadDiskData can read
+var supportedVersions = map[string]bool{"1.0": true, "0.1": true, "0.2": true}
+
+// historyTimestampLayouts lists the timestamp formats written by this tool
+var historyTimestampLayouts = []string{time.RFC3339, "2006-01-02 15:04:05"}
+
+// sizeFields hold cumulative byte counts, stored as human-readable sizes
+var sizeFields = []string{"Data_Read", "Data_Written"}
+
+// countFields hold cumulative counters and byte counts, stored as integers
+var countFields = []string{"Corrected_Errors", "Data_Read" + model.SizeBytesSuffix, "Data_Written" + model.SizeBytesSuffix}
+
+// hoursFields hold power-on times, which SAS disks report with fractional
+// hours ("36491.38") and some firmware with a unit ("20,662 h")
+var hoursFields = []string{"Power_On_Hours"}
+
+// ValidationIssue describes one problem found in a history file
+type ValidationIssue struct {
+	Severity string // SeverityError or SeverityWarning
+	Field    string // Location of the problem, e.g. "disks.sda.Data_Read"
+	Message  string // Human-readable description
+}
+
+// ValidationReport collects the issues found while validating a history file
+type ValidationReport struct {
+	Path   string
+	Issues []ValidationIssue
+}
+
+// addIssue records an issue in the report
+func (r *ValidationReport) addIssue(severity, field, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity: severity,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Valid reports whether the file has no errors; warnings are allowed
+func (r *ValidationReport) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the report for display on the console
+func (r *ValidationReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "History file: %s\n", r.Path)
+	for _, issue := range r.Issues {
+		if issue.Field != "" {
+			fmt.Fprintf(&b, "  [%s] %s: %s\n", issue.Severity, issue.Field, issue.Message)
+		} else {
+			fmt.Fprintf(&b, "  [%s] %s\n", issue.Severity, issue.Message)
+		}
+	}
+	if r.Valid() {
+		fmt.Fprintf(&b, "Result: OK (%d warnings)\n", len(r.Issues))
+	} else {
+		fmt.Fprintf(&b, "Result: INVALID (%d issues)\n", len(r.Issues))
+	}
+	return b.String()
+}
+
+// Validate checks the history file structurally (via VerifyIntegrity) and
+// semantically, returning every problem found rather than stopping at the first
+func (s *DiskHistoryStorage) Validate() *ValidationReport {
+	report := &ValidationReport{Path: s.path}
+
+	if _, err := os.Stat(s.path); err != nil {
+		report.addIssue(SeverityError, "", "cannot access file: %v", err)
+		return report
+	}
+
+	if ok, err := s.VerifyIntegrity(); !ok {
+		report.addIssue(SeverityError, "", "invalid JSON: %v", err)
+		return report
+	}
+
+	fileData, err := os.ReadFile(s.path)
+	if err != nil {
+		report.addIssue(SeverityError, "", "cannot read file: %v", err)
+		return report
+	}
+	var historyData HistoryData
+	if err := json.Unmarshal(fileData, &historyData); err != nil {
+		report.addIssue(SeverityError, "", "invalid JSON: %v", err)
+		return report
+	}
+
+	s.validateHeader(report, historyData)
+	s.validateDisks(report, historyData.Disks)
+	return report
+}
+
+// validateHeader checks the version and timestamp fields
+func (s *DiskHistoryStorage) validateHeader(report *ValidationReport, historyData HistoryData) {
+	// Files written by older releases carry no version and load as-is
+	if historyData.Version != "" && !supportedVersions[historyData.Version] {
+		report.addIssue(SeverityError, "version", "unsupported version %q", historyData.Version)
+	}
+
+	// An empty timestamp marks a reset baseline, which must not hold disk data
+	if historyData.Timestamp == "" {
+		if len(historyData.Disks) > 0 {
+			report.addIssue(SeverityError, "timestamp", "missing timestamp for %d disks", len(historyData.Disks))
+		}
+		return
+	}
+
+	parsed := false
+	for _, layout := range historyTimestampLayouts {
+		if _, err := time.Parse(layout, historyData.Timestamp); err == nil {
+			parsed = true
+			break
+		}
+	}
+	if !parsed {
+		report.addIssue(SeverityError, "timestamp", "unparseable timestamp %q", historyData.Timestamp)
+	}
+}
+
+// validateDisks checks that every stored counter is well-formed and non-negative
+func (s *DiskHistoryStorage) validateDisks(report *ValidationReport, disks map[string]map[string]string) {
+	if disks == nil {
+		report.addIssue(SeverityError, "disks", "missing disks section")
+		return
+	}
+
+	names := make([]string, 0, len(disks))
+	for name := range disks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attrs := disks[name]
+		if attrs == nil {
+			report.addIssue(SeverityError, "disks."+name, "disk entry is empty")
+			continue
+		}
+
+		for _, field := range sizeFields {
+			value := attrs[field]
+			if value == "" || value == "N/A" {
+				continue
+			}
+			location := fmt.Sprintf("disks.%s.%s", name, field)
+			if strings.HasPrefix(strings.TrimSpace(value), "-") {
+				report.addIssue(SeverityError, location, "negative size %q", value)
+			} else if _, err := s.parseStorageSizeToBytes(value); err != nil {
+				report.addIssue(SeverityError, location, "unparseable size %q", value)
+			}
+		}
+
+		for _, field := range countFields {
+			value := attrs[field]
+			if value == "" || value == "N/A" {
+				continue
+			}
+			location := fmt.Sprintf("disks.%s.%s", name, field)
+			count, err := strconv.ParseInt(strings.ReplaceAll(value, ",", ""), 10, 64)
+			if err != nil {
+				report.addIssue(SeverityError, location, "unparseable count %q", value)
+			} else if count < 0 {
+				report.addIssue(SeverityError, location, "negative count %d", count)
+			}
+		}
+
+		for _, field := range hoursFields {
+			value := attrs[field]
+			if value == "" || value == "N/A" {
+				continue
+			}
+			location := fmt.Sprintf("disks.%s.%s", name, field)
+			hours, err := strconv.ParseFloat(model.NormalizeNumber(value), 64)
+			if err != nil {
+				report.addIssue(SeverityError, location, "unparseable hours %q", value)
+			} else if hours < 0 {
+				report.addIssue(SeverityError, location, "negative hours %q", value)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateValidFile tests that files written by the tool validate cleanly
+func TestValidateValidFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test-data.json")
+	storage := NewDiskHistoryStorage(filePath, NewMockLogger())
+
+	testData := map[string]map[string]string{
+		"sda": {"Data_Read": "1.5 TB", "Data_Written": "800 GB", "Corrected_Errors": "12", "Power_On_Hours": "10024"},
+		"sdb": {"Data_Read": "N/A", "Power_On_Hours": ""},
+	}
+	if err := storage.SaveDiskData(testData); err != nil {
+		t.Fatalf("SaveDiskData failed: %v", err)
+	}
+
+	report := storage.Validate()
+	if !report.Valid() || len(report.Issues) != 0 {
+		t.Errorf("Expected a clean report, got:\n%s", report)
+	}
+
+	// A reset baseline has no timestamp and no disks, which is valid
+	if _, err := storage.ResetBaseline(); err != nil {
+		t.Fatalf("ResetBaseline failed: %v", err)
+	}
+	if report := storage.Validate(); !report.Valid() {
+		t.Errorf("Expected reset baseline to be valid, got:\n%s", report)
+	}
+}
+
+// TestValidateCollectorFormat tests a history file in the format written by
+// earlier releases of the disk collector: no version, a local timestamp and
+// fractional SAS power-on hours
+func TestValidateCollectorFormat(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "disk_data.json")
+	content := `{
+  "timestamp": "2025-03-10 12:34:56",
+  "disks": {
+    "sdd": {"Data_Read": "280210.01 GB", "Data_Written": "183549.24 GB", "Corrected_Errors": "5126437757", "Power_On_Hours": "36491.38", "Pool": "tank", "Serial": "ZA1", "Status": "PASSED"},
+    "nvme0n1": {"Data_Read": "5.61 TB", "Power_On_Hours": "20,662 h", "Wear_Baseline": "3@12000"}
+  }
+}`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	report := NewDiskHistoryStorage(filePath, NewMockLogger()).Validate()
+	if !report.Valid() || len(report.Issues) != 0 {
+		t.Errorf("Expected a clean report, got:\n%s", report)
+	}
+}
+
+// TestValidateCorruptedFiles tests the issues reported for corrupted history files
+func TestValidateCorruptedFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+		want    []string // Substrings expected in the report
+	}{
+		{
+			name:    "bad JSON",
+			content: `{"version": "1.0", "disks": {`,
+			want:    []string{"[error] invalid JSON"},
+		},
+		{
+			name:    "unsupported version",
+			content: `{"version": "9.9", "timestamp": "2024-09-15T10:21:03Z", "disks": {}}`,
+			want:    []string{`[error] version: unsupported version "9.9"`},
+		},
+		{
+			name:    "bad timestamp",
+			content: `{"version": "1.0", "timestamp": "yesterday", "disks": {}}`,
+			want:    []string{`[error] timestamp: unparseable timestamp "yesterday"`},
+		},
+		{
+			name:    "missing disks",
+			content: `{"version": "1.0", "timestamp": "2024-09-15T10:21:03Z"}`,
+			want:    []string{"[error] disks: missing disks section"},
+		},
+		{
+			name: "negative and unparseable counts",
+			content: `{"version": "1.0", "timestamp": "2024-09-15T10:21:03Z", "disks": {
+				"sda": {"Data_Read": "-2 TB", "Power_On_Hours": "-5"},
+				"sdb": {"Data_Written": "lots", "Corrected_Errors": "many"}
+			}}`,
+			want: []string{
+				`[error] disks.sda.Data_Read: negative size "-2 TB"`,
+				`[error] disks.sda.Power_On_Hours: negative hours "-5"`,
+				`[error] disks.sdb.Data_Written: unparseable size "lots"`,
+				`[error] disks.sdb.Corrected_Errors: unparseable count "many"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "test-data.json")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			report := NewDiskHistoryStorage(filePath, NewMockLogger()).Validate()
+			if report.Valid() != tt.valid {
+				t.Errorf("Valid() = %v, want %v", report.Valid(), tt.valid)
+			}
+			text := report.String()
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected report to contain %q, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+
+	// A missing file cannot be validated
+	report := NewDiskHistoryStorage(filepath.Join(t.TempDir(), "missing.json"), NewMockLogger()).Validate()
+	if report.Valid() {
+		t.Errorf("Expected a missing file to be invalid")
+	}
+}
@@ -0,0 +1,27 @@
+package system
+
+import "time"
+
+// Clock 定义时间来源接口，便于测试时固定时间
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+}
+
+// RealClock 使用系统时间的默认时钟
+type RealClock struct{}
+
+// Now 返回系统当前时间
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock 始终返回固定时间的时钟，用于测试
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now 返回固定的时间
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
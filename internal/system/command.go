@@ -2,6 +2,7 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -12,6 +13,7 @@ import (
 // CommandRunner 定义命令执行接口
 type CommandRunner interface {
 	// Run 执行命令并返回输出，如果命令失败则返回错误
+	// 命令以非零退出码结束时返回*ExitError，其中包含退出码和输出
 	Run(ctx context.Context, command string) (string, error)
 	
 	// RunIgnoreError 执行命令并返回输出，忽略命令执行错误
@@ -19,6 +21,41 @@ type CommandRunner interface {
 	
 	// RunWithTimeout 执行命令并设置超时，返回输出，如果命令失败或超时则返回错误
 	RunWithTimeout(command string, timeout time.Duration) (string, error)
+
+	// RunWithStatus 执行命令并返回输出和退出码，非零退出码不视为错误
+	// 只有命令无法执行或超时时才返回错误
+	RunWithStatus(ctx context.Context, command string) (string, int, error)
+}
+
+// ExitError 命令以非零退出码结束时返回的错误，保留退出码和输出
+// 部分工具(如smartctl)的退出码是状态位掩码，非零时输出仍然有效
+type ExitError struct {
+	Command string // 执行的命令
+	Code    int    // 退出码
+	Output  string // 命令输出(已移除首尾空格)
+}
+
+// Error 实现error接口
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command execution failed [%s]: exit status %d, output: %s", e.Command, e.Code, e.Output)
+}
+
+// GetExitError 从错误中提取ExitError，命令未能执行或超时时返回false
+func GetExitError(err error) (*ExitError, bool) {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr, true
+	}
+	return nil, false
+}
+
+// SplitExitStatus 将Run的结果转换为RunWithStatus的结果
+// ExitError中的退出码和输出被取出，其他错误原样返回
+func SplitExitStatus(output string, err error) (string, int, error) {
+	if exitErr, ok := GetExitError(err); ok {
+		return exitErr.Output, exitErr.Code, nil
+	}
+	return output, 0, err
 }
 
 // DefaultCommandRunner 实现CommandRunner接口的默认执行器
@@ -37,6 +74,10 @@ func (r DefaultCommandRunner) Run(ctx context.Context, command string) (string,
 	
 	// 执行命令并获取输出
 	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return "", &ExitError{Command: command, Code: exitErr.ExitCode(), Output: strings.TrimSpace(string(output))}
+	}
 	if err != nil {
 		return "", fmt.Errorf("command execution failed [%s]: %w, output: %s", 
 			command, err, string(output))
@@ -61,6 +102,11 @@ func (r DefaultCommandRunner) RunWithTimeout(command string, timeout time.Durati
 	return r.Run(ctx, command)
 }
 
+// RunWithStatus 执行命令并返回输出和退出码
+func (r DefaultCommandRunner) RunWithStatus(ctx context.Context, command string) (string, int, error) {
+	return SplitExitStatus(r.Run(ctx, command))
+}
+
 // MockCommandRunner 用于测试的模拟命令执行器
 type MockCommandRunner struct {
 	MockOutputs map[string]string // 命令到输出的映射
@@ -109,6 +155,11 @@ func (m *MockCommandRunner) RunWithTimeout(command string, timeout time.Duration
 	return m.Run(ctx, command)
 }
 
+// RunWithStatus 返回预定义的模拟输出和退出码
+func (m *MockCommandRunner) RunWithStatus(ctx context.Context, command string) (string, int, error) {
+	return SplitExitStatus(m.Run(ctx, command))
+}
+
 // SetMockOutput 设置命令的模拟输出
 func (m *MockCommandRunner) SetMockOutput(command, output string) {
 	m.MockOutputs[command] = output
@@ -118,3 +169,8 @@ func (m *MockCommandRunner) SetMockOutput(command, output string) {
 func (m *MockCommandRunner) SetMockError(command string, err error) {
 	m.MockErrors[command] = err
 }
+
+// SetMockExitStatus 设置命令以非零退出码结束时的模拟输出
+func (m *MockCommandRunner) SetMockExitStatus(command string, code int, output string) {
+	m.MockErrors[command] = &ExitError{Command: command, Code: code, Output: output}
+}
@@ -145,3 +145,61 @@ func TestMockCommandRunner(t *testing.T) {
 		t.Errorf("Expected first called command to be 'ls -la', got '%s'", mock.CalledCommands[0])
 	}
 }
+
+func TestDefaultCommandRunner_ExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("需要bash")
+	}
+
+	// 非零退出码时返回ExitError，保留退出码和输出
+	_, err := DefaultCommandRunner{}.Run(context.Background(), "echo 'partial data'; exit 68")
+	exitErr, ok := GetExitError(err)
+	if !ok {
+		t.Fatalf("Expected an ExitError, got %v", err)
+	}
+	if exitErr.Code != 68 || exitErr.Output != "partial data" {
+		t.Errorf("Expected exit status 68 with output 'partial data', got %d '%s'", exitErr.Code, exitErr.Output)
+	}
+
+	// 其他错误不是ExitError
+	if _, ok := GetExitError(errors.New("timeout")); ok {
+		t.Error("Expected a plain error not to be an ExitError")
+	}
+}
+
+func TestDefaultCommandRunner_RunWithStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("需要bash")
+	}
+	runner := DefaultCommandRunner{}
+	ctx := context.Background()
+
+	// 非零退出码和输出一起返回，不视为错误
+	output, code, err := runner.RunWithStatus(ctx, "echo 'partial data'; exit 64")
+	if err != nil {
+		t.Fatalf("Expected no error for a non-zero exit status, got %v", err)
+	}
+	if code != 64 || output != "partial data" {
+		t.Errorf("Expected exit status 64 with output 'partial data', got %d '%s'", code, output)
+	}
+
+	output, code, err = runner.RunWithStatus(ctx, "echo ok")
+	if err != nil || code != 0 || output != "ok" {
+		t.Errorf("Expected 'ok' with exit status 0, got '%s' %d %v", output, code, err)
+	}
+
+	// 超时仍然返回错误
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := runner.RunWithStatus(timeoutCtx, "sleep 2"); err == nil {
+		t.Error("Expected an error for a timed out command")
+	}
+
+	// 模拟执行器同样返回退出码
+	mock := NewMockCommandRunner()
+	mock.SetMockExitStatus("smartctl -a /dev/sda", 4, "Temperature: 35 Celsius")
+	output, code, err = mock.RunWithStatus(ctx, "smartctl -a /dev/sda")
+	if err != nil || code != 4 || output != "Temperature: 35 Celsius" {
+		t.Errorf("Expected mocked exit status 4 with output, got '%s' %d %v", output, code, err)
+	}
+}
@@ -0,0 +1,67 @@
+package system
+
+// DefaultSyslogTag 默认的syslog标签
+const DefaultSyslogTag = "disk-health-monitor"
+
+// SyslogWriter 定义按优先级写入系统日志的接口，*syslog.Writer 满足该接口
+type SyslogWriter interface {
+	// Info 以LOG_INFO优先级写入
+	Info(message string) error
+	// Warning 以LOG_WARNING优先级写入
+	Warning(message string) error
+	// Err 以LOG_ERR优先级写入
+	Err(message string) error
+	// Close 关闭与syslog的连接
+	Close() error
+}
+
+// SyslogPriority 模拟syslog记录的优先级名称
+type SyslogPriority string
+
+// 模拟syslog记录的优先级
+const (
+	SyslogPriorityInfo    SyslogPriority = "info"
+	SyslogPriorityWarning SyslogPriority = "warning"
+	SyslogPriorityErr     SyslogPriority = "err"
+)
+
+// SyslogMessage 模拟syslog记录的一条消息
+type SyslogMessage struct {
+	Priority SyslogPriority
+	Message  string
+}
+
+// MockSyslogWriter 模拟的syslog，记录写入的消息及其优先级，用于测试
+type MockSyslogWriter struct {
+	Messages []SyslogMessage
+	Closed   bool
+}
+
+// NewMockSyslogWriter 创建模拟的syslog
+func NewMockSyslogWriter() *MockSyslogWriter {
+	return &MockSyslogWriter{}
+}
+
+// Info 记录LOG_INFO消息
+func (m *MockSyslogWriter) Info(message string) error {
+	m.Messages = append(m.Messages, SyslogMessage{Priority: SyslogPriorityInfo, Message: message})
+	return nil
+}
+
+// Warning 记录LOG_WARNING消息
+func (m *MockSyslogWriter) Warning(message string) error {
+	m.Messages = append(m.Messages, SyslogMessage{Priority: SyslogPriorityWarning, Message: message})
+	return nil
+}
+
+// Err 记录LOG_ERR消息
+func (m *MockSyslogWriter) Err(message string) error {
+	m.Messages = append(m.Messages, SyslogMessage{Priority: SyslogPriorityErr, Message: message})
+	return nil
+}
+
+// Close 标记为已关闭
+func (m *MockSyslogWriter) Close() error {
+	m.Closed = true
+	return nil
+}
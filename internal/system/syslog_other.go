@@ -0,0 +1,10 @@
+//go:build !unix
+
+package system
+
+import "errors"
+
+// NewSyslogWriter 在没有log/syslog的平台上总是返回错误
+func NewSyslogWriter(tag string) (SyslogWriter, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
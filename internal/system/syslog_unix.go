@@ -0,0 +1,10 @@
+//go:build unix
+
+package system
+
+import "log/syslog"
+
+// NewSyslogWriter 连接本机的syslog(journald也会接收)，消息使用LOG_DAEMON设施和指定的标签
+func NewSyslogWriter(tag string) (SyslogWriter, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+}
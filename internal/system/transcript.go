@@ -0,0 +1,201 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TranscriptVersion 命令记录文件的格式版本
+const TranscriptVersion = 1
+
+// TranscriptEntry 一条命令及其输出
+type TranscriptEntry struct {
+	Command  string `json:"command"`
+	Output   string `json:"output"`
+	Error    string `json:"error,omitempty"`     // 命令失败时的错误信息
+	ExitCode int    `json:"exit_code,omitempty"` // 命令的非零退出码，此时Output为命令输出
+}
+
+// Transcript 命令记录文件，用于复现现场问题
+type Transcript struct {
+	Version    int               `json:"version"`
+	RecordedAt time.Time         `json:"recorded_at"`
+	Entries    []TranscriptEntry `json:"entries"`
+}
+
+// RecordingCommandRunner 包装另一个执行器，记录每条命令及其输出
+type RecordingCommandRunner struct {
+	runner  CommandRunner
+	started time.Time
+	mu      sync.Mutex
+	entries []TranscriptEntry
+}
+
+// NewRecordingCommandRunner 创建记录命令的执行器，以创建时间作为记录时间
+func NewRecordingCommandRunner(runner CommandRunner) *RecordingCommandRunner {
+	return &RecordingCommandRunner{runner: runner, started: time.Now()}
+}
+
+// RecordedAt 获取记录时间
+func (r *RecordingCommandRunner) RecordedAt() time.Time {
+	return r.started
+}
+
+// Run 执行命令并记录输出和错误
+func (r *RecordingCommandRunner) Run(ctx context.Context, command string) (string, error) {
+	output, err := r.runner.Run(ctx, command)
+
+	entry := TranscriptEntry{Command: command, Output: output}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if exitErr, ok := GetExitError(err); ok {
+		entry.ExitCode = exitErr.Code
+		entry.Output = exitErr.Output
+	}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+
+	return output, err
+}
+
+// RunIgnoreError 执行命令并忽略错误，命令仍会被记录
+func (r *RecordingCommandRunner) RunIgnoreError(ctx context.Context, command string) string {
+	output, _ := r.Run(ctx, command)
+	return output
+}
+
+// RunWithTimeout 使用指定的超时时间执行命令
+func (r *RecordingCommandRunner) RunWithTimeout(command string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return r.Run(ctx, command)
+}
+
+// RunWithStatus 执行命令并返回输出和退出码，命令仍会被记录
+func (r *RecordingCommandRunner) RunWithStatus(ctx context.Context, command string) (string, int, error) {
+	return SplitExitStatus(r.Run(ctx, command))
+}
+
+// Entries 获取已记录的命令
+func (r *RecordingCommandRunner) Entries() []TranscriptEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]TranscriptEntry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Save 将已记录的命令写入JSON文件
+func (r *RecordingCommandRunner) Save(path string) error {
+	transcript := Transcript{
+		Version:    TranscriptVersion,
+		RecordedAt: r.started,
+		Entries:    r.Entries(),
+	}
+
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
+
+// ReplayCommandRunner 按命令记录文件返回输出，不执行任何命令
+// 同一命令被记录多次时按顺序返回，用完后重复最后一次的结果
+type ReplayCommandRunner struct {
+	recordedAt time.Time
+	mu         sync.Mutex
+	entries    map[string][]TranscriptEntry
+	position   map[string]int
+}
+
+// NewReplayCommandRunner 根据已记录的命令创建回放执行器
+func NewReplayCommandRunner(entries []TranscriptEntry) *ReplayCommandRunner {
+	r := &ReplayCommandRunner{
+		entries:  make(map[string][]TranscriptEntry),
+		position: make(map[string]int),
+	}
+	for _, entry := range entries {
+		r.entries[entry.Command] = append(r.entries[entry.Command], entry)
+	}
+	return r
+}
+
+// LoadReplayCommandRunner 从命令记录文件创建回放执行器
+func LoadReplayCommandRunner(path string) (*ReplayCommandRunner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	var transcript Transcript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	if transcript.Version != TranscriptVersion {
+		return nil, fmt.Errorf("unsupported transcript version %d (expected %d)", transcript.Version, TranscriptVersion)
+	}
+
+	replay := NewReplayCommandRunner(transcript.Entries)
+	replay.recordedAt = transcript.RecordedAt
+	return replay, nil
+}
+
+// RecordedAt 获取记录文件的记录时间，直接由命令创建的回放执行器返回零值
+func (r *ReplayCommandRunner) RecordedAt() time.Time {
+	return r.recordedAt
+}
+
+// Run 返回记录中该命令的输出，未记录的命令返回错误
+func (r *ReplayCommandRunner) Run(ctx context.Context, command string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := r.entries[command]
+	if len(entries) == 0 {
+		return "", fmt.Errorf("command not in transcript: %s", command)
+	}
+
+	i := r.position[command]
+	if i < len(entries)-1 {
+		r.position[command] = i + 1
+	}
+
+	entry := entries[i]
+	if entry.ExitCode != 0 {
+		return "", &ExitError{Command: command, Code: entry.ExitCode, Output: entry.Output}
+	}
+	if entry.Error != "" {
+		return entry.Output, errors.New(entry.Error)
+	}
+	return entry.Output, nil
+}
+
+// RunIgnoreError 返回记录中该命令的输出并忽略错误
+func (r *ReplayCommandRunner) RunIgnoreError(ctx context.Context, command string) string {
+	output, _ := r.Run(ctx, command)
+	return output
+}
+
+// RunWithTimeout 回放不执行命令，超时设置不起作用
+func (r *ReplayCommandRunner) RunWithTimeout(command string, timeout time.Duration) (string, error) {
+	return r.Run(context.Background(), command)
+}
+
+// RunWithStatus 返回记录中该命令的输出和退出码
+func (r *ReplayCommandRunner) RunWithStatus(ctx context.Context, command string) (string, int, error) {
+	return SplitExitStatus(r.Run(ctx, command))
+}
@@ -0,0 +1,84 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordReplayCommandRunner(t *testing.T) {
+	mock := NewMockCommandRunner()
+	mock.SetMockOutput("smartctl -a /dev/sda", "Temperature: 35 Celsius")
+	mock.SetMockError("smartctl -a /dev/sdb", errors.New("device busy"))
+	mock.SetMockError("smartctl -a /dev/sdc", &ExitError{Command: "smartctl -a /dev/sdc", Code: 64, Output: "Temperature: 40 Celsius"})
+
+	recorder := NewRecordingCommandRunner(mock)
+	ctx := context.Background()
+	commands := []string{"smartctl -a /dev/sda", "smartctl -a /dev/sdb", "smartctl -a /dev/sdc", "lsblk", "smartctl -a /dev/sda"}
+
+	type result struct {
+		output string
+		err    string
+	}
+	run := func(runner CommandRunner) []result {
+		var results []result
+		for _, command := range commands {
+			output, err := runner.Run(ctx, command)
+			r := result{output: output}
+			if err != nil {
+				r.err = err.Error()
+			}
+			results = append(results, r)
+		}
+		return results
+	}
+
+	recorded := run(recorder)
+	if got := len(recorder.Entries()); got != len(commands) {
+		t.Fatalf("Expected %d recorded commands, got %d", len(commands), got)
+	}
+
+	path := filepath.Join(t.TempDir(), "transcript.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	replay, err := LoadReplayCommandRunner(path)
+	if err != nil {
+		t.Fatalf("LoadReplayCommandRunner failed: %v", err)
+	}
+
+	// 回放结果(包括错误)与记录时完全一致
+	replayed := run(replay)
+	for i := range commands {
+		if replayed[i] != recorded[i] {
+			t.Errorf("%s: recorded %+v, replayed %+v", commands[i], recorded[i], replayed[i])
+		}
+	}
+
+	// 非零退出码和输出在回放时仍可获取
+	_, err = replay.Run(ctx, "smartctl -a /dev/sdc")
+	if exitErr, ok := GetExitError(err); !ok || exitErr.Code != 64 || exitErr.Output != "Temperature: 40 Celsius" {
+		t.Errorf("Expected replayed exit status 64 with output, got %v", err)
+	}
+
+	// 未记录的命令返回错误
+	if _, err := replay.Run(ctx, "zpool status"); err == nil {
+		t.Error("Expected an error for a command that is not in the transcript")
+	}
+}
+
+func TestReplayCommandRunner_RepeatedCommand(t *testing.T) {
+	replay := NewReplayCommandRunner([]TranscriptEntry{
+		{Command: "sensors", Output: "first"},
+		{Command: "sensors", Output: "second"},
+	})
+	ctx := context.Background()
+
+	// 按记录顺序返回，用完后重复最后一次的输出
+	for _, want := range []string{"first", "second", "second"} {
+		if got := replay.RunIgnoreError(ctx, "sensors"); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}